@@ -1,9 +1,13 @@
 package address
 
 import (
+	"fmt"
+
 	btcec "github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
 	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/txscript"
 	"github.com/openweb3-io/crosschain/blockchain/btc/params"
 	xc "github.com/openweb3-io/crosschain/types"
 )
@@ -75,12 +79,37 @@ func (ab AddressBuilder) GetSegWitAddress(publicKey []byte) (xc.Address, error)
 	return xc.Address(address), nil
 }
 
-// GetAddressFromPublicKey returns an Address given a public key
+// Key-path only taproot address (BIP-86), from either a x-only or a regular public key.
+func (ab AddressBuilder) GetTaprootAddress(publicKey []byte) (xc.Address, error) {
+	internalKey, err := ParseTaprootPublicKey(publicKey)
+	if err != nil {
+		return "", err
+	}
+	outputKey := txscript.ComputeTaprootKeyNoScript(internalKey)
+	addressTaproot, err := btcutil.NewAddressTaproot(schnorr.SerializePubKey(outputKey), ab.params)
+	if err != nil {
+		return "", err
+	}
+	return xc.Address(addressTaproot.EncodeAddress()), nil
+}
+
+// Parse a 32 byte x-only public key, or a regular public key which is used as the internal key of a taproot output
+func ParseTaprootPublicKey(publicKey []byte) (*btcec.PublicKey, error) {
+	if len(publicKey) == schnorr.PubKeyBytesLen {
+		return schnorr.ParsePubKey(publicKey)
+	}
+	return btcec.ParsePubKey(publicKey)
+}
+
+// GetAddressFromPublicKey returns an Address given a public key.  A 32 byte x-only public key
+// returns a taproot address.
 func (ab AddressBuilder) GetAddressFromPublicKey(publicKeyBytes []byte) (xc.Address, error) {
-	// // hack to support Taproot until btcutil is bumped
-	// if len(publicKeyBytes) == 32 {
-	// 	publicKeyBytes = append([]byte{0x02}, publicKeyBytes...)
-	// }
+	if len(publicKeyBytes) == schnorr.PubKeyBytesLen {
+		if !ab.supportsTaproot() {
+			return "", fmt.Errorf("taproot is not supported on %s", ab.cfg.Chain)
+		}
+		return ab.GetTaprootAddress(publicKeyBytes)
+	}
 	pubkey, err := btcec.ParsePubKey(publicKeyBytes)
 	if err != nil {
 		return "", err
@@ -113,7 +142,7 @@ func (ab AddressBuilder) GetAllPossibleAddressesFromPublicKey(publicKeyBytes []b
 		return possibles, err
 	}
 
-	return []xc.PossibleAddress{
+	possibles = []xc.PossibleAddress{
 		{
 			Address: legacyAddress,
 			Type:    xc.AddressTypeP2PKH,
@@ -126,5 +155,20 @@ func (ab AddressBuilder) GetAllPossibleAddressesFromPublicKey(publicKeyBytes []b
 			Address: multiSigAddress,
 			Type:    "",
 		},
-	}, nil
+	}
+	if ab.supportsTaproot() {
+		taprootAddress, err := ab.GetTaprootAddress(publicKeyBytes)
+		if err != nil {
+			return possibles, err
+		}
+		possibles = append(possibles, xc.PossibleAddress{
+			Address: taprootAddress,
+			Type:    xc.AddressTypeP2TR,
+		})
+	}
+	return possibles, nil
+}
+
+func (ab AddressBuilder) supportsTaproot() bool {
+	return ab.cfg.Blockchain != xc.BlockchainBtcLegacy && ab.cfg.Blockchain != xc.BlockchainBtcCash
 }
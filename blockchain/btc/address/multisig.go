@@ -0,0 +1,78 @@
+package address
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+
+	btcec "github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/txscript"
+	xc "github.com/openweb3-io/crosschain/types"
+)
+
+// The most keys allowed in a standard multisig script
+const MaxMultisigKeys = 15
+
+// NewMultisigScript returns the m-of-n witness script of a multisig.  The keys are used in the
+// order given, which is part of the address; sort them first for wallets expecting sorted keys (BIP-67).
+func NewMultisigScript(threshold int, publicKeys [][]byte) ([]byte, error) {
+	if threshold <= 0 {
+		return nil, errors.New("multisig threshold must be positive")
+	}
+	if len(publicKeys) < threshold {
+		return nil, fmt.Errorf("multisig threshold of %d needs at least as many public keys, got %d", threshold, len(publicKeys))
+	}
+	if len(publicKeys) > MaxMultisigKeys {
+		return nil, fmt.Errorf("multisig can have at most %d public keys, got %d", MaxMultisigKeys, len(publicKeys))
+	}
+	builder := txscript.NewScriptBuilder().AddInt64(int64(threshold))
+	for i, publicKeyBytes := range publicKeys {
+		publicKey, err := btcec.ParsePubKey(publicKeyBytes)
+		if err != nil {
+			return nil, fmt.Errorf("invalid multisig public key %d: %v", i, err)
+		}
+		builder.AddData(publicKey.SerializeCompressed())
+	}
+	builder.AddInt64(int64(len(publicKeys))).AddOp(txscript.OP_CHECKMULTISIG)
+	return builder.Script()
+}
+
+func (ab AddressBuilder) getMultisigWitnessAddress(threshold int, publicKeys [][]byte) (*btcutil.AddressWitnessScriptHash, error) {
+	if ab.cfg.Blockchain == xc.BlockchainBtcLegacy || ab.cfg.Blockchain == xc.BlockchainBtcCash {
+		return nil, fmt.Errorf("segwit multisig is not supported on %s", ab.cfg.Chain)
+	}
+	script, err := NewMultisigScript(threshold, publicKeys)
+	if err != nil {
+		return nil, err
+	}
+	scriptHash := sha256.Sum256(script)
+	return btcutil.NewAddressWitnessScriptHash(scriptHash[:], ab.params)
+}
+
+// GetMultisigAddress returns the P2WSH address of a m-of-n multisig
+func (ab AddressBuilder) GetMultisigAddress(threshold int, publicKeys [][]byte) (xc.Address, error) {
+	address, err := ab.getMultisigWitnessAddress(threshold, publicKeys)
+	if err != nil {
+		return "", err
+	}
+	return xc.Address(address.EncodeAddress()), nil
+}
+
+// GetNestedMultisigAddress returns the P2SH-P2WSH address of a m-of-n multisig, for senders
+// that cannot pay to native segwit addresses
+func (ab AddressBuilder) GetNestedMultisigAddress(threshold int, publicKeys [][]byte) (xc.Address, error) {
+	witnessAddress, err := ab.getMultisigWitnessAddress(threshold, publicKeys)
+	if err != nil {
+		return "", err
+	}
+	redeemScript, err := txscript.PayToAddrScript(witnessAddress)
+	if err != nil {
+		return "", err
+	}
+	address, err := btcutil.NewAddressScriptHash(redeemScript, ab.params)
+	if err != nil {
+		return "", err
+	}
+	return xc.Address(address.EncodeAddress()), nil
+}
@@ -1,17 +1,23 @@
 package btc_test
 
 import (
+	"bytes"
 	"context"
 	"encoding/base64"
 	"encoding/hex"
 	"fmt"
 	"testing"
 
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
 	. "github.com/openweb3-io/crosschain/blockchain/btc"
 	"github.com/openweb3-io/crosschain/blockchain/btc/address"
 	"github.com/openweb3-io/crosschain/blockchain/btc/tx"
 	"github.com/openweb3-io/crosschain/blockchain/btc/tx_input"
 	xcbuilder "github.com/openweb3-io/crosschain/builder"
+	"github.com/openweb3-io/crosschain/factory/signer"
 	xc "github.com/openweb3-io/crosschain/types"
 	"github.com/stretchr/testify/suite"
 )
@@ -125,6 +131,53 @@ func (s *CrosschainTestSuite) TestGetAllPossibleAddressesFromPublicKey() {
 	}
 	require.True(validated_p2pkh)
 	require.True(validated_p2wkh)
+
+	types := func(addresses []xc.PossibleAddress) []xc.AddressType {
+		types := []xc.AddressType{}
+		for _, addr := range addresses {
+			types = append(types, addr.Type)
+		}
+		return types
+	}
+	require.Contains(types(addresses), xc.AddressTypeP2TR)
+
+	// chains without taproot have no P2TR address
+	for _, blockchain := range []xc.Blockchain{xc.BlockchainBtcLegacy, xc.BlockchainBtcCash} {
+		builder, err := address.NewAddressBuilder(&xc.ChainConfig{Network: "testnet", Chain: "DOGE", Blockchain: blockchain})
+		require.NoError(err)
+		addresses, err := builder.GetAllPossibleAddressesFromPublicKey(pubkey)
+		require.NoError(err)
+		require.NotContains(types(addresses), xc.AddressTypeP2TR, blockchain)
+	}
+}
+
+func (s *CrosschainTestSuite) TestGetTaprootAddress() {
+	require := s.Require()
+	builder, err := address.NewAddressBuilder(&xc.ChainConfig{
+		Network:    "mainnet",
+		Chain:      xc.BTC,
+		Blockchain: xc.BlockchainBtc,
+	})
+	require.NoError(err)
+	// BIP-86 test vector for m/86'/0'/0'/0/0
+	xonlyPubkey, _ := hex.DecodeString("cc8a4bc64d897bddc5fbc2f670f7a8ba0b386779106cf1223c6fc5d7cd6fc115")
+	addr, err := builder.GetAddressFromPublicKey(xonlyPubkey)
+	require.NoError(err)
+	require.EqualValues("bc1p5cyxnuxmeuwuvkwfem96lqzszd02n6xdcjrs20cac6yqjjwudpxqkedrcr", addr)
+
+	// the same key with either parity
+	for _, prefix := range []string{"02", "03"} {
+		pubkey, _ := hex.DecodeString(prefix + "cc8a4bc64d897bddc5fbc2f670f7a8ba0b386779106cf1223c6fc5d7cd6fc115")
+		addr, err = builder.(address.AddressBuilder).GetTaprootAddress(pubkey)
+		require.NoError(err)
+		require.EqualValues("bc1p5cyxnuxmeuwuvkwfem96lqzszd02n6xdcjrs20cac6yqjjwudpxqkedrcr", addr)
+	}
+
+	// not supported on chains without taproot
+	dogeBuilder, err := address.NewAddressBuilder(&xc.ChainConfig{Chain: xc.DOGE, Blockchain: xc.BlockchainBtcLegacy})
+	require.NoError(err)
+	_, err = dogeBuilder.GetAddressFromPublicKey(xonlyPubkey)
+	require.ErrorContains(err, "taproot is not supported")
 }
 
 // TxBuilder
@@ -145,8 +198,8 @@ func (s *CrosschainTestSuite) TestNewNativeTransfer() {
 		"mpjwFvP88ZwAt3wEHY6irKkGhxcsv22BP6",
 		// segwit
 		"tb1qhymp5maj7x2rqxsj02exqn26v5jcqm0q3x3pz4",
-		// taproot (not supported)
-		// "tb1p5gkytm46mtksmssryta62fejfxvh82vnqs96hnd96gwmn0ztz4esam80dt",
+		// taproot
+		"tb1p5gkytm46mtksmssryta62fejfxvh82vnqs96hnd96gwmn0ztz4esam80dt",
 	} {
 		for _, toAddr := range []string{
 			// legacy
@@ -206,6 +259,42 @@ func (s *CrosschainTestSuite) TestNewNativeTransfer() {
 	}
 }
 
+func (s *CrosschainTestSuite) TestNewBatchTransfer() {
+	require := s.Require()
+	builder, _ := NewTxBuilder(&xc.ChainConfig{Chain: xc.BTC, Network: "testnet"})
+	from := xc.Address("tb1qhymp5maj7x2rqxsj02exqn26v5jcqm0q3x3pz4")
+	recipients := []xcbuilder.Recipient{
+		{To: "mxVFsFW5N4mu1HPkxPttorvocvzeZ7KZyk", Amount: xc.NewBigIntFromUint64(1000)},
+		{To: "tb1qtguj96eqjtzt2fywyqdgmuw6wtpdsuahheqja6", Amount: xc.NewBigIntFromUint64(2000)},
+		{To: "tb1p5gkytm46mtksmssryta62fejfxvh82vnqs96hnd96gwmn0ztz4esam80dt", Amount: xc.NewBigIntFromUint64(3000)},
+	}
+	input := &tx_input.TxInput{
+		UnspentOutputs: []tx_input.Output{{
+			Value: xc.NewBigIntFromUint64(100_000),
+		}},
+		GasPricePerByte: xc.NewBigIntFromUint64(1),
+	}
+
+	args, err := xcbuilder.NewBatchTransferArgs(from, recipients)
+	require.NoError(err)
+	tf, err := builder.NewBatchTransfer(args, input)
+	require.NoError(err)
+	btcTx := tf.(*tx.Tx)
+	// an output per recipient, plus change
+	require.Len(btcTx.MsgTx.TxOut, 4)
+	for i, recipient := range recipients {
+		require.EqualValues(recipient.Amount.Uint64(), btcTx.MsgTx.TxOut[i].Value)
+	}
+	require.EqualValues(6000, btcTx.Amount.Uint64())
+	fee := uint64(100_000 - 6000 - btcTx.MsgTx.TxOut[3].Value)
+	require.Greater(fee, uint64(0))
+
+	_, err = xcbuilder.NewBatchTransferArgs(from, nil)
+	require.Error(err)
+	_, err = xcbuilder.NewBatchTransferArgs(from, []xcbuilder.Recipient{{To: "mxVFsFW5N4mu1HPkxPttorvocvzeZ7KZyk"}})
+	require.Error(err)
+}
+
 func (s *CrosschainTestSuite) TestNewTokenTransfer() {
 	require := s.Require()
 	asset := &xc.ChainConfig{Chain: xc.BTC, Network: "testnet"}
@@ -295,7 +384,9 @@ func (s *CrosschainTestSuite) TestTxHash() {
 	require.NoError(err)
 
 	tx := tf.(*tx.Tx)
-	require.Equal(xc.TxHash("0ebdd0e519cf4bf67ac4d924c07e3312483b09844c9f16f46c04f5fe1500c788"), tx.Hash())
+	require.Equal(xc.TxHash("a6a8458f8b26c0d334304162d8b5abb9402589acad0c5162bc41da0844c98a64"), tx.Hash())
+	// 223 vbytes at 1 sat/vbyte
+	require.EqualValues(776, tx.MsgTx.TxOut[1].Value)
 }
 
 func (s *CrosschainTestSuite) TestTxSighashes() {
@@ -373,3 +464,276 @@ func (s *CrosschainTestSuite) TestTxAddSignature() {
 	}...)
 	require.NoError(err)
 }
+
+func (s *CrosschainTestSuite) TestTxSignatureRequests() {
+	require := s.Require()
+	asset := &xc.ChainConfig{Chain: xc.BTC, Network: "testnet"}
+	builder, _ := NewTxBuilder(asset)
+	from := xc.Address("mpjwFvP88ZwAt3wEHY6irKkGhxcsv22BP6")
+	other := xc.Address("mxVFsFW5N4mu1HPkxPttorvocvzeZ7KZyk")
+	to := xc.Address("tb1qtpqqpgadjr2q3f4wrgd6ndclqtfg7cz5evtvs0")
+	args, err := xcbuilder.NewTransferArgs(from, to, xc.NewBigIntFromUint64(15000))
+	require.NoError(err)
+
+	fromPubkey := bytes.Repeat([]byte{2}, 33)
+	otherPubkey := bytes.Repeat([]byte{3}, 33)
+	input := &tx_input.TxInput{
+		UnspentOutputs: []tx_input.Output{
+			{Value: xc.NewBigIntFromUint64(10000)},
+			{Value: xc.NewBigIntFromUint64(10000), Address: other, PublicKey: otherPubkey},
+		},
+		FromPublicKey: fromPubkey,
+	}
+	tf, err := builder.NewNativeTransfer(args, input)
+	require.NoError(err)
+	txObject := tf.(*tx.Tx)
+
+	// each input is signed by the owner of the output it spends
+	requests, err := txObject.SignatureRequests()
+	require.NoError(err)
+	require.Len(requests, 2)
+	sighashes, err := txObject.Sighashes()
+	require.NoError(err)
+	signers := map[xc.Address][]byte{}
+	for i, request := range requests {
+		require.Equal(xc.K256Sha256, request.Algorithm)
+		require.EqualValues(sighashes[i], request.Payload)
+		signers[request.Signer] = txObject.Input.UnspentOutputs[i].PublicKey
+	}
+	require.Len(signers, 2)
+	require.Contains(signers, from)
+	require.Equal(otherPubkey, signers[other])
+
+	// and spent with its own public key
+	sig := make([]byte, 65)
+	for i := range sig {
+		sig[i] = byte(i + 1)
+	}
+	err = txObject.AddSignatures(sig, sig)
+	require.NoError(err)
+	for i, txIn := range txObject.MsgTx.TxIn {
+		expected := fromPubkey
+		if txObject.Input.UnspentOutputs[i].Address == other {
+			expected = otherPubkey
+		}
+		require.True(bytes.HasSuffix(txIn.SignatureScript, expected))
+	}
+}
+
+func (s *CrosschainTestSuite) TestTaprootTransfer() {
+	require := s.Require()
+	chain := &xc.ChainConfig{Chain: xc.BTC, Network: "testnet", Blockchain: xc.BlockchainBtc}
+	txSigner, err := signer.New(xc.BlockchainBtc, "289c2857d4598e37fb9647507e47a309d6133539bf21a8b9cb6df88fd5232032", chain)
+	require.NoError(err)
+	pubkey := txSigner.MustPublicKey()
+
+	addressBuilder, err := address.NewAddressBuilder(chain)
+	require.NoError(err)
+	taprootAddr, err := addressBuilder.(address.AddressBuilder).GetTaprootAddress(pubkey)
+	require.NoError(err)
+	segwitAddr, err := addressBuilder.GetAddressFromPublicKey(pubkey)
+	require.NoError(err)
+
+	params := &chaincfg.TestNet3Params
+	scriptOf := func(addr xc.Address) []byte {
+		decoded, err := btcutil.DecodeAddress(string(addr), params)
+		require.NoError(err)
+		script, err := txscript.PayToAddrScript(decoded)
+		require.NoError(err)
+		return script
+	}
+
+	// spend a taproot and a segwit output of the same key
+	input := &tx_input.TxInput{
+		UnspentOutputs: []tx_input.Output{
+			{
+				Outpoint:     tx_input.Outpoint{Hash: bytes.Repeat([]byte{1}, 32), Index: 0},
+				Value:        xc.NewBigIntFromUint64(20000),
+				PubKeyScript: scriptOf(taprootAddr),
+			},
+			{
+				Outpoint:     tx_input.Outpoint{Hash: bytes.Repeat([]byte{2}, 32), Index: 1},
+				Value:        xc.NewBigIntFromUint64(30000),
+				PubKeyScript: scriptOf(segwitAddr),
+			},
+		},
+		GasPricePerByte: xc.NewBigIntFromUint64(1),
+	}
+	require.NoError(input.SetPublicKey(pubkey))
+
+	builder, err := NewTxBuilder(chain)
+	require.NoError(err)
+	args, err := xcbuilder.NewTransferArgs(taprootAddr, "tb1p5gkytm46mtksmssryta62fejfxvh82vnqs96hnd96gwmn0ztz4esam80dt", xc.NewBigIntFromUint64(40000))
+	require.NoError(err)
+	tf, err := builder.NewNativeTransfer(args, input)
+	require.NoError(err)
+
+	// coin selection may reorder the inputs
+	spent := tf.(*tx.Tx).Input.UnspentOutputs
+	require.Len(spent, 2)
+	taprootIndex := 0
+	if !bytes.Equal(spent[0].PubKeyScript, scriptOf(taprootAddr)) {
+		taprootIndex = 1
+	}
+	require.Equal(scriptOf(taprootAddr), spent[taprootIndex].PubKeyScript)
+	expectedTypes := []xc.SignatureType{xc.K256Sha256, xc.K256Sha256}
+	expectedTypes[taprootIndex] = xc.Schnorr
+
+	types, err := tf.(xc.TxWithSignatureTypes).SignatureTypes()
+	require.NoError(err)
+	require.Equal(expectedTypes, types)
+
+	signatures, err := txSigner.SignTx(tf)
+	require.NoError(err)
+	require.Len(signatures[taprootIndex], 64)
+	require.NoError(tf.AddSignatures(signatures...))
+
+	// the script engine accepts every input
+	msgTx := tf.(*tx.Tx).MsgTx
+	fetcher := txscript.NewMultiPrevOutFetcher(nil)
+	for i, utxo := range spent {
+		fetcher.AddPrevOut(msgTx.TxIn[i].PreviousOutPoint, wire.NewTxOut(utxo.Value.Int().Int64(), utxo.PubKeyScript))
+	}
+	sigHashes := txscript.NewTxSigHashes(msgTx, fetcher)
+	for i, utxo := range spent {
+		engine, err := txscript.NewEngine(utxo.PubKeyScript, msgTx, i, txscript.StandardVerifyFlags, nil, sigHashes, utxo.Value.Int().Int64(), fetcher)
+		require.NoError(err)
+		require.NoError(engine.Execute(), "input %d", i)
+	}
+
+	// a taproot input needs a schnorr signature
+	tf, err = builder.NewNativeTransfer(args, input)
+	require.NoError(err)
+	sig := make([]byte, 65)
+	err = tf.AddSignatures(sig, sig)
+	require.ErrorContains(err, "invalid schnorr signature")
+}
+
+func (s *CrosschainTestSuite) TestMultisigTransfer() {
+	require := s.Require()
+	chain := &xc.ChainConfig{Chain: xc.BTC, Network: "testnet", Blockchain: xc.BlockchainBtc}
+	signers := []*signer.Signer{}
+	publicKeys := [][]byte{}
+	for _, secret := range []string{
+		"289c2857d4598e37fb9647507e47a309d6133539bf21a8b9cb6df88fd5232032",
+		"b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291",
+		"c90fdaa22168c234c4c6628b80dc1cd129024e088a67cc74020bbea63b139b22",
+	} {
+		txSigner, err := signer.New(xc.BlockchainBtc, secret, chain)
+		require.NoError(err)
+		signers = append(signers, txSigner)
+		publicKeys = append(publicKeys, txSigner.MustPublicKey())
+	}
+	addressBuilder, err := address.NewAddressBuilder(chain)
+	require.NoError(err)
+	multisigAddr, err := addressBuilder.(address.AddressBuilder).GetMultisigAddress(2, publicKeys)
+	require.NoError(err)
+	require.Len(multisigAddr, 62)
+	nestedAddr, err := addressBuilder.(address.AddressBuilder).GetNestedMultisigAddress(2, publicKeys)
+	require.NoError(err)
+	require.Equal("2", string(nestedAddr[:1]))
+
+	params := &chaincfg.TestNet3Params
+	scriptOf := func(addr xc.Address) []byte {
+		decoded, err := btcutil.DecodeAddress(string(addr), params)
+		require.NoError(err)
+		script, err := txscript.PayToAddrScript(decoded)
+		require.NoError(err)
+		return script
+	}
+	builder, err := NewTxBuilder(chain)
+	require.NoError(err)
+
+	for _, from := range []xc.Address{multisigAddr, nestedAddr} {
+		input := &tx_input.TxInput{
+			UnspentOutputs: []tx_input.Output{
+				{
+					Outpoint:     tx_input.Outpoint{Hash: bytes.Repeat([]byte{1}, 32), Index: 0},
+					Value:        xc.NewBigIntFromUint64(20000),
+					PubKeyScript: scriptOf(from),
+				},
+				{
+					Outpoint:     tx_input.Outpoint{Hash: bytes.Repeat([]byte{2}, 32), Index: 1},
+					Value:        xc.NewBigIntFromUint64(30000),
+					PubKeyScript: scriptOf(from),
+				},
+			},
+			GasPricePerByte: xc.NewBigIntFromUint64(1),
+		}
+		input.SetMultisig(2, publicKeys)
+		args, err := xcbuilder.NewTransferArgs(from, "tb1qtpqqpgadjr2q3f4wrgd6ndclqtfg7cz5evtvs0", xc.NewBigIntFromUint64(40000))
+		require.NoError(err)
+		tf, err := builder.NewNativeTransfer(args, input)
+		require.NoError(err)
+		multisigTx := tf.(*tx.Tx)
+		hash := multisigTx.Hash()
+
+		// the first member signs and exports a psbt
+		signatures, err := signers[0].SignTx(multisigTx)
+		require.NoError(err)
+		require.NoError(multisigTx.AddSignatures(signatures...))
+		require.False(multisigTx.Signed)
+		_, err = multisigTx.Serialize()
+		require.ErrorContains(err, "do not have enough signatures")
+		have, need, err := multisigTx.MultisigSignatureCounts()
+		require.NoError(err)
+		require.Equal([]int{1, 1}, have)
+		require.Equal([]int{2, 2}, need)
+		encoded, err := multisigTx.EncodePSBT()
+		require.NoError(err)
+
+		// the third member signs it elsewhere
+		cosigned, err := tx.NewTxFromPSBT(encoded)
+		require.NoError(err)
+		require.Equal(hash, cosigned.Hash())
+		signatures, err = signers[2].SignTx(cosigned)
+		require.NoError(err)
+		require.NoError(cosigned.AddSignatures(signatures...))
+		require.True(cosigned.Signed)
+		encoded, err = cosigned.EncodePSBT()
+		require.NoError(err)
+
+		// and the signatures are merged back
+		require.NoError(multisigTx.CombinePSBT(encoded))
+		require.True(multisigTx.Signed)
+		require.Equal(hash, multisigTx.Hash())
+		serialized, err := multisigTx.Serialize()
+		require.NoError(err)
+		cosignedSerialized, err := cosigned.Serialize()
+		require.NoError(err)
+		require.Equal(serialized, cosignedSerialized)
+
+		// the script engine accepts every input
+		msgTx := multisigTx.MsgTx
+		spent := multisigTx.Input.UnspentOutputs
+		fetcher := txscript.NewMultiPrevOutFetcher(nil)
+		for i, utxo := range spent {
+			fetcher.AddPrevOut(msgTx.TxIn[i].PreviousOutPoint, wire.NewTxOut(utxo.Value.Int().Int64(), utxo.PubKeyScript))
+		}
+		sigHashes := txscript.NewTxSigHashes(msgTx, fetcher)
+		for i, utxo := range spent {
+			engine, err := txscript.NewEngine(utxo.PubKeyScript, msgTx, i, txscript.StandardVerifyFlags, nil, sigHashes, utxo.Value.Int().Int64(), fetcher)
+			require.NoError(err)
+			require.NoError(engine.Execute(), "input %d", i)
+		}
+
+		// the fee covers the multisig witnesses
+		vsize := (uint64(msgTx.SerializeSizeStripped())*3 + uint64(msgTx.SerializeSize()) + 3) / 4
+		fee := uint64(50000 - msgTx.TxOut[0].Value - msgTx.TxOut[1].Value)
+		require.GreaterOrEqual(fee, vsize)
+
+		// only members can sign
+		outsider, err := signer.New(xc.BlockchainBtc, "0000000000000000000000000000000000000000000000000000000000000001", chain)
+		require.NoError(err)
+		tf, err = builder.NewNativeTransfer(args, input)
+		require.NoError(err)
+		signatures, err = outsider.SignTx(tf)
+		require.NoError(err)
+		require.ErrorContains(tf.AddSignatures(signatures...), "not from a member of its multisig")
+
+		// and only outputs of the multisig are spent
+		input.SetMultisig(2, publicKeys[1:])
+		_, err = builder.NewNativeTransfer(args, input)
+		require.ErrorContains(err, "is not paid to the multisig")
+	}
+}
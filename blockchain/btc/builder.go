@@ -1,9 +1,11 @@
 package btc
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 
+	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/chaincfg"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/btcsuite/btcd/txscript"
@@ -19,15 +21,28 @@ import (
 
 const TxVersion int32 = 2
 
+// Keeps transactions well below the standard size limit
+const DefaultMaxInputs = 500
+
+// 0.01 DOGE, the dust limit of dogecoin core
+const DogeDustThreshold = 1_000_000
+
 // TxBuilder for Bitcoin
 type TxBuilder struct {
 	Chain          *xc.ChainConfig
 	Params         *chaincfg.Params
 	AddressDecoder address.AddressDecoder
+	// Defaults to branch and bound, falling back to spending the largest outputs first
+	CoinSelector tx_input.CoinSelector
+	// The most outputs to spend in a transaction, 0 for no limit
+	MaxInputs int
+	// Change below this is added to the fee, defaults to the dust limit of the change address
+	DustThreshold uint64
 	// isBch  bool
 }
 
 var _ xcbuilder.TxBuilder = &TxBuilder{}
+var _ xcbuilder.TxBatchBuilder = &TxBuilder{}
 
 // NewTxBuilder creates a new Bitcoin TxBuilder
 func NewTxBuilder(cfg *xc.ChainConfig) (TxBuilder, error) {
@@ -39,6 +54,7 @@ func NewTxBuilder(cfg *xc.ChainConfig) (TxBuilder, error) {
 		Chain:          cfg,
 		Params:         params,
 		AddressDecoder: &address.BtcAddressDecoder{},
+		MaxInputs:      DefaultMaxInputs,
 		// isBch:  native.Chain == xc.BCH,
 	}, nil
 }
@@ -48,6 +64,21 @@ func (txBuilder TxBuilder) WithAddressDecoder(decoder address.AddressDecoder) Tx
 	return txBuilder
 }
 
+func (txBuilder TxBuilder) WithCoinSelector(selector tx_input.CoinSelector) TxBuilder {
+	txBuilder.CoinSelector = selector
+	return txBuilder
+}
+
+func (txBuilder TxBuilder) WithMaxInputs(maxInputs int) TxBuilder {
+	txBuilder.MaxInputs = maxInputs
+	return txBuilder
+}
+
+func (txBuilder TxBuilder) WithDustThreshold(threshold uint64) TxBuilder {
+	txBuilder.DustThreshold = threshold
+	return txBuilder
+}
+
 // Old transfer interface
 func (txBuilder TxBuilder) NewTransfer(args *xcbuilder.TransferArgs, input xc.TxInput) (xc.Tx, error) {
 	asset, _ := args.GetAsset()
@@ -71,79 +102,175 @@ func (txBuilder TxBuilder) NewNativeTransfer(args *xcbuilder.TransferArgs, input
 	if asset == nil {
 		asset = txBuilder.Chain
 	}
+	recipients := []xcbuilder.Recipient{{To: args.GetTo(), Amount: args.GetAmount()}}
+	return txBuilder.newTransfer(args.GetFrom(), recipients, asset, input)
+}
 
+// NewBatchTransfer pays each recipient with its own output, with a single change output
+func (txBuilder TxBuilder) NewBatchTransfer(args *xcbuilder.BatchTransferArgs, input xc.TxInput) (xc.Tx, error) {
+	asset, _ := args.GetAsset()
+	if asset == nil {
+		asset = txBuilder.Chain
+	}
+	if _, ok := asset.(*xc.ChainConfig); !ok {
+		return nil, errors.New("not implemented")
+	}
+	return txBuilder.newTransfer(args.GetFrom(), args.GetRecipients(), asset, input)
+}
+
+func (txBuilder TxBuilder) newTransfer(from xc.Address, payments []xcbuilder.Recipient, asset xc.IAsset, input xc.TxInput) (xc.Tx, error) {
 	var local_input *tx_input.TxInput
 	var ok bool
 	if local_input, ok = (input.(*tx_input.TxInput)); !ok {
 		return &tx.Tx{}, errors.New("xc.TxInput is not from a bitcoin chain")
 	}
-	// Only need to save min utxo for the transfer.
-	totalSpend := local_input.SumUtxo()
 
-	gasPrice := local_input.GasPricePerByte
-	// 255 for bitcoin, 300 for bch
-	estimatedTxBytesLength := xc.NewBigIntFromUint64(uint64(255 * len(local_input.UnspentOutputs)))
-	if xc.NativeAsset(txBuilder.Chain.Chain) == xc.BCH {
-		estimatedTxBytesLength = xc.NewBigIntFromUint64(uint64(300 * len(local_input.UnspentOutputs)))
+	recipients := []tx.Recipient{}
+	scripts := [][]byte{}
+	amount := xc.NewBigIntFromUint64(0)
+	for _, payment := range payments {
+		toScript, err := txBuilder.payToAddrScript(payment.To)
+		if err != nil {
+			return nil, err
+		}
+		recipients = append(recipients, tx.Recipient{
+			To:    payment.To,
+			Value: payment.Amount,
+		})
+		scripts = append(scripts, toScript)
+		amount = amount.Add(&payment.Amount)
+	}
+	changeScript, err := txBuilder.payToAddrScript(from)
+	if err != nil {
+		return nil, err
+	}
+
+	unspentOutputs := local_input.UnspentOutputs
+	if local_input.Multisig != nil {
+		unspentOutputs, err = txBuilder.withMultisigScript(unspentOutputs, local_input.Multisig)
+		if err != nil {
+			return nil, err
+		}
 	}
-	fee := gasPrice.Mul(&estimatedTxBytesLength)
 
-	amount := args.GetAmount()
-	transferAmountAndFee := amount.Add(&fee)
-	unspentAmountMinusTransferAndFee := totalSpend.Sub(&transferAmountAndFee)
-	recipients := []tx.Recipient{
-		{
-			To:    args.GetTo(),
-			Value: amount,
-		},
-		{
-			To:    args.GetFrom(),
-			Value: unspentAmountMinusTransferAndFee,
-		},
+	coinSelector := txBuilder.CoinSelector
+	if coinSelector == nil {
+		coinSelector = tx_input.DefaultCoinSelector()
+	}
+	selection, err := coinSelector.SelectCoins(unspentOutputs, &tx_input.SelectionParams{
+		Amount:        amount.Uint64(),
+		FeeRate:       local_input.GasPricePerByte.Uint64(),
+		OutputScripts: scripts,
+		ChangeScript:  changeScript,
+		DustThreshold: txBuilder.dustThreshold(changeScript),
+		MaxInputs:     txBuilder.MaxInputs,
+	})
+	if err != nil {
+		if errors.Is(err, tx_input.ErrInsufficientFunds) {
+			available := local_input.SumUtxo()
+			return nil, fmt.Errorf("not enough funds to transfer %s with fees, only %s is available: %v",
+				amount.ToHuman(asset.GetDecimals()).String(), available.ToHuman(asset.GetDecimals()).String(), err,
+			)
+		}
+		return nil, err
+	}
+
+	// only the selected outputs are spent
+	selectedInput := *local_input
+	selectedInput.UnspentOutputs = selection.Inputs
+
+	if selection.Change > 0 {
+		recipients = append(recipients, tx.Recipient{
+			To:    from,
+			Value: xc.NewBigIntFromUint64(selection.Change),
+		})
+		scripts = append(scripts, changeScript)
 	}
 
 	msgTx := wire.NewMsgTx(TxVersion)
 
-	for _, input := range local_input.UnspentOutputs {
+	for _, input := range selectedInput.UnspentOutputs {
 		hash := chainhash.Hash{}
 		copy(hash[:], input.Hash)
-		msgTx.AddTxIn(wire.NewTxIn(wire.NewOutPoint(&hash, input.Index), nil, nil))
+		txIn := wire.NewTxIn(wire.NewOutPoint(&hash, input.Index), nil, nil)
+		txIn.Sequence = txBuilder.sequence()
+		// the redeem script of a nested multisig is known before signing, and is part of the hash
+		if redeemScript := input.RedeemScript(); redeemScript != nil {
+			txIn.SignatureScript, err = txscript.NewScriptBuilder().AddData(redeemScript).Script()
+			if err != nil {
+				return nil, err
+			}
+		}
+		msgTx.AddTxIn(txIn)
 	}
 
 	// Outputs
-	for _, recipient := range recipients {
-		addr, err := txBuilder.AddressDecoder.Decode(recipient.To, txBuilder.Params)
-		if err != nil {
-			return nil, err
-		}
-		script, err := txscript.PayToAddrScript(addr)
-		if err != nil {
-			logrus.WithError(err).WithField("to", recipient.To).Error("trying paytoaddr")
-			return nil, err
-		}
-		value := recipient.Value.Int().Int64()
-		if value < 0 {
-			diff := local_input.SumUtxo().Sub(&amount)
-			return nil, fmt.Errorf("not enough funds for fees, estimated fee is %s but only %s is left after transfer",
-				fee.ToHuman(asset.GetDecimals()).String(), diff.ToHuman(asset.GetDecimals()).String(),
-			)
-		}
-		msgTx.AddTxOut(wire.NewTxOut(value, script))
+	for i, recipient := range recipients {
+		msgTx.AddTxOut(wire.NewTxOut(recipient.Value.Int().Int64(), scripts[i]))
 	}
 
 	tx := tx.Tx{
 		MsgTx: msgTx,
 
-		From:   args.GetFrom(),
-		To:     args.GetTo(),
+		From:   from,
+		To:     payments[0].To,
 		Amount: amount,
-		Input:  local_input,
+		Input:  &selectedInput,
 
 		Recipients: recipients,
 	}
 	return &tx, nil
 }
 
+// Attach the witness script of the multisig to the outputs it owns, which must all be paid to it
+func (txBuilder TxBuilder) withMultisigScript(unspentOutputs []tx_input.Output, multisig *tx_input.Multisig) ([]tx_input.Output, error) {
+	witnessScript, err := address.NewMultisigScript(multisig.Threshold, multisig.PublicKeys)
+	if err != nil {
+		return nil, err
+	}
+	witnessProgram := tx_input.WitnessProgram(witnessScript)
+	nestedAddress, err := btcutil.NewAddressScriptHash(witnessProgram, txBuilder.Params)
+	if err != nil {
+		return nil, err
+	}
+	nestedScript, err := txscript.PayToAddrScript(nestedAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	withScript := make([]tx_input.Output, len(unspentOutputs))
+	for i, output := range unspentOutputs {
+		if !bytes.Equal(output.PubKeyScript, witnessProgram) && !bytes.Equal(output.PubKeyScript, nestedScript) {
+			return nil, fmt.Errorf("unspent output %x:%d is not paid to the multisig", output.Hash, output.Index)
+		}
+		output.WitnessScript = witnessScript
+		withScript[i] = output
+	}
+	return withScript, nil
+}
+
+// Signal replaceability (BIP-125) so stuck transactions can have their fee bumped,
+// except on bitcoin cash, which does not support replacement.
+func (txBuilder TxBuilder) sequence() uint32 {
+	if txBuilder.Chain.Chain == xc.BCH {
+		return wire.MaxTxInSequenceNum
+	}
+	return tx_input.MaxRbfSequence
+}
+
+func (txBuilder TxBuilder) payToAddrScript(addr xc.Address) ([]byte, error) {
+	decoded, err := txBuilder.AddressDecoder.Decode(addr, txBuilder.Params)
+	if err != nil {
+		return nil, err
+	}
+	script, err := txscript.PayToAddrScript(decoded)
+	if err != nil {
+		logrus.WithError(err).WithField("to", addr).Error("trying paytoaddr")
+		return nil, err
+	}
+	return script, nil
+}
+
 // NewTokenTransfer creates a new transfer for a token asset
 func (txBuilder TxBuilder) NewTokenTransfer(args *xcbuilder.TransferArgs, input xc.TxInput) (xc.Tx, error) {
 	return nil, errors.New("not implemented")
@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
@@ -36,6 +37,9 @@ type BlockbookClient struct {
 }
 
 var _ xclient.IClient = &BlockbookClient{}
+var _ xclient.HistoryClient = &BlockbookClient{}
+var _ xclient.BatchTransferClient = &BlockbookClient{}
+var _ xclient.BlockClient = &BlockbookClient{}
 var _ address.WithAddressDecoder = &BlockbookClient{}
 
 func NewClient(cfg *xc.ChainConfig) (*BlockbookClient, error) {
@@ -261,21 +265,179 @@ func (client *BlockbookClient) FetchLegacyTxInfo(ctx context.Context, txHash xc.
 }
 
 func (client *BlockbookClient) FetchTxInfo(ctx context.Context, txHashStr xc.TxHash) (*xclient.TxInfo, error) {
-	legacyTx, err := client.FetchLegacyTxInfo(ctx, txHashStr)
+	var data TransactionResponse
+	err := client.get(ctx, "/api/v2/tx/"+string(txHashStr), &data)
+	if err != nil {
+		return nil, err
+	}
+	latestBlock, err := client.LatestBlock(ctx)
 	if err != nil {
 		return nil, err
 	}
+	return client.toTxInfo(string(txHashStr), &data, latestBlock), nil
+}
+
+// Fetch a transaction that is still in the mempool along with the outputs it spends, to bump its fee.
+func (client *BlockbookClient) FetchUnconfirmedTx(ctx context.Context, txHash xc.TxHash) (*tx_input.UnconfirmedTx, error) {
+	var data TransactionResponse
+	err := client.get(ctx, "/api/v2/tx/"+string(txHash), &data)
+	if err != nil {
+		return nil, err
+	}
+	if data.BlockHeight > 0 {
+		return nil, fmt.Errorf("transaction %s is already confirmed", txHash)
+	}
+	serial, err := hex.DecodeString(data.Hex)
+	if err != nil {
+		return nil, fmt.Errorf("bad transaction hex: %v", err)
+	}
+	msgTx := wire.NewMsgTx(0)
+	if err := msgTx.Deserialize(bytes.NewReader(serial)); err != nil {
+		return nil, fmt.Errorf("bad transaction hex: %v", err)
+	}
+	if len(data.Vin) != len(msgTx.TxIn) {
+		return nil, fmt.Errorf("expected %d inputs, got %d", len(msgTx.TxIn), len(data.Vin))
+	}
+	spent := []tx_input.Output{}
+	for i, in := range data.Vin {
+		if len(in.Addresses) == 0 {
+			return nil, fmt.Errorf("input %d of %s has no address", i, txHash)
+		}
+		addr, err := client.decoder.Decode(xc.Address(in.Addresses[0]), client.Chaincfg)
+		if err != nil {
+			return nil, err
+		}
+		script, err := txscript.PayToAddrScript(addr)
+		if err != nil {
+			return nil, err
+		}
+		prevOut := msgTx.TxIn[i].PreviousOutPoint
+		spent = append(spent, tx_input.Output{
+			Outpoint:     tx_input.Outpoint{Hash: prevOut.Hash[:], Index: prevOut.Index},
+			Value:        xc.NewBigIntFromStr(in.Value),
+			PubKeyScript: script,
+		})
+	}
+	vsize := data.Vsize
+	if vsize == 0 {
+		vsize = data.Size
+	}
+	return tx_input.NewUnconfirmedTx(msgTx, spent, uint64(vsize))
+}
+
+func (client *BlockbookClient) toTxInfo(txHash string, data *TransactionResponse, latestBlock uint64) *xclient.TxInfo {
 	chain := client.cfg.Chain
 
-	// delete the fee to avoid double counting.
-	// the new model will calculate fees from the difference of inflows/outflows
-	legacyTx.Fee = xc.NewBigIntFromUint64(0)
+	block := xclient.NewBlock(0, "", time.Unix(0, 0))
+	confirmations := uint64(0)
+	if data.BlockHeight > 0 {
+		block = xclient.NewBlock(uint64(data.BlockHeight), data.BlockHash, time.Unix(data.BlockTime, 0))
+		if latestBlock >= uint64(data.BlockHeight) {
+			confirmations = latestBlock - uint64(data.BlockHeight) + 1
+		}
+	}
+	txInfo := xclient.NewTxInfo(block, chain, txHash, confirmations, nil)
 
-	// add back the change movements
-	legacyTx.Destinations = append(legacyTx.Destinations, legacyTx.GetDroppedBtcDestinations()...)
+	// utxo movements are mapped as one large multitransfer, including change.
+	// the fee is then the difference of inflows/outflows.
+	tf := xclient.NewTransfer(chain)
+	for _, in := range data.Vin {
+		var from xc.Address
+		if len(in.Addresses) > 0 {
+			from = xc.Address(in.Addresses[0])
+		}
+		tf.AddSource(from, "", xc.NewBigIntFromStr(in.Value), nil)
+	}
+	for _, out := range data.Vout {
+		// skip outputs without an address (e.g. OP_RETURN)
+		if len(out.Addresses) == 0 {
+			continue
+		}
+		tf.AddDestination(xc.Address(out.Addresses[0]), "", xc.NewBigIntFromStr(out.Value), nil)
+	}
+	txInfo.AddTransfer(tf)
+	txInfo.Fees = txInfo.CalculateFees()
 
-	// remap to new tx
-	return xclient.TxInfoFromLegacy(chain, legacyTx, xclient.Utxo), nil
+	return txInfo
+}
+
+func (client *BlockbookClient) FetchTransactionsByAddress(ctx context.Context, args xclient.TransactionHistoryArgs) (*xclient.TransactionHistoryPage, error) {
+	if _, ok := args.GetContract(); ok {
+		return nil, fmt.Errorf("%s does not support filtering by contract", client.cfg.Chain)
+	}
+	page := 1
+	if cursor, ok := args.GetCursor(); ok {
+		var err error
+		page, err = strconv.Atoi(cursor)
+		if err != nil || page < 1 {
+			return nil, fmt.Errorf("invalid cursor: %s", cursor)
+		}
+	}
+	formattedAddr := string(args.GetAddress())
+	if client.cfg.Chain == xc.BCH {
+		if !strings.HasPrefix(formattedAddr, BitcoinCashPrefix) {
+			formattedAddr = fmt.Sprintf("%s%s", BitcoinCashPrefix, formattedAddr)
+		}
+	}
+
+	var data AddressResponse
+	err := client.get(ctx, fmt.Sprintf("api/v2/address/%s?details=txs&page=%d&pageSize=%d", formattedAddr, page, args.GetLimit()), &data)
+	if err != nil {
+		return nil, err
+	}
+	latestBlock, err := client.LatestBlock(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	txs := []*xclient.TxInfo{}
+	for i := range data.Transactions {
+		txs = append(txs, client.toTxInfo(data.Transactions[i].TxID, &data.Transactions[i], latestBlock))
+	}
+	nextCursor := ""
+	if data.Page < data.TotalPages {
+		nextCursor = strconv.Itoa(data.Page + 1)
+	}
+	return xclient.NewTransactionHistoryPage(txs, nextCursor), nil
+}
+
+func (client *BlockbookClient) FetchLatestHeight(ctx context.Context) (uint64, error) {
+	return client.LatestBlock(ctx)
+}
+
+// Fetch a block, going through all of the pages of transactions that blockbook splits it into.
+func (client *BlockbookClient) FetchBlock(ctx context.Context, height uint64) (*xclient.BlockWithTransactions, error) {
+	latestBlock, err := client.LatestBlock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if height > latestBlock {
+		return nil, fmt.Errorf("block %d is not yet available", height)
+	}
+
+	var data BlockResponse
+	txs := []*xclient.TxInfo{}
+	for page := 1; ; page++ {
+		data = BlockResponse{}
+		err := client.get(ctx, fmt.Sprintf("api/v2/block/%d?page=%d", height, page), &data)
+		if err != nil {
+			return nil, err
+		}
+		for i := range data.Txs {
+			tx := &data.Txs[i]
+			// transactions are not always annotated with the block when listed in a block
+			tx.BlockHash = data.Hash
+			tx.BlockHeight = data.Height
+			tx.BlockTime = data.Time
+			txs = append(txs, client.toTxInfo(tx.TxID, tx, latestBlock))
+		}
+		if data.Page >= data.TotalPages {
+			break
+		}
+	}
+
+	block := xclient.NewBlock(uint64(data.Height), data.Hash, time.Unix(data.Time, 0))
+	return xclient.NewBlockWithTransactions(block, data.PreviousBlockHash, txs), nil
 }
 
 func (client *BlockbookClient) FetchBalance(ctx context.Context, address xc.Address) (*xc.BigInt, error) {
@@ -313,6 +475,11 @@ func (client *BlockbookClient) FetchTransferInput(ctx context.Context, args *xcb
 	return input, nil
 }
 
+// The input only depends on the sender, so is the same as for a single transfer
+func (client *BlockbookClient) FetchBatchTransferInput(ctx context.Context, args *xcbuilder.BatchTransferArgs) (xc.TxInput, error) {
+	return client.FetchTransferInput(ctx, args.FirstTransferArgs())
+}
+
 func (client *BlockbookClient) FetchLegacyTxInput(ctx context.Context, from xc.Address, to xc.Address, asset xc.IAsset) (xc.TxInput, error) {
 	// No way to pass the amount in the input using legacy interface, so we estimate using min amount.
 	args, _ := xcbuilder.NewTransferArgs(from, to, xc.NewBigIntFromUint64(1), xcbuilder.WithAsset(asset))
@@ -10,8 +10,10 @@ import (
 
 	xc "github.com/openweb3-io/crosschain/types"
 
+	"github.com/openweb3-io/crosschain/blockchain/btc"
 	"github.com/openweb3-io/crosschain/blockchain/btc/client"
 	"github.com/openweb3-io/crosschain/blockchain/btc/tx_input"
+	xclient "github.com/openweb3-io/crosschain/client"
 	testtypes "github.com/openweb3-io/crosschain/testutil/types"
 	"github.com/stretchr/testify/suite"
 )
@@ -167,3 +169,115 @@ func (s *ClientTestSuite) TestFetchTxInfo() {
 	require.EqualValues(70, info.Confirmations)
 	require.EqualValues(3442, info.Fee.Uint64())
 }
+
+func (s *ClientTestSuite) TestFetchTxInfoNormalized() {
+	require := s.Require()
+	server, close := testtypes.MockHTTP(s.T(), []string{
+		// tx
+		`{"txid":"999be3740a25dc6def2e62df25be1387011c22bbf3a4b1b448ff1180e86e64f2","version":2,"vin":[{"txid":"6096941b53496f1c2196a8e5b589c01a0dd1f0b9b6754da5861d485b339b9436","vout":1,"sequence":4294967293,"n":0,"addresses":["bc1p6q4qhp9j008m2wvxjp0ffzc7ulkvzn8awaqgxjpcpzyxnlpfhrusst6t8h"],"isAddress":true,"value":"12651"}],"vout":[{"value":"546","n":0,"hex":"001436775d21d459d18cbf3d28b4eaaab0280cbcae19","addresses":["bc1qxem46gw5t8gce0ea9z6w424s9qxtetse5d69uu"],"isAddress":true},{"value":"0","n":1,"hex":"6a5d061486f533144d","addresses":[],"isAddress":false},{"value":"8663","n":2,"hex":"5120d02a0b84b27bcfb53986905e948b1ee7ecc14cfd7740834838088869fc29b8f9","addresses":["bc1p6q4qhp9j008m2wvxjp0ffzc7ulkvzn8awaqgxjpcpzyxnlpfhrusst6t8h"],"isAddress":true}],"blockHeight":850509,"confirmations":0,"blockTime":1720038342,"value":"9209","valueIn":"12651","fees":"3442","hex":"0200000000010136949b335b481d86a54d75b6b9f0d10d1ac089b5e5a896211c6f49531b9496600100000000fdffffff03220200000000000016001436775d21d459d18cbf3d28b4eaaab0280cbcae190000000000000000096a5d061486f533144dd721000000000000225120d02a0b84b27bcfb53986905e948b1ee7ecc14cfd7740834838088869fc29b8f901409fbf530c09ae37186996f2929b80a7028d3cc3176598e032af890eb2d053a518cefbe041fa7864c751b68a5f87f9b8f78ee3fd0aae353799d306078ec59301fe00000000","rbf":true,"coinSpecificData":{"txid":"999be3740a25dc6def2e62df25be1387011c22bbf3a4b1b448ff1180e86e64f2","hash":"7d414e1099cb205612e1b720d9b0665ab4a08746105f5a2ba581da3e8779f19e","version":2,"size":211,"vsize":160,"weight":640,"locktime":0,"vin":[{"txid":"6096941b53496f1c2196a8e5b589c01a0dd1f0b9b6754da5861d485b339b9436","vout":1,"scriptSig":{"asm":"","hex":""},"txinwitness":["9fbf530c09ae37186996f2929b80a7028d3cc3176598e032af890eb2d053a518cefbe041fa7864c751b68a5f87f9b8f78ee3fd0aae353799d306078ec59301fe"],"sequence":4294967293}],"vout":[{"value":0.00000546,"n":0,"scriptPubKey":{"asm":"0 36775d21d459d18cbf3d28b4eaaab0280cbcae19","desc":"addr(bc1qxem46gw5t8gce0ea9z6w424s9qxtetse5d69uu)#ncs86s49","hex":"001436775d21d459d18cbf3d28b4eaaab0280cbcae19","address":"bc1qxem46gw5t8gce0ea9z6w424s9qxtetse5d69uu","type":"witness_v0_keyhash"}},{"value":0.00000000,"n":1,"scriptPubKey":{"asm":"OP_RETURN 13 1486f533144d","desc":"raw(6a5d061486f533144d)#3s3j5jcn","hex":"6a5d061486f533144d","type":"nulldata"}},{"value":0.00008663,"n":2,"scriptPubKey":{"asm":"1 d02a0b84b27bcfb53986905e948b1ee7ecc14cfd7740834838088869fc29b8f9","desc":"rawtr(d02a0b84b27bcfb53986905e948b1ee7ecc14cfd7740834838088869fc29b8f9)#cemc67w3","hex":"5120d02a0b84b27bcfb53986905e948b1ee7ecc14cfd7740834838088869fc29b8f9","address":"bc1p6q4qhp9j008m2wvxjp0ffzc7ulkvzn8awaqgxjpcpzyxnlpfhrusst6t8h","type":"witness_v1_taproot"}}],"hex":"0200000000010136949b335b481d86a54d75b6b9f0d10d1ac089b5e5a896211c6f49531b9496600100000000fdffffff03220200000000000016001436775d21d459d18cbf3d28b4eaaab0280cbcae190000000000000000096a5d061486f533144dd721000000000000225120d02a0b84b27bcfb53986905e948b1ee7ecc14cfd7740834838088869fc29b8f901409fbf530c09ae37186996f2929b80a7028d3cc3176598e032af890eb2d053a518cefbe041fa7864c751b68a5f87f9b8f78ee3fd0aae353799d306078ec59301fe00000000"}}`,
+		// stats
+		`{"blockbook":{"coin":"Bitcoin","host":"2387762225de","version":"unknown","gitCommit":"unknown","buildTime":"unknown","syncMode":true,"initialSync":false,"inSync":true,"bestHeight":850578,"lastBlockTime":"2024-07-03T20:18:50.835054532Z","inSyncMempool":true,"lastMempoolTime":"2024-07-03T20:25:42.687082833Z","mempoolSize":55449,"decimals":8,"dbSize":499462256929,"about":"Blockbook - blockchain indexer for Trezor wallet https://trezor.io/. Do not use for any other purpose."},"backend":{"chain":"main","blocks":850578,"headers":850578,"bestBlockHash":"00000000000000000001e3bc7fc4fdf42af1968aa9f1c9d95a3089b0943efa12","difficulty":"83675262295059.91","sizeOnDisk":662338961933,"version":"270100","subversion":"/Satoshi:27.1.0/","protocolVersion":"70016"}}`,
+	}, 200)
+	defer close()
+	asset := &xc.ChainConfig{
+		Chain:   xc.BTC,
+		Network: "testnet",
+		Client: &xc.ClientConfig{
+			URL:      server.URL,
+			Provider: string(client.Blockbook),
+		},
+	}
+	client, err := client.NewClient(asset)
+	require.NoError(err)
+	info, err := client.FetchTxInfo(s.Ctx, xc.TxHash("227178d784150211e8ea5a586ee75bc97655e61f02bc8c07557e475cfecea3cd"))
+	require.NoError(err)
+	require.NotNil(info)
+	require.EqualValues("227178d784150211e8ea5a586ee75bc97655e61f02bc8c07557e475cfecea3cd", info.Hash)
+	require.EqualValues(850509, info.Block.Height)
+	require.EqualValues(70, info.Confirmations)
+	require.Nil(info.Error)
+
+	// all movements are one multi-transfer, including the change, but not the OP_RETURN
+	require.Len(info.Transfers, 1)
+	require.Len(info.Transfers[0].From, 1)
+	require.Len(info.Transfers[0].To, 2)
+	require.EqualValues(xclient.NewAddressName(xc.BTC, "bc1p6q4qhp9j008m2wvxjp0ffzc7ulkvzn8awaqgxjpcpzyxnlpfhrusst6t8h"), info.Transfers[0].From[0].Address)
+	require.EqualValues(12651, info.Transfers[0].From[0].Balance.Uint64())
+	require.EqualValues(546, info.Transfers[0].To[0].Balance.Uint64())
+	require.EqualValues(8663, info.Transfers[0].To[1].Balance.Uint64())
+
+	// fee is the difference of inflows/outflows
+	require.Len(info.Fees, 1)
+	require.EqualValues(3442, info.Fees[0].Balance.Uint64())
+}
+
+func (s *ClientTestSuite) TestFetchUnconfirmedTx() {
+	require := s.Require()
+	server, close := testtypes.MockHTTP(s.T(), []string{
+		`{"txid":"999be3740a25dc6def2e62df25be1387011c22bbf3a4b1b448ff1180e86e64f2","version":2,"vin":[{"txid":"6096941b53496f1c2196a8e5b589c01a0dd1f0b9b6754da5861d485b339b9436","vout":1,"sequence":4294967293,"n":0,"addresses":["bc1p6q4qhp9j008m2wvxjp0ffzc7ulkvzn8awaqgxjpcpzyxnlpfhrusst6t8h"],"isAddress":true,"value":"12651"}],"vout":[{"value":"546","n":0,"hex":"001436775d21d459d18cbf3d28b4eaaab0280cbcae19","addresses":["bc1qxem46gw5t8gce0ea9z6w424s9qxtetse5d69uu"],"isAddress":true},{"value":"0","n":1,"hex":"6a5d061486f533144d","addresses":[],"isAddress":false},{"value":"8663","n":2,"hex":"5120d02a0b84b27bcfb53986905e948b1ee7ecc14cfd7740834838088869fc29b8f9","addresses":["bc1p6q4qhp9j008m2wvxjp0ffzc7ulkvzn8awaqgxjpcpzyxnlpfhrusst6t8h"],"isAddress":true}],"blockHeight":0,"confirmations":0,"blockTime":1720038342,"size":211,"vsize":160,"value":"9209","valueIn":"12651","fees":"3442","hex":"0200000000010136949b335b481d86a54d75b6b9f0d10d1ac089b5e5a896211c6f49531b9496600100000000fdffffff03220200000000000016001436775d21d459d18cbf3d28b4eaaab0280cbcae190000000000000000096a5d061486f533144dd721000000000000225120d02a0b84b27bcfb53986905e948b1ee7ecc14cfd7740834838088869fc29b8f901409fbf530c09ae37186996f2929b80a7028d3cc3176598e032af890eb2d053a518cefbe041fa7864c751b68a5f87f9b8f78ee3fd0aae353799d306078ec59301fe00000000","rbf":true}`,
+	}, 200)
+	defer close()
+	asset := &xc.ChainConfig{
+		Chain:   xc.BTC,
+		Network: "mainnet",
+		Client: &xc.ClientConfig{
+			URL:      server.URL,
+			Provider: string(client.Blockbook),
+		},
+	}
+	cli, err := client.NewClient(asset)
+	require.NoError(err)
+	unconfirmed, err := cli.(btc.UnconfirmedTxClient).FetchUnconfirmedTx(s.Ctx, xc.TxHash("999be3740a25dc6def2e62df25be1387011c22bbf3a4b1b448ff1180e86e64f2"))
+	require.NoError(err)
+	require.EqualValues("999be3740a25dc6def2e62df25be1387011c22bbf3a4b1b448ff1180e86e64f2", unconfirmed.Hash)
+	require.True(unconfirmed.SignalsReplaceability())
+	require.EqualValues(160, unconfirmed.Vsize)
+	require.EqualValues(3442, unconfirmed.Fee())
+
+	require.Len(unconfirmed.Inputs, 1)
+	require.EqualValues(12651, unconfirmed.Inputs[0].Value.Uint64())
+	require.EqualValues("5120d02a0b84b27bcfb53986905e948b1ee7ecc14cfd7740834838088869fc29b8f9", hex.EncodeToString(unconfirmed.Inputs[0].PubKeyScript))
+	require.Len(unconfirmed.Outputs, 3)
+	require.EqualValues(8663, unconfirmed.Outputs[2].Value.Uint64())
+	require.EqualValues(2, unconfirmed.Outputs[2].Index)
+}
+
+func (s *ClientTestSuite) TestFetchTransactionsByAddress() {
+	require := s.Require()
+	server, close := testtypes.MockHTTP(s.T(), []string{
+		// address
+		`{"page":1,"totalPages":2,"itemsOnPage":1,"address":"bc1p6q4qhp9j008m2wvxjp0ffzc7ulkvzn8awaqgxjpcpzyxnlpfhrusst6t8h","txs":2,"transactions":[{"txid":"999be3740a25dc6def2e62df25be1387011c22bbf3a4b1b448ff1180e86e64f2","version":2,"vin":[{"txid":"6096941b53496f1c2196a8e5b589c01a0dd1f0b9b6754da5861d485b339b9436","vout":1,"sequence":4294967293,"n":0,"addresses":["bc1p6q4qhp9j008m2wvxjp0ffzc7ulkvzn8awaqgxjpcpzyxnlpfhrusst6t8h"],"isAddress":true,"value":"12651"}],"vout":[{"value":"546","n":0,"hex":"001436775d21d459d18cbf3d28b4eaaab0280cbcae19","addresses":["bc1qxem46gw5t8gce0ea9z6w424s9qxtetse5d69uu"],"isAddress":true},{"value":"8663","n":1,"hex":"5120d02a0b84b27bcfb53986905e948b1ee7ecc14cfd7740834838088869fc29b8f9","addresses":["bc1p6q4qhp9j008m2wvxjp0ffzc7ulkvzn8awaqgxjpcpzyxnlpfhrusst6t8h"],"isAddress":true}],"blockHash":"00000000000000000002a8e0d3b16d5ec6e3aebd2e1a1a0b4f3a9e8a3c4b5d6e","blockHeight":850509,"confirmations":70,"blockTime":1720038342,"value":"9209","valueIn":"12651","fees":"3442"}]}`,
+		// stats
+		`{"blockbook":{"coin":"Bitcoin","bestHeight":850578},"backend":{"chain":"main","blocks":850578,"headers":850578}}`,
+	}, 200)
+	defer close()
+	asset := &xc.ChainConfig{
+		Chain:   xc.BTC,
+		Network: "testnet",
+		Client: &xc.ClientConfig{
+			URL:      server.URL,
+			Provider: string(client.Blockbook),
+		},
+	}
+	cli, err := client.NewClient(asset)
+	require.NoError(err)
+	historyClient, ok := cli.(xclient.HistoryClient)
+	require.True(ok)
+
+	args, err := xclient.NewTransactionHistoryArgs("bc1p6q4qhp9j008m2wvxjp0ffzc7ulkvzn8awaqgxjpcpzyxnlpfhrusst6t8h", xclient.TransactionHistoryOptionLimit(1))
+	require.NoError(err)
+	page, err := historyClient.FetchTransactionsByAddress(s.Ctx, args)
+	require.NoError(err)
+	require.Len(page.Transactions, 1)
+	require.EqualValues("999be3740a25dc6def2e62df25be1387011c22bbf3a4b1b448ff1180e86e64f2", page.Transactions[0].Hash)
+	require.EqualValues(850509, page.Transactions[0].Block.Height)
+	require.EqualValues(70, page.Transactions[0].Confirmations)
+	require.EqualValues(3442, page.Transactions[0].Fees[0].Balance.Uint64())
+	require.Equal("2", page.NextCursor)
+
+	// filtering by contract is not supported on UTXO chains
+	args, err = xclient.NewTransactionHistoryArgs("bc1p6q4qhp9j008m2wvxjp0ffzc7ulkvzn8awaqgxjpcpzyxnlpfhrusst6t8h", xclient.TransactionHistoryOptionContract("abc"))
+	require.NoError(err)
+	_, err = historyClient.FetchTransactionsByAddress(s.Ctx, args)
+	require.Error(err)
+}
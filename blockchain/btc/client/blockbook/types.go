@@ -111,3 +111,25 @@ type EstimateFeeResponse struct {
 	// This is a decimal string.  It is BTC/kilobyte.
 	Result string `json:"result"`
 }
+
+type AddressResponse struct {
+	Page         int                   `json:"page"`
+	TotalPages   int                   `json:"totalPages"`
+	ItemsOnPage  int                   `json:"itemsOnPage"`
+	Address      string                `json:"address"`
+	Txs          int                   `json:"txs"`
+	Transactions []TransactionResponse `json:"transactions"`
+}
+
+type BlockResponse struct {
+	Page              int                   `json:"page"`
+	TotalPages        int                   `json:"totalPages"`
+	ItemsOnPage       int                   `json:"itemsOnPage"`
+	Hash              string                `json:"hash"`
+	PreviousBlockHash string                `json:"previousBlockHash"`
+	Height            int                   `json:"height"`
+	Confirmations     int                   `json:"confirmations"`
+	Time              int64                 `json:"time"`
+	TxCount           int                   `json:"txCount"`
+	Txs               []TransactionResponse `json:"txs"`
+}
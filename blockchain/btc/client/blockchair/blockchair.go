@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
@@ -36,6 +37,8 @@ type BlockchairClient struct {
 }
 
 var _ xclient.IClient = &BlockchairClient{}
+var _ xclient.HistoryClient = &BlockchairClient{}
+var _ xclient.BatchTransferClient = &BlockchairClient{}
 var _ address.WithAddressDecoder = &BlockchairClient{}
 
 // NewClient returns a new Bitcoin Client
@@ -176,6 +179,11 @@ func (client *BlockchairClient) FetchTransferInput(ctx context.Context, args *xc
 	return input, nil
 }
 
+// The input only depends on the sender, so is the same as for a single transfer
+func (client *BlockchairClient) FetchBatchTransferInput(ctx context.Context, args *xcbuilder.BatchTransferArgs) (xc.TxInput, error) {
+	return client.FetchTransferInput(ctx, args.FirstTransferArgs())
+}
+
 func (client *BlockchairClient) FetchLegacyTxInput(ctx context.Context, from xc.Address, to xc.Address, asset xc.IAsset) (xc.TxInput, error) {
 	// No way to pass the amount in the input using legacy interface, so we estimate using min amount.
 	args, _ := xcbuilder.NewTransferArgs(from, to, xc.NewBigIntFromUint64(1), xcbuilder.WithAsset(asset))
@@ -183,11 +191,18 @@ func (client *BlockchairClient) FetchLegacyTxInput(ctx context.Context, from xc.
 }
 
 func (client *BlockchairClient) send(ctx context.Context, resp interface{}, method string, params ...string) (*BlockchairContext, error) {
+	return client.sendWithQuery(ctx, resp, method, "", params...)
+}
+
+func (client *BlockchairClient) sendWithQuery(ctx context.Context, resp interface{}, method string, query string, params ...string) (*BlockchairContext, error) {
 	url := fmt.Sprintf("%s%s?key=%s", client.Url, method, client.ApiKey)
 	if len(params) > 0 {
 		value := params[0]
 		url = fmt.Sprintf("%s%s/%s?key=%s", client.Url, method, value, client.ApiKey)
 	}
+	if query != "" {
+		url = url + "&" + query
+	}
 
 	res, err := client.httpClient.Get(url)
 	if err != nil {
@@ -340,21 +355,83 @@ func (client *BlockchairClient) FetchLegacyTxInfo(ctx context.Context, txHash xc
 }
 
 func (client *BlockchairClient) FetchTxInfo(ctx context.Context, txHashStr xc.TxHash) (*xclient.TxInfo, error) {
-	legacyTx, err := client.FetchLegacyTxInfo(ctx, txHashStr)
+	var data blockchairTransactionData
+	blockchairContext, err := client.send(ctx, &data, "/dashboards/transaction", string(txHashStr))
 	if err != nil {
 		return nil, err
 	}
 	chain := client.Chain.Chain
 
-	// delete the fee to avoid double counting.
-	// the new model will calculate fees from the difference of inflows/outflows
-	legacyTx.Fee = xc.NewBigIntFromUint64(0)
+	// blockchair does not report the block hash
+	block := xclient.NewBlock(0, "", time.Unix(0, 0))
+	confirmations := uint64(0)
+	if data.Transaction.BlockId > 0 {
+		timestamp, _ := time.Parse(time.DateTime, data.Transaction.Time)
+		block = xclient.NewBlock(uint64(data.Transaction.BlockId), "", timestamp)
+		if blockchairContext.State >= data.Transaction.BlockId {
+			confirmations = uint64(blockchairContext.State - data.Transaction.BlockId + 1)
+		}
+	}
+	txHash := data.Transaction.Hash
+	if txHash == "" {
+		txHash = string(txHashStr)
+	}
+	txInfo := xclient.NewTxInfo(block, chain, txHash, confirmations, nil)
 
-	// add back the change movements
-	legacyTx.Destinations = append(legacyTx.Destinations, legacyTx.GetDroppedBtcDestinations()...)
+	// utxo movements are mapped as one large multitransfer, including change.
+	// the fee is then the difference of inflows/outflows.
+	tf := xclient.NewTransfer(chain)
+	for _, in := range data.Inputs {
+		tf.AddSource(xc.Address(in.Recipient), "", xc.NewBigIntFromUint64(in.Value), nil)
+	}
+	for _, out := range data.Outputs {
+		// skip outputs without an address (e.g. OP_RETURN)
+		if out.Recipient == "" {
+			continue
+		}
+		tf.AddDestination(xc.Address(out.Recipient), "", xc.NewBigIntFromUint64(out.Value), nil)
+	}
+	txInfo.AddTransfer(tf)
+	txInfo.Fees = txInfo.CalculateFees()
 
-	// remap to new tx
-	return xclient.TxInfoFromLegacy(chain, legacyTx, xclient.Utxo), nil
+	return txInfo, nil
+}
+
+func (client *BlockchairClient) FetchTransactionsByAddress(ctx context.Context, args xclient.TransactionHistoryArgs) (*xclient.TransactionHistoryPage, error) {
+	if _, ok := args.GetContract(); ok {
+		return nil, fmt.Errorf("%s does not support filtering by contract", client.Chain.Chain)
+	}
+	offset := 0
+	if cursor, ok := args.GetCursor(); ok {
+		var err error
+		offset, err = strconv.Atoi(cursor)
+		if err != nil || offset < 0 {
+			return nil, fmt.Errorf("invalid cursor: %s", cursor)
+		}
+	}
+	limit := args.GetLimit()
+
+	var data blockchairAddressData
+	// the limit/offset apply to the transactions and utxo lists respectively
+	query := fmt.Sprintf("limit=%d,0&offset=%d,0", limit, offset)
+	_, err := client.sendWithQuery(ctx, &data, "/dashboards/address", query, string(args.GetAddress()))
+	if err != nil {
+		return nil, err
+	}
+
+	txs := []*xclient.TxInfo{}
+	for _, txHash := range data.Transactions {
+		txInfo, err := client.FetchTxInfo(ctx, xc.TxHash(txHash))
+		if err != nil {
+			return nil, fmt.Errorf("could not fetch transaction %s: %v", txHash, err)
+		}
+		txs = append(txs, txInfo)
+	}
+	nextCursor := ""
+	if offset+len(data.Transactions) < data.Address.TransactionCount {
+		nextCursor = strconv.Itoa(offset + len(data.Transactions))
+	}
+	return xclient.NewTransactionHistoryPage(txs, nextCursor), nil
 }
 
 func (client *BlockchairClient) EstimateGasFee(ctx context.Context, tx xc.Tx) (*xc.BigInt, error) {
@@ -17,8 +17,9 @@ type blockchairStats struct {
 }
 
 type blockchairAddressFull struct {
-	ScriptHex string `json:"script_hex"`
-	Balance   uint64 `json:"balance"`
+	ScriptHex        string `json:"script_hex"`
+	Balance          uint64 `json:"balance"`
+	TransactionCount int    `json:"transaction_count"`
 }
 
 type blockchairTransactionFull struct {
@@ -79,9 +80,10 @@ type blockchairTransactionData struct {
 }
 
 type blockchairAddressData struct {
-	// Transactions []blockchairTransaction `json:"transactions"`
-	Address blockchairAddressFull `json:"address"`
-	Utxo    []blockchairUTXO      `json:"utxo"`
+	// Transaction hashes, most recent first
+	Transactions []string              `json:"transactions"`
+	Address      blockchairAddressFull `json:"address"`
+	Utxo         []blockchairUTXO      `json:"utxo"`
 }
 
 type blockchairData struct {
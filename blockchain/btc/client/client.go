@@ -3,6 +3,7 @@ package client
 import (
 	"strings"
 
+	"github.com/openweb3-io/crosschain/blockchain/btc"
 	"github.com/openweb3-io/crosschain/blockchain/btc/address"
 	"github.com/openweb3-io/crosschain/blockchain/btc/client/blockbook"
 	"github.com/openweb3-io/crosschain/blockchain/btc/client/blockchair"
@@ -22,6 +23,10 @@ type BtcClient interface {
 	address.WithAddressDecoder
 }
 
+// Clients that can look up unconfirmed transactions for btc.FeeBumper
+var _ btc.UnconfirmedTxClient = &blockbook.BlockbookClient{}
+var _ btc.UnconfirmedTxClient = &native.NativeClient{}
+
 func NewClient(cfg *xc.ChainConfig) (BtcClient, error) {
 	cli, err := NewBitcoinClient(cfg)
 	if err != nil {
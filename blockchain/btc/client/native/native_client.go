@@ -76,6 +76,8 @@ type NativeClient struct {
 }
 
 var _ xclient.IClient = &NativeClient{}
+var _ xclient.BlockClient = &NativeClient{}
+var _ xclient.BatchTransferClient = &NativeClient{}
 var _ address.WithAddressDecoder = &NativeClient{}
 
 // NewClient returns a new Bitcoin Client
@@ -131,6 +133,12 @@ func (client *NativeClient) BroadcastTx(ctx context.Context, txInput xc.Tx) erro
 	}
 	return nil
 }
+
+// The input only depends on the sender, so is the same as for a single transfer
+func (client *NativeClient) FetchBatchTransferInput(ctx context.Context, args *xcbuilder.BatchTransferArgs) (xc.TxInput, error) {
+	return client.FetchTransferInput(ctx, args.FirstTransferArgs())
+}
+
 func (client *NativeClient) FetchLegacyTxInput(ctx context.Context, from xc.Address, to xc.Address, asset xc.IAsset) (xc.TxInput, error) {
 	// No way to pass the amount in the input using legacy interface, so we estimate using min amount.
 	args, _ := xcbuilder.NewTransferArgs(from, to, xc.NewBigIntFromUint64(1), xcbuilder.WithAsset(asset))
@@ -258,17 +266,191 @@ func (client *NativeClient) FetchLegacyTxInfo(ctx context.Context, txHash xc.TxH
 }
 
 func (client *NativeClient) FetchTxInfo(ctx context.Context, txHashStr xc.TxHash) (*xclient.TxInfo, error) {
-	legacyTx, err := client.FetchLegacyTxInfo(ctx, txHashStr)
-	if err != nil {
-		return nil, err
+	resp := btcjson.TxRawResult{}
+	if err := client.send(ctx, &resp, "getrawtransaction", string(txHashStr), 1); err != nil {
+		return nil, fmt.Errorf("bad \"getrawtransaction\": %v", err)
 	}
 	chain := client.Chain.Chain
-	// delete the fee to avoid double counting.
-	// the new model will calculate fees from the difference of inflows/outflows
-	legacyTx.Fee = xc.NewBigIntFromUint64(0)
 
-	// remap to new tx
-	return xclient.TxInfoFromLegacy(chain, legacyTx, xclient.Utxo), nil
+	block := xclient.NewBlock(0, "", time.Unix(0, 0))
+	if resp.Confirmations > 0 {
+		latestBlock, err := client.LatestBlock(ctx)
+		if err != nil {
+			return nil, err
+		}
+		height := uint64(0)
+		if latestBlock+1 >= resp.Confirmations {
+			height = latestBlock + 1 - resp.Confirmations
+		}
+		block = xclient.NewBlock(height, resp.BlockHash, time.Unix(resp.Blocktime, 0))
+	}
+	txInfo := xclient.NewTxInfo(block, chain, resp.Txid, resp.Confirmations, nil)
+
+	// utxo movements are mapped as one large multitransfer, including change.
+	// the fee is then the difference of inflows/outflows.
+	tf := xclient.NewTransfer(chain)
+	for _, in := range resp.Vin {
+		if in.IsCoinBase() {
+			continue
+		}
+		prevHash, err := chainhash.NewHashFromStr(in.Txid)
+		if err != nil {
+			return nil, fmt.Errorf("bad input txid: %v", err)
+		}
+		outpoint := tx_input.Outpoint{
+			Hash:  prevHash[:],
+			Index: in.Vout,
+		}
+		output, _, err := client.Output(ctx, outpoint)
+		if err != nil {
+			return nil, fmt.Errorf("error retrieving input details: %v", err)
+		}
+		var from xc.Address
+		_, addresses, _, err := txscript.ExtractPkScriptAddrs(output.PubKeyScript, client.opts.Chaincfg)
+		if err == nil && len(addresses) == 1 {
+			from = xc.Address(addresses[0].String())
+		}
+		tf.AddSource(from, "", output.Value, nil)
+	}
+	if err := client.addDestinations(tf, resp.Vout); err != nil {
+		return nil, err
+	}
+	txInfo.AddTransfer(tf)
+	txInfo.Fees = txInfo.CalculateFees()
+
+	return txInfo, nil
+}
+
+// Fetch a transaction that is still in the mempool along with the outputs it spends, to bump its fee.
+func (client *NativeClient) FetchUnconfirmedTx(ctx context.Context, txHash xc.TxHash) (*tx_input.UnconfirmedTx, error) {
+	resp := btcjson.TxRawResult{}
+	if err := client.send(ctx, &resp, "getrawtransaction", string(txHash), 1); err != nil {
+		return nil, fmt.Errorf("bad \"getrawtransaction\": %v", err)
+	}
+	if resp.Confirmations > 0 {
+		return nil, fmt.Errorf("transaction %s is already confirmed", txHash)
+	}
+	serial, err := hex.DecodeString(resp.Hex)
+	if err != nil {
+		return nil, fmt.Errorf("bad transaction hex: %v", err)
+	}
+	msgTx := wire.NewMsgTx(0)
+	if err := msgTx.Deserialize(bytes.NewReader(serial)); err != nil {
+		return nil, fmt.Errorf("bad transaction hex: %v", err)
+	}
+	spent := []tx_input.Output{}
+	for _, txIn := range msgTx.TxIn {
+		output, _, err := client.Output(ctx, tx_input.Outpoint{
+			Hash:  txIn.PreviousOutPoint.Hash[:],
+			Index: txIn.PreviousOutPoint.Index,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("error retrieving input details: %v", err)
+		}
+		spent = append(spent, output)
+	}
+	return tx_input.NewUnconfirmedTx(msgTx, spent, uint64(resp.Vsize))
+}
+
+func (client *NativeClient) addDestinations(tf *xclient.Transfer, vout []btcjson.Vout) error {
+	for _, out := range vout {
+		pubKeyScript, err := hex.DecodeString(out.ScriptPubKey.Hex)
+		if err != nil {
+			return fmt.Errorf("bad pubkey script: %v", err)
+		}
+		_, addresses, _, err := txscript.ExtractPkScriptAddrs(pubKeyScript, client.opts.Chaincfg)
+		// skip outputs without an address (e.g. OP_RETURN)
+		if err != nil || len(addresses) != 1 {
+			continue
+		}
+		amount, err := btcutil.NewAmount(out.Value)
+		if err != nil {
+			return fmt.Errorf("bad amount: %v", err)
+		}
+		tf.AddDestination(xc.Address(addresses[0].String()), "", xc.NewBigIntFromUint64(uint64(amount)), nil)
+	}
+	return nil
+}
+
+// Input of a transaction, as returned by getblock with verbosity 3, which includes the spent output.
+type vinWithPrevout struct {
+	btcjson.Vin
+	Prevout *struct {
+		Value        float64                    `json:"value"`
+		ScriptPubKey btcjson.ScriptPubKeyResult `json:"scriptPubKey"`
+	} `json:"prevout"`
+}
+
+type txWithPrevouts struct {
+	btcjson.TxRawResult
+	Vin []vinWithPrevout `json:"vin"`
+}
+
+type blockWithPrevouts struct {
+	Hash              string           `json:"hash"`
+	PreviousBlockHash string           `json:"previousblockhash"`
+	Height            int64            `json:"height"`
+	Time              int64            `json:"time"`
+	Confirmations     int64            `json:"confirmations"`
+	Tx                []txWithPrevouts `json:"tx"`
+}
+
+func (client *NativeClient) FetchLatestHeight(ctx context.Context) (uint64, error) {
+	return client.LatestBlock(ctx)
+}
+
+// Fetch a block using getblock with verbosity 3, which requires bitcoind v23 or later.
+// This avoids having to look up the spent output of every input.
+func (client *NativeClient) FetchBlock(ctx context.Context, height uint64) (*xclient.BlockWithTransactions, error) {
+	var blockHash string
+	if err := client.send(ctx, &blockHash, "getblockhash", height); err != nil {
+		return nil, fmt.Errorf("bad \"getblockhash\": %v", err)
+	}
+	resp := blockWithPrevouts{}
+	if err := client.send(ctx, &resp, "getblock", blockHash, 3); err != nil {
+		return nil, fmt.Errorf("bad \"getblock\": %v", err)
+	}
+	chain := client.Chain.Chain
+	confirmations := uint64(0)
+	if resp.Confirmations > 0 {
+		confirmations = uint64(resp.Confirmations)
+	}
+
+	block := xclient.NewBlock(uint64(resp.Height), resp.Hash, time.Unix(resp.Time, 0))
+	txs := []*xclient.TxInfo{}
+	for _, tx := range resp.Tx {
+		txInfo := xclient.NewTxInfo(block, chain, tx.Txid, confirmations, nil)
+		tf := xclient.NewTransfer(chain)
+		for _, in := range tx.Vin {
+			if in.IsCoinBase() {
+				continue
+			}
+			if in.Prevout == nil {
+				return nil, fmt.Errorf("node did not return the spent output of %s:%d", in.Txid, in.Vout)
+			}
+			pubKeyScript, err := hex.DecodeString(in.Prevout.ScriptPubKey.Hex)
+			if err != nil {
+				return nil, fmt.Errorf("bad pubkey script: %v", err)
+			}
+			var from xc.Address
+			_, addresses, _, err := txscript.ExtractPkScriptAddrs(pubKeyScript, client.opts.Chaincfg)
+			if err == nil && len(addresses) == 1 {
+				from = xc.Address(addresses[0].String())
+			}
+			amount, err := btcutil.NewAmount(in.Prevout.Value)
+			if err != nil {
+				return nil, fmt.Errorf("bad amount: %v", err)
+			}
+			tf.AddSource(from, "", xc.NewBigIntFromUint64(uint64(amount)), nil)
+		}
+		if err := client.addDestinations(tf, tx.Vout); err != nil {
+			return nil, err
+		}
+		txInfo.AddTransfer(tf)
+		txInfo.Fees = txInfo.CalculateFees()
+		txs = append(txs, txInfo)
+	}
+	return xclient.NewBlockWithTransactions(block, resp.PreviousBlockHash, txs), nil
 }
 
 func (client *NativeClient) send(ctx context.Context, resp interface{}, method string, params ...interface{}) error {
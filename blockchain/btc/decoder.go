@@ -0,0 +1,110 @@
+package btc
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/openweb3-io/crosschain/blockchain/btc/params"
+	"github.com/openweb3-io/crosschain/blockchain/btc/tx"
+	xclient "github.com/openweb3-io/crosschain/client"
+	xc "github.com/openweb3-io/crosschain/types"
+)
+
+// AddressEncoder formats the address paid by an output script
+type AddressEncoder func(address btcutil.Address, params *chaincfg.Params) (xc.Address, error)
+
+func EncodeBtcAddress(address btcutil.Address, params *chaincfg.Params) (xc.Address, error) {
+	return xc.Address(address.EncodeAddress()), nil
+}
+
+// TxDecoder for Bitcoin
+type TxDecoder struct {
+	Chain          *xc.ChainConfig
+	Params         *chaincfg.Params
+	AddressEncoder AddressEncoder
+}
+
+var _ xclient.TxDecoder = &TxDecoder{}
+
+func NewTxDecoder(cfg *xc.ChainConfig) (TxDecoder, error) {
+	params, err := params.GetParams(cfg)
+	if err != nil {
+		return TxDecoder{}, err
+	}
+	return TxDecoder{
+		Chain:          cfg,
+		Params:         params,
+		AddressEncoder: EncodeBtcAddress,
+	}, nil
+}
+
+func (decoder TxDecoder) WithAddressEncoder(encoder AddressEncoder) TxDecoder {
+	decoder.AddressEncoder = encoder
+	return decoder
+}
+
+// DecodeTx decodes the outputs spent and created by the transaction, with any OP_RETURN data as the memo.
+// The values of the spent outputs come from the transaction input; only segwit and taproot signatures
+// commit to them, so for legacy outputs they must be trusted.
+func (decoder TxDecoder) DecodeTx(xcTx xc.Tx) (*xclient.TxInfo, error) {
+	btcTx, ok := xcTx.(*tx.Tx)
+	if !ok {
+		return nil, fmt.Errorf("expected bitcoin transaction, got %T", xcTx)
+	}
+	if btcTx.MsgTx == nil || btcTx.Input == nil {
+		return nil, errors.New("transaction not initialized")
+	}
+	if len(btcTx.Input.UnspentOutputs) != len(btcTx.MsgTx.TxIn) {
+		return nil, fmt.Errorf("expected %d unspent outputs, got %d", len(btcTx.MsgTx.TxIn), len(btcTx.Input.UnspentOutputs))
+	}
+	info := xclient.NewTxInfo(nil, decoder.Chain.Chain, string(btcTx.Hash()), 0, nil)
+	transfer := xclient.NewTransfer(decoder.Chain.Chain)
+
+	for i, utxo := range btcTx.Input.UnspentOutputs {
+		outpoint := btcTx.MsgTx.TxIn[i].PreviousOutPoint
+		if !bytes.Equal(outpoint.Hash[:], utxo.Hash) || outpoint.Index != utxo.Index {
+			return nil, fmt.Errorf("unspent output %d does not match input %s", i, outpoint.String())
+		}
+		from, err := decoder.scriptAddress(utxo.PubKeyScript)
+		if err != nil {
+			return nil, err
+		}
+		if from == "" {
+			from = btcTx.From
+		}
+		transfer.AddSource(from, "", utxo.Value, nil)
+	}
+	for _, txOut := range btcTx.MsgTx.TxOut {
+		if txscript.GetScriptClass(txOut.PkScript) == txscript.NullDataTy {
+			data, err := txscript.PushedData(txOut.PkScript)
+			if err == nil && len(data) > 0 {
+				transfer.SetMemo(string(data[0]))
+			}
+			continue
+		}
+		to, err := decoder.scriptAddress(txOut.PkScript)
+		if err != nil {
+			return nil, err
+		}
+		if to == "" {
+			return nil, fmt.Errorf("cannot decode the recipient of output script %x", txOut.PkScript)
+		}
+		transfer.AddDestination(to, "", xc.NewBigIntFromUint64(uint64(txOut.Value)), nil)
+	}
+	info.AddTransfer(transfer)
+	info.Fees = info.CalculateFees()
+	return info, nil
+}
+
+// The address paid by a script, or empty if it is not a standard script
+func (decoder TxDecoder) scriptAddress(script []byte) (xc.Address, error) {
+	_, addresses, _, err := txscript.ExtractPkScriptAddrs(script, decoder.Params)
+	if err != nil || len(addresses) != 1 {
+		return "", nil
+	}
+	return decoder.AddressEncoder(addresses[0], decoder.Params)
+}
@@ -0,0 +1,235 @@
+package btc
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/openweb3-io/crosschain/blockchain/btc/tx"
+	"github.com/openweb3-io/crosschain/blockchain/btc/tx_input"
+	xc "github.com/openweb3-io/crosschain/types"
+)
+
+// Minimum fee rate that a replacement must pay on top of the fee of the original transaction,
+// in sats per vbyte (BIP-125 rule 4)
+const IncrementalRelayFeeRate = 1
+
+var ErrNotReplaceable = errors.New("transaction does not signal replaceability")
+
+// Implemented by bitcoin clients that can look up the outputs spent by an unconfirmed transaction
+type UnconfirmedTxClient interface {
+	FetchUnconfirmedTx(ctx context.Context, txHash xc.TxHash) (*tx_input.UnconfirmedTx, error)
+}
+
+type FeeBumpArgs struct {
+	TxHash xc.TxHash
+	// the address of the sender, which any change is paid to
+	From          xc.Address
+	FromPublicKey []byte
+	// the new fee rate in sats per vbyte
+	FeeRate uint64
+}
+
+// Bumps the fee of a stuck transaction, either by replacing it (RBF) or by spending its change
+// in a child transaction that pays for both (CPFP).
+type FeeBumper struct {
+	Client  UnconfirmedTxClient
+	Builder TxBuilder
+}
+
+func NewFeeBumper(client UnconfirmedTxClient, builder TxBuilder) *FeeBumper {
+	return &FeeBumper{
+		Client:  client,
+		Builder: builder,
+	}
+}
+
+// Build a replacement of the transaction that spends the same inputs at a higher fee rate.  The
+// replacement always conflicts with the original, so `SafeFromDoubleSend` holds against the input
+// of the original, and only one of them can be confirmed.
+func (bumper *FeeBumper) NewReplacement(ctx context.Context, args *FeeBumpArgs) (*tx.Tx, error) {
+	original, err := bumper.Client.FetchUnconfirmedTx(ctx, args.TxHash)
+	if err != nil {
+		return nil, err
+	}
+	return bumper.Builder.NewReplacementTransfer(original, args)
+}
+
+// Build a child transaction that spends the change of the transaction, paying a fee high enough
+// for the package of both to reach the fee rate.
+func (bumper *FeeBumper) NewChild(ctx context.Context, args *FeeBumpArgs) (*tx.Tx, error) {
+	parent, err := bumper.Client.FetchUnconfirmedTx(ctx, args.TxHash)
+	if err != nil {
+		return nil, err
+	}
+	return bumper.Builder.NewChildTransfer(parent, args)
+}
+
+// Build a replacement (BIP-125) of the original transaction.  Outputs to other addresses are kept
+// as they are, and the higher fee is taken from the change, which is dropped if it becomes dust.
+func (txBuilder TxBuilder) NewReplacementTransfer(original *tx_input.UnconfirmedTx, args *FeeBumpArgs) (*tx.Tx, error) {
+	if !original.SignalsReplaceability() {
+		return nil, fmt.Errorf("%w: %s", ErrNotReplaceable, original.Hash)
+	}
+	changeScript, err := txBuilder.payToAddrScript(args.From)
+	if err != nil {
+		return nil, err
+	}
+
+	totalIn := uint64(0)
+	for _, input := range original.Inputs {
+		totalIn += input.Value.Uint64()
+	}
+	payments := []tx_input.Output{}
+	paid := uint64(0)
+	for _, output := range original.Outputs {
+		if !bytes.Equal(output.PubKeyScript, changeScript) {
+			payments = append(payments, output)
+			paid += output.Value.Uint64()
+		}
+	}
+	scripts := [][]byte{}
+	for _, output := range payments {
+		scripts = append(scripts, output.PubKeyScript)
+	}
+
+	originalFee := original.Fee()
+	requiredFee := func(vsize uint64) uint64 {
+		return max(args.FeeRate*vsize, originalFee+IncrementalRelayFeeRate*vsize)
+	}
+	withChange := append(append([][]byte{}, scripts...), changeScript)
+	fee := requiredFee(tx_input.EstimateVsize(original.Inputs, withChange))
+	change := uint64(0)
+	if totalIn >= paid+fee && totalIn-paid-fee >= txBuilder.dustThreshold(changeScript) {
+		change = totalIn - paid - fee
+	} else {
+		fee = totalIn - paid
+		if totalIn < paid || fee < requiredFee(tx_input.EstimateVsize(original.Inputs, scripts)) {
+			return nil, fmt.Errorf("the change of %s is not enough to pay a fee rate of %d sats/vbyte", original.Hash, args.FeeRate)
+		}
+	}
+
+	msgTx := wire.NewMsgTx(TxVersion)
+	for _, input := range original.Inputs {
+		txIn := wire.NewTxIn(outPoint(&input.Outpoint), nil, nil)
+		txIn.Sequence = tx_input.MaxRbfSequence
+		msgTx.AddTxIn(txIn)
+	}
+	recipients := []tx.Recipient{}
+	for _, output := range payments {
+		msgTx.AddTxOut(wire.NewTxOut(int64(output.Value.Uint64()), output.PubKeyScript))
+		recipients = append(recipients, tx.Recipient{
+			To:    txBuilder.scriptAddress(output.PubKeyScript),
+			Value: output.Value,
+		})
+	}
+	if change > 0 {
+		msgTx.AddTxOut(wire.NewTxOut(int64(change), changeScript))
+		recipients = append(recipients, tx.Recipient{
+			To:    args.From,
+			Value: xc.NewBigIntFromUint64(change),
+		})
+	}
+
+	replacement := &tx.Tx{
+		MsgTx:  msgTx,
+		From:   args.From,
+		Amount: xc.NewBigIntFromUint64(paid),
+		Input: &tx_input.TxInput{
+			UnspentOutputs:  original.Inputs,
+			FromPublicKey:   args.FromPublicKey,
+			GasPricePerByte: xc.NewBigIntFromUint64(args.FeeRate),
+		},
+		Recipients: recipients,
+	}
+	if len(recipients) > 0 {
+		replacement.To = recipients[0].To
+	}
+	return replacement, nil
+}
+
+// Build a transaction spending the change of the parent back to the sender (CPFP), with a fee
+// that brings the combined fee rate of the parent and child up to the requested rate.
+func (txBuilder TxBuilder) NewChildTransfer(parent *tx_input.UnconfirmedTx, args *FeeBumpArgs) (*tx.Tx, error) {
+	changeScript, err := txBuilder.payToAddrScript(args.From)
+	if err != nil {
+		return nil, err
+	}
+	change := []tx_input.Output{}
+	total := uint64(0)
+	for _, output := range parent.Outputs {
+		if bytes.Equal(output.PubKeyScript, changeScript) {
+			change = append(change, output)
+			total += output.Value.Uint64()
+		}
+	}
+	if len(change) == 0 {
+		return nil, fmt.Errorf("%s has no output to %s to spend", parent.Hash, args.From)
+	}
+
+	childVsize := tx_input.EstimateVsize(change, [][]byte{changeScript})
+	fee := args.FeeRate * childVsize
+	// the child also pays for what the parent is short of the fee rate
+	if packageFee := args.FeeRate * (parent.Vsize + childVsize); packageFee > parent.Fee()+fee {
+		fee = packageFee - parent.Fee()
+	}
+	if total < fee || total-fee < txBuilder.dustThreshold(changeScript) {
+		return nil, fmt.Errorf("the change of %s is not enough to pay a fee rate of %d sats/vbyte", parent.Hash, args.FeeRate)
+	}
+	value := total - fee
+
+	msgTx := wire.NewMsgTx(TxVersion)
+	for _, output := range change {
+		txIn := wire.NewTxIn(outPoint(&output.Outpoint), nil, nil)
+		txIn.Sequence = txBuilder.sequence()
+		msgTx.AddTxIn(txIn)
+	}
+	msgTx.AddTxOut(wire.NewTxOut(int64(value), changeScript))
+
+	return &tx.Tx{
+		MsgTx:  msgTx,
+		From:   args.From,
+		To:     args.From,
+		Amount: xc.NewBigIntFromUint64(value),
+		Input: &tx_input.TxInput{
+			UnspentOutputs:  change,
+			FromPublicKey:   args.FromPublicKey,
+			GasPricePerByte: xc.NewBigIntFromUint64(args.FeeRate),
+		},
+		Recipients: []tx.Recipient{
+			{
+				To:    args.From,
+				Value: xc.NewBigIntFromUint64(value),
+			},
+		},
+	}, nil
+}
+
+func (txBuilder TxBuilder) dustThreshold(script []byte) uint64 {
+	if txBuilder.DustThreshold > 0 {
+		return txBuilder.DustThreshold
+	}
+	if txBuilder.Chain.Chain == xc.DOGE {
+		return DogeDustThreshold
+	}
+	return tx_input.DustThreshold(script)
+}
+
+// The address paid by a script, or empty if it is not a standard script
+func (txBuilder TxBuilder) scriptAddress(script []byte) xc.Address {
+	_, addresses, _, err := txscript.ExtractPkScriptAddrs(script, txBuilder.Params)
+	if err != nil || len(addresses) != 1 {
+		return ""
+	}
+	return xc.Address(addresses[0].EncodeAddress())
+}
+
+func outPoint(outpoint *tx_input.Outpoint) *wire.OutPoint {
+	hash := chainhash.Hash{}
+	copy(hash[:], outpoint.Hash)
+	return wire.NewOutPoint(&hash, outpoint.Index)
+}
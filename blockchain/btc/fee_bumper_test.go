@@ -0,0 +1,136 @@
+package btc_test
+
+import (
+	"context"
+
+	. "github.com/openweb3-io/crosschain/blockchain/btc"
+	"github.com/openweb3-io/crosschain/blockchain/btc/tx"
+	"github.com/openweb3-io/crosschain/blockchain/btc/tx_input"
+	xcbuilder "github.com/openweb3-io/crosschain/builder"
+	xc "github.com/openweb3-io/crosschain/types"
+)
+
+type unconfirmedTxClient struct {
+	txs map[xc.TxHash]*tx_input.UnconfirmedTx
+}
+
+var _ UnconfirmedTxClient = &unconfirmedTxClient{}
+
+func (c *unconfirmedTxClient) FetchUnconfirmedTx(ctx context.Context, txHash xc.TxHash) (*tx_input.UnconfirmedTx, error) {
+	return c.txs[txHash], nil
+}
+
+// Build a transfer at 2 sats/vbyte, as it would be seen in the mempool
+func (s *CrosschainTestSuite) newUnconfirmedTransfer(builder TxBuilder, from xc.Address, value uint64) *tx_input.UnconfirmedTx {
+	require := s.Require()
+	to := xc.Address("mpjwFvP88ZwAt3wEHY6irKkGhxcsv22BP6")
+	args, err := xcbuilder.NewTransferArgs(from, to, xc.NewBigIntFromUint64(10_000))
+	require.NoError(err)
+
+	addr, err := builder.AddressDecoder.Decode(from, builder.Params)
+	require.NoError(err)
+	script := addr.ScriptAddress()
+	script = append([]byte{0x00, byte(len(script))}, script...)
+	input := &tx_input.TxInput{
+		UnspentOutputs: []tx_input.Output{{
+			Outpoint:     tx_input.Outpoint{Hash: make([]byte, 32), Index: 1},
+			Value:        xc.NewBigIntFromUint64(value),
+			PubKeyScript: script,
+		}},
+		GasPricePerByte: xc.NewBigIntFromUint64(2),
+	}
+	tf, err := builder.NewNativeTransfer(args, input)
+	require.NoError(err)
+	msgTx := tf.(*tx.Tx).MsgTx
+	scripts := [][]byte{}
+	for _, txOut := range msgTx.TxOut {
+		scripts = append(scripts, txOut.PkScript)
+	}
+	vsize := tx_input.EstimateVsize(input.UnspentOutputs, scripts)
+	unconfirmed, err := tx_input.NewUnconfirmedTx(msgTx, input.UnspentOutputs, vsize)
+	require.NoError(err)
+	return unconfirmed
+}
+
+func (s *CrosschainTestSuite) TestFeeBumpReplacement() {
+	require := s.Require()
+	builder, err := NewTxBuilder(&xc.ChainConfig{Chain: xc.BTC, Network: "testnet"})
+	require.NoError(err)
+	from := xc.Address("tb1qtpqqpgadjr2q3f4wrgd6ndclqtfg7cz5evtvs0")
+	original := s.newUnconfirmedTransfer(builder, from, 100_000)
+	require.True(original.SignalsReplaceability())
+	require.Len(original.Outputs, 2)
+
+	client := &unconfirmedTxClient{txs: map[xc.TxHash]*tx_input.UnconfirmedTx{original.Hash: original}}
+	bumper := NewFeeBumper(client, builder)
+	replacement, err := bumper.NewReplacement(s.Ctx, &FeeBumpArgs{TxHash: original.Hash, From: from, FeeRate: 10})
+	require.NoError(err)
+
+	// the payment is unchanged and the fee is taken from the change
+	require.Len(replacement.MsgTx.TxOut, 2)
+	require.EqualValues(10_000, replacement.MsgTx.TxOut[0].Value)
+	require.Equal(original.Outputs[0].PubKeyScript, replacement.MsgTx.TxOut[0].PkScript)
+	fee := 100_000 - 10_000 - uint64(replacement.MsgTx.TxOut[1].Value)
+	require.EqualValues(10*original.Vsize, fee)
+	require.Greater(fee, original.Fee()+original.Vsize)
+	for _, txIn := range replacement.MsgTx.TxIn {
+		require.Equal(tx_input.MaxRbfSequence, txIn.Sequence)
+	}
+
+	// the replacement conflicts with the original, so only one can be confirmed
+	require.True(replacement.Input.SafeFromDoubleSend(original.TxInput()))
+
+	// a fee rate that is barely higher must still pay for the replacement's own relay
+	replacement, err = builder.NewReplacementTransfer(original, &FeeBumpArgs{From: from, FeeRate: 2})
+	require.NoError(err)
+	fee = 100_000 - 10_000 - uint64(replacement.MsgTx.TxOut[1].Value)
+	require.EqualValues(original.Fee()+original.Vsize, fee)
+
+	// not enough change left to pay the fee
+	_, err = builder.NewReplacementTransfer(original, &FeeBumpArgs{From: from, FeeRate: 1_000})
+	require.ErrorContains(err, "not enough")
+
+	original.Sequences[0] = tx_input.MaxRbfSequence + 1
+	_, err = builder.NewReplacementTransfer(original, &FeeBumpArgs{From: from, FeeRate: 10})
+	require.ErrorIs(err, ErrNotReplaceable)
+}
+
+func (s *CrosschainTestSuite) TestFeeBumpReplacementDropsDustChange() {
+	require := s.Require()
+	builder, err := NewTxBuilder(&xc.ChainConfig{Chain: xc.BTC, Network: "testnet"})
+	require.NoError(err)
+	from := xc.Address("tb1qtpqqpgadjr2q3f4wrgd6ndclqtfg7cz5evtvs0")
+	original := s.newUnconfirmedTransfer(builder, from, 11_000)
+	require.Len(original.Outputs, 2)
+
+	replacement, err := builder.NewReplacementTransfer(original, &FeeBumpArgs{From: from, FeeRate: 5})
+	require.NoError(err)
+	require.Len(replacement.MsgTx.TxOut, 1)
+	require.EqualValues(10_000, replacement.MsgTx.TxOut[0].Value)
+}
+
+func (s *CrosschainTestSuite) TestFeeBumpChild() {
+	require := s.Require()
+	builder, err := NewTxBuilder(&xc.ChainConfig{Chain: xc.BTC, Network: "testnet"})
+	require.NoError(err)
+	from := xc.Address("tb1qtpqqpgadjr2q3f4wrgd6ndclqtfg7cz5evtvs0")
+	parent := s.newUnconfirmedTransfer(builder, from, 100_000)
+
+	client := &unconfirmedTxClient{txs: map[xc.TxHash]*tx_input.UnconfirmedTx{parent.Hash: parent}}
+	child, err := NewFeeBumper(client, builder).NewChild(s.Ctx, &FeeBumpArgs{TxHash: parent.Hash, From: from, FeeRate: 10})
+	require.NoError(err)
+
+	// spends the change of the parent
+	require.Len(child.MsgTx.TxIn, 1)
+	require.Equal(parent.Outputs[1].Outpoint, child.Input.UnspentOutputs[0].Outpoint)
+	require.EqualValues(parent.Hash, child.MsgTx.TxIn[0].PreviousOutPoint.Hash.String())
+	require.Len(child.MsgTx.TxOut, 1)
+
+	// the package of both pays 10 sats/vbyte
+	childVsize := tx_input.EstimateVsize(child.Input.UnspentOutputs, [][]byte{parent.Outputs[1].PubKeyScript})
+	childFee := parent.Outputs[1].Value.Uint64() - uint64(child.MsgTx.TxOut[0].Value)
+	require.EqualValues(10*(parent.Vsize+childVsize), parent.Fee()+childFee)
+
+	// the child does not conflict with the parent
+	require.False(child.Input.SafeFromDoubleSend(parent.TxInput()))
+}
@@ -0,0 +1,68 @@
+package tx
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/btcsuite/btcd/btcutil/psbt"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/openweb3-io/crosschain/blockchain/btc/tx_input"
+	xc "github.com/openweb3-io/crosschain/types"
+)
+
+type txJSON struct {
+	MsgTx      []byte            `json:"msg_tx"`
+	Signed     bool              `json:"signed,omitempty"`
+	Recipients []Recipient       `json:"recipients"`
+	Amount     xc.BigInt         `json:"amount"`
+	Input      *tx_input.TxInput `json:"input"`
+	From       xc.Address        `json:"from"`
+	To         xc.Address        `json:"to"`
+	// Signatures of multisig members, indexed by input
+	MultisigSignatures [][]*psbt.PartialSig `json:"multisig_signatures,omitempty"`
+}
+
+// MarshalJSON encodes the transaction with the outputs it spends, which are needed for its sighashes
+func (tx Tx) MarshalJSON() ([]byte, error) {
+	buf := new(bytes.Buffer)
+	if tx.MsgTx != nil {
+		if err := tx.MsgTx.Serialize(buf); err != nil {
+			return nil, err
+		}
+	}
+	return json.Marshal(txJSON{
+		MsgTx:              buf.Bytes(),
+		Signed:             tx.Signed,
+		Recipients:         tx.Recipients,
+		Amount:             tx.Amount,
+		Input:              tx.Input,
+		From:               tx.From,
+		To:                 tx.To,
+		MultisigSignatures: tx.MultisigSignatures,
+	})
+}
+
+func (tx *Tx) UnmarshalJSON(data []byte) error {
+	var decoded txJSON
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	msgTx := wire.NewMsgTx(wire.TxVersion)
+	if err := msgTx.Deserialize(bytes.NewReader(decoded.MsgTx)); err != nil {
+		return err
+	}
+	*tx = Tx{
+		MsgTx:              msgTx,
+		Signed:             decoded.Signed,
+		Recipients:         decoded.Recipients,
+		Amount:             decoded.Amount,
+		Input:              decoded.Input,
+		From:               decoded.From,
+		To:                 decoded.To,
+		MultisigSignatures: decoded.MultisigSignatures,
+	}
+	if tx.Input == nil {
+		tx.Input = tx_input.NewTxInput()
+	}
+	return nil
+}
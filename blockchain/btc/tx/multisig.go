@@ -0,0 +1,187 @@
+package tx
+
+import (
+	"bytes"
+	"fmt"
+
+	btcec "github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/ecdsa"
+	"github.com/btcsuite/btcd/btcutil/psbt"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	xc "github.com/openweb3-io/crosschain/types"
+)
+
+// Returns true if any input spends a multisig output
+func (tx *Tx) isMultisig() bool {
+	if tx.Input == nil {
+		return false
+	}
+	for _, utxo := range tx.Input.UnspentOutputs {
+		if len(utxo.WitnessScript) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// The public keys of the multisig spent by the input, in the order of its script, and how many must sign
+func (tx *Tx) multisigKeys(i int) ([][]byte, int, error) {
+	witnessScript := tx.Input.UnspentOutputs[i].WitnessScript
+	if isMultisig, _ := txscript.IsMultisigScript(witnessScript); !isMultisig {
+		return nil, 0, fmt.Errorf("input %d does not spend a multisig script", i)
+	}
+	_, threshold, err := txscript.CalcMultiSigStats(witnessScript)
+	if err != nil {
+		return nil, 0, err
+	}
+	publicKeys, err := txscript.PushedData(witnessScript)
+	if err != nil {
+		return nil, 0, err
+	}
+	return publicKeys, threshold, nil
+}
+
+// Add the signature of whichever member of the multisig made it.  An empty signature is skipped, so a
+// member can sign only some of the inputs.
+func (tx *Tx) addMultisigSignature(i int, sighash []byte, signature xc.TxSignature) error {
+	if len(signature) == 0 {
+		return nil
+	}
+	r, s, err := DecodeEcdsaSignature(signature)
+	if err != nil {
+		return err
+	}
+	sig := ecdsa.NewSignature(&r, &s)
+	publicKeys, _, err := tx.multisigKeys(i)
+	if err != nil {
+		return err
+	}
+	for _, publicKeyBytes := range publicKeys {
+		publicKey, err := btcec.ParsePubKey(publicKeyBytes)
+		if err != nil {
+			return err
+		}
+		if sig.Verify(sighash, publicKey) {
+			return tx.addPartialSignature(i, sighash, &psbt.PartialSig{
+				PubKey:    publicKeyBytes,
+				Signature: append(sig.Serialize(), byte(txscript.SigHashAll)),
+			})
+		}
+	}
+	return fmt.Errorf("signature for input %d is not from a member of its multisig", i)
+}
+
+// Add a verified signature of a member of the multisig, as found in a PSBT, replacing any it made before.
+// The input is finalized once the multisig has enough signatures.
+func (tx *Tx) addPartialSignature(i int, sighash []byte, partial *psbt.PartialSig) error {
+	publicKeys, _, err := tx.multisigKeys(i)
+	if err != nil {
+		return err
+	}
+	member := false
+	for _, publicKey := range publicKeys {
+		member = member || bytes.Equal(publicKey, partial.PubKey)
+	}
+	if !member {
+		return fmt.Errorf("public key %x is not a member of the multisig of input %d", partial.PubKey, i)
+	}
+	if len(partial.Signature) == 0 || partial.Signature[len(partial.Signature)-1] != byte(txscript.SigHashAll) {
+		return fmt.Errorf("signature of %x for input %d must sign all of the transaction", partial.PubKey, i)
+	}
+	sig, err := ecdsa.ParseDERSignature(partial.Signature[:len(partial.Signature)-1])
+	if err != nil {
+		return fmt.Errorf("invalid signature of %x for input %d: %v", partial.PubKey, i, err)
+	}
+	publicKey, err := btcec.ParsePubKey(partial.PubKey)
+	if err != nil {
+		return err
+	}
+	if !sig.Verify(sighash, publicKey) {
+		return fmt.Errorf("invalid signature of %x for input %d", partial.PubKey, i)
+	}
+
+	if len(tx.MultisigSignatures) != len(tx.MsgTx.TxIn) {
+		tx.MultisigSignatures = make([][]*psbt.PartialSig, len(tx.MsgTx.TxIn))
+	}
+	partials := []*psbt.PartialSig{}
+	for _, existing := range tx.MultisigSignatures[i] {
+		if !bytes.Equal(existing.PubKey, partial.PubKey) {
+			partials = append(partials, existing)
+		}
+	}
+	tx.MultisigSignatures[i] = append(partials, partial)
+	return tx.finalizeMultisig(i)
+}
+
+// Set the witness of a multisig input once it has enough signatures, which must be in the order of the keys
+func (tx *Tx) finalizeMultisig(i int) error {
+	publicKeys, threshold, err := tx.multisigKeys(i)
+	if err != nil {
+		return err
+	}
+	// the extra item is consumed by an off-by-one in OP_CHECKMULTISIG
+	witness := wire.TxWitness{nil}
+	for _, publicKey := range publicKeys {
+		if len(witness)-1 == threshold {
+			break
+		}
+		for _, partial := range tx.MultisigSignatures[i] {
+			if bytes.Equal(partial.PubKey, publicKey) {
+				witness = append(witness, partial.Signature)
+				break
+			}
+		}
+	}
+	if len(witness)-1 < threshold {
+		return nil
+	}
+	utxo := tx.Input.UnspentOutputs[i]
+	tx.MsgTx.TxIn[i].Witness = append(witness, utxo.WitnessScript)
+	if redeemScript := utxo.RedeemScript(); redeemScript != nil {
+		tx.MsgTx.TxIn[i].SignatureScript, err = txscript.NewScriptBuilder().AddData(redeemScript).Script()
+	}
+	return err
+}
+
+// The number of signatures each multisig input has, and needs.  Zero for other inputs.
+func (tx *Tx) MultisigSignatureCounts() (have []int, need []int, err error) {
+	have = make([]int, len(tx.MsgTx.TxIn))
+	need = make([]int, len(tx.MsgTx.TxIn))
+	for i, utxo := range tx.Input.UnspentOutputs {
+		if len(utxo.WitnessScript) == 0 {
+			continue
+		}
+		if _, need[i], err = tx.multisigKeys(i); err != nil {
+			return nil, nil, err
+		}
+		if i < len(tx.MultisigSignatures) {
+			have[i] = len(tx.MultisigSignatures[i])
+		}
+	}
+	return have, need, nil
+}
+
+// Returns true once every multisig input has enough signatures
+func (tx *Tx) multisigComplete() bool {
+	have, need, err := tx.MultisigSignatureCounts()
+	if err != nil {
+		return false
+	}
+	for i := range have {
+		if have[i] < need[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// The hash of the transaction without any signatures, which identifies it while it is being signed
+func (tx *Tx) unsignedHash() string {
+	unsigned := tx.MsgTx.Copy()
+	for _, txIn := range unsigned.TxIn {
+		txIn.SignatureScript = nil
+		txIn.Witness = nil
+	}
+	return unsigned.TxHash().String()
+}
@@ -0,0 +1,144 @@
+package tx
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/btcsuite/btcd/btcutil/psbt"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/openweb3-io/crosschain/blockchain/btc/tx_input"
+	xc "github.com/openweb3-io/crosschain/types"
+)
+
+// ToPSBT exports the unsigned transaction as a BIP-174 PSBT, with the signatures collected so far
+// from the members of any multisig it spends.  Only segwit outputs can be spent, as other outputs
+// need their whole previous transaction in a PSBT.
+func (tx *Tx) ToPSBT() (*psbt.Packet, error) {
+	if len(tx.Input.UnspentOutputs) != len(tx.MsgTx.TxIn) {
+		return nil, fmt.Errorf("expected %d unspent outputs, got %d", len(tx.MsgTx.TxIn), len(tx.Input.UnspentOutputs))
+	}
+	unsigned := tx.MsgTx.Copy()
+	for _, txIn := range unsigned.TxIn {
+		txIn.SignatureScript = nil
+		txIn.Witness = nil
+	}
+	packet, err := psbt.NewFromUnsignedTx(unsigned)
+	if err != nil {
+		return nil, err
+	}
+	for i, utxo := range tx.Input.UnspentOutputs {
+		if !txscript.IsWitnessProgram(utxo.PubKeyScript) && utxo.RedeemScript() == nil {
+			return nil, fmt.Errorf("input %d does not spend a segwit output", i)
+		}
+		packet.Inputs[i].WitnessUtxo = wire.NewTxOut(utxo.Value.Int().Int64(), utxo.PubKeyScript)
+		if txscript.IsPayToTaproot(utxo.PubKeyScript) {
+			continue
+		}
+		packet.Inputs[i].SighashType = txscript.SigHashAll
+		if len(utxo.WitnessScript) > 0 {
+			packet.Inputs[i].WitnessScript = utxo.WitnessScript
+			packet.Inputs[i].RedeemScript = utxo.RedeemScript()
+		}
+		if i < len(tx.MultisigSignatures) {
+			packet.Inputs[i].PartialSigs = tx.MultisigSignatures[i]
+		}
+	}
+	return packet, nil
+}
+
+// EncodePSBT exports the transaction as a base64 PSBT, to be signed by the other members of a multisig
+func (tx *Tx) EncodePSBT() (string, error) {
+	packet, err := tx.ToPSBT()
+	if err != nil {
+		return "", err
+	}
+	return packet.B64Encode()
+}
+
+// NewTxFromPSBT imports a base64 PSBT, such as one exported by EncodePSBT or by another wallet.  The
+// signatures it has from members of a multisig are verified and kept.
+func NewTxFromPSBT(encoded string) (*Tx, error) {
+	packet, err := psbt.NewFromRawBytes(strings.NewReader(encoded), true)
+	if err != nil {
+		return nil, fmt.Errorf("invalid psbt: %v", err)
+	}
+	input := tx_input.NewTxInput()
+	for i, pInput := range packet.Inputs {
+		if pInput.WitnessUtxo == nil {
+			return nil, fmt.Errorf("input %d of the psbt does not spend a segwit output", i)
+		}
+		outpoint := packet.UnsignedTx.TxIn[i].PreviousOutPoint
+		input.UnspentOutputs = append(input.UnspentOutputs, tx_input.Output{
+			Outpoint: tx_input.Outpoint{
+				Hash:  append([]byte{}, outpoint.Hash[:]...),
+				Index: outpoint.Index,
+			},
+			Value:         xc.NewBigIntFromUint64(uint64(pInput.WitnessUtxo.Value)),
+			PubKeyScript:  pInput.WitnessUtxo.PkScript,
+			WitnessScript: pInput.WitnessScript,
+		})
+	}
+
+	tx := &Tx{
+		MsgTx: packet.UnsignedTx.Copy(),
+		Input: input,
+	}
+	amount := xc.NewBigIntFromUint64(0)
+	for _, txOut := range tx.MsgTx.TxOut {
+		value := xc.NewBigIntFromUint64(uint64(txOut.Value))
+		amount = amount.Add(&value)
+	}
+	tx.Amount = amount
+	for i, utxo := range input.UnspentOutputs {
+		if redeemScript := utxo.RedeemScript(); redeemScript != nil {
+			tx.MsgTx.TxIn[i].SignatureScript, err = txscript.NewScriptBuilder().AddData(redeemScript).Script()
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err := tx.addPSBTSignatures(packet); err != nil {
+		return nil, err
+	}
+	return tx, nil
+}
+
+// CombinePSBT merges the multisig signatures from another copy of the transaction, signed elsewhere
+func (tx *Tx) CombinePSBT(encoded string) error {
+	if tx.Signed {
+		return errors.New("already signed")
+	}
+	other, err := NewTxFromPSBT(encoded)
+	if err != nil {
+		return err
+	}
+	if other.unsignedHash() != tx.unsignedHash() {
+		return fmt.Errorf("psbt is for transaction %s, not %s", other.unsignedHash(), tx.unsignedHash())
+	}
+	packet, err := other.ToPSBT()
+	if err != nil {
+		return err
+	}
+	return tx.addPSBTSignatures(packet)
+}
+
+func (tx *Tx) addPSBTSignatures(packet *psbt.Packet) error {
+	sighashes, err := tx.Sighashes()
+	if err != nil {
+		return err
+	}
+	for i, pInput := range packet.Inputs {
+		if len(pInput.PartialSigs) > 0 && len(tx.Input.UnspentOutputs[i].WitnessScript) == 0 {
+			return fmt.Errorf("input %d has partial signatures, but does not spend a multisig", i)
+		}
+		for _, partial := range pInput.PartialSigs {
+			if err := tx.addPartialSignature(i, sighashes[i], partial); err != nil {
+				return err
+			}
+		}
+	}
+	tx.Signed = tx.isMultisig() && tx.multisigComplete()
+	return nil
+}
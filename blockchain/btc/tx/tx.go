@@ -10,9 +10,12 @@ import (
 
 	btcec "github.com/btcsuite/btcd/btcec/v2"
 	"github.com/btcsuite/btcd/btcec/v2/ecdsa"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/btcutil/psbt"
 	"github.com/btcsuite/btcd/txscript"
 	"github.com/btcsuite/btcd/wire"
 	"github.com/openweb3-io/crosschain/blockchain/btc/tx_input"
+	"github.com/openweb3-io/crosschain/factory/blockchains/registry"
 	xc "github.com/openweb3-io/crosschain/types"
 	log "github.com/sirupsen/logrus"
 )
@@ -38,10 +41,18 @@ type Tx struct {
 	Input  *tx_input.TxInput
 	From   xc.Address
 	To     xc.Address
+	// Signatures collected from the members of the multisig spent by each input
+	MultisigSignatures [][]*psbt.PartialSig
 	// isBch  bool
 }
 
 var _ xc.Tx = &Tx{}
+var _ xc.TxWithSignatureTypes = &Tx{}
+var _ xc.TxWithSigners = &Tx{}
+
+func init() {
+	registry.RegisterUnsignedTx(xc.BlockchainBtc, &Tx{})
+}
 
 // Hash returns the tx hash or id
 func (tx *Tx) Hash() xc.TxHash {
@@ -71,21 +82,32 @@ func (tx *Tx) txHashNormalBytes() []byte {
 // Sighashes returns the tx payload to sign, aka sighash
 func (tx *Tx) Sighashes() ([]xc.TxDataToSign, error) {
 	sighashes := make([]xc.TxDataToSign, len(tx.Input.UnspentOutputs))
+	if len(tx.Input.UnspentOutputs) == 0 {
+		return sighashes, nil
+	}
+	if len(tx.Input.UnspentOutputs) != len(tx.MsgTx.TxIn) {
+		return nil, fmt.Errorf("expected %d unspent outputs, got %d", len(tx.MsgTx.TxIn), len(tx.Input.UnspentOutputs))
+	}
+	// taproot sighashes commit to all of the outputs being spent
+	fetcher := tx.prevOutputFetcher()
+	txSigHashes := txscript.NewTxSigHashes(tx.MsgTx, fetcher)
 
 	for i, utxo := range tx.Input.UnspentOutputs {
 		pubKeyScript := utxo.PubKeyScript
 		value := utxo.Value.Uint64()
-		fetcher := txscript.NewCannedPrevOutputFetcher(
-			pubKeyScript, int64(value),
-		)
 
 		var hash []byte
 		var err error
 
 		log.Debugf("Sighashes params: IsPayToWitnessPubKeyHash(pubKeyScript)=%t", txscript.IsPayToWitnessPubKeyHash(pubKeyScript))
-		if txscript.IsPayToWitnessPubKeyHash(pubKeyScript) {
+		if len(utxo.WitnessScript) > 0 {
+			hash, err = txscript.CalcWitnessSigHash(utxo.WitnessScript, txSigHashes, txscript.SigHashAll, tx.MsgTx, i, int64(value))
+		} else if txscript.IsPayToTaproot(pubKeyScript) {
+			log.Debugf("CalcTaprootSignatureHash with pubKeyScript: %s", base64.RawURLEncoding.EncodeToString(pubKeyScript))
+			hash, err = txscript.CalcTaprootSignatureHash(txSigHashes, txscript.SigHashDefault, tx.MsgTx, i, fetcher)
+		} else if txscript.IsPayToWitnessPubKeyHash(pubKeyScript) {
 			log.Debugf("CalcWitnessSigHash with pubKeyScript: %s", base64.RawURLEncoding.EncodeToString(pubKeyScript))
-			hash, err = txscript.CalcWitnessSigHash(pubKeyScript, txscript.NewTxSigHashes(tx.MsgTx, fetcher), txscript.SigHashAll, tx.MsgTx, i, int64(value))
+			hash, err = txscript.CalcWitnessSigHash(pubKeyScript, txSigHashes, txscript.SigHashAll, tx.MsgTx, i, int64(value))
 		} else {
 			log.Debugf("CalcSignatureHash with pubKeyScript: %s", base64.RawURLEncoding.EncodeToString(pubKeyScript))
 			hash, err = txscript.CalcSignatureHash(pubKeyScript, txscript.SigHashAll, tx.MsgTx, i)
@@ -101,6 +123,58 @@ func (tx *Tx) Sighashes() ([]xc.TxDataToSign, error) {
 	return sighashes, nil
 }
 
+// Taproot inputs are signed with schnorr (BIP-340), by the private key tweaked as in BIP-86.
+// All other inputs are signed with ecdsa.
+func (tx *Tx) SignatureTypes() ([]xc.SignatureType, error) {
+	types := make([]xc.SignatureType, len(tx.Input.UnspentOutputs))
+	for i, utxo := range tx.Input.UnspentOutputs {
+		if txscript.IsPayToTaproot(utxo.PubKeyScript) {
+			types[i] = xc.Schnorr
+		} else {
+			types[i] = xc.K256Sha256
+		}
+	}
+	return types, nil
+}
+
+// Each input is signed by the owner of the output it spends, which is the sender unless the output
+// records another owner.  A multisig input is requested once, and is signed by each of its members in turn.
+func (tx *Tx) SignatureRequests() ([]*xc.SignatureRequest, error) {
+	sighashes, err := tx.Sighashes()
+	if err != nil {
+		return nil, err
+	}
+	types, err := tx.SignatureTypes()
+	if err != nil {
+		return nil, err
+	}
+	requests := make([]*xc.SignatureRequest, len(sighashes))
+	for i, sighash := range sighashes {
+		signer := tx.Input.UnspentOutputs[i].Address
+		if signer == "" {
+			signer = tx.From
+		}
+		requests[i] = xc.NewSignatureRequest(signer, sighash, types[i])
+	}
+	return requests, nil
+}
+
+// The public key that signs the input
+func (tx *Tx) publicKey(i int) []byte {
+	if publicKey := tx.Input.UnspentOutputs[i].PublicKey; len(publicKey) > 0 {
+		return publicKey
+	}
+	return tx.Input.FromPublicKey
+}
+
+func (tx *Tx) prevOutputFetcher() *txscript.MultiPrevOutFetcher {
+	fetcher := txscript.NewMultiPrevOutFetcher(nil)
+	for i, utxo := range tx.Input.UnspentOutputs {
+		fetcher.AddPrevOut(tx.MsgTx.TxIn[i].PreviousOutPoint, wire.NewTxOut(utxo.Value.Int().Int64(), utxo.PubKeyScript))
+	}
+	return fetcher
+}
+
 // returns (r, s, err)
 func DecodeEcdsaSignature(signature xc.TxSignature) (btcec.ModNScalar, btcec.ModNScalar, error) {
 	var err error
@@ -125,7 +199,8 @@ func DecodeEcdsaSignature(signature xc.TxSignature) (btcec.ModNScalar, btcec.Mod
 	return r, s, err
 }
 
-// AddSignatures adds a signature to Tx
+// AddSignatures adds a signature to Tx.  Multisig inputs may be left without a signature, and
+// collect the signatures of their members over several calls until they have enough.
 func (tx *Tx) AddSignatures(signatures ...xc.TxSignature) error {
 	if tx.Signed {
 		return fmt.Errorf("already signed")
@@ -134,35 +209,61 @@ func (tx *Tx) AddSignatures(signatures ...xc.TxSignature) error {
 	if len(signatures) != len(tx.MsgTx.TxIn) {
 		return fmt.Errorf("expected %v signatures, got %v signatures", len(tx.MsgTx.TxIn), len(signatures))
 	}
+	var sighashes []xc.TxDataToSign
+	if tx.isMultisig() {
+		var err error
+		if sighashes, err = tx.Sighashes(); err != nil {
+			return err
+		}
+	}
 
 	for i, rsvBytes := range signatures {
+		pubKeyScript := tx.Input.UnspentOutputs[i].PubKeyScript
+
+		if len(tx.Input.UnspentOutputs[i].WitnessScript) > 0 {
+			if err := tx.addMultisigSignature(i, sighashes[i], rsvBytes); err != nil {
+				return err
+			}
+			continue
+		}
+
+		// Support taproot key path spends, signed with the default sighash type which is not suffixed.
+		if txscript.IsPayToTaproot(pubKeyScript) {
+			signature, err := schnorr.ParseSignature(rsvBytes)
+			if err != nil {
+				return fmt.Errorf("invalid schnorr signature for input %d: %v", i, err)
+			}
+			log.Debug("append signature (taproot)")
+			tx.MsgTx.TxIn[i].Witness = wire.TxWitness([][]byte{signature.Serialize()})
+			continue
+		}
+
 		r, s, err := DecodeEcdsaSignature(rsvBytes)
 		if err != nil {
 			return err
 		}
 
 		signature := ecdsa.NewSignature(&r, &s)
-		pubKeyScript := tx.Input.UnspentOutputs[i].PubKeyScript
 		signatureWithSuffix := append(signature.Serialize(), byte(txscript.SigHashAll))
 
 		// Support segwit.
 		if txscript.IsPayToWitnessPubKeyHash(pubKeyScript) || txscript.IsPayToWitnessScriptHash(pubKeyScript) {
 			log.Debug("append signature (segwit)")
-			tx.MsgTx.TxIn[i].Witness = wire.TxWitness([][]byte{signatureWithSuffix, tx.Input.FromPublicKey})
+			tx.MsgTx.TxIn[i].Witness = wire.TxWitness([][]byte{signatureWithSuffix, tx.publicKey(i)})
 			continue
 		}
 
 		// Support non-segwit
 		builder := txscript.NewScriptBuilder()
 		builder.AddData(signatureWithSuffix)
-		builder.AddData(tx.Input.FromPublicKey)
+		builder.AddData(tx.publicKey(i))
 		tx.MsgTx.TxIn[i].SignatureScript, err = builder.Script()
 		if err != nil {
 			return err
 		}
 	}
 
-	tx.Signed = true
+	tx.Signed = tx.multisigComplete()
 	return nil
 }
 
@@ -171,6 +272,9 @@ func (tx *Tx) GetSignatures() []xc.TxSignature {
 }
 
 func (tx *Tx) Serialize() ([]byte, error) {
+	if tx.isMultisig() && !tx.multisigComplete() {
+		return []byte{}, errors.New("multisig inputs do not have enough signatures")
+	}
 	buf := new(bytes.Buffer)
 	if err := tx.MsgTx.Serialize(buf); err != nil {
 		return []byte{}, err
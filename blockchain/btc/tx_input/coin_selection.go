@@ -0,0 +1,248 @@
+package tx_input
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"sort"
+)
+
+var ErrInsufficientFunds = errors.New("insufficient funds")
+
+type SelectionParams struct {
+	// the amount paid to the outputs, excluding change
+	Amount uint64
+	// in sats per vbyte
+	FeeRate uint64
+	// pubkey scripts of the outputs paid, excluding change
+	OutputScripts [][]byte
+	ChangeScript  []byte
+	// change below this is added to the fee rather than creating an output
+	DustThreshold uint64
+	// the most inputs to spend, 0 for no limit
+	MaxInputs int
+}
+
+type Selection struct {
+	Inputs []Output
+	Fee    uint64
+	// zero if there is no change output
+	Change uint64
+}
+
+// A strategy for picking which unspent outputs to spend in a transaction
+type CoinSelector interface {
+	SelectCoins(unspentOutputs []Output, params *SelectionParams) (*Selection, error)
+}
+
+// Finish a selection by calculating the fee and the change for the inputs, or return false if they
+// are not enough.  Change that is dust is added to the fee.
+func finalizeSelection(inputs []Output, params *SelectionParams, allowChange bool) (*Selection, bool) {
+	total := uint64(0)
+	for _, input := range inputs {
+		total += input.Value.Uint64()
+	}
+	feeWithoutChange := params.FeeRate * EstimateVsize(inputs, params.OutputScripts)
+	if total < params.Amount+feeWithoutChange {
+		return nil, false
+	}
+	selection := &Selection{
+		Inputs: inputs,
+		Fee:    total - params.Amount,
+	}
+	if allowChange {
+		withChange := append(append([][]byte{}, params.OutputScripts...), params.ChangeScript)
+		feeWithChange := params.FeeRate * EstimateVsize(inputs, withChange)
+		if total >= params.Amount+feeWithChange && total-params.Amount-feeWithChange >= params.DustThreshold {
+			selection.Fee = feeWithChange
+			selection.Change = total - params.Amount - feeWithChange
+		}
+	}
+	return selection, true
+}
+
+func insufficientFunds(unspentOutputs []Output, params *SelectionParams) error {
+	total := uint64(0)
+	for _, output := range unspentOutputs {
+		total += output.Value.Uint64()
+	}
+	fee := params.FeeRate * EstimateVsize(unspentOutputs, params.OutputScripts)
+	if params.MaxInputs > 0 && len(unspentOutputs) > params.MaxInputs {
+		return fmt.Errorf("%w: need %d sats including fees within %d inputs", ErrInsufficientFunds, params.Amount+fee, params.MaxInputs)
+	}
+	return fmt.Errorf("%w: need %d sats including an estimated fee of %d sats, but only %d sats are available", ErrInsufficientFunds, params.Amount+fee, fee, total)
+}
+
+// Add inputs in the given order until they cover the amount and fees
+func accumulate(ordered []Output, params *SelectionParams) (*Selection, bool) {
+	selected := []Output{}
+	for _, output := range ordered {
+		if params.MaxInputs > 0 && len(selected) >= params.MaxInputs {
+			break
+		}
+		selected = append(selected, output)
+		if selection, ok := finalizeSelection(selected, params, true); ok {
+			return selection, true
+		}
+	}
+	return nil, false
+}
+
+func sortedByValueDesc(unspentOutputs []Output) []Output {
+	sorted := append([]Output{}, unspentOutputs...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Value.Cmp(&sorted[j].Value) > 0
+	})
+	return sorted
+}
+
+// Spend the largest outputs first, which minimizes the number of inputs and so the fee of the transaction.
+type LargestFirst struct{}
+
+var _ CoinSelector = &LargestFirst{}
+
+func NewLargestFirst() *LargestFirst {
+	return &LargestFirst{}
+}
+
+func (*LargestFirst) SelectCoins(unspentOutputs []Output, params *SelectionParams) (*Selection, error) {
+	if selection, ok := accumulate(sortedByValueDesc(unspentOutputs), params); ok {
+		return selection, nil
+	}
+	return nil, insufficientFunds(unspentOutputs, params)
+}
+
+// Search for a set of outputs that pays the amount and fees without needing a change output,
+// wasting at most the cost of creating and later spending change.  Falls back to another strategy
+// if there is no such set.  This is the main algorithm used by bitcoin core.
+type BranchAndBound struct {
+	Fallback CoinSelector
+	// bound on the number of branches explored
+	MaxTries int
+}
+
+var _ CoinSelector = &BranchAndBound{}
+
+const DefaultBranchAndBoundTries = 100_000
+
+func NewBranchAndBound(fallback CoinSelector) *BranchAndBound {
+	return &BranchAndBound{
+		Fallback: fallback,
+		MaxTries: DefaultBranchAndBoundTries,
+	}
+}
+
+func (bnb *BranchAndBound) SelectCoins(unspentOutputs []Output, params *SelectionParams) (*Selection, error) {
+	if inputs, ok := bnb.search(unspentOutputs, params); ok {
+		if selection, ok := finalizeSelection(inputs, params, false); ok {
+			return selection, nil
+		}
+	}
+	if bnb.Fallback != nil {
+		return bnb.Fallback.SelectCoins(unspentOutputs, params)
+	}
+	return nil, insufficientFunds(unspentOutputs, params)
+}
+
+func (bnb *BranchAndBound) search(unspentOutputs []Output, params *SelectionParams) ([]Output, bool) {
+	// work with the value of each output after paying for its own input
+	type candidate struct {
+		output    Output
+		effective uint64
+	}
+	candidates := []candidate{}
+	for _, output := range sortedByValueDesc(unspentOutputs) {
+		inputFee := params.FeeRate * ((SpendWeight(output) + 3) / 4)
+		if output.Value.Uint64() > inputFee {
+			candidates = append(candidates, candidate{output, output.Value.Uint64() - inputFee})
+		}
+	}
+	remaining := make([]uint64, len(candidates)+1)
+	for i := len(candidates) - 1; i >= 0; i-- {
+		remaining[i] = remaining[i+1] + candidates[i].effective
+	}
+
+	// the fixed part of the transaction, including the segwit marker to be safe
+	target := params.Amount + params.FeeRate*(EstimateVsize(nil, params.OutputScripts)+1)
+	changeVsize := (OutputWeight(params.ChangeScript) + InputWeight(params.ChangeScript) + 3) / 4
+	costOfChange := params.FeeRate * changeVsize
+
+	var best []int
+	bestExcess := uint64(0)
+	selected := []int{}
+	tries := 0
+	var explore func(i int, sum uint64)
+	explore = func(i int, sum uint64) {
+		tries++
+		if tries > bnb.MaxTries || sum > target+costOfChange {
+			return
+		}
+		if sum >= target {
+			if excess := sum - target; best == nil || excess < bestExcess {
+				best = append([]int{}, selected...)
+				bestExcess = excess
+			}
+			return
+		}
+		if i >= len(candidates) || sum+remaining[i] < target {
+			return
+		}
+		if params.MaxInputs > 0 && len(selected) >= params.MaxInputs {
+			return
+		}
+		// include the candidate, then try without it
+		selected = append(selected, i)
+		explore(i+1, sum+candidates[i].effective)
+		selected = selected[:len(selected)-1]
+		explore(i+1, sum)
+	}
+	explore(0, 0)
+
+	if best == nil {
+		return nil, false
+	}
+	inputs := make([]Output, len(best))
+	for j, i := range best {
+		inputs[j] = candidates[i].output
+	}
+	return inputs, true
+}
+
+// Avoid linking outputs together where possible: spend the smallest single output that covers the
+// amount, otherwise spend outputs in a random order so the selection does not reveal the wallet's outputs.
+type PrivacyPreserving struct {
+	Rand *rand.Rand
+}
+
+var _ CoinSelector = &PrivacyPreserving{}
+
+func NewPrivacyPreserving() *PrivacyPreserving {
+	return &PrivacyPreserving{}
+}
+
+func (p *PrivacyPreserving) SelectCoins(unspentOutputs []Output, params *SelectionParams) (*Selection, error) {
+	sorted := sortedByValueDesc(unspentOutputs)
+	for i := len(sorted) - 1; i >= 0; i-- {
+		if selection, ok := finalizeSelection(sorted[i:i+1], params, true); ok {
+			return selection, nil
+		}
+	}
+
+	shuffled := append([]Output{}, unspentOutputs...)
+	shuffle := rand.Shuffle
+	if p.Rand != nil {
+		shuffle = p.Rand.Shuffle
+	}
+	shuffle(len(shuffled), func(i, j int) {
+		shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+	})
+	if selection, ok := accumulate(shuffled, params); ok {
+		return selection, nil
+	}
+	// a random order may hit the input limit where the largest outputs would not
+	return NewLargestFirst().SelectCoins(unspentOutputs, params)
+}
+
+func DefaultCoinSelector() CoinSelector {
+	return NewBranchAndBound(NewLargestFirst())
+}
@@ -0,0 +1,128 @@
+package tx_input_test
+
+import (
+	"bytes"
+	"math/rand"
+	"testing"
+
+	"github.com/openweb3-io/crosschain/blockchain/btc/tx_input"
+	xc "github.com/openweb3-io/crosschain/types"
+	"github.com/stretchr/testify/require"
+)
+
+var p2pkhScript = append(append([]byte{0x76, 0xa9, 0x14}, bytes.Repeat([]byte{1}, 20)...), 0x88, 0xac)
+var p2wpkhScript = append([]byte{0x00, 0x14}, bytes.Repeat([]byte{2}, 20)...)
+var p2trScript = append([]byte{0x51, 0x20}, bytes.Repeat([]byte{3}, 32)...)
+
+func newUtxos(script []byte, values ...uint64) []tx_input.Output {
+	outputs := []tx_input.Output{}
+	for i, value := range values {
+		outputs = append(outputs, tx_input.Output{
+			Outpoint:     tx_input.Outpoint{Hash: []byte{byte(i)}, Index: uint32(i)},
+			Value:        xc.NewBigIntFromUint64(value),
+			PubKeyScript: script,
+		})
+	}
+	return outputs
+}
+
+func values(outputs []tx_input.Output) []uint64 {
+	res := []uint64{}
+	for _, output := range outputs {
+		res = append(res, output.Value.Uint64())
+	}
+	return res
+}
+
+func TestEstimateVsize(t *testing.T) {
+	require := require.New(t)
+
+	// the typical 1 input, 2 output segwit transaction
+	require.EqualValues(141, tx_input.EstimateVsize(newUtxos(p2wpkhScript, 1), [][]byte{p2wpkhScript, p2wpkhScript}))
+	require.EqualValues(226, tx_input.EstimateVsize(newUtxos(p2pkhScript, 1), [][]byte{p2pkhScript, p2pkhScript}))
+	require.EqualValues(154, tx_input.EstimateVsize(newUtxos(p2trScript, 1), [][]byte{p2trScript, p2trScript}))
+	// mixed inputs
+	inputs := append(newUtxos(p2wpkhScript, 1), newUtxos(p2pkhScript, 1)...)
+	require.EqualValues(289, tx_input.EstimateVsize(inputs, [][]byte{p2wpkhScript, p2wpkhScript}))
+
+	require.EqualValues(546, tx_input.DustThreshold(p2pkhScript))
+	require.EqualValues(294, tx_input.DustThreshold(p2wpkhScript))
+	require.EqualValues(330, tx_input.DustThreshold(p2trScript))
+}
+
+func newParams(amount uint64) *tx_input.SelectionParams {
+	return &tx_input.SelectionParams{
+		Amount:        amount,
+		FeeRate:       10,
+		OutputScripts: [][]byte{p2wpkhScript},
+		ChangeScript:  p2wpkhScript,
+		DustThreshold: tx_input.DustThreshold(p2wpkhScript),
+	}
+}
+
+func TestLargestFirst(t *testing.T) {
+	require := require.New(t)
+	utxos := newUtxos(p2wpkhScript, 1_000, 50_000, 20_000, 30_000)
+
+	selection, err := tx_input.NewLargestFirst().SelectCoins(utxos, newParams(60_000))
+	require.NoError(err)
+	require.Equal([]uint64{50_000, 30_000}, values(selection.Inputs))
+	// 2 inputs and 2 outputs
+	require.EqualValues(2090, selection.Fee)
+	require.EqualValues(80_000-60_000-2090, selection.Change)
+
+	// change that is dust is added to the fee
+	selection, err = tx_input.NewLargestFirst().SelectCoins(utxos, newParams(50_000-1_100-100))
+	require.NoError(err)
+	require.Equal([]uint64{50_000}, values(selection.Inputs))
+	require.EqualValues(0, selection.Change)
+	require.EqualValues(1_200, selection.Fee)
+
+	_, err = tx_input.NewLargestFirst().SelectCoins(utxos, newParams(101_000))
+	require.ErrorIs(err, tx_input.ErrInsufficientFunds)
+
+	params := newParams(60_000)
+	params.MaxInputs = 1
+	_, err = tx_input.NewLargestFirst().SelectCoins(utxos, params)
+	require.ErrorIs(err, tx_input.ErrInsufficientFunds)
+	require.ErrorContains(err, "within 1 inputs")
+}
+
+func TestBranchAndBound(t *testing.T) {
+	require := require.New(t)
+	utxos := newUtxos(p2wpkhScript, 40_000, 25_000, 16_000, 9_000)
+
+	// 25k + 16k pays exactly for 40k with 2 inputs and no change
+	amount := uint64(41_000 - 10*(11+31+2*68))
+	selection, err := tx_input.NewBranchAndBound(nil).SelectCoins(utxos, newParams(amount))
+	require.NoError(err)
+	require.Equal([]uint64{25_000, 16_000}, values(selection.Inputs))
+	require.EqualValues(0, selection.Change)
+	require.EqualValues(41_000-amount, selection.Fee)
+
+	// no exact match, use the fallback
+	_, err = tx_input.NewBranchAndBound(nil).SelectCoins(utxos, newParams(30_000))
+	require.ErrorIs(err, tx_input.ErrInsufficientFunds)
+	selection, err = tx_input.DefaultCoinSelector().SelectCoins(utxos, newParams(30_000))
+	require.NoError(err)
+	require.Equal([]uint64{40_000}, values(selection.Inputs))
+	require.NotZero(selection.Change)
+}
+
+func TestPrivacyPreserving(t *testing.T) {
+	require := require.New(t)
+	utxos := newUtxos(p2wpkhScript, 100_000, 12_000, 30_000, 20_000)
+	selector := &tx_input.PrivacyPreserving{Rand: rand.New(rand.NewSource(1))}
+
+	// the smallest single output that covers the amount
+	selection, err := selector.SelectCoins(utxos, newParams(15_000))
+	require.NoError(err)
+	require.Equal([]uint64{20_000}, values(selection.Inputs))
+
+	selection, err = selector.SelectCoins(utxos[1:], newParams(55_000))
+	require.NoError(err)
+	require.ElementsMatch([]uint64{12_000, 30_000, 20_000}, values(selection.Inputs))
+
+	_, err = selector.SelectCoins(utxos[1:], newParams(65_000))
+	require.ErrorIs(err, tx_input.ErrInsufficientFunds)
+}
@@ -0,0 +1,106 @@
+package tx_input
+
+import (
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+)
+
+// Weight units, where a non-witness byte weighs 4 and a witness byte weighs 1 (BIP-141)
+const (
+	// version, locktime and the input and output counts
+	txOverheadWeight = 10 * 4
+	// segwit marker and flag
+	segwitMarkerWeight = 2
+	// previous outpoint, sequence and the length of the signature script
+	inputBaseWeight = 41 * 4
+	// value and the length of the pubkey script
+	outputBaseWeight = 9 * 4
+
+	// 72 byte signature and 33 byte public key pushed in the signature script
+	p2pkhSigScriptWeight = 107 * 4
+	// witness item count, 72 byte signature and 33 byte public key
+	p2wpkhWitnessWeight = 1 + 1 + 72 + 1 + 33
+	// push of the 22 byte witness program in the signature script
+	p2shP2wpkhSigScriptWeight = 23 * 4
+	// witness item count and 64 byte schnorr signature
+	p2trKeyPathWitnessWeight = 1 + 1 + 64
+	// push of the 34 byte witness program in the signature script
+	p2shP2wshSigScriptWeight = 35 * 4
+)
+
+// Weight of spending an output with the given script.  Unknown scripts are assumed to be P2PKH, which is the largest.
+func InputWeight(pubKeyScript []byte) uint64 {
+	switch {
+	case txscript.IsPayToTaproot(pubKeyScript):
+		return inputBaseWeight + p2trKeyPathWitnessWeight
+	case txscript.IsPayToWitnessPubKeyHash(pubKeyScript):
+		return inputBaseWeight + p2wpkhWitnessWeight
+	case txscript.IsPayToScriptHash(pubKeyScript):
+		// assume the script is a nested P2WPKH
+		return inputBaseWeight + p2shP2wpkhSigScriptWeight + p2wpkhWitnessWeight
+	default:
+		return inputBaseWeight + p2pkhSigScriptWeight
+	}
+}
+
+// Weight of spending the output, which for a multisig depends on its script rather than the pubkey script
+func SpendWeight(output Output) uint64 {
+	if len(output.WitnessScript) == 0 {
+		return InputWeight(output.PubKeyScript)
+	}
+	_, threshold, err := txscript.CalcMultiSigStats(output.WitnessScript)
+	if err != nil {
+		threshold = 1
+	}
+	// witness item count, the empty item consumed by OP_CHECKMULTISIG, the signatures and the script
+	scriptLen := uint64(len(output.WitnessScript))
+	weight := uint64(inputBaseWeight) + 1 + 1 + uint64(threshold)*(1+72) + uint64(wire.VarIntSerializeSize(scriptLen)) + scriptLen
+	if txscript.IsPayToScriptHash(output.PubKeyScript) {
+		weight += p2shP2wshSigScriptWeight
+	}
+	return weight
+}
+
+func OutputWeight(pubKeyScript []byte) uint64 {
+	return outputBaseWeight + uint64(len(pubKeyScript))*4
+}
+
+func isWitnessInput(pubKeyScript []byte) bool {
+	return txscript.IsPayToTaproot(pubKeyScript) ||
+		txscript.IsPayToWitnessPubKeyHash(pubKeyScript) ||
+		txscript.IsPayToWitnessScriptHash(pubKeyScript) ||
+		txscript.IsPayToScriptHash(pubKeyScript)
+}
+
+// Estimate the virtual size of a signed transaction spending the given outputs to the given scripts.
+func EstimateVsize(inputs []Output, outputScripts [][]byte) uint64 {
+	weight := uint64(txOverheadWeight)
+	witness := false
+	for _, input := range inputs {
+		weight += SpendWeight(input)
+		witness = witness || isWitnessInput(input.PubKeyScript)
+	}
+	if witness {
+		weight += segwitMarkerWeight
+	}
+	for _, script := range outputScripts {
+		weight += OutputWeight(script)
+	}
+	// round up to whole vbytes
+	return (weight + 3) / 4
+}
+
+// Fee rate used by bitcoin core to determine dust, in sats per vbyte
+const dustRelayFeeRate = 3
+
+// The smallest output to the script that is worth creating, as it costs more in fees to spend than
+// it is worth below this value.  This matches the dust limit of bitcoin core, e.g. 546 sats for P2PKH.
+func DustThreshold(pubKeyScript []byte) uint64 {
+	size := uint64(9 + len(pubKeyScript))
+	if txscript.IsWitnessProgram(pubKeyScript) {
+		size += 41 + 107/4
+	} else {
+		size += 148
+	}
+	return size * dustRelayFeeRate
+}
@@ -2,11 +2,13 @@ package tx_input
 
 import (
 	"bytes"
+	"crypto/sha256"
 	"encoding/base64"
 	"encoding/hex"
 	"fmt"
 	"sort"
 
+	"github.com/btcsuite/btcd/txscript"
 	"github.com/openweb3-io/crosschain/factory/blockchains/registry"
 	xc "github.com/openweb3-io/crosschain/types"
 	"github.com/shopspring/decimal"
@@ -27,6 +29,33 @@ type Output struct {
 	Outpoint     `json:"outpoint"`
 	Value        xc.BigInt `json:"value"`
 	PubKeyScript []byte    `json:"pubkey_script"`
+	// The owner of the output and its public key, if it is not owned by the sender of the transaction
+	Address   xc.Address `json:"address,omitempty"`
+	PublicKey []byte     `json:"public_key,omitempty"`
+	// The multisig script of a P2WSH or P2SH-P2WSH output
+	WitnessScript []byte `json:"witness_script,omitempty"`
+}
+
+// The script a P2SH-P2WSH output is redeemed with, which is pushed in the signature script of
+// the input spending it.  Nil for other outputs.
+func (output *Output) RedeemScript() []byte {
+	if len(output.WitnessScript) == 0 || !txscript.IsPayToScriptHash(output.PubKeyScript) {
+		return nil
+	}
+	return WitnessProgram(output.WitnessScript)
+}
+
+// The P2WSH pubkey script paying to a witness script
+func WitnessProgram(witnessScript []byte) []byte {
+	scriptHash := sha256.Sum256(witnessScript)
+	program, _ := txscript.NewScriptBuilder().AddOp(txscript.OP_0).AddData(scriptHash[:]).Script()
+	return program
+}
+
+// The members of a m-of-n multisig sender
+type Multisig struct {
+	Threshold  int      `json:"threshold"`
+	PublicKeys [][]byte `json:"public_keys"`
 }
 
 // TxInput for Bitcoin
@@ -34,6 +63,8 @@ type TxInput struct {
 	UnspentOutputs  []Output  `json:"unspent_outputs"`
 	FromPublicKey   []byte    `json:"from_pubkey"`
 	GasPricePerByte xc.BigInt `json:"gas_price_per_byte"`
+	// Set when sending from a multisig, instead of the public key
+	Multisig *Multisig `json:"multisig,omitempty"`
 }
 
 func init() {
@@ -101,6 +132,14 @@ func (txInput *TxInput) SetPublicKey(publicKeyBytes []byte) error {
 	return nil
 }
 
+// SetMultisig marks the sender as a m-of-n multisig of the given public keys, in the order of its script
+func (txInput *TxInput) SetMultisig(threshold int, publicKeys [][]byte) {
+	txInput.Multisig = &Multisig{
+		Threshold:  threshold,
+		PublicKeys: publicKeys,
+	}
+}
+
 func (txInput *TxInput) SetPublicKeyFromStr(publicKeyStr string) error {
 	publicKeyBytes, err := base64.StdEncoding.DecodeString(publicKeyStr)
 	if err != nil {
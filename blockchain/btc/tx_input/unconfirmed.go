@@ -0,0 +1,78 @@
+package tx_input
+
+import (
+	"fmt"
+
+	"github.com/btcsuite/btcd/wire"
+	xc "github.com/openweb3-io/crosschain/types"
+)
+
+// Inputs with a sequence number below this signal that the transaction may be replaced (BIP-125)
+const MaxRbfSequence = wire.MaxTxInSequenceNum - 2
+
+// A transaction in the mempool, along with the outputs it spends, as needed to bump its fee.
+type UnconfirmedTx struct {
+	Hash xc.TxHash `json:"hash"`
+	// the outputs spent by the transaction, in the order of its inputs
+	Inputs    []Output `json:"inputs"`
+	Sequences []uint32 `json:"sequences"`
+	// the outputs created by the transaction
+	Outputs []Output `json:"outputs"`
+	Vsize   uint64   `json:"vsize"`
+}
+
+// Build from a decoded transaction and the outputs spent by each of its inputs
+func NewUnconfirmedTx(msgTx *wire.MsgTx, spent []Output, vsize uint64) (*UnconfirmedTx, error) {
+	if len(spent) != len(msgTx.TxIn) {
+		return nil, fmt.Errorf("expected %d spent outputs, got %d", len(msgTx.TxIn), len(spent))
+	}
+	hash := msgTx.TxHash()
+	unconfirmed := &UnconfirmedTx{
+		Hash:   xc.TxHash(hash.String()),
+		Inputs: spent,
+		Vsize:  vsize,
+	}
+	for i, txIn := range msgTx.TxIn {
+		if !spent[i].Outpoint.Equals(&Outpoint{Hash: txIn.PreviousOutPoint.Hash[:], Index: txIn.PreviousOutPoint.Index}) {
+			return nil, fmt.Errorf("spent output %d does not match the outpoint of the input", i)
+		}
+		unconfirmed.Sequences = append(unconfirmed.Sequences, txIn.Sequence)
+	}
+	for i, txOut := range msgTx.TxOut {
+		unconfirmed.Outputs = append(unconfirmed.Outputs, Output{
+			Outpoint:     Outpoint{Hash: hash[:], Index: uint32(i)},
+			Value:        xc.NewBigIntFromInt64(txOut.Value),
+			PubKeyScript: txOut.PkScript,
+		})
+	}
+	return unconfirmed, nil
+}
+
+func (tx *UnconfirmedTx) Fee() uint64 {
+	total := uint64(0)
+	for _, input := range tx.Inputs {
+		total += input.Value.Uint64()
+	}
+	for _, output := range tx.Outputs {
+		total -= output.Value.Uint64()
+	}
+	return total
+}
+
+// Whether the transaction opts in to being replaced by a transaction paying a higher fee (BIP-125)
+func (tx *UnconfirmedTx) SignalsReplaceability() bool {
+	for _, sequence := range tx.Sequences {
+		if sequence <= MaxRbfSequence {
+			return true
+		}
+	}
+	return false
+}
+
+// The input of the transaction, to check that a replacement is safe from double sending with
+// TxInput.SafeFromDoubleSend.
+func (tx *UnconfirmedTx) TxInput() *TxInput {
+	return &TxInput{
+		UnspentOutputs: tx.Inputs,
+	}
+}
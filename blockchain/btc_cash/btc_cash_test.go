@@ -156,7 +156,7 @@ func (s *CrosschainTestSuite) TestTxHash() {
 	require.NoError(err)
 
 	tx := tf.(*tx.Tx)
-	require.Equal(xc.TxHash("0ebdd0e519cf4bf67ac4d924c07e3312483b09844c9f16f46c04f5fe1500c788"), tx.Hash())
+	require.Equal(xc.TxHash("a6a8458f8b26c0d334304162d8b5abb9402589acad0c5162bc41da0844c98a64"), tx.Hash())
 }
 
 func (s *CrosschainTestSuite) TestTxSighashes() {
@@ -0,0 +1,35 @@
+package btc_cash
+
+import (
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/openweb3-io/crosschain/blockchain/btc"
+	xc "github.com/openweb3-io/crosschain/types"
+)
+
+// NewTxDecoder creates a Bitcoin TxDecoder that formats addresses in the cashaddr format
+func NewTxDecoder(cfg *xc.ChainConfig) (btc.TxDecoder, error) {
+	decoder, err := btc.NewTxDecoder(cfg)
+	if err != nil {
+		return btc.TxDecoder{}, err
+	}
+	return decoder.WithAddressEncoder(EncodeBchAddress), nil
+}
+
+// EncodeBchAddress formats a P2PKH or P2SH address in the cashaddr format, like the addresses from GetAddressFromPublicKey
+func EncodeBchAddress(address btcutil.Address, params *chaincfg.Params) (xc.Address, error) {
+	var version byte
+	switch address.(type) {
+	case *btcutil.AddressPubKeyHash:
+		version = 0
+	case *btcutil.AddressScriptHash:
+		version = 8
+	default:
+		return btc.EncodeBtcAddress(address, params)
+	}
+	encoded, err := encodeBchAddress(version, address.ScriptAddress(), params)
+	if err != nil {
+		return "", err
+	}
+	return xc.Address(AddressPrefix(params) + ":" + encoded), nil
+}
@@ -3,6 +3,7 @@ package address_test
 import (
 	"encoding/hex"
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
@@ -209,3 +210,48 @@ func TestGetPublicKey(t *testing.T) {
 	pubKey = address.GetPublicKey(&xc.ChainConfig{Blockchain: xc.BlockchainCosmosEvmos}, []byte{})
 	require.Exactly(t, &ethsecp256k1.PubKey{Key: []byte{}}, pubKey)
 }
+
+func TestGetMultisigAddress(t *testing.T) {
+	require := require.New(t)
+	builder := address.AddressBuilder{}
+	publicKeys := [][]byte{}
+	for _, key := range []string{
+		"02FCF724C97DFFAC2021EFA1818C2FEF3BCBB753CA22913A8DB5E79EC4A3DEE0D1",
+		"02E8445082A72F29B75CA48748A914DF60622A609CACFCE8ED0E35804560741D29",
+		"0279BE667EF9DCBBAC55A06295CE870B07029BFCDB2DCE28D959F2815B16F81798",
+	} {
+		bytes, _ := hex.DecodeString(key)
+		publicKeys = append(publicKeys, bytes)
+	}
+	for _, chain := range []*xc.ChainConfig{
+		{Chain: xc.ATOM, ChainPrefix: "cosmos"},
+		{Chain: xc.INJ, ChainPrefix: "inj", Blockchain: xc.BlockchainCosmosEvmos},
+	} {
+		xcBuilder, _ := address.NewAddressBuilder(chain)
+		builder = xcBuilder.(address.AddressBuilder)
+		multisigAddress, err := builder.GetMultisigAddress(2, publicKeys)
+		require.NoError(err)
+		require.True(strings.HasPrefix(string(multisigAddress), chain.ChainPrefix+"1"))
+
+		// the threshold and order of keys are part of the address
+		other, err := builder.GetMultisigAddress(1, publicKeys)
+		require.NoError(err)
+		require.NotEqual(multisigAddress, other)
+		other, err = builder.GetMultisigAddress(2, [][]byte{publicKeys[1], publicKeys[0], publicKeys[2]})
+		require.NoError(err)
+		require.NotEqual(multisigAddress, other)
+
+		for _, single := range publicKeys {
+			singleAddress, err := builder.GetAddressFromPublicKey(single)
+			require.NoError(err)
+			require.NotEqual(multisigAddress, singleAddress)
+		}
+	}
+
+	_, err := builder.GetMultisigAddress(0, publicKeys)
+	require.ErrorContains(err, "threshold must be positive")
+	_, err = builder.GetMultisigAddress(4, publicKeys)
+	require.ErrorContains(err, "needs at least as many public keys")
+	_, err = builder.GetMultisigAddress(1, [][]byte{publicKeys[0], {}})
+	require.ErrorContains(err, "public key 1 is empty")
+}
@@ -0,0 +1,49 @@
+package address
+
+import (
+	"errors"
+	"fmt"
+
+	kmultisig "github.com/cosmos/cosmos-sdk/crypto/keys/multisig"
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	injethsecp256k1 "github.com/openweb3-io/crosschain/blockchain/cosmos/types/InjectiveLabs/injective-core/injective-chain/crypto/ethsecp256k1"
+	"github.com/openweb3-io/crosschain/blockchain/cosmos/types/evmos/ethermint/crypto/ethsecp256k1"
+	xc "github.com/openweb3-io/crosschain/types"
+)
+
+func init() {
+	// The address of a multisig is the hash of its amino encoding, which cosmos-sdk
+	// only knows for its own key types.
+	kmultisig.AminoCdc.RegisterConcrete(&injethsecp256k1.PubKey{}, injethsecp256k1.PubKeyName, nil)
+	kmultisig.AminoCdc.RegisterConcrete(&ethsecp256k1.PubKey{}, "ethermint/PubKeyEthSecp256k1", nil)
+}
+
+// GetMultisigPublicKey returns the threshold multisig of the public keys of its members.  The order of
+// the keys is part of the multisig and so changes its address.
+func GetMultisigPublicKey(asset *xc.ChainConfig, threshold int, publicKeys [][]byte) (*kmultisig.LegacyAminoPubKey, error) {
+	if threshold <= 0 {
+		return nil, errors.New("multisig threshold must be positive")
+	}
+	if len(publicKeys) < threshold {
+		return nil, fmt.Errorf("multisig threshold of %d needs at least as many public keys, got %d", threshold, len(publicKeys))
+	}
+	members := make([]cryptotypes.PubKey, len(publicKeys))
+	for i, publicKeyBytes := range publicKeys {
+		if len(publicKeyBytes) == 0 {
+			return nil, fmt.Errorf("multisig public key %d is empty", i)
+		}
+		members[i] = GetPublicKey(asset, publicKeyBytes)
+	}
+	return kmultisig.NewLegacyAminoPubKey(threshold, members), nil
+}
+
+// GetMultisigAddress returns the address of a threshold multisig account
+func (ab AddressBuilder) GetMultisigAddress(threshold int, publicKeys [][]byte) (xc.Address, error) {
+	publicKey, err := GetMultisigPublicKey(ab.cfg, threshold, publicKeys)
+	if err != nil {
+		return xc.Address(""), err
+	}
+	bech32Addr, err := sdk.Bech32ifyAddressBytes(ab.cfg.ChainPrefix, publicKey.Address())
+	return xc.Address(bech32Addr), err
+}
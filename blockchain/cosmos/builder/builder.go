@@ -11,6 +11,9 @@ import (
 	"cosmossdk.io/math"
 	wasmtypes "github.com/CosmWasm/wasmd/x/wasm/types"
 	"github.com/cosmos/cosmos-sdk/client"
+	kmultisig "github.com/cosmos/cosmos-sdk/crypto/keys/multisig"
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
+	"github.com/cosmos/cosmos-sdk/crypto/types/multisig"
 	"github.com/cosmos/cosmos-sdk/types"
 	signingtypes "github.com/cosmos/cosmos-sdk/types/tx/signing"
 	authsigning "github.com/cosmos/cosmos-sdk/x/auth/signing"
@@ -35,6 +38,7 @@ type TxBuilder struct {
 }
 
 var _ xcbuilder.FullBuilder = &TxBuilder{}
+var _ xcbuilder.TxBatchBuilder = &TxBuilder{}
 
 // NewTxBuilder creates a new Cosmos TxBuilder
 func NewTxBuilder(chain *xc.ChainConfig) (TxBuilder, error) {
@@ -126,6 +130,66 @@ func (txBuilder TxBuilder) NewBankTransfer(args *xcbuilder.TransferArgs, input x
 	fees := txBuilder.calculateFees(asset, args.GetAmount(), txInput, true)
 	return txBuilder.createTxWithMsg(txInput, msgSend, txArgs{
 		Memo:          txInput.LegacyMemo,
+		From:          args.GetFrom(),
+		FromPublicKey: txInput.LegacyFromPublicKey,
+	}, fees)
+}
+
+// x/bank MsgMultiSend transfer, paying every recipient from a single input
+func (txBuilder TxBuilder) NewBatchTransfer(args *xcbuilder.BatchTransferArgs, input xc.TxInput) (xc.Tx, error) {
+	txInput := input.(*tx_input.TxInput)
+	if txInput.AssetType != tx_input.BANK {
+		return nil, errors.New("batch transfers are only supported for x/bank assets, not " + string(txInput.AssetType))
+	}
+	max := txBuilder.Chain.ChainMaxGasPrice
+	if max <= 0 {
+		max = DefaultMaxGasPrice(txBuilder.Chain)
+	}
+	if txInput.GasPrice > max {
+		txInput.GasPrice = max
+	}
+
+	asset, _ := args.GetAsset()
+	if asset == nil {
+		asset = txBuilder.Chain
+	}
+
+	recipients := args.GetRecipients()
+	if txInput.GasLimit == 0 {
+		txInput.GasLimit = gas.MultiSendGasLimit(len(recipients))
+	}
+
+	denom := txBuilder.GetDenom(asset)
+	coins := func(amount xc.BigInt) types.Coins {
+		return types.Coins{
+			{
+				Denom:  denom,
+				Amount: math.NewIntFromBigInt(amount.Int()),
+			},
+		}
+	}
+	total := args.GetTotalAmount()
+	outputs := make([]banktypes.Output, len(recipients))
+	for i, recipient := range recipients {
+		outputs[i] = banktypes.Output{
+			Address: string(recipient.To),
+			Coins:   coins(recipient.Amount),
+		}
+	}
+	msgMultiSend := &banktypes.MsgMultiSend{
+		Inputs: []banktypes.Input{
+			{
+				Address: string(args.GetFrom()),
+				Coins:   coins(total),
+			},
+		},
+		Outputs: outputs,
+	}
+
+	fees := txBuilder.calculateFees(asset, total, txInput, true)
+	return txBuilder.createTxWithMsg(txInput, msgMultiSend, txArgs{
+		Memo:          txInput.LegacyMemo,
+		From:          args.GetFrom(),
 		FromPublicKey: txInput.LegacyFromPublicKey,
 	}, fees)
 }
@@ -153,6 +217,7 @@ func (txBuilder TxBuilder) NewCW20Transfer(args *xcbuilder.TransferArgs, input x
 
 	return txBuilder.createTxWithMsg(txInput, msgSend, txArgs{
 		Memo:          txInput.LegacyMemo,
+		From:          args.GetFrom(),
 		FromPublicKey: txInput.LegacyFromPublicKey,
 	}, fees)
 }
@@ -193,7 +258,7 @@ func (txBuilder TxBuilder) calculateFees(asset xc.IAsset, amount xc.BigInt, inpu
 	feeCoins := types.Coins{
 		{
 			Denom:  gasDenom,
-			Amount: math.NewIntFromUint64(uint64(input.GasPrice * float64(input.GasLimit))),
+			Amount: math.NewIntFromUint64(uint64(input.GasPrice * float64(gasLimit(input)))),
 		},
 	}
 	if includeTax {
@@ -225,8 +290,17 @@ func (txBuilder TxBuilder) calculateFees(asset xc.IAsset, amount xc.BigInt, inpu
 	return feeCoins
 }
 
+// The gas limit of the input, plus what the signatures of a multisig sender need
+func gasLimit(input *tx_input.TxInput) uint64 {
+	if input.Multisig != nil {
+		return input.GasLimit + gas.MultisigGasLimit(len(input.Multisig.PublicKeys))
+	}
+	return input.GasLimit
+}
+
 type txArgs struct {
 	Memo          string
+	From          xc.Address
 	FromPublicKey []byte
 }
 
@@ -241,18 +315,36 @@ func (txBuilder TxBuilder) createTxWithMsg(input *tx_input.TxInput, msg types.Ms
 	}
 
 	cosmosBuilder.SetMemo(args.Memo)
-	cosmosBuilder.SetGasLimit(input.GasLimit)
-
+	cosmosBuilder.SetGasLimit(gasLimit(input))
 	cosmosBuilder.SetFeeAmount(fees)
 
 	sigMode := signingtypes.SignMode_SIGN_MODE_DIRECT
+	var pubKey cryptotypes.PubKey
+	var sigData signingtypes.SignatureData
+	var multisigPubKey *kmultisig.LegacyAminoPubKey
+	signers := []xc.Address{args.From}
+	if input.Multisig != nil {
+		// Members of a multisig sign without knowing which of the others will, which is
+		// part of the sign doc in direct mode.
+		sigMode = signingtypes.SignMode_SIGN_MODE_LEGACY_AMINO_JSON
+		multisigPubKey, signers, err = txBuilder.getMultisig(input.Multisig, args.From)
+		if err != nil {
+			return nil, err
+		}
+		pubKey = multisigPubKey
+		sigData = multisig.NewMultisig(len(signers))
+	} else {
+		pubKey = address.GetPublicKey(txBuilder.Chain, args.FromPublicKey)
+		sigData = &signingtypes.SingleSignatureData{
+			SignMode:  sigMode,
+			Signature: nil,
+		}
+	}
+
 	sigsV2 := []signingtypes.SignatureV2{
 		{
-			PubKey: address.GetPublicKey(txBuilder.Chain, args.FromPublicKey),
-			Data: &signingtypes.SingleSignatureData{
-				SignMode:  sigMode,
-				Signature: nil,
-			},
+			PubKey:   pubKey,
+			Data:     sigData,
 			Sequence: input.Sequence,
 		},
 	}
@@ -267,6 +359,7 @@ func (txBuilder TxBuilder) createTxWithMsg(input *tx_input.TxInput, msg types.Ms
 	}
 
 	signerData := authsigning.SignerData{
+		Address:       string(args.From),
 		AccountNumber: input.AccountNumber,
 		ChainID:       chainId,
 		Sequence:      input.Sequence,
@@ -285,5 +378,35 @@ func (txBuilder TxBuilder) createTxWithMsg(input *tx_input.TxInput, msg types.Ms
 		CosmosTxEncoder: cosmosTxConfig.TxEncoder(),
 		SigsV2:          sigsV2,
 		TxDataToSign:    sighash,
+		Signers:         signers,
+		Multisig:        multisigPubKey,
+		SignBytes:       sighashData,
 	}, nil
 }
+
+// getMultisig returns the public key of the multisig sending the transaction and the addresses of its members
+func (txBuilder TxBuilder) getMultisig(input *tx_input.Multisig, from xc.Address) (*kmultisig.LegacyAminoPubKey, []xc.Address, error) {
+	multisigPubKey, err := address.GetMultisigPublicKey(txBuilder.Chain, int(input.Threshold), input.PublicKeys)
+	if err != nil {
+		return nil, nil, err
+	}
+	multisigAddress, err := types.Bech32ifyAddressBytes(txBuilder.Chain.ChainPrefix, multisigPubKey.Address())
+	if err != nil {
+		return nil, nil, err
+	}
+	if xc.Address(multisigAddress) != from {
+		return nil, nil, fmt.Errorf("multisig of the input has address %s, not that of the sender %s", multisigAddress, from)
+	}
+	addressBuilder, err := address.NewAddressBuilder(txBuilder.Chain)
+	if err != nil {
+		return nil, nil, err
+	}
+	members := make([]xc.Address, len(input.PublicKeys))
+	for i, publicKey := range input.PublicKeys {
+		members[i], err = addressBuilder.GetAddressFromPublicKey(publicKey)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	return multisigPubKey, members, nil
+}
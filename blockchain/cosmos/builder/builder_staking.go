@@ -48,6 +48,7 @@ func (txBuilder TxBuilder) Stake(args xcbuilder.StakeArgs, input xc.StakeTxInput
 
 	return txBuilder.createTxWithMsg(&stakeInput.TxInput, msg, txArgs{
 		Memo:          memo,
+		From:          args.GetFrom(),
 		FromPublicKey: pubkey,
 	}, fees)
 }
@@ -87,6 +88,7 @@ func (txBuilder TxBuilder) Unstake(args xcbuilder.StakeArgs, input xc.UnstakeTxI
 
 	return txBuilder.createTxWithMsg(&stakeInput.TxInput, msg, txArgs{
 		Memo:          memo,
+		From:          args.GetFrom(),
 		FromPublicKey: pubkey,
 	}, fees)
 }
@@ -125,6 +127,7 @@ func (txBuilder TxBuilder) Withdraw(args xcbuilder.StakeArgs, input xc.WithdrawT
 
 	return txBuilder.createTxWithMsg(&withdrawInput.TxInput, msg, txArgs{
 		Memo:          memo,
+		From:          args.GetFrom(),
 		FromPublicKey: pubkey,
 	}, fees)
 }
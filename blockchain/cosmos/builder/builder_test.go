@@ -4,14 +4,19 @@ import (
 
 	// "github.com/cosmos/cosmos-sdk/types/tx"
 
+	"fmt"
 	"testing"
 
 	"github.com/cosmos/cosmos-sdk/types"
+	signingtypes "github.com/cosmos/cosmos-sdk/types/tx/signing"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	"github.com/openweb3-io/crosschain/blockchain/cosmos/address"
 	"github.com/openweb3-io/crosschain/blockchain/cosmos/builder"
 	"github.com/openweb3-io/crosschain/blockchain/cosmos/tx"
 	"github.com/openweb3-io/crosschain/blockchain/cosmos/tx_input"
 	"github.com/openweb3-io/crosschain/blockchain/cosmos/tx_input/gas"
 	xcbuilder "github.com/openweb3-io/crosschain/builder"
+	"github.com/openweb3-io/crosschain/factory/signer"
 	xc "github.com/openweb3-io/crosschain/types"
 	"github.com/stretchr/testify/require"
 )
@@ -155,3 +160,123 @@ func TestTransferWithMaxGasPrice(t *testing.T) {
 
 	}
 }
+
+func TestBatchTransfer(t *testing.T) {
+	require := require.New(t)
+	chain := &xc.ChainConfig{
+		Chain:       "XPLA",
+		ChainCoin:   "axpla",
+		ChainPrefix: "xpla",
+	}
+	txBuilder, err := builder.NewTxBuilder(chain)
+	require.NoError(err)
+
+	from := xc.Address("xpla1hdvf6vv5amc7wp84js0ls27apekwxpr0ge96kg")
+	args, err := xcbuilder.NewBatchTransferArgs(from, []xcbuilder.Recipient{
+		{To: "xpla1q8hwmpvyv7mh6qyvctsdms5flwxvfa3j9v3rd4", Amount: xc.NewBigIntFromUint64(100)},
+		{To: "xpla1hdvf6vv5amc7wp84js0ls27apekwxpr0ge96kg", Amount: xc.NewBigIntFromUint64(250)},
+	})
+	require.NoError(err)
+
+	input := tx_input.NewTxInput()
+	input.AssetType = tx_input.BANK
+	xcTx, err := txBuilder.NewBatchTransfer(args, input)
+	require.NoError(err)
+	require.Equal(gas.MultiSendGasLimit(2), input.GasLimit)
+
+	msgs := xcTx.(*tx.Tx).CosmosTx.GetMsgs()
+	require.Len(msgs, 1)
+	multiSend := msgs[0].(*banktypes.MsgMultiSend)
+	require.Len(multiSend.Inputs, 1)
+	require.Equal(string(from), multiSend.Inputs[0].Address)
+	require.EqualValues(350, multiSend.Inputs[0].Coins.AmountOf("axpla").Uint64())
+	require.Len(multiSend.Outputs, 2)
+	require.Equal("xpla1q8hwmpvyv7mh6qyvctsdms5flwxvfa3j9v3rd4", multiSend.Outputs[0].Address)
+	require.EqualValues(250, multiSend.Outputs[1].Coins.AmountOf("axpla").Uint64())
+
+	requests, err := xcTx.(*tx.Tx).SignatureRequests()
+	require.NoError(err)
+	require.Len(requests, 1)
+	require.Equal(from, requests[0].Signer)
+	require.Equal(xc.K256Keccak, requests[0].Algorithm)
+
+	input.AssetType = tx_input.CW20
+	_, err = txBuilder.NewBatchTransfer(args, input)
+	require.Error(err)
+}
+
+func TestMultisigTransfer(t *testing.T) {
+	require := require.New(t)
+	for _, chain := range []*xc.ChainConfig{
+		{Chain: xc.ATOM, ChainCoin: "uatom", ChainPrefix: "cosmos", Blockchain: xc.BlockchainCosmos},
+		{Chain: xc.INJ, ChainCoin: "inj", ChainPrefix: "inj", Blockchain: xc.BlockchainCosmosEvmos},
+	} {
+		signers := []*signer.Signer{}
+		publicKeys := [][]byte{}
+		for i := 1; i <= 3; i++ {
+			s, err := signer.New(chain.Blockchain, fmt.Sprintf("%064x", i), chain)
+			require.NoError(err)
+			signers = append(signers, s)
+			publicKeys = append(publicKeys, s.MustPublicKey())
+		}
+		addressBuilder, err := address.NewAddressBuilder(chain)
+		require.NoError(err)
+		from, err := addressBuilder.(address.AddressBuilder).GetMultisigAddress(2, publicKeys)
+		require.NoError(err)
+
+		txBuilder, err := builder.NewTxBuilder(chain)
+		require.NoError(err)
+		args, err := xcbuilder.NewTransferArgs(from, from, xc.NewBigIntFromUint64(100))
+		require.NoError(err)
+		input := tx_input.NewTxInput()
+		input.AssetType = tx_input.BANK
+		input.GasPrice = 1
+		input.SetMultisig(2, publicKeys)
+		xcTx, err := txBuilder.NewTransfer(args, input)
+		require.NoError(err)
+		cosmosTx := xcTx.(*tx.Tx)
+		require.EqualValues(gas.NativeTransferGasLimit+gas.MultisigGasLimit(3), cosmosTx.CosmosTxBuilder.GetTx().GetGas())
+
+		// each member signs the same payload
+		requests, err := cosmosTx.SignatureRequests()
+		require.NoError(err)
+		require.Len(requests, 3)
+		for i, request := range requests {
+			member, err := addressBuilder.GetAddressFromPublicKey(publicKeys[i])
+			require.NoError(err)
+			require.Equal(member, request.Signer)
+			require.EqualValues(cosmosTx.TxDataToSign, request.Payload)
+		}
+
+		// collected over several rounds
+		sig0, err := signers[0].Sign(requests[0].Payload)
+		require.NoError(err)
+		sig2, err := signers[2].Sign(requests[2].Payload)
+		require.NoError(err)
+		require.NoError(cosmosTx.AddSignatures(sig0, nil, nil))
+		_, err = cosmosTx.Serialize()
+		require.ErrorContains(err, "multisig has 1 of 2 required signatures")
+		require.ErrorContains(cosmosTx.AddSignatures(nil, sig0, nil), "invalid signature from multisig member 1")
+		require.Error(cosmosTx.AddSignatures(sig2))
+		require.NoError(cosmosTx.AddSignatures(nil, nil, sig2))
+		require.Equal(2, cosmosTx.MultisigSignatureCount())
+		_, err = cosmosTx.Serialize()
+		require.NoError(err)
+
+		// the combined signature is valid for the multisig
+		multiSig := cosmosTx.SigsV2[0].Data.(*signingtypes.MultiSignatureData)
+		require.True(multiSig.BitArray.GetIndex(0))
+		require.False(multiSig.BitArray.GetIndex(1))
+		require.True(multiSig.BitArray.GetIndex(2))
+		err = cosmosTx.Multisig.VerifyMultisignature(func(mode signingtypes.SignMode) ([]byte, error) {
+			require.Equal(signingtypes.SignMode_SIGN_MODE_LEGACY_AMINO_JSON, mode)
+			return cosmosTx.SignBytes, nil
+		}, multiSig)
+		require.NoError(err)
+
+		// the multisig must be the sender
+		input.SetMultisig(2, publicKeys[1:])
+		_, err = txBuilder.NewTransfer(args, input)
+		require.ErrorContains(err, "not that of the sender")
+	}
+}
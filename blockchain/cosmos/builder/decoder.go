@@ -0,0 +1,125 @@
+package builder
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	wasmtypes "github.com/CosmWasm/wasmd/x/wasm/types"
+	"github.com/cosmos/cosmos-sdk/types"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+	"github.com/openweb3-io/crosschain/blockchain/cosmos/tx"
+	xclient "github.com/openweb3-io/crosschain/client"
+	xc "github.com/openweb3-io/crosschain/types"
+)
+
+// TxDecoder for Cosmos
+type TxDecoder struct {
+	Chain *xc.ChainConfig
+}
+
+var _ xclient.TxDecoder = &TxDecoder{}
+
+func NewTxDecoder(chain *xc.ChainConfig) (TxDecoder, error) {
+	return TxDecoder{
+		Chain: chain,
+	}, nil
+}
+
+// DecodeTx decodes the bank, cw20 and staking messages of the transaction, with its memo and fee.
+// Any other message is rejected.
+func (decoder TxDecoder) DecodeTx(xcTx xc.Tx) (*xclient.TxInfo, error) {
+	cosmosTx, ok := xcTx.(*tx.Tx)
+	if !ok {
+		return nil, fmt.Errorf("expected cosmos transaction, got %T", xcTx)
+	}
+	if cosmosTx.CosmosTx == nil {
+		return nil, errors.New("transaction not initialized")
+	}
+	info := xclient.NewTxInfo(nil, decoder.Chain.Chain, string(cosmosTx.Hash()), 0, nil)
+	memo := ""
+	if withMemo, ok := cosmosTx.CosmosTx.(types.TxWithMemo); ok {
+		memo = withMemo.GetMemo()
+	}
+
+	feePayer := xc.Address("")
+	for _, msg := range cosmosTx.CosmosTx.GetMsgs() {
+		switch msg := msg.(type) {
+		case *banktypes.MsgSend:
+			for _, coin := range msg.Amount {
+				info.AddSimpleTransfer(xc.Address(msg.FromAddress), xc.Address(msg.ToAddress), decoder.contractFromDenom(coin.Denom), xc.BigInt(*coin.Amount.BigInt()), nil, memo)
+			}
+			feePayer = firstAddress(feePayer, msg.FromAddress)
+		case *banktypes.MsgMultiSend:
+			transfer := xclient.NewTransfer(decoder.Chain.Chain)
+			transfer.SetMemo(memo)
+			for _, input := range msg.Inputs {
+				for _, coin := range input.Coins {
+					transfer.AddSource(xc.Address(input.Address), decoder.contractFromDenom(coin.Denom), xc.BigInt(*coin.Amount.BigInt()), nil)
+				}
+				feePayer = firstAddress(feePayer, input.Address)
+			}
+			for _, output := range msg.Outputs {
+				for _, coin := range output.Coins {
+					transfer.AddDestination(xc.Address(output.Address), decoder.contractFromDenom(coin.Denom), xc.BigInt(*coin.Amount.BigInt()), nil)
+				}
+			}
+			info.AddTransfer(transfer)
+		case *wasmtypes.MsgExecuteContract:
+			var cw20 tx.Cw20MsgTransfer
+			if err := json.Unmarshal(msg.Msg, &cw20); err != nil || cw20.Transfer == nil {
+				return nil, fmt.Errorf("cannot decode execution of contract %s", msg.Contract)
+			}
+			if len(msg.Funds) > 0 {
+				return nil, fmt.Errorf("cannot decode funds sent to contract %s", msg.Contract)
+			}
+			amount := xc.NewBigIntFromStr(cw20.Transfer.Amount)
+			info.AddSimpleTransfer(xc.Address(msg.Sender), xc.Address(cw20.Transfer.Recipient), xc.ContractAddress(msg.Contract), amount, nil, memo)
+			feePayer = firstAddress(feePayer, msg.Sender)
+		case *stakingtypes.MsgDelegate:
+			info.Stakes = append(info.Stakes, &xclient.Stake{
+				Balance:   xc.BigInt(*msg.Amount.Amount.BigInt()),
+				Validator: msg.ValidatorAddress,
+				Address:   msg.DelegatorAddress,
+			})
+			feePayer = firstAddress(feePayer, msg.DelegatorAddress)
+		case *stakingtypes.MsgUndelegate:
+			info.Unstakes = append(info.Unstakes, &xclient.Unstake{
+				Balance:   xc.BigInt(*msg.Amount.Amount.BigInt()),
+				Validator: msg.ValidatorAddress,
+				Address:   msg.DelegatorAddress,
+			})
+			feePayer = firstAddress(feePayer, msg.DelegatorAddress)
+		default:
+			return nil, fmt.Errorf("cannot decode message %s", types.MsgTypeURL(msg))
+		}
+	}
+
+	// without a fee granter, the fee is paid by the first signer, which is the sender of the first message
+	if feeTx, ok := cosmosTx.CosmosTx.(types.FeeTx); ok {
+		if len(feeTx.FeeGranter()) > 0 || (feePayer == "" && !feeTx.GetFee().IsZero()) {
+			return nil, errors.New("cannot decode the payer of the fee")
+		}
+		for _, coin := range feeTx.GetFee() {
+			info.AddFee(feePayer, decoder.contractFromDenom(coin.Denom), xc.BigInt(*coin.Amount.BigInt()), nil)
+		}
+	}
+	info.Fees = info.CalculateFees()
+	return info, nil
+}
+
+// Native denoms are reported as the chain asset, anything else is reported as a contract
+func (decoder TxDecoder) contractFromDenom(denom string) xc.ContractAddress {
+	if denom == decoder.Chain.ChainCoin {
+		return ""
+	}
+	return xc.ContractAddress(denom)
+}
+
+func firstAddress(current xc.Address, address string) xc.Address {
+	if current != "" {
+		return current
+	}
+	return xc.Address(address)
+}
@@ -41,6 +41,7 @@ func (txBuilder TxBuilder) NewTask(args *xcbuilder.TransferArgs, input xc.TxInpu
 		fees := txBuilder.calculateFees(asset, args.GetAmount(), txInput, false)
 		return txBuilder.createTxWithMsg(txInput, msgUndelegate, txArgs{
 			Memo:          txInput.LegacyMemo,
+			From:          args.GetFrom(),
 			FromPublicKey: txInput.LegacyFromPublicKey,
 		}, fees)
 	}
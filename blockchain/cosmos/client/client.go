@@ -17,6 +17,7 @@ import (
 	xcbuilder "github.com/openweb3-io/crosschain/builder"
 
 	"github.com/cosmos/cosmos-sdk/client"
+	kmultisig "github.com/cosmos/cosmos-sdk/crypto/keys/multisig"
 	"github.com/cosmos/cosmos-sdk/types"
 	"github.com/cosmos/cosmos-sdk/types/query"
 	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
@@ -43,6 +44,8 @@ type Client struct {
 
 var _ xclient.IClient = &Client{}
 var _ xclient.StakingClient = &Client{}
+var _ xclient.NonceClient = &Client{}
+var _ xclient.BatchTransferClient = &Client{}
 
 func ReplaceIncompatiableCosmosResponses(body []byte) []byte {
 	bodyStr := string(body)
@@ -130,6 +133,25 @@ func (client *Client) FetchTransferInput(ctx context.Context, args *xcbuilder.Tr
 	return baseTxInput, nil
 }
 
+func (client *Client) FetchBatchTransferInput(ctx context.Context, args *xcbuilder.BatchTransferArgs) (xc.TxInput, error) {
+	asset, _ := args.GetAsset()
+	txInput, err := client.FetchBaseTxInput(ctx, args.GetFrom(), asset)
+	if err != nil {
+		return nil, err
+	}
+	txInput.GasLimit = gas.MultiSendGasLimit(len(args.GetRecipients()))
+	return txInput, nil
+}
+
+// The sequence of the account, which only counts committed transactions
+func (client *Client) FetchNonce(ctx context.Context, address xc.Address) (uint64, error) {
+	account, err := client.GetAccount(ctx, address)
+	if err != nil || account == nil {
+		return 0, fmt.Errorf("failed to get account data for %v: %v", address, err)
+	}
+	return account.GetSequence(), nil
+}
+
 func (client *Client) FetchBaseTxInput(ctx context.Context, from xc.Address, asset xc.IAsset) (*tx_input.TxInput, error) {
 	txInput := tx_input.NewTxInput()
 
@@ -139,6 +161,14 @@ func (client *Client) FetchBaseTxInput(ctx context.Context, from xc.Address, ass
 	}
 	txInput.AccountNumber = account.GetAccountNumber()
 	txInput.Sequence = account.GetSequence()
+	// accounts that have sent before record their public key, which tells a multisig apart
+	if multisigPubKey, ok := account.GetPubKey().(*kmultisig.LegacyAminoPubKey); ok {
+		publicKeys := [][]byte{}
+		for _, member := range multisigPubKey.GetPubKeys() {
+			publicKeys = append(publicKeys, member.Bytes())
+		}
+		txInput.SetMultisig(multisigPubKey.Threshold, publicKeys)
+	}
 
 	var assetI xc.IAsset
 	if asset != nil {
@@ -325,15 +355,110 @@ func (client *Client) FetchLegacyTxInfo(ctx context.Context, txHash xc.TxHash) (
 	return result, nil
 }
 
+// Native denoms are reported as the chain asset, anything else is reported as a contract
+func (client *Client) contractFromDenom(denom string) xc.ContractAddress {
+	if denom == client.Chain.ChainCoin {
+		return ""
+	}
+	return xc.ContractAddress(denom)
+}
+
 func (client *Client) FetchTxInfo(ctx context.Context, txHashStr xc.TxHash) (*xclient.TxInfo, error) {
-	legacyTx, err := client.FetchLegacyTxInfo(ctx, txHashStr)
+	txHash := strings.TrimPrefix(string(txHashStr), "0x")
+	hash, err := hex.DecodeString(txHash)
 	if err != nil {
 		return nil, err
 	}
+
+	var hashFormatted interface{} = hash
+	switch client.Chain.Chain {
+	case xc.SEI:
+		// Frustratingly, SEI expects the hash as a hex encoded string
+		hashFormatted = hex.EncodeToString(hash)
+	}
+
+	resultRaw := new(comettypes.ResultTx)
+	_, err = client.rpcClient.Call(ctx, "tx", map[string]interface{}{
+		"hash":  hashFormatted,
+		"prove": false,
+	}, resultRaw)
+	if err != nil {
+		return nil, fmt.Errorf("could not download tx: %v", err)
+	}
+
+	abciInfo, err := client.Ctx.Client.ABCIInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	blockResultRaw, err := client.Ctx.Client.Block(ctx, &resultRaw.Height)
+	if err != nil {
+		return nil, err
+	}
+
+	return client.toTxInfo(txHash, resultRaw, blockResultRaw, abciInfo.Response.LastBlockHeight)
+}
+
+func (client *Client) toTxInfo(txHash string, resultRaw *comettypes.ResultTx, blockResultRaw *comettypes.ResultBlock, lastBlockHeight int64) (*xclient.TxInfo, error) {
 	chain := client.Chain.Chain
+	decodedTx, err := client.Ctx.TxConfig.TxDecoder()(resultRaw.Tx)
+	if err != nil {
+		return nil, err
+	}
+
+	var errMsg *string
+	if resultRaw.TxResult.Code != 0 {
+		msg := fmt.Sprintf("transaction failed with code %d: %s", resultRaw.TxResult.Code, resultRaw.TxResult.Log)
+		errMsg = &msg
+	}
+	confirmations := uint64(0)
+	if lastBlockHeight >= resultRaw.Height {
+		confirmations = uint64(lastBlockHeight-resultRaw.Height) + 1
+	}
+	block := xclient.NewBlock(
+		uint64(resultRaw.Height),
+		blockResultRaw.BlockID.Hash.String(),
+		blockResultRaw.Block.Header.Time,
+	)
+	txInfo := xclient.NewTxInfo(block, chain, txHash, confirmations, errMsg)
+
+	memo := ""
+	if withMemo, ok := decodedTx.(types.TxWithMemo); ok {
+		memo = withMemo.GetMemo()
+	}
+
+	events := ParseEvents(resultRaw.TxResult.Events)
+	for _, ev := range events.Transfers {
+		txInfo.AddSimpleTransfer(xc.Address(ev.Sender), xc.Address(ev.Recipient), client.contractFromDenom(ev.Contract), ev.Amount, nil, memo)
+	}
+
+	if feeTx, ok := decodedTx.(types.FeeTx); ok {
+		payer, err := types.Bech32ifyAddressBytes(client.Prefix, feeTx.FeePayer())
+		if err != nil {
+			return nil, fmt.Errorf("could not encode fee payer: %v", err)
+		}
+		for _, coin := range feeTx.GetFee() {
+			txInfo.AddFee(xc.Address(payer), client.contractFromDenom(coin.Denom), xc.BigInt(*coin.Amount.BigInt()), nil)
+		}
+	}
+	txInfo.Fees = txInfo.CalculateFees()
+
+	for _, ev := range events.Delegates {
+		txInfo.Stakes = append(txInfo.Stakes, &xclient.Stake{
+			Balance:   ev.Amount,
+			Validator: ev.Validator,
+			Address:   ev.Delegator,
+		})
+	}
+	for _, ev := range events.Unbonds {
+		txInfo.Unstakes = append(txInfo.Unstakes, &xclient.Unstake{
+			Balance:   ev.Amount,
+			Validator: ev.Validator,
+			Address:   ev.Delegator,
+		})
+	}
 
-	// remap to new tx
-	return xclient.TxInfoFromLegacy(chain, legacyTx, xclient.Account), nil
+	return txInfo, nil
 }
 
 // GetAccount returns a Cosmos account
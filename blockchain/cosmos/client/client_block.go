@@ -0,0 +1,63 @@
+package client
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+
+	xclient "github.com/openweb3-io/crosschain/client"
+)
+
+var _ xclient.BlockClient = &Client{}
+
+// Number of transactions to search for at a time when fetching a block
+const blockTxsPerPage = 100
+
+func (client *Client) FetchLatestHeight(ctx context.Context) (uint64, error) {
+	abciInfo, err := client.Ctx.Client.ABCIInfo(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return uint64(abciInfo.Response.LastBlockHeight), nil
+}
+
+func (client *Client) FetchBlock(ctx context.Context, height uint64) (*xclient.BlockWithTransactions, error) {
+	latestHeight, err := client.FetchLatestHeight(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if height > latestHeight {
+		return nil, fmt.Errorf("block %d is not yet available", height)
+	}
+
+	blockHeight := int64(height)
+	blockResultRaw, err := client.Ctx.Client.Block(ctx, &blockHeight)
+	if err != nil {
+		return nil, err
+	}
+
+	// the results of the transactions are needed as well, which the tx search includes
+	txs := []*xclient.TxInfo{}
+	query := fmt.Sprintf("tx.height=%d", height)
+	for page := 1; ; page++ {
+		perPage := blockTxsPerPage
+		search, err := client.Ctx.Client.TxSearch(ctx, query, false, &page, &perPage, "asc")
+		if err != nil {
+			return nil, fmt.Errorf("could not search transactions: %v", err)
+		}
+		for _, result := range search.Txs {
+			hash := hex.EncodeToString(result.Hash)
+			txInfo, err := client.toTxInfo(hash, result, blockResultRaw, int64(latestHeight))
+			if err != nil {
+				return nil, fmt.Errorf("could not parse transaction %s: %v", hash, err)
+			}
+			txs = append(txs, txInfo)
+		}
+		if len(search.Txs) == 0 || page*perPage >= search.TotalCount {
+			break
+		}
+	}
+
+	block := xclient.NewBlock(height, blockResultRaw.BlockID.Hash.String(), blockResultRaw.Block.Header.Time)
+	return xclient.NewBlockWithTransactions(block, blockResultRaw.Block.Header.LastBlockID.Hash.String(), txs), nil
+}
@@ -0,0 +1,139 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+
+	comettypes "github.com/cometbft/cometbft/rpc/core/types"
+	xclient "github.com/openweb3-io/crosschain/client"
+)
+
+var _ xclient.HistoryClient = &Client{}
+
+// Fetch the transactions of an address by searching for transactions it either sent or received coins in.
+// Both searches are merged by recency and capped at the limit.  The cursor holds the number of results
+// consumed from each search, as "<sent>:<received>".
+func (client *Client) FetchTransactionsByAddress(ctx context.Context, args xclient.TransactionHistoryArgs) (*xclient.TransactionHistoryPage, error) {
+	address := args.GetAddress()
+	queries := []string{
+		fmt.Sprintf("message.sender='%s'", address),
+		fmt.Sprintf("transfer.recipient='%s'", address),
+	}
+	offsets := make([]int, len(queries))
+	if cursor, ok := args.GetCursor(); ok {
+		parts := strings.Split(cursor, ":")
+		if len(parts) != len(queries) {
+			return nil, fmt.Errorf("invalid cursor: %s", cursor)
+		}
+		for i, part := range parts {
+			offset, err := strconv.Atoi(part)
+			if err != nil || offset < 0 {
+				return nil, fmt.Errorf("invalid cursor: %s", cursor)
+			}
+			offsets[i] = offset
+		}
+	}
+	limit := args.GetLimit()
+
+	abciInfo, err := client.Ctx.Client.ABCIInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	streams := make([][]*comettypes.ResultTx, len(queries))
+	totals := make([]int, len(queries))
+	for i, query := range queries {
+		streams[i], totals[i], err = client.searchTxs(ctx, query, offsets[i], limit)
+		if err != nil {
+			return nil, fmt.Errorf("could not search transactions: %v", err)
+		}
+	}
+
+	// merge the searches, most recent first; a transaction found by both is consumed from both
+	results := []*comettypes.ResultTx{}
+	for len(results) < limit {
+		var next *comettypes.ResultTx
+		for _, stream := range streams {
+			if len(stream) > 0 && (next == nil || isMoreRecent(stream[0], next)) {
+				next = stream[0]
+			}
+		}
+		if next == nil {
+			break
+		}
+		for i, stream := range streams {
+			if len(stream) > 0 && bytes.Equal(stream[0].Hash, next.Hash) {
+				streams[i] = stream[1:]
+				offsets[i]++
+			}
+		}
+		results = append(results, next)
+	}
+
+	txs := []*xclient.TxInfo{}
+	blocks := map[int64]*comettypes.ResultBlock{}
+	for _, result := range results {
+		hash := hex.EncodeToString(result.Hash)
+		block, ok := blocks[result.Height]
+		if !ok {
+			block, err = client.Ctx.Client.Block(ctx, &result.Height)
+			if err != nil {
+				return nil, err
+			}
+			blocks[result.Height] = block
+		}
+		txInfo, err := client.toTxInfo(hash, result, block, abciInfo.Response.LastBlockHeight)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse transaction %s: %v", hash, err)
+		}
+		txs = append(txs, txInfo)
+	}
+	if contract, ok := args.GetContract(); ok {
+		txs = xclient.FilterByContract(txs, contract)
+	}
+
+	nextCursor := ""
+	for i := range queries {
+		if offsets[i] < totals[i] {
+			nextCursor = fmt.Sprintf("%d:%d", offsets[0], offsets[1])
+		}
+	}
+	return xclient.NewTransactionHistoryPage(txs, nextCursor), nil
+}
+
+// Search for up to count transactions matching the query, newest first, skipping the first offset results.
+// Returns the total number of matching transactions.
+func (client *Client) searchTxs(ctx context.Context, query string, offset int, count int) ([]*comettypes.ResultTx, int, error) {
+	perPage := count
+	page := offset/perPage + 1
+	skip := offset % perPage
+	results := []*comettypes.ResultTx{}
+	for {
+		search, err := client.Ctx.Client.TxSearch(ctx, query, false, &page, &perPage, "desc")
+		if err != nil {
+			return nil, 0, err
+		}
+		if skip < len(search.Txs) {
+			results = append(results, search.Txs[skip:]...)
+		}
+		skip = 0
+		if len(results) >= count || page*perPage >= search.TotalCount {
+			if len(results) > count {
+				results = results[:count]
+			}
+			return results, search.TotalCount, nil
+		}
+		page++
+	}
+}
+
+func isMoreRecent(a, b *comettypes.ResultTx) bool {
+	if a.Height != b.Height {
+		return a.Height > b.Height
+	}
+	return a.Index > b.Index
+}
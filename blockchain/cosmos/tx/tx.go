@@ -4,13 +4,17 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"errors"
+	"fmt"
 
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/openweb3-io/crosschain/blockchain/cosmos/address"
+	"github.com/openweb3-io/crosschain/factory/blockchains/registry"
 	xc "github.com/openweb3-io/crosschain/types"
 
 	"github.com/cometbft/cometbft/crypto/tmhash"
 	"github.com/cosmos/cosmos-sdk/client"
+	kmultisig "github.com/cosmos/cosmos-sdk/crypto/keys/multisig"
+	"github.com/cosmos/cosmos-sdk/crypto/types/multisig"
 	"github.com/cosmos/cosmos-sdk/types"
 	signingtypes "github.com/cosmos/cosmos-sdk/types/tx/signing"
 )
@@ -25,9 +29,21 @@ type Tx struct {
 	SigsV2          []signingtypes.SignatureV2
 	InputSignatures []xc.TxSignature
 	TxDataToSign    []byte
+	// The address behind each of SigsV2, or each member of a multisig sender
+	Signers []xc.Address
+	// Set when sending from a multisig, whose members may sign over several calls to AddSignatures
+	Multisig           *kmultisig.LegacyAminoPubKey
+	MultisigSignatures []xc.TxSignature
+	// The sign doc that TxDataToSign is the digest of
+	SignBytes []byte
 }
 
 var _ xc.Tx = &Tx{}
+var _ xc.TxWithSigners = &Tx{}
+
+func init() {
+	registry.RegisterUnsignedTx(xc.BlockchainCosmos, &Tx{})
+}
 
 type Cw20MsgTransfer struct {
 	Transfer *Cw20Transfer `json:"transfer,omitempty"`
@@ -59,11 +75,33 @@ func (tx Tx) Sighashes() ([]xc.TxDataToSign, error) {
 	return []xc.TxDataToSign{tx.TxDataToSign}, nil
 }
 
+// Every signer signs the same sign doc
+func (tx Tx) SignatureRequests() ([]*xc.SignatureRequest, error) {
+	if tx.TxDataToSign == nil {
+		return nil, errors.New("transaction not initialized")
+	}
+	expected := len(tx.SigsV2)
+	if tx.Multisig != nil {
+		expected = len(tx.Multisig.GetPubKeys())
+	}
+	if len(tx.Signers) != expected {
+		return nil, fmt.Errorf("expected %d signers, got %d", expected, len(tx.Signers))
+	}
+	requests := make([]*xc.SignatureRequest, len(tx.Signers))
+	for i, signer := range tx.Signers {
+		requests[i] = xc.NewSignatureRequest(signer, tx.TxDataToSign, xc.BlockchainCosmos.SignatureAlgorithm())
+	}
+	return requests, nil
+}
+
 // AddSignatures adds a signature to Tx
 func (tx *Tx) AddSignatures(signatures ...xc.TxSignature) error {
 	if tx.SigsV2 == nil || len(tx.SigsV2) < 1 || tx.CosmosTxBuilder == nil {
 		return errors.New("transaction not initialized")
 	}
+	if tx.Multisig != nil {
+		return tx.addMultisigSignatures(signatures)
+	}
 	if len(signatures) != len(tx.SigsV2) {
 		return errors.New("invalid signatures size")
 	}
@@ -83,6 +121,55 @@ func (tx *Tx) AddSignatures(signatures ...xc.TxSignature) error {
 	return tx.CosmosTxBuilder.SetSignatures(tx.SigsV2...)
 }
 
+// Signatures are given for each member of the multisig in order, left empty by those that have not signed.
+// They are combined with any added before, and the transaction is complete once the threshold is met.
+func (tx *Tx) addMultisigSignatures(signatures []xc.TxSignature) error {
+	members := tx.Multisig.GetPubKeys()
+	if len(signatures) != len(members) {
+		return fmt.Errorf("expected a signature or nothing for each of %d multisig members, got %d", len(members), len(signatures))
+	}
+	memberSignatures := make([]xc.TxSignature, len(members))
+	copy(memberSignatures, tx.MultisigSignatures)
+	for i, signature := range signatures {
+		if len(signature) == 0 {
+			continue
+		}
+		sig := signature[:]
+		if len(sig) > 64 {
+			sig = sig[:64]
+		}
+		if !members[i].VerifySignature(tx.SignBytes, sig) {
+			return fmt.Errorf("invalid signature from multisig member %d (%s)", i, tx.Signers[i])
+		}
+		memberSignatures[i] = sig
+	}
+
+	multiSig := multisig.NewMultisig(len(members))
+	for i, sig := range memberSignatures {
+		if len(sig) > 0 {
+			multisig.AddSignature(multiSig, &signingtypes.SingleSignatureData{
+				SignMode:  signingtypes.SignMode_SIGN_MODE_LEGACY_AMINO_JSON,
+				Signature: sig,
+			}, i)
+		}
+	}
+	tx.MultisigSignatures = memberSignatures
+	tx.SigsV2[0].Data = multiSig
+	tx.InputSignatures = memberSignatures
+	return tx.CosmosTxBuilder.SetSignatures(tx.SigsV2...)
+}
+
+// The number of members of a multisig sender that have signed
+func (tx Tx) MultisigSignatureCount() int {
+	count := 0
+	for _, sig := range tx.MultisigSignatures {
+		if len(sig) > 0 {
+			count++
+		}
+	}
+	return count
+}
+
 func (tx Tx) GetSignatures() []xc.TxSignature {
 	return tx.InputSignatures
 }
@@ -92,6 +179,9 @@ func (tx Tx) Serialize() ([]byte, error) {
 	if tx.CosmosTxEncoder == nil {
 		return []byte{}, errors.New("transaction not initialized")
 	}
+	if tx.Multisig != nil && tx.MultisigSignatureCount() < int(tx.Multisig.Threshold) {
+		return []byte{}, fmt.Errorf("multisig has %d of %d required signatures", tx.MultisigSignatureCount(), tx.Multisig.Threshold)
+	}
 
 	// if CosmosTxBuilder is set, prioritize GetTx()
 	txToEncode := tx.CosmosTx
@@ -0,0 +1,84 @@
+package tx
+
+import (
+	"encoding/json"
+	"errors"
+
+	kmultisig "github.com/cosmos/cosmos-sdk/crypto/keys/multisig"
+	localcodectypes "github.com/openweb3-io/crosschain/blockchain/cosmos/types"
+	xc "github.com/openweb3-io/crosschain/types"
+)
+
+type txJSON struct {
+	// The protobuf encoding of the transaction, with its signatures left empty until signed
+	Tx                 []byte           `json:"tx"`
+	TxDataToSign       []byte           `json:"tx_data_to_sign"`
+	SignBytes          []byte           `json:"sign_bytes,omitempty"`
+	Signers            []xc.Address     `json:"signers"`
+	Signatures         []xc.TxSignature `json:"signatures,omitempty"`
+	MultisigSignatures []xc.TxSignature `json:"multisig_signatures,omitempty"`
+}
+
+func (tx Tx) MarshalJSON() ([]byte, error) {
+	txToEncode := tx.CosmosTx
+	if tx.CosmosTxBuilder != nil {
+		txToEncode = tx.CosmosTxBuilder.GetTx()
+	}
+	if txToEncode == nil {
+		return nil, errors.New("transaction not initialized")
+	}
+	encoder := tx.CosmosTxEncoder
+	if encoder == nil {
+		encoder = localcodectypes.MakeCosmosConfig().TxConfig.TxEncoder()
+	}
+	bz, err := encoder(txToEncode)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(txJSON{
+		Tx:                 bz,
+		TxDataToSign:       tx.TxDataToSign,
+		SignBytes:          tx.SignBytes,
+		Signers:            tx.Signers,
+		Signatures:         tx.InputSignatures,
+		MultisigSignatures: tx.MultisigSignatures,
+	})
+}
+
+func (tx *Tx) UnmarshalJSON(data []byte) error {
+	var decoded txJSON
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	txConfig := localcodectypes.MakeCosmosConfig().TxConfig
+	cosmosTx, err := txConfig.TxDecoder()(decoded.Tx)
+	if err != nil {
+		return err
+	}
+	cosmosTxBuilder, err := txConfig.WrapTxBuilder(cosmosTx)
+	if err != nil {
+		return err
+	}
+	sigsV2, err := cosmosTxBuilder.GetTx().GetSignaturesV2()
+	if err != nil {
+		return err
+	}
+	*tx = Tx{
+		CosmosTx:           cosmosTxBuilder.GetTx(),
+		ParsedTransfers:    cosmosTx.GetMsgs(),
+		CosmosTxBuilder:    cosmosTxBuilder,
+		CosmosTxEncoder:    txConfig.TxEncoder(),
+		SigsV2:             sigsV2,
+		InputSignatures:    decoded.Signatures,
+		TxDataToSign:       decoded.TxDataToSign,
+		Signers:            decoded.Signers,
+		MultisigSignatures: decoded.MultisigSignatures,
+		SignBytes:          decoded.SignBytes,
+	}
+	if len(sigsV2) > 0 {
+		if multisigPubKey, ok := sigsV2[0].PubKey.(*kmultisig.LegacyAminoPubKey); ok {
+			tx.Multisig = multisigPubKey
+		}
+	}
+	return nil
+}
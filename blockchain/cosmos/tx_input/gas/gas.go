@@ -12,6 +12,20 @@ import (
 const NativeTransferGasLimit = uint64(400_000)
 const TokenTransferGasLimit = uint64(900_000)
 
+// Additional gas for each output of a x/bank MsgMultiSend
+const MultiSendOutputGasLimit = uint64(30_000)
+
+func MultiSendGasLimit(outputs int) uint64 {
+	return NativeTransferGasLimit + MultiSendOutputGasLimit*uint64(outputs)
+}
+
+// Additional gas for each member of a multisig sender, to verify its signature and store its public key
+const MultisigMemberGasLimit = uint64(20_000)
+
+func MultisigGasLimit(members int) uint64 {
+	return MultisigMemberGasLimit * uint64(members)
+}
+
 // Divide totalFee/totalGas and return as float safely
 func TotalFeeToFeePerGas(totalFee string, totalGas uint64) float64 {
 	ten := big.NewInt(10)
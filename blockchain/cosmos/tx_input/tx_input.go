@@ -30,11 +30,21 @@ type TxInput struct {
 
 	AssetType CosmoAssetType `json:"asset_type,omitempty"`
 	ChainId   string         `json:"chain_id,omitempty"`
+
+	// Set when sending from a threshold multisig account, instead of the public key
+	Multisig *Multisig `json:"multisig,omitempty"`
+}
+
+// The members of a threshold multisig account, in the order they make up its public key
+type Multisig struct {
+	Threshold  uint32   `json:"threshold"`
+	PublicKeys [][]byte `json:"public_keys"`
 }
 
 var _ xc.TxInput = &TxInput{}
 var _ xc.TxInputWithPublicKey = &TxInput{}
 var _ xc.TxInputWithMemo = &TxInput{}
+var _ xc.TxInputWithNonce = &TxInput{}
 
 func init() {
 	registry.RegisterTxBaseInput(&TxInput{})
@@ -55,6 +65,10 @@ func (input *TxInput) SetGasFeePriority(other xc.GasFeePriority) error {
 	input.GasPrice, _ = multiplier.Mul(decimal.NewFromFloat(input.GasPrice)).Float64()
 	return nil
 }
+func (input *TxInput) SetNonce(sequence uint64) {
+	input.Sequence = sequence
+}
+
 func (input *TxInput) IndependentOf(other xc.TxInput) (independent bool) {
 	// different sequence means independence
 	if cosmosOther, ok := other.(*TxInput); ok {
@@ -82,6 +96,14 @@ func (txInput *TxInput) SetPublicKey(publicKeyBytes []byte) error {
 	return nil
 }
 
+// SetMultisig marks the sender as a threshold multisig account of the given public keys
+func (txInput *TxInput) SetMultisig(threshold uint32, publicKeys [][]byte) {
+	txInput.Multisig = &Multisig{
+		Threshold:  threshold,
+		PublicKeys: publicKeys,
+	}
+}
+
 func (txInput *TxInput) SetMemo(memo string) {
 	txInput.LegacyMemo = memo
 }
@@ -258,17 +258,20 @@ func (client *Client) FetchLegacyTxInfo(ctx context.Context, txHash xc.TxHash) (
 	return &r.LegacyTxInfo, err
 }
 
-func (client *Client) FetchTxInfo(ctx context.Context, txHashStr xc.TxHash) (xclient.TxInfo, error) {
+func (client *Client) FetchTxInfo(ctx context.Context, txHashStr xc.TxHash) (*xclient.TxInfo, error) {
 	chain := client.cfg.Chain
 	apiURL := fmt.Sprintf("%s/v1/chains/%s/transactions/%s", client.URL, chain, txHashStr)
 	res, err := client.ApiCallWithUrl(ctx, "GET", apiURL, nil)
 	if err != nil {
-		return xclient.TxInfo{}, err
+		return nil, err
 	}
 
 	r := types.TransactionInfoRes{}
 	err = json.Unmarshal(res, &r)
-	return r.TxInfo, err
+	if err != nil {
+		return nil, err
+	}
+	return &r.TxInfo, nil
 }
 
 // FetchNativeBalance fetches account balance from a Crosschain endpoint
@@ -0,0 +1,92 @@
+package forwarder
+
+import (
+	_ "embed"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// The subset of the OpenZeppelin ERC2771Forwarder used to relay requests signed by senders
+//
+//go:embed abi.json
+var abiJson string
+var forwarderAbi abi.ABI
+
+func NewAbi() abi.ABI {
+	a, err := abi.JSON(strings.NewReader(abiJson))
+	if err != nil {
+		panic(err)
+	}
+	return a
+}
+func init() {
+	forwarderAbi = NewAbi()
+}
+
+// Field names must match the components of the request tuple
+type ForwardRequestData struct {
+	From      common.Address
+	To        common.Address
+	Value     *big.Int
+	Gas       *big.Int
+	Deadline  *big.Int
+	Data      []byte
+	Signature []byte
+}
+
+// The call to relay a signed request
+func SerializeExecute(request ForwardRequestData) ([]byte, error) {
+	return forwarderAbi.Pack("execute", request)
+}
+
+func SerializeNonces(owner common.Address) ([]byte, error) {
+	return forwarderAbi.Pack("nonces", owner)
+}
+
+func ParseNonces(result []byte) (*big.Int, error) {
+	values, err := forwarderAbi.Unpack("nonces", result)
+	if err != nil {
+		return nil, err
+	}
+	nonce, ok := values[0].(*big.Int)
+	if !ok {
+		return nil, fmt.Errorf("unexpected nonce type %T", values[0])
+	}
+	return nonce, nil
+}
+
+// The EIP-712 domain that requests are signed under (EIP-5267)
+type Domain struct {
+	Name              string
+	Version           string
+	ChainId           *big.Int
+	VerifyingContract common.Address
+}
+
+func SerializeEip712Domain() ([]byte, error) {
+	return forwarderAbi.Pack("eip712Domain")
+}
+
+func ParseEip712Domain(result []byte) (*Domain, error) {
+	values, err := forwarderAbi.Unpack("eip712Domain", result)
+	if err != nil {
+		return nil, err
+	}
+	if len(values) < 5 {
+		return nil, fmt.Errorf("unexpected eip712 domain of %d values", len(values))
+	}
+	name, _ := values[1].(string)
+	version, _ := values[2].(string)
+	chainId, _ := values[3].(*big.Int)
+	verifyingContract, _ := values[4].(common.Address)
+	return &Domain{
+		Name:              name,
+		Version:           version,
+		ChainId:           chainId,
+		VerifyingContract: verifyingContract,
+	}, nil
+}
@@ -69,6 +69,9 @@ func (txBuilder TxBuilder) WithTxBuilder(buider GethTxBuilder) TxBuilder {
 // }
 
 func (txBuilder TxBuilder) NewTransfer(args *xcbuilder.TransferArgs, input xc.TxInput) (xc.Tx, error) {
+	if _, ok := args.GetFeePayer(); ok {
+		return txBuilder.NewForwardTransfer(args, input)
+	}
 	asset, _ := args.GetAsset()
 	if asset == nil {
 		asset = txBuilder.Chain
@@ -104,7 +107,8 @@ func (txBuilder TxBuilder) NewTransfer(args *xcbuilder.TransferArgs, input xc.Tx
 
 // NewNativeTransfer creates a new transfer for a native asset
 func (txBuilder TxBuilder) NewNativeTransfer(args *xcbuilder.TransferArgs, input xc.TxInput) (xc.Tx, error) {
-	return txBuilder.gethTxBuilder.BuildTxWithPayload(txBuilder.Chain, args.GetTo(), args.GetAmount(), []byte{}, input)
+	trans, err := txBuilder.gethTxBuilder.BuildTxWithPayload(txBuilder.Chain, args.GetTo(), args.GetAmount(), []byte{}, input)
+	return withSender(trans, err, args.GetFrom())
 }
 
 // NewTokenTransfer creates a new transfer for a token asset
@@ -120,7 +124,19 @@ func (txBuilder TxBuilder) NewTokenTransfer(args *xcbuilder.TransferArgs, input
 	if err != nil {
 		return nil, err
 	}
-	return txBuilder.gethTxBuilder.BuildTxWithPayload(txBuilder.Chain, xc.Address(contract), zero, payload, input)
+	trans, err := txBuilder.gethTxBuilder.BuildTxWithPayload(txBuilder.Chain, xc.Address(contract), zero, payload, input)
+	return withSender(trans, err, args.GetFrom())
+}
+
+// Record who must sign the transaction, for builders that produce an EVM transaction
+func withSender(trans xc.Tx, err error, sender xc.Address) (xc.Tx, error) {
+	if err != nil {
+		return nil, err
+	}
+	if evmTx, ok := trans.(*tx.Tx); ok {
+		evmTx.Sender = sender
+	}
+	return trans, nil
 }
 
 func BuildERC20Payload(to xc.Address, amount xc.BigInt) ([]byte, error) {
@@ -230,7 +246,7 @@ func (txBuilder TxBuilder) Stake(stakeArgs xcbuilder.StakeArgs, input xc.StakeTx
 		if err != nil {
 			return nil, fmt.Errorf("could not build tx for %T: %v", input, err)
 		}
-		return tx, nil
+		return withSender(tx, nil, stakeArgs.GetFrom())
 	default:
 		return nil, fmt.Errorf("unsupported staking type %T", input)
 	}
@@ -258,7 +274,7 @@ func (txBuilder TxBuilder) Unstake(stakeArgs xcbuilder.StakeArgs, input xc.Unsta
 		if err != nil {
 			return nil, fmt.Errorf("could not build tx for %T: %v", input, err)
 		}
-		return tx, nil
+		return withSender(tx, nil, stakeArgs.GetFrom())
 	default:
 		return nil, fmt.Errorf("unsupported unstaking type %T", input)
 	}
@@ -104,3 +104,60 @@ func TestUnstakingTx(t *testing.T) {
 
 	require.Equal(t, hex.EncodeToString(expected), hex.EncodeToString(data))
 }
+
+func TestSpeedUpAndCancel(t *testing.T) {
+	require := require.New(t)
+	b, _ := builder.NewTxBuilder(&xc_types.ChainConfig{ChainID: 1})
+
+	pending := &tx_input.PendingTx{
+		Hash:      "0xabc",
+		From:      "0x724435CC1B2821362c2CD425F2744Bd7347bf299",
+		To:        "0x3ad57b83B2E3dC5648F32e98e386935A9B10bb9F",
+		Nonce:     7,
+		Value:     xc_types.NewBigIntFromUint64(100),
+		Data:      []byte{1, 2, 3},
+		GasLimit:  50_000,
+		GasTipCap: builder.GweiToWei(1),
+		GasFeeCap: builder.GweiToWei(20),
+	}
+	// the market fees are lower than the pending transaction's
+	input := tx_input.NewTxInput()
+	input.GasTipCap = builder.GweiToWei(1)
+	input.GasFeeCap = builder.GweiToWei(10)
+	input.GasLimit = 21_000
+	input.SetReplacementOf(pending)
+	require.EqualValues(7, input.Nonce)
+	require.EqualValues(50_000, input.GasLimit)
+	require.EqualValues(1_100_000_000, input.GasTipCap.Uint64())
+	require.EqualValues(22_000_000_000, input.GasFeeCap.Uint64())
+	require.True(input.SafeFromDoubleSend(&tx_input.TxInput{Nonce: pending.Nonce}))
+
+	trans, err := b.NewSpeedUp(pending, input)
+	require.NoError(err)
+	ethTx := trans.(*tx.Tx).EthTx
+	require.EqualValues(7, ethTx.Nonce())
+	require.EqualValues(100, ethTx.Value().Uint64())
+	require.Equal([]byte{1, 2, 3}, ethTx.Data())
+	require.EqualValues(pending.To, ethTx.To().Hex())
+	require.EqualValues(1_100_000_000, ethTx.GasTipCap().Uint64())
+
+	trans, err = b.NewCancel(pending, input)
+	require.NoError(err)
+	ethTx = trans.(*tx.Tx).EthTx
+	require.EqualValues(7, ethTx.Nonce())
+	require.EqualValues(0, ethTx.Value().Uint64())
+	require.Empty(ethTx.Data())
+	require.EqualValues(pending.From, ethTx.To().Hex())
+	require.EqualValues(builder.NativeTransferGasLimit, ethTx.Gas())
+	require.EqualValues(22_000_000_000, ethTx.GasFeeCap().Uint64())
+
+	// the tip would be capped below what the node accepts as a replacement
+	pending.GasTipCap = builder.GweiToWei(builder.DefaultMaxTipCapGwei)
+	input.SetReplacementOf(pending)
+	_, err = b.NewSpeedUp(pending, input)
+	require.ErrorContains(err, "max tip")
+
+	input.Nonce = 8
+	_, err = b.NewCancel(pending, input)
+	require.ErrorContains(err, "does not replace")
+}
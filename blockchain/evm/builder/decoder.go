@@ -0,0 +1,88 @@
+package builder
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/openweb3-io/crosschain/blockchain/evm/tx"
+	xclient "github.com/openweb3-io/crosschain/client"
+	xc "github.com/openweb3-io/crosschain/types"
+)
+
+// TxDecoder for EVM
+type TxDecoder struct {
+	Chain *xc.ChainConfig
+}
+
+var _ xclient.TxDecoder = &TxDecoder{}
+
+func NewTxDecoder(cfg *xc.ChainConfig) (*TxDecoder, error) {
+	return &TxDecoder{Chain: cfg}, nil
+}
+
+// DecodeTx decodes native and ERC20 transfers, sent directly or as forward requests.  Other contract
+// calls are rejected, as what they do cannot be known from the calldata alone.  The sender of a
+// transaction is only known once it can be recovered from the signature, so it is empty on unsigned
+// transactions.
+func (decoder *TxDecoder) DecodeTx(xcTx xc.Tx) (*xclient.TxInfo, error) {
+	if forwardTx, ok := xcTx.(*tx.ForwardTx); ok {
+		return decoder.decodeForwardTx(forwardTx)
+	}
+	evmTx, ok := xcTx.(*tx.Tx)
+	if !ok {
+		return nil, fmt.Errorf("expected EVM transaction, got %T", xcTx)
+	}
+	if evmTx.EthTx == nil {
+		return nil, errors.New("transaction not initialized")
+	}
+	ethTx := evmTx.EthTx
+	if ethTx.To() == nil {
+		return nil, errors.New("cannot decode contract deployment")
+	}
+	// not the Sender of the envelope, which the signature does not cover
+	from := evmTx.From()
+	chain := decoder.Chain.Chain
+	info := xclient.NewTxInfo(nil, chain, string(evmTx.Hash()), 0, nil)
+
+	value := xc.BigInt(*ethTx.Value())
+	if value.Sign() > 0 {
+		info.AddSimpleTransfer(from, xc.Address(ethTx.To().String()), "", value, nil, "")
+	}
+	if len(ethTx.Data()) > 0 {
+		erc20, err := evmTx.ParseERC20TransferTx(chain)
+		if err != nil {
+			return nil, fmt.Errorf("cannot decode call to %s: %v", ethTx.To().String(), err)
+		}
+		dest := erc20.Destinations[0]
+		info.AddSimpleTransfer(from, dest.Address, dest.ContractAddress, dest.Amount, nil, "")
+	}
+
+	// the most the transaction can pay, as the base fee is only known once it is included
+	gas := xc.NewBigIntFromUint64(ethTx.Gas())
+	feeCap := xc.BigInt(*ethTx.GasFeeCap())
+	info.AddFee(from, "", gas.Mul(&feeCap), nil)
+	info.Fees = info.CalculateFees()
+	return info, nil
+}
+
+// The transfer of a forward request is sent from the signer of the request, while the relayer pays
+// the fee of the transaction that executes it.
+func (decoder *TxDecoder) decodeForwardTx(forwardTx *tx.ForwardTx) (*xclient.TxInfo, error) {
+	request := forwardTx.Request
+	from := xc.Address(request.From.String())
+	chain := decoder.Chain.Chain
+	info := xclient.NewTxInfo(nil, chain, string(forwardTx.Hash()), 0, nil)
+
+	if request.Value != nil && request.Value.Sign() > 0 {
+		info.AddSimpleTransfer(from, xc.Address(request.To.String()), "", xc.BigInt(*request.Value), nil, "")
+	}
+	if len(request.Data) > 0 {
+		to, amount, err := tx.ParseERC20Transfer(request.Data)
+		if err != nil {
+			return nil, fmt.Errorf("cannot decode call to %s: %v", request.To.String(), err)
+		}
+		info.AddSimpleTransfer(from, to, xc.ContractAddress(request.To.String()), xc.BigInt(*amount), nil, "")
+	}
+	info.Fees = info.CalculateFees()
+	return info, nil
+}
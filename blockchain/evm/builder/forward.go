@@ -0,0 +1,90 @@
+package builder
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/openweb3-io/crosschain/blockchain/evm/abi/forwarder"
+	"github.com/openweb3-io/crosschain/blockchain/evm/address"
+	"github.com/openweb3-io/crosschain/blockchain/evm/tx"
+	"github.com/openweb3-io/crosschain/blockchain/evm/tx_input"
+	xcbuilder "github.com/openweb3-io/crosschain/builder"
+	xc "github.com/openweb3-io/crosschain/types"
+)
+
+// Gas of the relaying transaction on top of the gas given to the forwarded call, covering the
+// forwarder's checks of the request and signature
+const ForwardGasOverhead = 60_000
+
+// NewForwardTransfer creates a token transfer signed by the sender, for the fee payer to relay
+// through the forwarder of the chain
+func (txBuilder TxBuilder) NewForwardTransfer(args *xcbuilder.TransferArgs, input xc.TxInput) (xc.Tx, error) {
+	txInput := input.(*tx_input.TxInput)
+	feePayer, _ := args.GetFeePayer()
+	if txInput.Forward == nil {
+		return nil, fmt.Errorf("input was not fetched to be relayed by fee payer %s", feePayer)
+	}
+	asset, _ := args.GetAsset()
+	if asset == nil || asset.GetContract() == "" {
+		return nil, errors.New("only token transfers can be relayed, the fee payer would pay the value of a native transfer")
+	}
+
+	from, err := address.FromHex(args.GetFrom())
+	if err != nil {
+		return nil, err
+	}
+	contract, err := address.FromHex(xc.Address(asset.GetContract()))
+	if err != nil {
+		return nil, err
+	}
+	forwarderAddress, err := address.FromHex(txInput.Forward.Forwarder)
+	if err != nil {
+		return nil, err
+	}
+	payload, err := BuildERC20Payload(args.GetTo(), args.GetAmount())
+	if err != nil {
+		return nil, err
+	}
+	chainId := txInput.ChainId.Int()
+	if txInput.ChainId.Uint64() == 0 {
+		chainId = new(big.Int).SetInt64(txBuilder.Chain.ChainID)
+	}
+
+	return &tx.ForwardTx{
+		Domain: forwarder.Domain{
+			Name:              txInput.Forward.Name,
+			Version:           txInput.Forward.Version,
+			ChainId:           chainId,
+			VerifyingContract: forwarderAddress,
+		},
+		Request: tx.ForwardRequest{
+			From:     from,
+			To:       contract,
+			Value:    big.NewInt(0),
+			Gas:      txInput.GasLimit,
+			Nonce:    txInput.Forward.Nonce.Int(),
+			Deadline: uint64(txInput.Forward.Deadline),
+			Data:     payload,
+		},
+	}, nil
+}
+
+// NewRelayTx creates the fee payer's transaction that submits a signed request to the forwarder.
+// The input is that of the fee payer.
+func (txBuilder TxBuilder) NewRelayTx(feePayer xc.Address, forwardTx *tx.ForwardTx, input xc.TxInput) (xc.Tx, error) {
+	data, err := forwardTx.Serialize()
+	if err != nil {
+		return nil, err
+	}
+	txInput := *input.(*tx_input.TxInput)
+	if minGas := forwardTx.Request.Gas + ForwardGasOverhead; txInput.GasLimit < minGas {
+		txInput.GasLimit = minGas
+	}
+	value := xc.NewBigIntFromUint64(0)
+	if forwardTx.Request.Value != nil {
+		value = xc.BigInt(*forwardTx.Request.Value)
+	}
+	trans, err := txBuilder.gethTxBuilder.BuildTxWithPayload(txBuilder.Chain, xc.Address(forwardTx.Domain.VerifyingContract.Hex()), value, data, &txInput)
+	return withSender(trans, err, feePayer)
+}
@@ -0,0 +1,57 @@
+package builder
+
+import (
+	"fmt"
+
+	"github.com/openweb3-io/crosschain/blockchain/evm/tx"
+	"github.com/openweb3-io/crosschain/blockchain/evm/tx_input"
+	xc "github.com/openweb3-io/crosschain/types"
+)
+
+// Gas used by a transfer of the native asset without any data
+const NativeTransferGasLimit = 21_000
+
+// Re-send a pending transaction at the same nonce with higher fees, so it gets included sooner.
+// The input should have its fees bumped with `SetReplacementOf`.
+func (txBuilder TxBuilder) NewSpeedUp(pending *tx_input.PendingTx, input *tx_input.TxInput) (xc.Tx, error) {
+	if input.Nonce != pending.Nonce {
+		return nil, fmt.Errorf("nonce %d does not replace pending transaction %s with nonce %d", input.Nonce, pending.Hash, pending.Nonce)
+	}
+	trans, err := txBuilder.gethTxBuilder.BuildTxWithPayload(txBuilder.Chain, pending.To, pending.Value, pending.Data, input)
+	trans, err = withSender(trans, err, pending.From)
+	if err != nil {
+		return nil, err
+	}
+	return trans, txBuilder.checkReplaces(trans, pending)
+}
+
+// Replace a pending transaction with a transfer of nothing to the sender at the same nonce,
+// so the pending transaction can no longer be included.
+func (txBuilder TxBuilder) NewCancel(pending *tx_input.PendingTx, input *tx_input.TxInput) (xc.Tx, error) {
+	if input.Nonce != pending.Nonce {
+		return nil, fmt.Errorf("nonce %d does not replace pending transaction %s with nonce %d", input.Nonce, pending.Hash, pending.Nonce)
+	}
+	cancelInput := *input
+	cancelInput.GasLimit = NativeTransferGasLimit
+	zero := xc.NewBigIntFromUint64(0)
+	trans, err := txBuilder.gethTxBuilder.BuildTxWithPayload(txBuilder.Chain, pending.From, zero, []byte{}, &cancelInput)
+	trans, err = withSender(trans, err, pending.From)
+	if err != nil {
+		return nil, err
+	}
+	return trans, txBuilder.checkReplaces(trans, pending)
+}
+
+// The tip may have been capped by the builder, in which case the node would reject the replacement
+func (txBuilder TxBuilder) checkReplaces(trans xc.Tx, pending *tx_input.PendingTx) error {
+	ethTx := trans.(*tx.Tx).EthTx
+	minTipCap, minFeeCap := pending.MinReplacementFees()
+	if ethTx.GasTipCap().Cmp(minTipCap.Int()) < 0 {
+		return fmt.Errorf("replacing %s needs a tip of at least %s, which is above the max tip of %s; raise the chain's max gas price",
+			pending.Hash, minTipCap.String(), xc.BigInt(*ethTx.GasTipCap()).String())
+	}
+	if ethTx.GasFeeCap().Cmp(minFeeCap.Int()) < 0 {
+		return fmt.Errorf("replacing %s needs a fee cap of at least %s", pending.Hash, minFeeCap.String())
+	}
+	return nil
+}
@@ -8,6 +8,7 @@ import (
 	"math/big"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
@@ -50,6 +51,7 @@ type Client struct {
 }
 
 var _ xclient.IClient = &Client{}
+var _ xclient.NonceClient = &Client{}
 
 // Ethereum does not support full delegated staking, so we can only report balance information.
 // A 3rd party 'staking provider' is required to do the rest.
@@ -118,6 +120,8 @@ func (client *Client) BroadcastTx(ctx context.Context, trans xc.Tx) error {
 			return fmt.Errorf("sending transaction '%v': %v", tx.Hash(), err)
 		}
 		return nil
+	case *tx.ForwardTx:
+		return fmt.Errorf("request '%v' must be relayed by the fee payer, build its transaction with NewRelayTx", tx.Hash())
 	default:
 		bz, err := tx.Serialize()
 		if err != nil {
@@ -127,134 +131,88 @@ func (client *Client) BroadcastTx(ctx context.Context, trans xc.Tx) error {
 	}
 }
 
-// FetchLegacyTxInfo returns tx info for a EVM tx
-func (client *Client) FetchLegacyTxInfo(ctx context.Context, txHashStr xc.TxHash) (*xc.LegacyTxInfo, error) {
-	nativeAsset := client.Chain
-	txHashHex := address.TrimPrefixes(string(txHashStr))
-	txHash := common.HexToHash(txHashHex)
-
-	result := &xc.LegacyTxInfo{
-		TxID:        txHashHex,
-		ExplorerURL: nativeAsset.ExplorerURL + "/tx/0x" + txHashHex,
-	}
-
+func (client *Client) fetchTransactionByHash(ctx context.Context, txHash common.Hash) (*types.Transaction, bool, error) {
 	trans, pending, err := client.EthClient.TransactionByHash(ctx, txHash)
 	if err != nil {
 		// TODO retry only for KLAY
 		client.Interceptor.Enable()
 		trans, pending, err = client.EthClient.TransactionByHash(ctx, txHash)
 		client.Interceptor.Disable()
-		if err != nil {
-			return result, fmt.Errorf(fmt.Sprintf("fetching tx by hash '%s': %v", txHashStr, err))
-		}
-	}
-
-	chainID := new(big.Int).SetInt64(nativeAsset.ChainID)
-
-	// If the transaction is still pending, return an empty txInfo.
-	if pending {
-		return result, nil
 	}
+	return trans, pending, err
+}
 
+func (client *Client) fetchTransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
 	receipt, err := client.EthClient.TransactionReceipt(ctx, txHash)
 	if err != nil {
 		// TODO retry only for KLAY
 		client.Interceptor.Enable()
 		receipt, err = client.EthClient.TransactionReceipt(ctx, txHash)
 		client.Interceptor.Disable()
-		if err != nil {
-			return result, fmt.Errorf("fetching receipt for tx %v : %v", txHashStr, err)
-		}
-	}
-
-	// if no receipt, tx has 0 confirmations
-	if receipt == nil {
-		return result, nil
-	}
-
-	result.BlockIndex = receipt.BlockNumber.Int64()
-	result.BlockHash = receipt.BlockHash.Hex()
-	gasUsed := receipt.GasUsed
-	if receipt.Status == 0 {
-		result.Status = xc.TxStatusFailure
-		result.Error = "transaction reverted"
-	}
-
-	// tx confirmed
-	currentHeader, err := client.EthClient.HeaderByNumber(ctx, receipt.BlockNumber)
-	if err != nil {
-		client.Interceptor.Enable()
-		currentHeader, err = client.EthClient.HeaderByNumber(ctx, receipt.BlockNumber)
-		client.Interceptor.Disable()
-		if err != nil {
-			return result, fmt.Errorf("fetching current header: (%T) %v", err, err)
-		}
-	}
-	result.BlockTime = int64(currentHeader.Time)
-	var baseFee uint64
-	if currentHeader.BaseFee != nil {
-		baseFee = currentHeader.BaseFee.Uint64()
 	}
+	return receipt, err
+}
 
-	latestHeader, err := client.EthClient.HeaderByNumber(ctx, nil)
+func (client *Client) fetchHeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	header, err := client.EthClient.HeaderByNumber(ctx, number)
 	if err != nil {
 		client.Interceptor.Enable()
-		latestHeader, err = client.EthClient.HeaderByNumber(ctx, nil)
+		header, err = client.EthClient.HeaderByNumber(ctx, number)
 		client.Interceptor.Disable()
-		if err != nil {
-			return result, fmt.Errorf("fetching latest header: %v", err)
-		}
-	}
-	result.Confirmations = latestHeader.Number.Int64() - receipt.BlockNumber.Int64()
-
-	// // tx confirmed
-	confirmedTx := tx.Tx{
-		EthTx:  trans,
-		Signer: types.LatestSignerForChainID(chainID),
 	}
+	return header, err
+}
 
-	tokenMovements := confirmedTx.ParseTokenLogs(receipt, xc.NativeAsset(nativeAsset.Chain))
+// Trace the native asset movements of a tx, falling back to the tx value if tracing is not supported.
+func (client *Client) fetchEthMovements(ctx context.Context, txHash common.Hash, confirmedTx *tx.Tx) tx.SourcesAndDests {
+	nativeAsset := client.Chain
 	ethMovements, err := client.TraceEthMovements(ctx, txHash)
 	if err != nil {
 		// Not all RPC nodes support this trace call, so we'll just drop reporting
 		// internal eth movements if there's an issue.
 		zap.S().Warn("could not trace ETH tx",
-			zap.String("tx_hash", string(txHashStr)),
+			zap.String("tx_hash", txHash.Hex()),
 			zap.String("chain", string(nativeAsset.Chain)),
 			zap.Error(err),
 		)
 		// set default eth movements
-		amount := trans.Value()
-		zero := big.NewInt(0)
-		if amount.Cmp(zero) > 0 {
-			ethMovements = tx.SourcesAndDests{
-				Sources: []*xc.LegacyTxInfoEndpoint{{
-					Address:     confirmedTx.From(),
-					NativeAsset: nativeAsset.Chain,
-					Amount:      xc.BigInt(*amount),
-				}},
-				Destinations: []*xc.LegacyTxInfoEndpoint{{
-					Address:     confirmedTx.To(),
-					NativeAsset: nativeAsset.Chain,
-					Amount:      xc.BigInt(*amount),
-				}},
-			}
-		}
+		ethMovements = client.valueMovements(confirmedTx)
 	}
+	return ethMovements
+}
 
-	result.From = confirmedTx.From()
-	result.To = confirmedTx.To()
-	result.ContractAddress = confirmedTx.ContractAddress()
-	result.Amount = confirmedTx.Amount()
-	result.Fee = confirmedTx.Fee(baseFee, gasUsed)
-	result.Sources = append(ethMovements.Sources, tokenMovements.Sources...)
-	result.Destinations = append(ethMovements.Destinations, tokenMovements.Destinations...)
+// The native asset movement of a tx based on only its value, which excludes any internal transactions.
+func (client *Client) valueMovements(confirmedTx *tx.Tx) tx.SourcesAndDests {
+	nativeAsset := client.Chain
+	amount := confirmedTx.EthTx.Value()
+	zero := big.NewInt(0)
+	if amount.Cmp(zero) <= 0 {
+		return tx.SourcesAndDests{}
+	}
+	return tx.SourcesAndDests{
+		Sources: []*xc.LegacyTxInfoEndpoint{{
+			Address:     confirmedTx.From(),
+			NativeAsset: nativeAsset.Chain,
+			Amount:      xc.BigInt(*amount),
+		}},
+		Destinations: []*xc.LegacyTxInfoEndpoint{{
+			Address:     confirmedTx.To(),
+			NativeAsset: nativeAsset.Chain,
+			Amount:      xc.BigInt(*amount),
+		}},
+	}
+}
 
-	// Look for stake/unstake events
+// Look for stake/unstake events
+func (client *Client) parseStakeEvents(receipt *types.Receipt) []xc.StakeEvent {
+	nativeAsset := client.Chain
+	events := []xc.StakeEvent{}
 	for _, log := range receipt.Logs {
+		if len(log.Topics) == 0 {
+			continue
+		}
 		ev, _ := stake_deposit.EventByID(log.Topics[0])
 		if ev != nil {
-			// fmt.Println("found staking event")
 			dep, err := stake_deposit.ParseDeposit(*log)
 			if err != nil {
 				zap.S().Error("could not parse stake deposit log", err)
@@ -267,7 +225,7 @@ func (client *Client) FetchLegacyTxInfo(ctx context.Context, txHashStr xc.TxHash
 				address = hex.EncodeToString(dep.WithdrawalCredentials[len(dep.WithdrawalCredentials)-common.AddressLength:])
 			}
 
-			result.AddStakeEvent(&xclient.Stake{
+			events = append(events, &xclient.Stake{
 				Balance:   dep.Amount,
 				Validator: normalize.NormalizeAddressString(hex.EncodeToString(dep.Pubkey), nativeAsset.Chain),
 				Address:   normalize.NormalizeAddressString(address, nativeAsset.Chain),
@@ -282,26 +240,190 @@ func (client *Client) FetchLegacyTxInfo(ctx context.Context, txHashStr xc.TxHash
 			}
 			// assume 32 ether
 			inc, _ := xc.NewAmountHumanReadableFromStr("32")
-			result.AddStakeEvent(&xclient.Unstake{
+			events = append(events, &xclient.Unstake{
 				Balance:   inc.ToBlockchain(client.Chain.Decimals),
 				Validator: normalize.NormalizeAddressString(hex.EncodeToString(exitLog.Pubkey), nativeAsset.Chain),
 				Address:   normalize.NormalizeAddressString(hex.EncodeToString(exitLog.Caller[:]), nativeAsset.Chain),
 			})
 		}
 	}
+	return events
+}
+
+// FetchLegacyTxInfo returns tx info for a EVM tx
+func (client *Client) FetchLegacyTxInfo(ctx context.Context, txHashStr xc.TxHash) (*xc.LegacyTxInfo, error) {
+	nativeAsset := client.Chain
+	txHashHex := address.TrimPrefixes(string(txHashStr))
+	txHash := common.HexToHash(txHashHex)
+
+	result := &xc.LegacyTxInfo{
+		TxID:        txHashHex,
+		ExplorerURL: nativeAsset.ExplorerURL + "/tx/0x" + txHashHex,
+	}
+
+	trans, pending, err := client.fetchTransactionByHash(ctx, txHash)
+	if err != nil {
+		return result, fmt.Errorf(fmt.Sprintf("fetching tx by hash '%s': %v", txHashStr, err))
+	}
+
+	chainID := new(big.Int).SetInt64(nativeAsset.ChainID)
+
+	// If the transaction is still pending, return an empty txInfo.
+	if pending {
+		return result, nil
+	}
+
+	receipt, err := client.fetchTransactionReceipt(ctx, txHash)
+	if err != nil {
+		return result, fmt.Errorf("fetching receipt for tx %v : %v", txHashStr, err)
+	}
+
+	// if no receipt, tx has 0 confirmations
+	if receipt == nil {
+		return result, nil
+	}
+
+	result.BlockIndex = receipt.BlockNumber.Int64()
+	result.BlockHash = receipt.BlockHash.Hex()
+	gasUsed := receipt.GasUsed
+	if receipt.Status == 0 {
+		result.Status = xc.TxStatusFailure
+		result.Error = "transaction reverted"
+	}
+
+	// tx confirmed
+	currentHeader, err := client.fetchHeaderByNumber(ctx, receipt.BlockNumber)
+	if err != nil {
+		return result, fmt.Errorf("fetching current header: (%T) %v", err, err)
+	}
+	result.BlockTime = int64(currentHeader.Time)
+	var baseFee uint64
+	if currentHeader.BaseFee != nil {
+		baseFee = currentHeader.BaseFee.Uint64()
+	}
+
+	latestHeader, err := client.fetchHeaderByNumber(ctx, nil)
+	if err != nil {
+		return result, fmt.Errorf("fetching latest header: %v", err)
+	}
+	result.Confirmations = latestHeader.Number.Int64() - receipt.BlockNumber.Int64()
+
+	// // tx confirmed
+	confirmedTx := &tx.Tx{
+		EthTx:  trans,
+		Signer: types.LatestSignerForChainID(chainID),
+	}
+
+	tokenMovements := confirmedTx.ParseTokenLogs(receipt, xc.NativeAsset(nativeAsset.Chain))
+	ethMovements := client.fetchEthMovements(ctx, txHash, confirmedTx)
+
+	result.From = confirmedTx.From()
+	result.To = confirmedTx.To()
+	result.ContractAddress = confirmedTx.ContractAddress()
+	result.Amount = confirmedTx.Amount()
+	result.Fee = confirmedTx.Fee(baseFee, gasUsed)
+	result.Sources = append(ethMovements.Sources, tokenMovements.Sources...)
+	result.Destinations = append(ethMovements.Destinations, tokenMovements.Destinations...)
+
+	for _, ev := range client.parseStakeEvents(receipt) {
+		result.AddStakeEvent(ev)
+	}
 
 	return result, nil
 }
 
 func (client *Client) FetchTxInfo(ctx context.Context, txHashStr xc.TxHash) (*xclient.TxInfo, error) {
-	legacyTx, err := client.FetchLegacyTxInfo(ctx, txHashStr)
+	chain := client.Chain.Chain
+	txHashHex := address.TrimPrefixes(string(txHashStr))
+	txHash := common.HexToHash(txHashHex)
+
+	trans, pending, err := client.fetchTransactionByHash(ctx, txHash)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("fetching tx by hash '%s': %v", txHashStr, err)
+	}
+
+	// If the transaction is still pending, there is no block or movements to report yet.
+	if pending {
+		return xclient.NewTxInfo(xclient.NewBlock(0, "", time.Unix(0, 0)), chain, txHashHex, 0, nil), nil
+	}
+
+	receipt, err := client.fetchTransactionReceipt(ctx, txHash)
+	if err != nil {
+		return nil, fmt.Errorf("fetching receipt for tx %v : %v", txHashStr, err)
+	}
+	if receipt == nil {
+		return xclient.NewTxInfo(xclient.NewBlock(0, "", time.Unix(0, 0)), chain, txHashHex, 0, nil), nil
+	}
+
+	currentHeader, err := client.fetchHeaderByNumber(ctx, receipt.BlockNumber)
+	if err != nil {
+		return nil, fmt.Errorf("fetching current header: (%T) %v", err, err)
 	}
+
+	latestHeader, err := client.fetchHeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("fetching latest header: %v", err)
+	}
+	confirmations := uint64(0)
+	if latestHeader.Number.Cmp(receipt.BlockNumber) >= 0 {
+		confirmations = new(big.Int).Sub(latestHeader.Number, receipt.BlockNumber).Uint64() + 1
+	}
+
+	confirmedTx := &tx.Tx{
+		EthTx:  trans,
+		Signer: types.LatestSignerForChainID(big.NewInt(client.Chain.ChainID)),
+	}
+	ethMovements := tx.SourcesAndDests{}
+	// a reverted tx does not move any funds, but still pays the fee
+	if receipt.Status != types.ReceiptStatusFailed {
+		ethMovements = client.fetchEthMovements(ctx, txHash, confirmedTx)
+	}
+	return client.toTxInfo(confirmedTx, receipt, currentHeader, confirmations, ethMovements), nil
+}
+
+func (client *Client) toTxInfo(confirmedTx *tx.Tx, receipt *types.Receipt, header *types.Header, confirmations uint64, ethMovements tx.SourcesAndDests) *xclient.TxInfo {
 	chain := client.Chain.Chain
+	var baseFee uint64
+	if header.BaseFee != nil {
+		baseFee = header.BaseFee.Uint64()
+	}
 
-	// remap to new tx
-	return xclient.TxInfoFromLegacy(chain, legacyTx, xclient.Account), nil
+	var errMsg *string
+	if receipt.Status == types.ReceiptStatusFailed {
+		msg := "transaction reverted"
+		errMsg = &msg
+	}
+
+	block := xclient.NewBlock(receipt.BlockNumber.Uint64(), receipt.BlockHash.Hex(), time.Unix(int64(header.Time), 0))
+	txInfo := xclient.NewTxInfo(block, chain, address.TrimPrefixes(receipt.TxHash.Hex()), confirmations, errMsg)
+
+	// a reverted tx does not move any funds, but still pays the fee
+	if receipt.Status != types.ReceiptStatusFailed {
+		for i, dest := range ethMovements.Destinations {
+			from := confirmedTx.From()
+			if i < len(ethMovements.Sources) {
+				from = ethMovements.Sources[i].Address
+			}
+			txInfo.AddSimpleTransfer(from, dest.Address, "", dest.Amount, nil, "")
+		}
+		tokenMovements := confirmedTx.ParseTokenLogs(receipt, chain)
+		for i, dest := range tokenMovements.Destinations {
+			txInfo.AddSimpleTransfer(tokenMovements.Sources[i].Address, dest.Address, dest.ContractAddress, dest.Amount, nil, "")
+		}
+	}
+	txInfo.AddFee(confirmedTx.From(), "", confirmedTx.Fee(baseFee, receipt.GasUsed), nil)
+	txInfo.Fees = txInfo.CalculateFees()
+
+	for _, ev := range client.parseStakeEvents(receipt) {
+		switch ev := ev.(type) {
+		case *xclient.Stake:
+			txInfo.Stakes = append(txInfo.Stakes, ev)
+		case *xclient.Unstake:
+			txInfo.Unstakes = append(txInfo.Unstakes, ev)
+		}
+	}
+
+	return txInfo
 }
 
 // Fetch the balance of the native asset that this client is configured for
@@ -344,6 +466,11 @@ func (client *Client) FetchBalanceForAsset(ctx context.Context, addr xc.Address,
 }
 
 func (client *Client) EstimateGasFee(ctx context.Context, _tx xc.Tx) (*xc.BigInt, error) {
+	if _, ok := _tx.(*tx.ForwardTx); ok {
+		// the fee payer pays the gas of relaying the request
+		zero := xc.NewBigIntFromUint64(0)
+		return &zero, nil
+	}
 	tx := _tx.(*tx.Tx)
 
 	from, err := types.Sender(tx.Signer, tx.EthTx)
@@ -0,0 +1,110 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/openweb3-io/crosschain/blockchain/evm/tx"
+	xclient "github.com/openweb3-io/crosschain/client"
+	"go.uber.org/zap"
+)
+
+var _ xclient.BlockClient = &Client{}
+var _ xclient.FinalityClient = &Client{}
+
+func (client *Client) FetchLatestHeight(ctx context.Context) (uint64, error) {
+	header, err := client.fetchHeaderByNumber(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("fetching latest header: %v", err)
+	}
+	return header.Number.Uint64(), nil
+}
+
+// The most recent block with the "finalized" tag.  Chains that predate the merge, or nodes that don't
+// support the tag, return an error.
+func (client *Client) FetchFinalizedHeight(ctx context.Context) (uint64, error) {
+	header, err := client.fetchHeaderByNumber(ctx, big.NewInt(int64(rpc.FinalizedBlockNumber)))
+	if err != nil {
+		return 0, fmt.Errorf("fetching finalized header: %v", err)
+	}
+	return header.Number.Uint64(), nil
+}
+
+// Fetch a block with all of its transactions.  Native movements are based on the value of each transaction,
+// as tracing every transaction in a block is too expensive; internal transactions are not reported.
+func (client *Client) FetchBlock(ctx context.Context, height uint64) (*xclient.BlockWithTransactions, error) {
+	latestHeight, err := client.FetchLatestHeight(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if height > latestHeight {
+		return nil, fmt.Errorf("block %d is not yet available", height)
+	}
+
+	ethBlock, err := client.fetchBlockByNumber(ctx, new(big.Int).SetUint64(height))
+	if err != nil {
+		return nil, fmt.Errorf("fetching block %d: %v", height, err)
+	}
+	receipts, err := client.fetchBlockReceipts(ctx, ethBlock)
+	if err != nil {
+		return nil, fmt.Errorf("fetching receipts for block %d: %v", height, err)
+	}
+	confirmations := latestHeight - height + 1
+	header := ethBlock.Header()
+	signer := types.LatestSignerForChainID(big.NewInt(client.Chain.ChainID))
+
+	txs := []*xclient.TxInfo{}
+	for i, trans := range ethBlock.Transactions() {
+		confirmedTx := &tx.Tx{
+			EthTx:  trans,
+			Signer: signer,
+		}
+		ethMovements := tx.SourcesAndDests{}
+		if receipts[i].Status != types.ReceiptStatusFailed {
+			ethMovements = client.valueMovements(confirmedTx)
+		}
+		txs = append(txs, client.toTxInfo(confirmedTx, receipts[i], header, confirmations, ethMovements))
+	}
+
+	block := xclient.NewBlock(height, ethBlock.Hash().Hex(), time.Unix(int64(header.Time), 0))
+	return xclient.NewBlockWithTransactions(block, ethBlock.ParentHash().Hex(), txs), nil
+}
+
+func (client *Client) fetchBlockByNumber(ctx context.Context, number *big.Int) (*types.Block, error) {
+	block, err := client.EthClient.BlockByNumber(ctx, number)
+	if err != nil {
+		client.Interceptor.Enable()
+		block, err = client.EthClient.BlockByNumber(ctx, number)
+		client.Interceptor.Disable()
+	}
+	return block, err
+}
+
+// Fetch all of the receipts of a block in one call if the node supports eth_getBlockReceipts,
+// otherwise fall back to fetching each receipt.
+func (client *Client) fetchBlockReceipts(ctx context.Context, block *types.Block) ([]*types.Receipt, error) {
+	var receipts []*types.Receipt
+	err := client.EthClient.Client().CallContext(ctx, &receipts, "eth_getBlockReceipts", block.Hash())
+	if err == nil && len(receipts) == len(block.Transactions()) {
+		return receipts, nil
+	}
+	zap.S().Debug("could not fetch block receipts, fetching individually",
+		zap.Uint64("block", block.NumberU64()),
+		zap.String("chain", string(client.Chain.Chain)),
+		zap.Error(err),
+	)
+
+	receipts = make([]*types.Receipt, len(block.Transactions()))
+	for i, trans := range block.Transactions() {
+		receipt, err := client.fetchTransactionReceipt(ctx, trans.Hash())
+		if err != nil {
+			return nil, fmt.Errorf("fetching receipt for tx %v: %v", trans.Hash().Hex(), err)
+		}
+		receipts[i] = receipt
+	}
+	return receipts, nil
+}
@@ -0,0 +1,176 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/openweb3-io/crosschain/blockchain/evm/address"
+	xclient "github.com/openweb3-io/crosschain/client"
+	xc "github.com/openweb3-io/crosschain/types"
+	"go.uber.org/zap"
+)
+
+// Number of blocks scanned for each page of address history
+const HistoryBlockRange = 2_000
+
+var _ xclient.HistoryClient = &Client{}
+
+type TraceFilterArgs struct {
+	FromBlock   hexutil.Uint64   `json:"fromBlock"`
+	ToBlock     hexutil.Uint64   `json:"toBlock"`
+	FromAddress []common.Address `json:"fromAddress,omitempty"`
+	ToAddress   []common.Address `json:"toAddress,omitempty"`
+}
+
+type TraceFilterAction struct {
+	From  common.Address `json:"from"`
+	To    common.Address `json:"to"`
+	Value hexutil.Big    `json:"value"`
+}
+
+type TraceFilterResult struct {
+	Action          TraceFilterAction `json:"action"`
+	BlockNumber     uint64            `json:"blockNumber"`
+	TransactionHash common.Hash       `json:"transactionHash"`
+}
+
+// Implements trace_filter, which is supported on erigon, nethermind and some RPC providers.
+// This reveals ETH transfers to or from an address, including internal transactions.
+func (client *Client) TraceFilter(ctx context.Context, args *TraceFilterArgs) ([]*TraceFilterResult, error) {
+	var result []*TraceFilterResult
+	err := client.EthClient.Client().CallContext(ctx, &result, "trace_filter", args)
+	return result, err
+}
+
+// Fetch the transactions of an address by scanning ranges of blocks, starting with the most recent.
+// ERC-20 movements are found via the Transfer logs, native movements via trace_filter.
+// The cursor is the height of the last block to scan; pages may be empty while there are more blocks left.
+// At most the limit of transactions are returned, unless they all belong to a single block.
+func (client *Client) FetchTransactionsByAddress(ctx context.Context, args xclient.TransactionHistoryArgs) (*xclient.TransactionHistoryPage, error) {
+	addr, err := address.FromHex(args.GetAddress())
+	if err != nil {
+		return nil, err
+	}
+	toBlock := uint64(0)
+	if cursor, ok := args.GetCursor(); ok {
+		toBlock, err = strconv.ParseUint(cursor, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cursor: %s", cursor)
+		}
+	} else {
+		latest, err := client.fetchHeaderByNumber(ctx, nil)
+		if err != nil {
+			return nil, fmt.Errorf("fetching latest header: %v", err)
+		}
+		toBlock = latest.Number.Uint64()
+	}
+	fromBlock := uint64(0)
+	if toBlock >= HistoryBlockRange {
+		fromBlock = toBlock - HistoryBlockRange + 1
+	}
+
+	heights := map[common.Hash]uint64{}
+	contract, filterContract := args.GetContract()
+
+	// ERC-20 transfers either from or to the address
+	addrTopic := common.BytesToHash(addr.Bytes())
+	transferTopic := ERC20.Events["Transfer"].ID
+	query := ethereum.FilterQuery{
+		FromBlock: new(big.Int).SetUint64(fromBlock),
+		ToBlock:   new(big.Int).SetUint64(toBlock),
+	}
+	if filterContract {
+		contractAddr, err := address.FromHex(xc.Address(contract))
+		if err != nil {
+			return nil, err
+		}
+		query.Addresses = []common.Address{contractAddr}
+	}
+	for _, topics := range [][][]common.Hash{
+		{{transferTopic}, {addrTopic}},
+		{{transferTopic}, {}, {addrTopic}},
+	} {
+		query.Topics = topics
+		logs, err := client.EthClient.FilterLogs(ctx, query)
+		if err != nil {
+			return nil, fmt.Errorf("fetching transfer logs: %v", err)
+		}
+		for _, log := range logs {
+			heights[log.TxHash] = log.BlockNumber
+		}
+	}
+
+	// native transfers either from or to the address
+	if !filterContract {
+		for _, traceArgs := range []*TraceFilterArgs{
+			{FromBlock: hexutil.Uint64(fromBlock), ToBlock: hexutil.Uint64(toBlock), FromAddress: []common.Address{addr}},
+			{FromBlock: hexutil.Uint64(fromBlock), ToBlock: hexutil.Uint64(toBlock), ToAddress: []common.Address{addr}},
+		} {
+			traces, err := client.TraceFilter(ctx, traceArgs)
+			if err != nil {
+				// Not all RPC nodes support this trace call, so we'll just drop reporting
+				// native movements if there's an issue.
+				zap.S().Warn("could not trace ETH movements",
+					zap.String("address", string(args.GetAddress())),
+					zap.String("chain", string(client.Chain.Chain)),
+					zap.Error(err),
+				)
+				break
+			}
+			for _, trace := range traces {
+				heights[trace.TransactionHash] = trace.BlockNumber
+			}
+		}
+	}
+
+	hashes := make([]common.Hash, 0, len(heights))
+	for hash := range heights {
+		hashes = append(hashes, hash)
+	}
+	// most recent first
+	sort.Slice(hashes, func(i, j int) bool {
+		if heights[hashes[i]] != heights[hashes[j]] {
+			return heights[hashes[i]] > heights[hashes[j]]
+		}
+		return hashes[i].Hex() < hashes[j].Hex()
+	})
+
+	// Truncate to the limit without splitting a block, so the cursor can resume below the last block
+	// returned.  A single block with more transactions than the limit is returned whole.
+	nextCursor := ""
+	if fromBlock > 0 {
+		nextCursor = strconv.FormatUint(fromBlock-1, 10)
+	}
+	if limit := args.GetLimit(); len(hashes) > limit {
+		cut := limit
+		for cut > 0 && heights[hashes[cut-1]] == heights[hashes[cut]] {
+			cut--
+		}
+		if cut == 0 {
+			for cut < len(hashes) && heights[hashes[cut]] == heights[hashes[0]] {
+				cut++
+			}
+		}
+		if cut < len(hashes) {
+			hashes = hashes[:cut]
+			nextCursor = strconv.FormatUint(heights[hashes[cut-1]]-1, 10)
+		}
+	}
+
+	txs := []*xclient.TxInfo{}
+	for _, hash := range hashes {
+		txInfo, err := client.FetchTxInfo(ctx, xc.TxHash(hash.Hex()))
+		if err != nil {
+			return nil, fmt.Errorf("could not fetch transaction %s: %v", hash.Hex(), err)
+		}
+		txs = append(txs, txInfo)
+	}
+
+	return xclient.NewTransactionHistoryPage(txs, nextCursor), nil
+}
@@ -0,0 +1,58 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/openweb3-io/crosschain/blockchain/evm/address"
+	"github.com/openweb3-io/crosschain/blockchain/evm/tx_input"
+	xc "github.com/openweb3-io/crosschain/types"
+)
+
+// The transactions from an address waiting in the txpool, by increasing nonce.  The first one is
+// what any later transactions are stuck behind.
+func (client *Client) FetchPendingTxs(ctx context.Context, from xc.Address) ([]*tx_input.PendingTx, error) {
+	fromAddr, err := address.FromHex(from)
+	if err != nil {
+		return nil, fmt.Errorf("bad from address '%v': %v", from, err)
+	}
+	result, err := client.TxPoolContentFrom(ctx, fromAddr)
+	if err != nil {
+		return nil, fmt.Errorf("could not see pending tx pool: %v", err)
+	}
+	pending := []*tx_input.PendingTx{}
+	for _, info := range result.Pending {
+		pending = append(pending, info.PendingTx())
+	}
+	sort.Slice(pending, func(i, j int) bool {
+		return pending[i].Nonce < pending[j].Nonce
+	})
+	return pending, nil
+}
+
+// Fetch the input to replace the pending transaction with the given nonce, either to speed it up
+// or to cancel it.  The fees are the current market fees, raised to what the node requires to
+// accept a replacement.
+func (client *Client) FetchReplacementInput(ctx context.Context, from xc.Address, nonce uint64) (*tx_input.TxInput, *tx_input.PendingTx, error) {
+	pendingTxs, err := client.FetchPendingTxs(ctx, from)
+	if err != nil {
+		return nil, nil, err
+	}
+	var pending *tx_input.PendingTx
+	for _, tx := range pendingTxs {
+		if tx.Nonce == nonce {
+			pending = tx
+		}
+	}
+	if pending == nil {
+		return nil, nil, fmt.Errorf("no pending transaction from %s with nonce %d", from, nonce)
+	}
+
+	input, err := client.FetchUnsimulatedInput(ctx, from)
+	if err != nil {
+		return nil, nil, err
+	}
+	input.SetReplacementOf(pending)
+	return input, pending, nil
+}
@@ -0,0 +1,39 @@
+package client_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/openweb3-io/crosschain/blockchain/evm/client"
+	testtypes "github.com/openweb3-io/crosschain/testutil/types"
+	xc_types "github.com/openweb3-io/crosschain/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFetchPendingTxs(t *testing.T) {
+	require := require.New(t)
+	server, close := testtypes.MockJSONRPC(t, `{"pending":{
+		"5":{"from":"0x724435cc1b2821362c2cd425f2744bd7347bf299","to":"0x3ad57b83b2e3dc5648f32e98e386935a9b10bb9f","nonce":"0x5","value":"0x64","input":"0x","gas":"0x5208","gasPrice":"0x3b9aca00","hash":"0x05"},
+		"4":{"from":"0x724435cc1b2821362c2cd425f2744bd7347bf299","to":"0x3ad57b83b2e3dc5648f32e98e386935a9b10bb9f","nonce":"0x4","value":"0x0","input":"0xa9059cbb","gas":"0x7a120","gasPrice":"0x4a817c800","maxFeePerGas":"0x4a817c800","maxPriorityFeePerGas":"0x3b9aca00","hash":"0x04"}
+	},"queued":{}}`)
+	defer close()
+
+	cli, err := client.NewClient(&xc_types.ChainConfig{Chain: xc_types.ETH, Client: &xc_types.ClientConfig{URL: server.URL}})
+	require.NoError(err)
+	pending, err := cli.FetchPendingTxs(context.Background(), "0x724435CC1B2821362c2CD425F2744Bd7347bf299")
+	require.NoError(err)
+	require.Len(pending, 2)
+
+	require.EqualValues(4, pending[0].Nonce)
+	require.Equal("0x04", pending[0].Hash)
+	require.Equal([]byte{0xa9, 0x05, 0x9c, 0xbb}, pending[0].Data)
+	require.EqualValues(500_000, pending[0].GasLimit)
+	require.EqualValues(1_000_000_000, pending[0].GasTipCap.Uint64())
+	require.EqualValues(20_000_000_000, pending[0].GasFeeCap.Uint64())
+
+	// legacy transactions pay the gas price as both the tip and fee cap
+	require.EqualValues(5, pending[1].Nonce)
+	require.EqualValues(100, pending[1].Value.Uint64())
+	require.EqualValues(1_000_000_000, pending[1].GasTipCap.Uint64())
+	require.EqualValues(1_000_000_000, pending[1].GasFeeCap.Uint64())
+}
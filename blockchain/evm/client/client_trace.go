@@ -8,6 +8,7 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/openweb3-io/crosschain/blockchain/evm/tx"
+	"github.com/openweb3-io/crosschain/blockchain/evm/tx_input"
 	xc_types "github.com/openweb3-io/crosschain/types"
 )
 
@@ -66,7 +67,7 @@ func (client *Client) TraceEthMovements(ctx context.Context, txHash common.Hash)
 	for _, trace := range traces {
 		if trace.Value.ToInt().Cmp(zero) > 0 {
 			amount := xc_types.BigInt(*trace.Value.ToInt())
-			sourcesAndDests.Sources = append(sourcesAndDests.Destinations, &xc_types.LegacyTxInfoEndpoint{
+			sourcesAndDests.Sources = append(sourcesAndDests.Sources, &xc_types.LegacyTxInfoEndpoint{
 				Address:     xc_types.Address(trace.From.String()),
 				Amount:      amount,
 				NativeAsset: native,
@@ -109,12 +110,37 @@ func (result *TxPoolResult) InfoFor(address string) (*TxPoolTxInfo, bool) {
 type TxPoolPendingMap struct {
 }
 type TxPoolTxInfo struct {
-	From                 string      `json:"from"`
-	Gas                  hexutil.Big `json:"gas"`
-	GasPrice             hexutil.Big `json:"gasPrice"`
-	MaxFeePerGas         hexutil.Big `json:"maxFeePerGas"`
-	MaxPriorityFeePerGas hexutil.Big `json:"maxPriorityFeePerGas"`
-	Hash                 string      `json:"hash"`
+	From                 string         `json:"from"`
+	To                   string         `json:"to"`
+	Nonce                hexutil.Uint64 `json:"nonce"`
+	Value                hexutil.Big    `json:"value"`
+	Input                hexutil.Bytes  `json:"input"`
+	Gas                  hexutil.Big    `json:"gas"`
+	GasPrice             hexutil.Big    `json:"gasPrice"`
+	MaxFeePerGas         hexutil.Big    `json:"maxFeePerGas"`
+	MaxPriorityFeePerGas hexutil.Big    `json:"maxPriorityFeePerGas"`
+	Hash                 string         `json:"hash"`
+}
+
+func (info *TxPoolTxInfo) PendingTx() *tx_input.PendingTx {
+	gasTipCap := xc_types.BigInt(*info.MaxPriorityFeePerGas.ToInt())
+	gasFeeCap := xc_types.BigInt(*info.MaxFeePerGas.ToInt())
+	if gasFeeCap.IsZero() {
+		// legacy transaction
+		gasTipCap = xc_types.BigInt(*info.GasPrice.ToInt())
+		gasFeeCap = gasTipCap
+	}
+	return &tx_input.PendingTx{
+		Hash:      info.Hash,
+		From:      xc_types.Address(info.From),
+		To:        xc_types.Address(info.To),
+		Nonce:     uint64(info.Nonce),
+		Value:     xc_types.BigInt(*info.Value.ToInt()),
+		Data:      info.Input,
+		GasLimit:  info.Gas.ToInt().Uint64(),
+		GasTipCap: gasTipCap,
+		GasFeeCap: gasFeeCap,
+	}
 }
 
 // Get current pending transaction queue for a given address
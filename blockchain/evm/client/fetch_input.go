@@ -5,10 +5,12 @@ import (
 	"fmt"
 	"math/big"
 	"strings"
+	"time"
 
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/openweb3-io/crosschain/blockchain/evm/abi/forwarder"
 	"github.com/openweb3-io/crosschain/blockchain/evm/address"
 	"github.com/openweb3-io/crosschain/blockchain/evm/builder"
 	"github.com/openweb3-io/crosschain/blockchain/evm/tx"
@@ -108,6 +110,15 @@ func (client *Client) GetNonce(ctx context.Context, from xc.Address) (uint64, er
 	return nonce, nil
 }
 
+// The next nonce including transactions pending in the node's txpool, unlike GetNonce which only counts mined ones
+func (client *Client) FetchNonce(ctx context.Context, from xc.Address) (uint64, error) {
+	fromAddr, err := address.FromHex(from)
+	if err != nil {
+		return 0, fmt.Errorf("bad from address '%v': %v", from, err)
+	}
+	return client.EthClient.PendingNonceAt(ctx, fromAddr)
+}
+
 func (client *Client) FetchTransferInput(ctx context.Context, args *xcbuilder.TransferArgs) (xc.TxInput, error) {
 	txInput, err := client.FetchUnsimulatedInput(ctx, args.GetFrom())
 	if err != nil {
@@ -125,7 +136,14 @@ func (client *Client) FetchTransferInput(ctx context.Context, args *xcbuilder.Tr
 	if err != nil {
 		return nil, fmt.Errorf("could not prepare to simulate: %v", err)
 	}
-	exampleTf, err := builder.NewTransfer(args, txInput)
+	_, sponsored := args.GetFeePayer()
+	var exampleTf xc.Tx
+	if sponsored {
+		// simulate the call the forwarder will make on behalf of the sender
+		exampleTf, err = builder.NewTokenTransfer(args, txInput)
+	} else {
+		exampleTf, err = builder.NewTransfer(args, txInput)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("could not prepare to simulate: %v", err)
 	}
@@ -135,9 +153,65 @@ func (client *Client) FetchTransferInput(ctx context.Context, args *xcbuilder.Tr
 		return nil, err
 	}
 	txInput.GasLimit = gasLimit
+
+	if sponsored {
+		txInput.Forward, err = client.FetchForwardInput(ctx, args.GetFrom())
+		if err != nil {
+			return nil, err
+		}
+	}
 	return txInput, nil
 }
 
+// Fetch the nonce and EIP-712 domain of the sender in the forwarder of the chain
+func (client *Client) FetchForwardInput(ctx context.Context, from xc.Address) (*tx_input.ForwardInput, error) {
+	if client.Chain.ForwarderContract == "" {
+		return nil, fmt.Errorf("no forwarder contract is configured for %s", client.Chain.Chain)
+	}
+	forwarderAddr, err := address.FromHex(xc.Address(client.Chain.ForwarderContract))
+	if err != nil {
+		return nil, fmt.Errorf("bad forwarder address '%v': %v", client.Chain.ForwarderContract, err)
+	}
+	fromAddr, err := address.FromHex(from)
+	if err != nil {
+		return nil, fmt.Errorf("bad from address '%v': %v", from, err)
+	}
+
+	data, err := forwarder.SerializeNonces(fromAddr)
+	if err != nil {
+		return nil, err
+	}
+	result, err := client.EthClient.CallContract(ctx, ethereum.CallMsg{To: &forwarderAddr, Data: data}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch forwarder nonce: %v", err)
+	}
+	nonce, err := forwarder.ParseNonces(result)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err = forwarder.SerializeEip712Domain()
+	if err != nil {
+		return nil, err
+	}
+	result, err = client.EthClient.CallContract(ctx, ethereum.CallMsg{To: &forwarderAddr, Data: data}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch forwarder domain: %v", err)
+	}
+	domain, err := forwarder.ParseEip712Domain(result)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tx_input.ForwardInput{
+		Forwarder: xc.Address(forwarderAddr.Hex()),
+		Name:      domain.Name,
+		Version:   domain.Version,
+		Nonce:     xc.BigInt(*nonce),
+		Deadline:  time.Now().Unix() + tx_input.DefaultForwardTTLSeconds,
+	}, nil
+}
+
 func (client *Client) FetchLegacyTxInput(ctx context.Context, from xc.Address, to xc.Address, asset xc.IAsset) (xc.TxInput, error) {
 	// No way to pass the amount in the input using legacy interface, so we estimate using min amount.
 	args, _ := xcbuilder.NewTransferArgs(from, to, xc.NewBigIntFromUint64(1), xcbuilder.WithAsset(asset))
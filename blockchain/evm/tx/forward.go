@@ -0,0 +1,148 @@
+package tx
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/openweb3-io/crosschain/blockchain/evm/abi/forwarder"
+	"github.com/openweb3-io/crosschain/factory/blockchains/registry"
+	xc_types "github.com/openweb3-io/crosschain/types"
+)
+
+var eip712DomainTypeHash = crypto.Keccak256([]byte("EIP712Domain(string name,string version,uint256 chainId,address verifyingContract)"))
+var forwardRequestTypeHash = crypto.Keccak256([]byte("ForwardRequest(address from,address to,uint256 value,uint256 gas,uint256 nonce,uint48 deadline,bytes data)"))
+
+// A call the sender signs for a relayer to submit through an ERC-2771 forwarder, so the relayer pays
+// the gas.  This does not need any ERC-4337 infrastructure, only a forwarder the target contract trusts.
+type ForwardRequest struct {
+	From     common.Address
+	To       common.Address
+	Value    *big.Int
+	Gas      uint64
+	Nonce    *big.Int
+	Deadline uint64
+	Data     []byte
+}
+
+// Signed by the sender only; the relayer then sends the serialized call to the forwarder in its
+// own transaction, which it signs separately.
+type ForwardTx struct {
+	Domain    forwarder.Domain
+	Request   ForwardRequest
+	Signature []byte
+}
+
+var _ xc_types.Tx = &ForwardTx{}
+var _ xc_types.TxWithSigners = &ForwardTx{}
+
+// Envelope type of unsigned forward requests, as they are signed differently from transactions of the chain
+const ForwardTxType = xc_types.Blockchain("evm-forward")
+
+func init() {
+	registry.RegisterUnsignedTx(ForwardTxType, &ForwardTx{})
+}
+
+func word(bz []byte) []byte {
+	return common.LeftPadBytes(bz, 32)
+}
+
+func (tx *ForwardTx) domainSeparator() []byte {
+	chainId := tx.Domain.ChainId
+	if chainId == nil {
+		chainId = big.NewInt(0)
+	}
+	return crypto.Keccak256(
+		eip712DomainTypeHash,
+		crypto.Keccak256([]byte(tx.Domain.Name)),
+		crypto.Keccak256([]byte(tx.Domain.Version)),
+		word(chainId.Bytes()),
+		word(tx.Domain.VerifyingContract.Bytes()),
+	)
+}
+
+func (tx *ForwardTx) structHash() []byte {
+	value := tx.Request.Value
+	if value == nil {
+		value = big.NewInt(0)
+	}
+	nonce := tx.Request.Nonce
+	if nonce == nil {
+		nonce = big.NewInt(0)
+	}
+	return crypto.Keccak256(
+		forwardRequestTypeHash,
+		word(tx.Request.From.Bytes()),
+		word(tx.Request.To.Bytes()),
+		word(value.Bytes()),
+		word(new(big.Int).SetUint64(tx.Request.Gas).Bytes()),
+		word(nonce.Bytes()),
+		word(new(big.Int).SetUint64(tx.Request.Deadline).Bytes()),
+		crypto.Keccak256(tx.Request.Data),
+	)
+}
+
+// The EIP-712 digest of the request, which the sender signs
+func (tx *ForwardTx) Digest() []byte {
+	return crypto.Keccak256([]byte{0x19, 0x01}, tx.domainSeparator(), tx.structHash())
+}
+
+// The digest identifies the request, as the hash of the relaying transaction is not known until it is sent
+func (tx *ForwardTx) Hash() xc_types.TxHash {
+	return xc_types.TxHash(common.BytesToHash(tx.Digest()).Hex())
+}
+
+func (tx *ForwardTx) Sighashes() ([]xc_types.TxDataToSign, error) {
+	return []xc_types.TxDataToSign{tx.Digest()}, nil
+}
+
+func (tx *ForwardTx) SignatureRequests() ([]*xc_types.SignatureRequest, error) {
+	return []*xc_types.SignatureRequest{
+		xc_types.NewSignatureRequest(xc_types.Address(tx.Request.From.Hex()), tx.Digest(), xc_types.K256Keccak),
+	}, nil
+}
+
+func (tx *ForwardTx) AddSignatures(signatures ...xc_types.TxSignature) error {
+	if len(signatures) != 1 {
+		return fmt.Errorf("expected 1 signature, got %d", len(signatures))
+	}
+	if len(signatures[0]) != crypto.SignatureLength {
+		return fmt.Errorf("invalid signature (%d): %x", len(signatures[0]), signatures[0])
+	}
+	signature := append([]byte{}, signatures[0]...)
+	// the forwarder recovers with ecrecover, which expects v to be 27 or 28
+	if signature[crypto.RecoveryIDOffset] < 27 {
+		signature[crypto.RecoveryIDOffset] += 27
+	}
+	tx.Signature = signature
+	return nil
+}
+
+func (tx *ForwardTx) GetSignatures() []xc_types.TxSignature {
+	if len(tx.Signature) == 0 {
+		return []xc_types.TxSignature{}
+	}
+	return []xc_types.TxSignature{tx.Signature}
+}
+
+// Serialize returns the call of the forwarder that relays the signed request
+func (tx *ForwardTx) Serialize() ([]byte, error) {
+	if len(tx.Signature) == 0 {
+		return nil, errors.New("request is not signed")
+	}
+	value := tx.Request.Value
+	if value == nil {
+		value = big.NewInt(0)
+	}
+	return forwarder.SerializeExecute(forwarder.ForwardRequestData{
+		From:      tx.Request.From,
+		To:        tx.Request.To,
+		Value:     value,
+		Gas:       new(big.Int).SetUint64(tx.Request.Gas),
+		Deadline:  new(big.Int).SetUint64(tx.Request.Deadline),
+		Data:      tx.Request.Data,
+		Signature: tx.Signature,
+	})
+}
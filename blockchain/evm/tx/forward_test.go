@@ -0,0 +1,120 @@
+package tx_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"github.com/openweb3-io/crosschain/blockchain/evm/abi/forwarder"
+	"github.com/openweb3-io/crosschain/blockchain/evm/tx"
+	xc_types "github.com/openweb3-io/crosschain/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestForwardTx(t *testing.T) {
+	require := require.New(t)
+	key, err := crypto.HexToECDSA("4646464646464646464646464646464646464646464646464646464646464646")
+	require.NoError(err)
+	from := crypto.PubkeyToAddress(key.PublicKey)
+	forwarderAddress := common.HexToAddress("0x5FbDB2315678afecb367f032d93F642f64180aa3")
+	token := common.HexToAddress("0xdAC17F958D2ee523a2206206994597C13D831ec7")
+
+	forwardTx := &tx.ForwardTx{
+		Domain: forwarder.Domain{
+			Name:              "ERC2771Forwarder",
+			Version:           "1",
+			ChainId:           big.NewInt(1),
+			VerifyingContract: forwarderAddress,
+		},
+		Request: tx.ForwardRequest{
+			From:     from,
+			To:       token,
+			Value:    big.NewInt(0),
+			Gas:      65_000,
+			Nonce:    big.NewInt(3),
+			Deadline: 1_700_000_000,
+			Data:     []byte{0xa9, 0x05, 0x9c, 0xbb, 0x01},
+		},
+	}
+
+	// same digest as the generic EIP-712 implementation
+	typedData := apitypes.TypedData{
+		Types: apitypes.Types{
+			"EIP712Domain": {
+				{Name: "name", Type: "string"},
+				{Name: "version", Type: "string"},
+				{Name: "chainId", Type: "uint256"},
+				{Name: "verifyingContract", Type: "address"},
+			},
+			"ForwardRequest": {
+				{Name: "from", Type: "address"},
+				{Name: "to", Type: "address"},
+				{Name: "value", Type: "uint256"},
+				{Name: "gas", Type: "uint256"},
+				{Name: "nonce", Type: "uint256"},
+				{Name: "deadline", Type: "uint48"},
+				{Name: "data", Type: "bytes"},
+			},
+		},
+		PrimaryType: "ForwardRequest",
+		Domain: apitypes.TypedDataDomain{
+			Name:              "ERC2771Forwarder",
+			Version:           "1",
+			ChainId:           math.NewHexOrDecimal256(1),
+			VerifyingContract: forwarderAddress.Hex(),
+		},
+		Message: apitypes.TypedDataMessage{
+			"from":     from.Hex(),
+			"to":       token.Hex(),
+			"value":    "0",
+			"gas":      "65000",
+			"nonce":    "3",
+			"deadline": "1700000000",
+			"data":     hexutil.Encode(forwardTx.Request.Data),
+		},
+	}
+	expected, _, err := apitypes.TypedDataAndHash(typedData)
+	require.NoError(err)
+	require.Equal(expected, forwardTx.Digest())
+
+	requests, err := forwardTx.SignatureRequests()
+	require.NoError(err)
+	require.Len(requests, 1)
+	require.EqualValues(from.Hex(), requests[0].Signer)
+
+	_, err = forwardTx.Serialize()
+	require.ErrorContains(err, "not signed")
+	signature, err := crypto.Sign(requests[0].Payload, key)
+	require.NoError(err)
+	require.NoError(forwardTx.AddSignatures(signature))
+	// v is moved to 27/28 for ecrecover
+	require.Equal(signature[64]+27, forwardTx.Signature[64])
+	require.Equal([]xc_types.TxSignature{forwardTx.Signature}, forwardTx.GetSignatures())
+
+	// relays the signed request
+	data, err := forwardTx.Serialize()
+	require.NoError(err)
+	method := forwarder.NewAbi().Methods["execute"]
+	require.Equal(method.ID, data[:4])
+	values, err := method.Inputs.Unpack(data[4:])
+	require.NoError(err)
+	request := values[0].(struct {
+		From      common.Address `json:"from"`
+		To        common.Address `json:"to"`
+		Value     *big.Int       `json:"value"`
+		Gas       *big.Int       `json:"gas"`
+		Deadline  *big.Int       `json:"deadline"`
+		Data      []byte         `json:"data"`
+		Signature []byte         `json:"signature"`
+	})
+	require.Equal(from, request.From)
+	require.Equal(token, request.To)
+	require.EqualValues(65_000, request.Gas.Uint64())
+	require.EqualValues(1_700_000_000, request.Deadline.Uint64())
+	require.Equal(forwardTx.Request.Data, request.Data)
+	require.Equal(forwardTx.Signature, request.Signature)
+}
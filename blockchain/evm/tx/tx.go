@@ -3,7 +3,6 @@ package tx
 import (
 	"encoding/hex"
 	"errors"
-	"fmt"
 	"math/big"
 	"strings"
 
@@ -11,6 +10,7 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/openweb3-io/crosschain/blockchain/evm/abi/erc20"
+	"github.com/openweb3-io/crosschain/factory/blockchains/registry"
 	xc_types "github.com/openweb3-io/crosschain/types"
 )
 
@@ -22,14 +22,19 @@ func init() {
 	if err != nil {
 		panic(err)
 	}
+	registry.RegisterUnsignedTx(xc_types.BlockchainEVM, &Tx{})
 }
 
 type Tx struct {
 	EthTx      *types.Transaction
 	Signer     types.Signer
 	Signatures []xc_types.TxSignature
+	// The address expected to sign, as it cannot be recovered before the transaction is signed
+	Sender xc_types.Address
 }
 
+var _ xc_types.TxWithSigners = &Tx{}
+
 type SourcesAndDests struct {
 	Sources      []*xc_types.LegacyTxInfoEndpoint
 	Destinations []*xc_types.LegacyTxInfoEndpoint
@@ -51,6 +56,20 @@ func (tx *Tx) Sighashes() ([]xc_types.TxDataToSign, error) {
 	return []xc_types.TxDataToSign{sighash}, nil
 }
 
+func (tx *Tx) SignatureRequests() ([]*xc_types.SignatureRequest, error) {
+	sighashes, err := tx.Sighashes()
+	if err != nil {
+		return nil, err
+	}
+	sender := tx.Sender
+	if sender == "" {
+		sender = tx.From()
+	}
+	return []*xc_types.SignatureRequest{
+		xc_types.NewSignatureRequest(sender, sighashes[0], xc_types.K256Keccak),
+	}, nil
+}
+
 // AddSignatures adds a signature to Tx
 func (tx *Tx) AddSignatures(signatures ...xc_types.TxSignature) error {
 	if tx.EthTx == nil {
@@ -84,15 +103,15 @@ func (tx *Tx) ParseTokenLogs(receipt *types.Receipt, nativeAsset xc_types.Native
 	loggedSources := []*xc_types.LegacyTxInfoEndpoint{}
 	loggedDestinations := []*xc_types.LegacyTxInfoEndpoint{}
 	for _, log := range receipt.Logs {
-		event, _ := ERC20.EventByID(log.Topics[0])
-		if event != nil {
-			fmt.Println("PARSE LOG", event.RawName)
+		// anonymous events have no topics
+		if len(log.Topics) == 0 {
+			continue
 		}
+		event, _ := ERC20.EventByID(log.Topics[0])
 		if event != nil && event.RawName == "Transfer" {
 			erc20, _ := erc20.NewErc20(receipt.ContractAddress, nil)
 			tf, err := erc20.ParseTransfer(*log)
 			if err != nil {
-				fmt.Println("could not parse log: ", log.Index)
 				continue
 			}
 			loggedDestinations = append(loggedDestinations, &xc_types.LegacyTxInfoEndpoint{
@@ -211,10 +230,10 @@ func ensure0x(address string) string {
 }
 
 // ParseERC20TransferTx parses the tx payload as ERC20 transfer
-func (tx *Tx) ParseERC20TransferTx(nativeAsset xc_types.NativeAsset) (SourcesAndDests, error) {
-	payload := tx.EthTx.Data()
+// ParseERC20Transfer decodes the recipient and amount of the calldata of ERC20.transfer(address,uint256)
+func ParseERC20Transfer(payload []byte) (xc_types.Address, *big.Int, error) {
 	if len(payload) != 4+32*2 || hex.EncodeToString(payload[:4]) != "a9059cbb" {
-		return SourcesAndDests{}, errors.New("payload is not ERC20.transfer(address,uint256)")
+		return "", nil, errors.New("payload is not ERC20.transfer(address,uint256)")
 	}
 
 	var buf1 [20]byte
@@ -223,6 +242,14 @@ func (tx *Tx) ParseERC20TransferTx(nativeAsset xc_types.NativeAsset) (SourcesAnd
 	var buf2 [32]byte
 	copy(buf2[:], payload[4+32:4+2*32])
 	amount := new(big.Int).SetBytes(buf2[:])
+	return to, amount, nil
+}
+
+func (tx *Tx) ParseERC20TransferTx(nativeAsset xc_types.NativeAsset) (SourcesAndDests, error) {
+	to, amount, err := ParseERC20Transfer(tx.EthTx.Data())
+	if err != nil {
+		return SourcesAndDests{}, err
+	}
 
 	return SourcesAndDests{
 		// the from should be the tx sender
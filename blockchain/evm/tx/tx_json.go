@@ -0,0 +1,56 @@
+package tx
+
+import (
+	"encoding/json"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	xc_types "github.com/openweb3-io/crosschain/types"
+)
+
+type txJSON struct {
+	EthTx []byte `json:"eth_tx"`
+	// Legacy transactions only commit to their chain once signed, so it is kept with the transaction
+	ChainId    *big.Int               `json:"chain_id"`
+	Signatures []xc_types.TxSignature `json:"signatures,omitempty"`
+	Sender     xc_types.Address       `json:"sender,omitempty"`
+}
+
+func (tx Tx) MarshalJSON() ([]byte, error) {
+	encoded := txJSON{
+		Signatures: tx.Signatures,
+		Sender:     tx.Sender,
+	}
+	if tx.EthTx != nil {
+		var err error
+		if encoded.EthTx, err = tx.EthTx.MarshalBinary(); err != nil {
+			return nil, err
+		}
+	}
+	if tx.Signer != nil {
+		encoded.ChainId = tx.Signer.ChainID()
+	}
+	return json.Marshal(encoded)
+}
+
+func (tx *Tx) UnmarshalJSON(data []byte) error {
+	var decoded txJSON
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	ethTx := &types.Transaction{}
+	if err := ethTx.UnmarshalBinary(decoded.EthTx); err != nil {
+		return err
+	}
+	chainId := decoded.ChainId
+	if chainId == nil {
+		chainId = ethTx.ChainId()
+	}
+	*tx = Tx{
+		EthTx:      ethTx,
+		Signer:     types.LatestSignerForChainID(chainId),
+		Signatures: decoded.Signatures,
+		Sender:     decoded.Sender,
+	}
+	return nil
+}
@@ -1,8 +1,12 @@
 package tx_test
 
 import (
+	"math/big"
 	"testing"
 
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/openweb3-io/crosschain/blockchain/evm/tx"
 	xc_types "github.com/openweb3-io/crosschain/types"
 	"github.com/stretchr/testify/require"
@@ -25,3 +29,48 @@ func TestTxAddSignatureEmpty(t *testing.T) {
 	err := tx.AddSignatures([]xc_types.TxSignature{}...)
 	require.EqualError(t, err, "transaction not initialized")
 }
+
+func TestTxSignatureRequests(t *testing.T) {
+	require := require.New(t)
+	chainId := big.NewInt(1)
+	sender := xc_types.Address("0x724435CC1B2821362c2CD425F2744Bd7347bf299")
+	ethTx := types.NewTx(&types.DynamicFeeTx{ChainID: chainId, Nonce: 1, Gas: 21000})
+	tx := tx.Tx{
+		EthTx:  ethTx,
+		Signer: types.LatestSignerForChainID(chainId),
+		Sender: sender,
+	}
+	requests, err := tx.SignatureRequests()
+	require.NoError(err)
+	require.Len(requests, 1)
+	require.Equal(sender, requests[0].Signer)
+	require.Equal(xc_types.K256Keccak, requests[0].Algorithm)
+	require.EqualValues(tx.Signer.Hash(ethTx).Bytes(), requests[0].Payload)
+}
+
+func TestParseTokenLogs(t *testing.T) {
+	require := require.New(t)
+	from := common.HexToAddress("0x724435CC1B2821362c2CD425F2744Bd7347bf299")
+	to := common.HexToAddress("0x3ad57b83B2E3dC5648F32e98e386935A9B10bb9F")
+	token := common.HexToAddress("0xdAC17F958D2ee523a2206206994597C13D831ec7")
+	receipt := &types.Receipt{Logs: []*types.Log{
+		// an anonymous event
+		{Address: token, Data: []byte{1}},
+		{
+			Address: token,
+			Topics: []common.Hash{
+				crypto.Keccak256Hash([]byte("Transfer(address,address,uint256)")),
+				common.BytesToHash(from.Bytes()),
+				common.BytesToHash(to.Bytes()),
+			},
+			Data: common.BigToHash(big.NewInt(1000)).Bytes(),
+		},
+	}}
+	parsed := (&tx.Tx{}).ParseTokenLogs(receipt, xc_types.ETH)
+	require.Len(parsed.Destinations, 1)
+	require.Equal(xc_types.Address(to.String()), parsed.Destinations[0].Address)
+	require.Equal(xc_types.ContractAddress(token.String()), parsed.Destinations[0].ContractAddress)
+	require.Equal("1000", parsed.Destinations[0].Amount.String())
+	require.Len(parsed.Sources, 1)
+	require.Equal(xc_types.Address(from.String()), parsed.Sources[0].Address)
+}
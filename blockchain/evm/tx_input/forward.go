@@ -0,0 +1,21 @@
+package tx_input
+
+import (
+	xc "github.com/openweb3-io/crosschain/types"
+)
+
+// How long a relayer has to submit a forwarded request before the forwarder rejects it
+const DefaultForwardTTLSeconds = 3600
+
+// A request relayed through an ERC-2771 forwarder, so the fee payer pays the gas instead of the sender.
+// The target contract must trust the forwarder.
+type ForwardInput struct {
+	Forwarder xc.Address `json:"forwarder"`
+	// EIP-712 domain of the forwarder
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	// The sender's nonce in the forwarder, which is separate from the nonce of its account
+	Nonce xc.BigInt `json:"nonce"`
+	// Unix time after which the forwarder rejects the request
+	Deadline int64 `json:"deadline"`
+}
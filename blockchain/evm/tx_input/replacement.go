@@ -0,0 +1,58 @@
+package tx_input
+
+import (
+	"math/big"
+
+	xc "github.com/openweb3-io/crosschain/types"
+)
+
+// Nodes only accept a transaction with the same nonce as a pending one if it raises both the
+// tip and the fee cap by at least this much (geth's default txpool.pricebump).
+const ReplacementPriceBumpPercent = 10
+
+// A transaction from an address that is waiting in the txpool
+type PendingTx struct {
+	Hash     string     `json:"hash"`
+	From     xc.Address `json:"from"`
+	To       xc.Address `json:"to"`
+	Nonce    uint64     `json:"nonce"`
+	Value    xc.BigInt  `json:"value"`
+	Data     []byte     `json:"data"`
+	GasLimit uint64     `json:"gas_limit"`
+	// for legacy transactions, both are the gas price
+	GasTipCap xc.BigInt `json:"gas_tip_cap"`
+	GasFeeCap xc.BigInt `json:"gas_fee_cap"`
+}
+
+func bumpForReplacement(fee xc.BigInt) xc.BigInt {
+	// round up so integer division never lands below the node's threshold
+	bumped := new(big.Int).Mul(fee.Int(), big.NewInt(100+ReplacementPriceBumpPercent))
+	bumped.Add(bumped, big.NewInt(99))
+	bumped.Div(bumped, big.NewInt(100))
+	return xc.BigInt(*bumped)
+}
+
+// The lowest tip and fee cap that a transaction replacing this one can pay
+func (tx *PendingTx) MinReplacementFees() (gasTipCap xc.BigInt, gasFeeCap xc.BigInt) {
+	return bumpForReplacement(tx.GasTipCap), bumpForReplacement(tx.GasFeeCap)
+}
+
+// Reuse the nonce of a pending transaction, raising the fees if needed so the node accepts
+// the new transaction as its replacement.  The input is then not independent of the input of the
+// pending transaction, so only one of them can be included.
+func (input *TxInput) SetReplacementOf(pending *PendingTx) {
+	input.Nonce = pending.Nonce
+	minTipCap, minFeeCap := pending.MinReplacementFees()
+	if input.GasTipCap.Cmp(&minTipCap) < 0 {
+		input.GasTipCap = minTipCap
+	}
+	if input.GasFeeCap.Cmp(&minFeeCap) < 0 {
+		input.GasFeeCap = minFeeCap
+	}
+	if input.GasFeeCap.Cmp(&input.GasTipCap) < 0 {
+		input.GasFeeCap = input.GasTipCap
+	}
+	if input.GasLimit < pending.GasLimit {
+		input.GasLimit = pending.GasLimit
+	}
+}
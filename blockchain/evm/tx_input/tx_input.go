@@ -27,9 +27,13 @@ type TxInput struct {
 
 	// legacy only
 	Prices []*Price `json:"prices,omitempty"`
+
+	// Set when a fee payer relays the transaction
+	Forward *ForwardInput `json:"forward,omitempty"`
 }
 
 var _ xc.TxInput = &TxInput{}
+var _ xc.TxInputWithNonce = &TxInput{}
 
 func init() {
 	registry.RegisterTxBaseInput(&TxInput{})
@@ -69,9 +73,20 @@ func (input *TxInput) SetGasFeePriority(other xc.GasFeePriority) error {
 	return nil
 }
 
+func (input *TxInput) SetNonce(nonce uint64) {
+	input.Nonce = nonce
+}
+
 func (input *TxInput) IndependentOf(other xc.TxInput) (independent bool) {
 	// different sequence means independence
 	if evmOther, ok := other.(*TxInput); ok {
+		if input.Forward != nil || evmOther.Forward != nil {
+			// forwarded requests are sequenced by the forwarder instead
+			if input.Forward == nil || evmOther.Forward == nil || input.Forward.Forwarder != evmOther.Forward.Forwarder {
+				return true
+			}
+			return input.Forward.Nonce.Cmp(&evmOther.Forward.Nonce) != 0
+		}
 		return evmOther.Nonce != input.Nonce
 	}
 	return
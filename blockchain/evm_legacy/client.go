@@ -18,6 +18,9 @@ type Client struct {
 }
 
 var _ xclient.IClient = &Client{}
+var _ xclient.FinalityClient = &Client{}
+var _ xclient.BlockClient = &Client{}
+var _ xclient.HistoryClient = &Client{}
 
 type TxInput evminput.TxInput
 
@@ -130,3 +133,19 @@ func (client *Client) FetchBalanceForAsset(ctx context.Context, address xc.Addre
 func (client *Client) EstimateGasFee(ctx context.Context, tx xc.Tx) (*xc.BigInt, error) {
 	return client.evmClient.EstimateGasFee(ctx, tx)
 }
+
+func (client *Client) FetchFinalizedHeight(ctx context.Context) (uint64, error) {
+	return client.evmClient.FetchFinalizedHeight(ctx)
+}
+
+func (client *Client) FetchLatestHeight(ctx context.Context) (uint64, error) {
+	return client.evmClient.FetchLatestHeight(ctx)
+}
+
+func (client *Client) FetchBlock(ctx context.Context, height uint64) (*xclient.BlockWithTransactions, error) {
+	return client.evmClient.FetchBlock(ctx, height)
+}
+
+func (client *Client) FetchTransactionsByAddress(ctx context.Context, args xclient.TransactionHistoryArgs) (*xclient.TransactionHistoryPage, error) {
+	return client.evmClient.FetchTransactionsByAddress(ctx, args)
+}
@@ -22,10 +22,16 @@ const MaxTokenTransfers = 20
 const MaxAccountUnstakes = 20
 const MaxAccountWithdraws = 20
 
+// Max number of native transfers that fit in the 1232 byte limit of a solana transaction,
+// as each recipient adds an account key and a transfer instruction.
+const MaxNativeTransfers = 20
+
 type TxBuilder struct {
 	Chain *xc_types.ChainConfig
 }
 
+var _ xcbuilder.TxBatchBuilder = &TxBuilder{}
+
 func NewTxBuilder(chain *xc_types.ChainConfig) (*TxBuilder, error) {
 	return &TxBuilder{
 		Chain: chain,
@@ -65,6 +71,10 @@ func (b *TxBuilder) NewTokenTransfer(args *xcbuilder.TransferArgs, input xc_type
 	if err != nil {
 		return nil, err
 	}
+	feePayer, err := getFeePayer(args, accountFrom)
+	if err != nil {
+		return nil, err
+	}
 
 	accountContract, err := solana.PublicKeyFromBase58(string(contract))
 	if err != nil {
@@ -104,8 +114,9 @@ func (b *TxBuilder) NewTokenTransfer(args *xcbuilder.TransferArgs, input xc_type
 
 	instructions := []solana.Instruction{}
 	if txInput.ShouldCreateATA {
+		// the fee payer also pays the rent of the new account
 		createAta := ata.NewCreateInstruction(
-			accountFrom,
+			feePayer,
 			accountTo,
 			accountContract,
 		).Build()
@@ -177,14 +188,33 @@ func (b *TxBuilder) NewTokenTransfer(args *xcbuilder.TransferArgs, input xc_type
 		)
 	}
 
-	return b.buildSolanaTx(instructions, accountFrom, txInput)
+	return b.buildSponsoredSolanaTx(instructions, accountFrom, feePayer, txInput)
 }
 
 func (txBuilder TxBuilder) buildSolanaTx(instructions []solana.Instruction, accountFrom solana.PublicKey, txInput *tx_input.TxInput) (*tx.Tx, error) {
+	return txBuilder.buildSponsoredSolanaTx(instructions, accountFrom, accountFrom, txInput)
+}
+
+// Build a transaction whose fees are paid by the fee payer, which then signs first
+func (txBuilder TxBuilder) buildSponsoredSolanaTx(instructions []solana.Instruction, accountFrom solana.PublicKey, feePayer solana.PublicKey, txInput *tx_input.TxInput) (*tx.Tx, error) {
+	if nonce := txInput.DurableNonce; nonce != nil {
+		// the authority must be one of the signers already
+		if !nonce.Authority.Equals(accountFrom) && !nonce.Authority.Equals(feePayer) {
+			return nil, fmt.Errorf("nonce account %s must be authorized to the sender %s or fee payer %s", nonce.Account, accountFrom, feePayer)
+		}
+		// advancing the nonce must be the first instruction
+		instructions = append([]solana.Instruction{
+			system.NewAdvanceNonceAccountInstruction(
+				nonce.Account,
+				solana.SysVarRecentBlockHashesPubkey,
+				nonce.Authority,
+			).Build(),
+		}, instructions...)
+	}
 	tx1, err := solana.NewTransaction(
 		instructions,
 		txInput.RecentBlockHash,
-		solana.TransactionPayer(accountFrom),
+		solana.TransactionPayer(feePayer),
 	)
 	if err != nil {
 		return nil, err
@@ -194,6 +224,17 @@ func (txBuilder TxBuilder) buildSolanaTx(instructions []solana.Instruction, acco
 	}, nil
 }
 
+// The account paying the fees, which is the sender unless another fee payer is set
+func getFeePayer(args interface {
+	GetFeePayer() (xc_types.Address, bool)
+}, accountFrom solana.PublicKey) (solana.PublicKey, error) {
+	feePayer, ok := args.GetFeePayer()
+	if !ok {
+		return accountFrom, nil
+	}
+	return solana.PublicKeyFromBase58(string(feePayer))
+}
+
 func (b *TxBuilder) NewNativeTransfer(args *xcbuilder.TransferArgs, input xc_types.TxInput) (xc_types.Tx, error) {
 	txInput := input.(*tx_input.TxInput)
 
@@ -201,6 +242,10 @@ func (b *TxBuilder) NewNativeTransfer(args *xcbuilder.TransferArgs, input xc_typ
 	if err != nil {
 		return nil, err
 	}
+	feePayer, err := getFeePayer(args, accountFrom)
+	if err != nil {
+		return nil, err
+	}
 
 	accountTo, err := solana.PublicKeyFromBase58(string(args.GetTo()))
 	if err != nil {
@@ -220,17 +265,52 @@ func (b *TxBuilder) NewNativeTransfer(args *xcbuilder.TransferArgs, input xc_typ
 		instructions = append(instructions, compute_budget.NewSetComputeUnitPriceInstruction(prioprityFee).Build())
 	}
 
-	solTx, err := solana.NewTransaction(
-		instructions,
-		txInput.RecentBlockHash,
-	)
+	return b.buildSponsoredSolanaTx(instructions, accountFrom, feePayer, txInput)
+}
+
+// Pay each recipient with its own system transfer instruction
+func (b *TxBuilder) NewBatchTransfer(args *xcbuilder.BatchTransferArgs, input xc_types.TxInput) (xc_types.Tx, error) {
+	txInput := input.(*tx_input.TxInput)
+	if asset, _ := args.GetAsset(); asset != nil {
+		if _, ok := asset.(*xc_types.TokenAssetConfig); ok {
+			return nil, errors.New("not implemented: batch transfer of solana tokens")
+		}
+	}
+	recipients := args.GetRecipients()
+	if len(recipients) > MaxNativeTransfers {
+		return nil, fmt.Errorf("cannot pay more than %d recipients in a single tx", MaxNativeTransfers)
+	}
+
+	accountFrom, err := solana.PublicKeyFromBase58(string(args.GetFrom()))
+	if err != nil {
+		return nil, err
+	}
+	feePayer, err := getFeePayer(args, accountFrom)
 	if err != nil {
 		return nil, err
 	}
 
-	return &tx.Tx{
-		SolTx: solTx,
-	}, nil
+	instructions := []solana.Instruction{}
+	for _, recipient := range recipients {
+		accountTo, err := solana.PublicKeyFromBase58(string(recipient.To))
+		if err != nil {
+			return nil, err
+		}
+		instructions = append(instructions,
+			system.NewTransferInstruction(
+				recipient.Amount.Uint64(),
+				accountFrom,
+				accountTo,
+			).Build(),
+		)
+	}
+
+	priorityFee := txInput.GetLimitedPrioritizationFee(b.Chain)
+	if priorityFee > 0 {
+		instructions = append(instructions, compute_budget.NewSetComputeUnitPriceInstruction(priorityFee).Build())
+	}
+
+	return b.buildSponsoredSolanaTx(instructions, accountFrom, feePayer, txInput)
 }
 
 func (txBuilder TxBuilder) NewTask(args *xcbuilder.TransferArgs, input xc_types.TxInput) (xc_types.Tx, error) {
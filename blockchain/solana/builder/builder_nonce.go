@@ -0,0 +1,58 @@
+package builder
+
+import (
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/programs/system"
+	"github.com/openweb3-io/crosschain/blockchain/solana/tx_input"
+	xc_types "github.com/openweb3-io/crosschain/types"
+)
+
+// Size of the state of a nonce account
+const NonceAccountSize = 80
+
+// Create a new nonce account funded by the sender, with the authority that may advance it.
+// Transactions built against it must be signed by the authority.
+func (txBuilder TxBuilder) NewCreateNonceAccount(from xc_types.Address, authority xc_types.Address, input *tx_input.CreateNonceAccountInput) (xc_types.Tx, error) {
+	accountFrom, err := solana.PublicKeyFromBase58(string(from))
+	if err != nil {
+		return nil, err
+	}
+	accountAuthority, err := solana.PublicKeyFromBase58(string(authority))
+	if err != nil {
+		return nil, err
+	}
+	nonceAccount := input.NonceKey.PublicKey()
+
+	instructions := []solana.Instruction{
+		system.NewCreateAccountInstruction(input.Lamports.Uint64(), NonceAccountSize, solana.SystemProgramID, accountFrom, nonceAccount).Build(),
+		system.NewInitializeNonceAccountInstruction(accountAuthority, nonceAccount, solana.SysVarRecentBlockHashesPubkey, solana.SysVarRentPubkey).Build(),
+	}
+	tx, err := txBuilder.buildSolanaTx(instructions, accountFrom, &input.TxInput)
+	if err != nil {
+		return nil, err
+	}
+	// The transient key behind the new nonce account must sign the transaction also
+	tx.AddTransientSigner(input.NonceKey)
+	return tx, nil
+}
+
+// Hand the authority over the nonce account to another address.  Must be sent by the current authority.
+func (txBuilder TxBuilder) NewAuthorizeNonceAccount(authority xc_types.Address, nonceAccount xc_types.Address, newAuthority xc_types.Address, input *tx_input.TxInput) (xc_types.Tx, error) {
+	accountAuthority, err := solana.PublicKeyFromBase58(string(authority))
+	if err != nil {
+		return nil, err
+	}
+	accountNonce, err := solana.PublicKeyFromBase58(string(nonceAccount))
+	if err != nil {
+		return nil, err
+	}
+	accountNewAuthority, err := solana.PublicKeyFromBase58(string(newAuthority))
+	if err != nil {
+		return nil, err
+	}
+
+	instructions := []solana.Instruction{
+		system.NewAuthorizeNonceAccountInstruction(accountNewAuthority, accountNonce, accountAuthority).Build(),
+	}
+	return txBuilder.buildSolanaTx(instructions, accountAuthority, input)
+}
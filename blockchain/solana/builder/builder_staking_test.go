@@ -49,6 +49,12 @@ func TestNewStakingTransfer(t *testing.T) {
 	// delegated to validator
 	require.Equal(t, input.ValidatorVoteAccount, stakes[0].GetVoteAccount().PublicKey)
 	require.Equal(t, stakeKey.PublicKey(), stakes[0].GetStakeAccount().PublicKey)
+
+	decoder, _ := builder.NewTxDecoder(&xc_types.ChainConfig{Chain: xc_types.SOL})
+	info, err := decoder.DecodeTx(tx)
+	require.NoError(t, err)
+	require.Len(t, info.Stakes, 1)
+	require.Equal(t, amount.String(), info.Stakes[0].Balance.String())
 }
 
 func TestNewUnstakeTransfer(t *testing.T) {
@@ -110,6 +116,10 @@ func TestNewUnstakeTransfer(t *testing.T) {
 	// 5 SOL remainder to be split (along with the inactive stakes)
 	require.EqualValues(t, 5_000_000_000+2282880*3, *splits[0].Lamports)
 
+	decoder, _ := builder.NewTxDecoder(&xc_types.ChainConfig{Chain: xc_types.SOL})
+	info, err := decoder.DecodeTx(tx)
+	require.NoError(t, err)
+	require.Len(t, info.Unstakes, 3)
 }
 func TestNewWithdrawTransfer(t *testing.T) {
 
@@ -157,4 +167,10 @@ func TestNewWithdrawTransfer(t *testing.T) {
 	fmt.Println(amount.Uint64())
 	fmt.Println(total)
 	require.EqualValues(t, amount.Uint64(), total)
+
+	decoder, _ := builder.NewTxDecoder(&xc_types.ChainConfig{Chain: xc_types.SOL})
+	info, err := decoder.DecodeTx(tx)
+	require.NoError(t, err)
+	// the two withdrawals and the fee
+	require.Len(t, info.Transfers, 3)
 }
@@ -45,6 +45,39 @@ func TestNewNativeTransfer(t *testing.T) {
 	require.Equal(t, uint16(0x2), solTx.Message.Instructions[0].ProgramIDIndex) // system tx
 }
 
+func TestNewBatchTransfer(t *testing.T) {
+	require := require.New(t)
+	txBuilder, _ := builder.NewTxBuilder(&xc_types.ChainConfig{})
+	recipients := []xcbuilder.Recipient{
+		{To: "BWbmXj5ckAaWCAtzMZ97qnJhBAKegoXtgNrv9BUpAB11", Amount: xc_types.NewBigIntFromUint64(1000)},
+		{To: "Hzn3n914JaSpnxo5mBbmuCDmGL6mxWN9Ac2HzEXFSGtc", Amount: xc_types.NewBigIntFromUint64(2000)},
+		{To: "5qWgSWsCGUCbfYTsKhmNPFZwwYw8MJGR3bmyyjWYPpXF", Amount: xc_types.NewBigIntFromUint64(3000)},
+	}
+	args, err := xcbuilder.NewBatchTransferArgs("Hzn3n914JaSpnxo5mBbmuCDmGL6mxWN9Ac2HzEXFSGtb", recipients)
+	require.NoError(err)
+
+	input := &tx_input.TxInput{PrioritizationFee: xc_types.NewBigIntFromUint64(100)}
+	tx, err := txBuilder.NewBatchTransfer(args, input)
+	require.NoError(err)
+	solTx := tx.(*Tx).SolTx
+	// a transfer per recipient plus the priority fee
+	require.Len(solTx.Message.Instructions, 4)
+	require.Equal("Hzn3n914JaSpnxo5mBbmuCDmGL6mxWN9Ac2HzEXFSGtb", solTx.Message.AccountKeys[0].String())
+	for i, recipient := range recipients {
+		instruction := solTx.Message.Instructions[i]
+		require.Equal(solana.SystemProgramID, solTx.Message.AccountKeys[instruction.ProgramIDIndex])
+		require.Equal(string(recipient.To), solTx.Message.AccountKeys[instruction.Accounts[1]].String())
+	}
+
+	for len(recipients) <= builder.MaxNativeTransfers {
+		recipients = append(recipients, recipients[0])
+	}
+	args, err = xcbuilder.NewBatchTransferArgs("Hzn3n914JaSpnxo5mBbmuCDmGL6mxWN9Ac2HzEXFSGtb", recipients)
+	require.NoError(err)
+	_, err = txBuilder.NewBatchTransfer(args, input)
+	require.ErrorContains(err, "cannot pay more than")
+}
+
 func TestNewNativeTransferErr(t *testing.T) {
 
 	builder, _ := builder.NewTxBuilder(&xc_types.ChainConfig{})
@@ -392,3 +425,42 @@ func TestNewTransferAsToken(t *testing.T) {
 		require.Equal(t, v.expectedSourceAccount, tokenTf.Accounts[0].PublicKey.String())
 	}
 }
+
+func TestNewNativeTransferWithFeePayer(t *testing.T) {
+	require := require.New(t)
+	txBuilder, _ := builder.NewTxBuilder(&xc_types.ChainConfig{})
+	from := "Hzn3n914JaSpnxo5mBbmuCDmGL6mxWN9Ac2HzEXFSGtb"
+	feePayer := "BWbmXj5ckAaWCAtzMZ97qnJhBAKegoXtgNrv9BUpAB11"
+	args, err := xcbuilder.NewTransferArgs(
+		xc_types.Address(from),
+		"5qWgSWsCGUCbfYTsKhmNPFZwwYw8MJGR3bmyyjWYPpXF",
+		xc_types.NewBigIntFromUint64(1000),
+		xcbuilder.WithFeePayer(xc_types.Address(feePayer)),
+	)
+	require.NoError(err)
+
+	tx, err := txBuilder.NewNativeTransfer(args, &tx_input.TxInput{})
+	require.NoError(err)
+	solTx := tx.(*Tx).SolTx
+	require.Equal(feePayer, solTx.Message.AccountKeys[0].String())
+	require.EqualValues(2, solTx.Message.Header.NumRequiredSignatures)
+
+	// the fee payer signs first
+	requests, err := tx.(*Tx).SignatureRequests()
+	require.NoError(err)
+	require.Len(requests, 2)
+	require.EqualValues(feePayer, requests[0].Signer)
+	require.EqualValues(from, requests[1].Signer)
+	require.Equal(requests[0].Payload, requests[1].Payload)
+	sighashes, err := tx.Sighashes()
+	require.NoError(err)
+	require.Len(sighashes, 2)
+
+	require.ErrorContains(tx.AddSignatures(make([]byte, 64)), "missing signature")
+	feePayerSig, senderSig := make([]byte, 64), make([]byte, 64)
+	feePayerSig[0], senderSig[0] = 1, 2
+	require.NoError(tx.AddSignatures(feePayerSig, senderSig))
+	require.Equal(byte(1), solTx.Signatures[0][0])
+	require.Equal(byte(2), solTx.Signatures[1][0])
+	require.Equal(solTx.Signatures[0].String(), string(tx.Hash()))
+}
@@ -0,0 +1,219 @@
+package builder
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/programs/stake"
+	"github.com/gagliardetto/solana-go/programs/system"
+	"github.com/gagliardetto/solana-go/programs/token"
+	"github.com/gagliardetto/solana-go/programs/vote"
+	"github.com/openweb3-io/crosschain/blockchain/solana/tx"
+	xclient "github.com/openweb3-io/crosschain/client"
+	xc_types "github.com/openweb3-io/crosschain/types"
+)
+
+// The fee paid for each signature of a transaction
+const LamportsPerSignature = 5000
+
+// TxDecoder for Solana
+type TxDecoder struct {
+	Chain *xc_types.ChainConfig
+}
+
+var _ xclient.TxDecoder = &TxDecoder{}
+
+func NewTxDecoder(chain *xc_types.ChainConfig) (*TxDecoder, error) {
+	return &TxDecoder{
+		Chain: chain,
+	}, nil
+}
+
+// DecodeTx decodes the system, token and stake instructions of the transaction, and any memo.
+// Token transfers are to the token account of the recipient, as its owner can only be looked up
+// on chain.  Besides the decoded instructions, only compute budget, memo, nonce and token account
+// creation instructions are allowed, and any other instruction is an error.
+func (decoder *TxDecoder) DecodeTx(xcTx xc_types.Tx) (*xclient.TxInfo, error) {
+	solTx, ok := xcTx.(*tx.Tx)
+	if !ok {
+		return nil, fmt.Errorf("expected solana transaction, got %T", xcTx)
+	}
+	if solTx.SolTx == nil {
+		return nil, errors.New("transaction not initialized")
+	}
+	message := solTx.SolTx.Message
+	if len(message.AccountKeys) == 0 {
+		return nil, errors.New("transaction has no accounts")
+	}
+	if len(solTx.GetTokenTransfers()) > 0 {
+		return nil, errors.New("cannot decode token transfers without their mint")
+	}
+	if err := checkInstructions(solTx); err != nil {
+		return nil, err
+	}
+	chain := decoder.Chain.Chain
+	info := xclient.NewTxInfo(nil, chain, string(solTx.Hash()), 0, nil)
+
+	memo, err := decodeMemo(message)
+	if err != nil {
+		return nil, err
+	}
+	for _, instr := range solTx.GetSystemTransfers() {
+		from := xc_types.Address(instr.GetFundingAccount().PublicKey.String())
+		to := xc_types.Address(instr.GetRecipientAccount().PublicKey.String())
+		info.AddSimpleTransfer(from, to, "", xc_types.NewBigIntFromUint64(*instr.Lamports), nil, memo)
+	}
+	for _, instr := range solTx.GetVoteWithdraws() {
+		from := xc_types.Address(instr.GetWithdrawAuthorityAccount().PublicKey.String())
+		to := xc_types.Address(instr.GetRecipientAccount().PublicKey.String())
+		info.AddSimpleTransfer(from, to, "", xc_types.NewBigIntFromUint64(*instr.Lamports), nil, memo)
+	}
+	for _, instr := range solTx.GetStakeWithdraws() {
+		from := xc_types.Address(instr.GetStakeAccount().PublicKey.String())
+		to := xc_types.Address(instr.GetRecipientAccount().PublicKey.String())
+		info.AddSimpleTransfer(from, to, "", xc_types.NewBigIntFromUint64(*instr.Lamports), nil, memo)
+	}
+	for _, instr := range solTx.GetTokenTransferCheckeds() {
+		from := xc_types.Address(instr.GetOwnerAccount().PublicKey.String())
+		to := xc_types.Address(instr.GetDestinationAccount().PublicKey.String())
+		contract := xc_types.ContractAddress(instr.GetMintAccount().PublicKey.String())
+		info.AddSimpleTransfer(from, to, contract, xc_types.NewBigIntFromUint64(*instr.Amount), nil, memo)
+	}
+
+	for _, instr := range solTx.GetDelegateStake() {
+		stake := &xclient.Stake{
+			Account:   instr.GetStakeAccount().PublicKey.String(),
+			Validator: instr.GetVoteAccount().PublicKey.String(),
+			Address:   instr.GetStakeAuthority().PublicKey.String(),
+		}
+		// the stake is funded when the stake account is created
+		for _, createAccount := range solTx.GetCreateAccounts() {
+			if createAccount.NewAccount.Equals(instr.GetStakeAccount().PublicKey) {
+				stake.Balance = xc_types.NewBigIntFromUint64(createAccount.Lamports)
+			}
+		}
+		info.Stakes = append(info.Stakes, stake)
+	}
+	for _, instr := range solTx.GetDeactivateStakes() {
+		info.Unstakes = append(info.Unstakes, &xclient.Unstake{
+			Account: instr.GetStakeAccount().PublicKey.String(),
+			Address: instr.GetStakeAuthority().PublicKey.String(),
+		})
+	}
+
+	// the first account is always the fee payer
+	feePayer := xc_types.Address(message.AccountKeys[0].String())
+	info.AddFee(feePayer, "", maxFee(solTx, message.Header.NumRequiredSignatures), nil)
+	info.Fees = info.CalculateFees()
+	return info, nil
+}
+
+// The base fee of the signatures, and the priority fee if all of the compute units are used
+func maxFee(solTx *tx.Tx, signatures uint8) xc_types.BigInt {
+	fee := new(big.Int).SetUint64(solTx.GetComputeUnitPrice())
+	fee.Mul(fee, new(big.Int).SetUint64(solTx.GetComputeUnitLimit()))
+	// the price is in micro-lamports
+	fee.Div(fee, big.NewInt(1_000_000))
+	fee.Add(fee, big.NewInt(int64(signatures)*LamportsPerSignature))
+	return xc_types.BigInt(*fee)
+}
+
+// Checks that every instruction is either decoded, or moves no funds other than the rent of the
+// accounts it creates.  Stake accounts may only be created and initialized to be delegated by the
+// reported authority, and only split off the part of a deactivated stake that stays delegated.
+func checkInstructions(solTx *tx.Tx) error {
+	delegated := map[solana.PublicKey]solana.PublicKey{}
+	for _, instr := range solTx.GetDelegateStake() {
+		delegated[instr.GetStakeAccount().PublicKey] = instr.GetStakeAuthority().PublicKey
+	}
+	deactivated := map[solana.PublicKey]bool{}
+	for _, instr := range solTx.GetDeactivateStakes() {
+		deactivated[instr.GetStakeAccount().PublicKey] = true
+	}
+	splitInto := map[solana.PublicKey]bool{}
+	for _, instr := range solTx.GetSplitStakes() {
+		splitInto[instr.GetNewStakeAccount().PublicKey] = true
+	}
+
+	message := solTx.SolTx.Message
+	for i, instruction := range message.Instructions {
+		program, err := message.ResolveProgramIDIndex(instruction.ProgramIDIndex)
+		if err != nil {
+			return err
+		}
+		accounts, err := instruction.ResolveInstructionAccounts(&message)
+		if err != nil {
+			return err
+		}
+		var impl interface{}
+		switch {
+		case program.Equals(solana.ComputeBudget), program.Equals(solana.MemoProgramID):
+			continue
+		case program.Equals(solana.SPLAssociatedTokenAccountProgramID):
+			// create, or create idempotent
+			if len(instruction.Data) == 0 || instruction.Data[0] <= 1 {
+				continue
+			}
+		case program.Equals(solana.SystemProgramID):
+			if decoded, err := system.DecodeInstruction(accounts, instruction.Data); err == nil {
+				impl = decoded.Impl
+			}
+		case program.Equals(solana.StakeProgramID):
+			if decoded, err := stake.DecodeInstruction(accounts, instruction.Data); err == nil {
+				impl = decoded.Impl
+			}
+		case program.Equals(solana.VoteProgramID):
+			if decoded, err := vote.DecodeInstruction(accounts, instruction.Data); err == nil {
+				impl = decoded.Impl
+			}
+		case program.Equals(solana.TokenProgramID), program.Equals(solana.Token2022ProgramID):
+			if decoded, err := token.DecodeInstruction(accounts, instruction.Data); err == nil {
+				impl = decoded.Impl
+			}
+		}
+
+		switch instr := impl.(type) {
+		case *system.Transfer, *system.AdvanceNonceAccount, *stake.DelegateStake, *stake.Deactivate,
+			*stake.Withdraw, *vote.Withdraw, *token.TransferChecked:
+			continue
+		case *system.CreateAccount:
+			newAccount := instr.GetNewAccount().PublicKey
+			_, isDelegated := delegated[newAccount]
+			if instr.Owner.Equals(solana.StakeProgramID) && (isDelegated || splitInto[newAccount]) {
+				continue
+			}
+		case *stake.Initialize:
+			// the withdrawer is the reported stake authority, and there is no lockup
+			authority, isDelegated := delegated[instr.GetStakeAccount().PublicKey]
+			if isDelegated && instr.Authorized.Staker.Equals(authority) && instr.Authorized.Withdrawer.Equals(authority) &&
+				*instr.Lockup.UnixTimestamp == 0 && *instr.Lockup.Epoch == 0 {
+				continue
+			}
+		case *stake.Split:
+			if deactivated[instr.GetStakeAccount().PublicKey] {
+				continue
+			}
+		}
+		return fmt.Errorf("cannot decode instruction %d of program %s", i, program)
+	}
+	return nil
+}
+
+func decodeMemo(message solana.Message) (string, error) {
+	memo := ""
+	for _, instruction := range message.Instructions {
+		program, err := message.ResolveProgramIDIndex(instruction.ProgramIDIndex)
+		if err != nil {
+			return "", err
+		}
+		if program.Equals(solana.MemoProgramID) {
+			if memo != "" {
+				return "", errors.New("cannot decode more than one memo")
+			}
+			memo = string(instruction.Data)
+		}
+	}
+	return memo, nil
+}
@@ -5,10 +5,13 @@ import (
 	"encoding/json"
 	"fmt"
 	"sort"
+	"time"
 
 	bin "github.com/gagliardetto/binary"
 	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/programs/system"
 	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/gagliardetto/solana-go/rpc/jsonrpc"
 	"github.com/openweb3-io/crosschain/blockchain/solana/builder"
 	"github.com/openweb3-io/crosschain/blockchain/solana/tx"
 	"github.com/openweb3-io/crosschain/blockchain/solana/tx_input"
@@ -27,6 +30,10 @@ type Client struct {
 
 var _ xcclient.IClient = &Client{}
 var _ xcclient.StakingClient = &Client{}
+var _ xcclient.HistoryClient = &Client{}
+var _ xcclient.BlockClient = &Client{}
+var _ xcclient.FinalityClient = &Client{}
+var _ xcclient.BatchTransferClient = &Client{}
 
 func NewClient(cfg *xc.ChainConfig) (*Client, error) {
 	endpoint := cfg.Client.URL
@@ -53,11 +60,74 @@ func (client *Client) FetchBaseInput(ctx context.Context, fromAddr xc.Address) (
 	return txInput, nil
 }
 
+// Fetch the nonce stored in a durable nonce account and its authority
+func (client *Client) FetchNonceAccount(ctx context.Context, nonceAccount solana.PublicKey) (*system.NonceAccount, error) {
+	info, err := client.client.GetAccountInfo(ctx, nonceAccount)
+	if err != nil {
+		return nil, fmt.Errorf("could not get nonce account %s: %v", nonceAccount, err)
+	}
+	if !info.Value.Owner.Equals(solana.SystemProgramID) || len(info.Value.Data.GetBinary()) != builder.NonceAccountSize {
+		return nil, fmt.Errorf("%s is not a nonce account", nonceAccount)
+	}
+	account := &system.NonceAccount{}
+	err = bin.NewBinDecoder(info.Value.Data.GetBinary()).Decode(account)
+	if err != nil {
+		return nil, fmt.Errorf("could not decode nonce account %s: %v", nonceAccount, err)
+	}
+	if account.State != 1 {
+		return nil, fmt.Errorf("nonce account %s is not initialized", nonceAccount)
+	}
+	return account, nil
+}
+
+// Use the nonce account set by the "nonceAccount" extra option in place of the recent blockhash
+func (client *Client) setDurableNonce(ctx context.Context, extra map[string]any, txInput *tx_input.TxInput) error {
+	nonceAccountStr, ok := extra["nonceAccount"].(string)
+	if !ok || nonceAccountStr == "" {
+		return nil
+	}
+	nonceAccount, err := solana.PublicKeyFromBase58(nonceAccountStr)
+	if err != nil {
+		return fmt.Errorf("invalid nonce account %s: %v", nonceAccountStr, err)
+	}
+	account, err := client.FetchNonceAccount(ctx, nonceAccount)
+	if err != nil {
+		return err
+	}
+	txInput.UseDurableNonce(nonceAccount, account.AuthorizedPubkey, solana.Hash(account.Nonce))
+	return nil
+}
+
+// Input to create a new nonce account, see builder.NewCreateNonceAccount
+func (client *Client) FetchCreateNonceAccountInput(ctx context.Context, from xc.Address) (*tx_input.CreateNonceAccountInput, error) {
+	txInput, err := client.FetchBaseInput(ctx, from)
+	if err != nil {
+		return nil, err
+	}
+	rent, err := client.client.GetMinimumBalanceForRentExemption(ctx, builder.NonceAccountSize, rpc.CommitmentFinalized)
+	if err != nil {
+		return nil, fmt.Errorf("could not get rent exemption for nonce account: %v", err)
+	}
+	privKey, err := solana.NewRandomPrivateKey()
+	if err != nil {
+		return nil, err
+	}
+	return &tx_input.CreateNonceAccountInput{
+		TxInput:  *txInput,
+		NonceKey: privKey,
+		Lamports: xc.NewBigIntFromUint64(rent),
+	}, nil
+}
+
 func (client *Client) FetchTransferInput(ctx context.Context, args *xcbuilder.TransferArgs) (xc.TxInput, error) {
 	txInput, err := client.FetchBaseInput(ctx, args.GetFrom())
 	if err != nil {
 		return nil, err
 	}
+	extra, _ := args.GetExtra()
+	if err := client.setDurableNonce(ctx, extra, txInput); err != nil {
+		return nil, err
+	}
 
 	asset, _ := args.GetAsset()
 	if asset == nil {
@@ -169,6 +239,18 @@ func (client *Client) FetchTransferInput(ctx context.Context, args *xcbuilder.Tr
 	return txInput, nil
 }
 
+func (client *Client) FetchBatchTransferInput(ctx context.Context, args *xcbuilder.BatchTransferArgs) (xc.TxInput, error) {
+	txInput, err := client.FetchBaseInput(ctx, args.GetFrom())
+	if err != nil {
+		return nil, err
+	}
+	extra, _ := args.GetExtra()
+	if err := client.setDurableNonce(ctx, extra, txInput); err != nil {
+		return nil, err
+	}
+	return txInput, nil
+}
+
 func (a *Client) EstimateGasFee(ctx context.Context, _tx xc.Tx) (*xc.BigInt, error) {
 	tx := _tx.(*tx.Tx)
 	solanaTx := tx.SolTx
@@ -449,6 +531,339 @@ func (client *Client) FetchLegacyTxInfo(ctx context.Context, txHash xc.TxHash) (
 			ContractAddress: contract,
 		})
 	}
+	for _, ev := range client.parseStakeEvents(ctx, tx) {
+		result.AddStakeEvent(ev)
+	}
+
+	if len(sources) > 0 {
+		result.From = sources[0].Address
+	}
+	if len(dests) > 0 {
+		result.To = dests[0].Address
+		result.Amount = dests[0].Amount
+		result.ContractAddress = dests[0].ContractAddress
+	}
+
+	result.Sources = sources
+	result.Destinations = dests
+
+	return result, nil
+}
+
+func (client *Client) FetchTxInfo(ctx context.Context, txHash xc.TxHash) (*xcclient.TxInfo, error) {
+	txSig, err := solana.SignatureFromBase58(string(txHash))
+	if err != nil {
+		return nil, err
+	}
+	// confusingly, '0' is the latest version, which comes after 'legacy' (no version).
+	maxVersion := uint64(0)
+	res, err := client.client.GetTransaction(
+		ctx,
+		txSig,
+		&rpc.GetTransactionOpts{
+			Encoding:                       solana.EncodingBase64,
+			Commitment:                     rpc.CommitmentFinalized,
+			MaxSupportedTransactionVersion: &maxVersion,
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	if res == nil || res.Transaction == nil || res.Meta == nil {
+		return nil, errors.New("invalid transaction in response")
+	}
+
+	solTx, err := solana.TransactionFromDecoder(bin.NewBinDecoder(res.Transaction.GetBinary()))
+	if err != nil {
+		return nil, err
+	}
+	meta := res.Meta
+
+	blockTime := time.Unix(0, 0)
+	if res.BlockTime != nil {
+		blockTime = res.BlockTime.Time()
+	}
+	blockHash := ""
+	confirmations := uint64(0)
+	if res.Slot > 0 {
+		rewards := false
+		block, err := client.client.GetBlockWithOpts(ctx, res.Slot, &rpc.GetBlockOpts{
+			TransactionDetails:             rpc.TransactionDetailsNone,
+			Rewards:                        &rewards,
+			Commitment:                     rpc.CommitmentFinalized,
+			MaxSupportedTransactionVersion: &maxVersion,
+		})
+		if err != nil {
+			// ignore
+			logrus.WithError(err).Warn("failed to get block")
+		} else {
+			blockHash = block.Blockhash.String()
+		}
+
+		recent, err := client.client.GetLatestBlockhash(ctx, rpc.CommitmentFinalized)
+		if err != nil {
+			// ignore
+			logrus.WithError(err).Warn("failed to get latest blockhash")
+		} else if recent.Context.Slot >= res.Slot {
+			confirmations = recent.Context.Slot - res.Slot + 1
+		}
+	}
+
+	return client.toTxInfo(ctx, solTx, meta, xcclient.NewBlock(res.Slot, blockHash, blockTime), confirmations), nil
+}
+
+func (client *Client) toTxInfo(ctx context.Context, solTx *solana.Transaction, meta *rpc.TransactionMeta, block *xcclient.Block, confirmations uint64) *xcclient.TxInfo {
+	chain := client.cfg.Chain
+	tx := tx.NewTxFrom(solTx)
+	txHash := ""
+	if len(solTx.Signatures) > 0 {
+		txHash = solTx.Signatures[0].String()
+	}
+	tokenBalances := tokenBalancesByAccount(solTx, meta)
+
+	var errMsg *string
+	if meta.Err != nil {
+		msg := fmt.Sprintf("%v", meta.Err)
+		errMsg = &msg
+	}
+	txInfo := xcclient.NewTxInfo(block, chain, txHash, confirmations, errMsg)
+
+	// failed transactions only pay the fee
+	if meta.Err == nil {
+		for _, instr := range tx.GetSystemTransfers() {
+			from := xc.Address(instr.GetFundingAccount().PublicKey.String())
+			to := xc.Address(instr.GetRecipientAccount().PublicKey.String())
+			txInfo.AddSimpleTransfer(from, to, "", xc.NewBigIntFromUint64(*instr.Lamports), nil, "")
+		}
+		for _, instr := range tx.GetVoteWithdraws() {
+			from := xc.Address(instr.GetWithdrawAuthorityAccount().PublicKey.String())
+			to := xc.Address(instr.GetRecipientAccount().PublicKey.String())
+			txInfo.AddSimpleTransfer(from, to, "", xc.NewBigIntFromUint64(*instr.Lamports), nil, "")
+		}
+		for _, instr := range tx.GetStakeWithdraws() {
+			from := xc.Address(instr.GetStakeAccount().PublicKey.String())
+			to := xc.Address(instr.GetRecipientAccount().PublicKey.String())
+			txInfo.AddSimpleTransfer(from, to, "", xc.NewBigIntFromUint64(*instr.Lamports), nil, "")
+		}
+		for _, instr := range tx.GetTokenTransferCheckeds() {
+			from := xc.Address(instr.GetOwnerAccount().PublicKey.String())
+			toTokenAccount := instr.GetDestinationAccount().PublicKey
+			contract := xc.ContractAddress(instr.GetMintAccount().PublicKey.String())
+			to := xc.Address(toTokenAccount.String())
+			if balance, ok := tokenBalances[toTokenAccount]; ok && balance.Owner != nil {
+				to = xc.Address(balance.Owner.String())
+			} else {
+				// Solana doesn't keep full historical state, so we can't rely on always being able to lookup the account.
+				tokenAccountInfo, err := client.LookupTokenAccount(ctx, toTokenAccount)
+				if err != nil {
+					logrus.WithError(err).Warn("failed to lookup token account")
+				} else {
+					to = xc.Address(tokenAccountInfo.Parsed.Info.Owner)
+				}
+			}
+			txInfo.AddSimpleTransfer(from, to, contract, xc.NewBigIntFromUint64(*instr.Amount), nil, "")
+		}
+		for _, instr := range tx.GetTokenTransfers() {
+			from := xc.Address(instr.GetOwnerAccount().PublicKey.String())
+			toTokenAccount := instr.GetDestinationAccount().PublicKey
+			to := xc.Address(toTokenAccount.String())
+			contract := xc.ContractAddress("")
+			if balance, ok := tokenBalances[toTokenAccount]; ok && balance.Owner != nil {
+				to = xc.Address(balance.Owner.String())
+				contract = xc.ContractAddress(balance.Mint.String())
+			} else {
+				// Solana doesn't keep full historical state, so we can't rely on always being able to lookup the account.
+				tokenAccountInfo, err := client.LookupTokenAccount(ctx, toTokenAccount)
+				if err != nil {
+					logrus.WithError(err).Warn("failed to lookup token account")
+				} else {
+					to = xc.Address(tokenAccountInfo.Parsed.Info.Owner)
+					contract = xc.ContractAddress(tokenAccountInfo.Parsed.Info.Mint)
+				}
+			}
+			txInfo.AddSimpleTransfer(from, to, contract, xc.NewBigIntFromUint64(*instr.Amount), nil, "")
+		}
+
+		for _, ev := range client.parseStakeEvents(ctx, tx) {
+			switch ev := ev.(type) {
+			case *xcclient.Stake:
+				txInfo.Stakes = append(txInfo.Stakes, ev)
+			case *xcclient.Unstake:
+				txInfo.Unstakes = append(txInfo.Unstakes, ev)
+			}
+		}
+	}
+
+	// the first account is always the fee payer
+	if len(solTx.Message.AccountKeys) > 0 {
+		feePayer := xc.Address(solTx.Message.AccountKeys[0].String())
+		txInfo.AddFee(feePayer, "", xc.NewBigIntFromUint64(meta.Fee), nil)
+	}
+	txInfo.Fees = txInfo.CalculateFees()
+
+	return txInfo
+}
+
+// RPC error codes for slots that were skipped by their leader
+const (
+	slotSkippedErrorCode           = -32007
+	longTermStorageSlotSkippedCode = -32009
+)
+
+// The height of a block on Solana is its slot
+func (client *Client) FetchLatestHeight(ctx context.Context) (uint64, error) {
+	return client.client.GetSlot(ctx, rpc.CommitmentConfirmed)
+}
+
+// The most recent slot that has reached the "finalized" commitment
+func (client *Client) FetchFinalizedHeight(ctx context.Context) (uint64, error) {
+	return client.client.GetSlot(ctx, rpc.CommitmentFinalized)
+}
+
+// Fetch the block at a slot.  Blocks are fetched at the "confirmed" commitment, so they may still be rolled back.
+func (client *Client) FetchBlock(ctx context.Context, slot uint64) (*xcclient.BlockWithTransactions, error) {
+	latestSlot, err := client.FetchLatestHeight(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if slot > latestSlot {
+		return nil, fmt.Errorf("slot %d is not yet available", slot)
+	}
+
+	maxVersion := uint64(0)
+	rewards := false
+	res, err := client.client.GetBlockWithOpts(ctx, slot, &rpc.GetBlockOpts{
+		Encoding:                       solana.EncodingBase64,
+		TransactionDetails:             rpc.TransactionDetailsFull,
+		Rewards:                        &rewards,
+		Commitment:                     rpc.CommitmentConfirmed,
+		MaxSupportedTransactionVersion: &maxVersion,
+	})
+	if err != nil {
+		var rpcErr *jsonrpc.RPCError
+		if errors.As(err, &rpcErr) && (rpcErr.Code == slotSkippedErrorCode || rpcErr.Code == longTermStorageSlotSkippedCode) {
+			return nil, xcclient.ErrBlockSkipped
+		}
+		return nil, err
+	}
+
+	blockTime := time.Unix(0, 0)
+	if res.BlockTime != nil {
+		blockTime = res.BlockTime.Time()
+	}
+	block := xcclient.NewBlock(slot, res.Blockhash.String(), blockTime)
+	confirmations := uint64(0)
+	if latestSlot >= slot {
+		confirmations = latestSlot - slot + 1
+	}
+
+	txs := []*xcclient.TxInfo{}
+	for _, txWithMeta := range res.Transactions {
+		if txWithMeta.Transaction == nil || txWithMeta.Meta == nil {
+			return nil, errors.New("invalid transaction in block")
+		}
+		solTx, err := solana.TransactionFromDecoder(bin.NewBinDecoder(txWithMeta.Transaction.GetBinary()))
+		if err != nil {
+			return nil, err
+		}
+		if isVoteTransaction(solTx) {
+			// vote transactions make up most of every block and never move funds
+			continue
+		}
+		txs = append(txs, client.toTxInfo(ctx, solTx, txWithMeta.Meta, block, confirmations))
+	}
+	return xcclient.NewBlockWithTransactions(block, res.PreviousBlockhash.String(), txs), nil
+}
+
+func isVoteTransaction(solTx *solana.Transaction) bool {
+	for _, instr := range solTx.Message.Instructions {
+		program, err := solTx.Message.Program(instr.ProgramIDIndex)
+		if err != nil || !program.Equals(solana.VoteProgramID) {
+			return false
+		}
+	}
+	return len(solTx.Message.Instructions) > 0
+}
+
+// The token balances in the transaction meta report the owner and mint of each token account,
+// which saves having to look up the token accounts.
+func tokenBalancesByAccount(solTx *solana.Transaction, meta *rpc.TransactionMeta) map[solana.PublicKey]rpc.TokenBalance {
+	// accounts loaded from lookup tables come after the static accounts
+	accounts := append([]solana.PublicKey{}, solTx.Message.AccountKeys...)
+	accounts = append(accounts, meta.LoadedAddresses.Writable...)
+	accounts = append(accounts, meta.LoadedAddresses.ReadOnly...)
+
+	balances := map[solana.PublicKey]rpc.TokenBalance{}
+	for _, tokenBalances := range [][]rpc.TokenBalance{meta.PreTokenBalances, meta.PostTokenBalances} {
+		for _, balance := range tokenBalances {
+			if int(balance.AccountIndex) < len(accounts) {
+				balances[accounts[balance.AccountIndex]] = balance
+			}
+		}
+	}
+	return balances
+}
+
+// Fetch the transactions of an address, most recent first.  The cursor is the last signature of the previous page.
+// When filtering by contract, the history of the associated token account is returned instead.
+func (client *Client) FetchTransactionsByAddress(ctx context.Context, args xcclient.TransactionHistoryArgs) (*xcclient.TransactionHistoryPage, error) {
+	account, err := solana.PublicKeyFromBase58(string(args.GetAddress()))
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid address: %s", string(args.GetAddress()))
+	}
+	if contract, ok := args.GetContract(); ok {
+		mint, err := solana.PublicKeyFromBase58(string(contract))
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid mint address: %s", string(contract))
+		}
+		mintInfo, err := client.client.GetAccountInfo(ctx, mint)
+		if err != nil {
+			return nil, err
+		}
+		ata, err := solana_types.FindAssociatedTokenAddress(string(args.GetAddress()), string(contract), mintInfo.Value.Owner)
+		if err != nil {
+			return nil, err
+		}
+		account, err = solana.PublicKeyFromBase58(ata)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	limit := args.GetLimit()
+	opts := &rpc.GetSignaturesForAddressOpts{
+		Limit:      &limit,
+		Commitment: rpc.CommitmentFinalized,
+	}
+	if cursor, ok := args.GetCursor(); ok {
+		opts.Before, err = solana.SignatureFromBase58(cursor)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cursor: %s", cursor)
+		}
+	}
+	signatures, err := client.client.GetSignaturesForAddressWithOpts(ctx, account, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	txs := []*xcclient.TxInfo{}
+	for _, sig := range signatures {
+		txInfo, err := client.FetchTxInfo(ctx, xc.TxHash(sig.Signature.String()))
+		if err != nil {
+			return nil, fmt.Errorf("could not fetch transaction %s: %v", sig.Signature, err)
+		}
+		txs = append(txs, txInfo)
+	}
+
+	nextCursor := ""
+	if len(signatures) == limit {
+		nextCursor = signatures[len(signatures)-1].Signature.String()
+	}
+	return xcclient.NewTransactionHistoryPage(txs, nextCursor), nil
+}
+
+func (client *Client) parseStakeEvents(ctx context.Context, tx *tx.Tx) []xc.StakeEvent {
+	events := []xc.StakeEvent{}
 	for _, instr := range tx.GetDelegateStake() {
 		xcStake := &xcclient.Stake{
 			Account:   instr.GetStakeAccount().PublicKey.String(),
@@ -463,7 +878,7 @@ func (client *Client) FetchLegacyTxInfo(ctx context.Context, txHash xc.TxHash) (
 			}
 		}
 
-		result.AddStakeEvent(xcStake)
+		events = append(events, xcStake)
 	}
 	for _, instr := range tx.GetDeactivateStakes() {
 		xcStake := &xcclient.Unstake{
@@ -481,22 +896,9 @@ func (client *Client) FetchLegacyTxInfo(ctx context.Context, txHash xc.TxHash) (
 			xcStake.Validator = stakeAccountInfo.Parsed.Info.Stake.Delegation.Voter
 			xcStake.Balance = xc.NewBigIntFromStr(stakeAccountInfo.Parsed.Info.Stake.Delegation.Stake)
 		}
-		result.AddStakeEvent(xcStake)
+		events = append(events, xcStake)
 	}
-
-	if len(sources) > 0 {
-		result.From = sources[0].Address
-	}
-	if len(dests) > 0 {
-		result.To = dests[0].Address
-		result.Amount = dests[0].Amount
-		result.ContractAddress = dests[0].ContractAddress
-	}
-
-	result.Sources = sources
-	result.Destinations = dests
-
-	return result, nil
+	return events
 }
 
 func (client *Client) LookupTokenAccount(ctx context.Context, tokenAccount solana.PublicKey) (solana_types.TokenAccountInfo, error) {
@@ -0,0 +1,117 @@
+package client_test
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"testing"
+
+	bin "github.com/gagliardetto/binary"
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/programs/system"
+	"github.com/openweb3-io/crosschain/blockchain/solana/builder"
+	"github.com/openweb3-io/crosschain/blockchain/solana/client"
+	"github.com/openweb3-io/crosschain/blockchain/solana/tx"
+	"github.com/openweb3-io/crosschain/blockchain/solana/tx_input"
+	xcbuilder "github.com/openweb3-io/crosschain/builder"
+	testtypes "github.com/openweb3-io/crosschain/testutil/types"
+	xc_types "github.com/openweb3-io/crosschain/types"
+	"github.com/stretchr/testify/require"
+)
+
+func nonceAccountResponse(t *testing.T, state uint32, authority solana.PublicKey, nonce solana.Hash) string {
+	account := system.NonceAccount{
+		State:            state,
+		AuthorizedPubkey: authority,
+		Nonce:            solana.PublicKey(nonce),
+		FeeCalculator:    system.FeeCalculator{LamportsPerSignature: 5000},
+	}
+	bz, err := bin.MarshalBin(account)
+	require.NoError(t, err)
+	return fmt.Sprintf(`{"context":{"slot":1},"value":{"data":["%s","base64"],"executable":false,"lamports":1447680,"owner":"11111111111111111111111111111111","rentEpoch":0,"space":80}}`,
+		base64.StdEncoding.EncodeToString(bz))
+}
+
+func TestFetchTransferInputWithDurableNonce(t *testing.T) {
+	require := require.New(t)
+	from := solana.MustPublicKeyFromBase58("DBomk9vPzgLWpDBvvQpJUAB1aFz8EHsPq6xEuA1cGMcV")
+	to := solana.MustPublicKeyFromBase58("8FLngQGnatEDQwNBV27yFxuWDhvQfriaCL56fx84TxoN")
+	nonceAccount := solana.MustPublicKeyFromBase58("5qWgSWsCGUCbfYTsKhmNPFZwwYw8MJGR3bmyyjWYPpXF")
+	nonce := solana.Hash{7, 7, 7}
+
+	server, close := testtypes.MockJSONRPC(t, []string{
+		`{"context":{"slot":1},"value":{"blockhash":"DvLEyV2GHk86K5GojpqnRsvhfMF5kdZomKMnhVpvHyqK","lastValidBlockHeight":100}}`,
+		nonceAccountResponse(t, 1, from, nonce),
+	})
+	defer close()
+	chain := &xc_types.ChainConfig{Chain: xc_types.SOL, Client: &xc_types.ClientConfig{URL: server.URL}}
+	client, err := client.NewClient(chain)
+	require.NoError(err)
+
+	args, err := xcbuilder.NewTransferArgs(
+		xc_types.Address(from.String()),
+		xc_types.Address(to.String()),
+		xc_types.NewBigIntFromUint64(1000),
+		xcbuilder.WithExtra(map[string]any{"nonceAccount": nonceAccount.String()}),
+	)
+	require.NoError(err)
+	input, err := client.FetchTransferInput(context.Background(), args)
+	require.NoError(err)
+	txInput := input.(*tx_input.TxInput)
+	require.Equal(nonce, txInput.RecentBlockHash)
+	require.Equal(&tx_input.DurableNonce{Account: nonceAccount, Authority: from}, txInput.DurableNonce)
+
+	txBuilder, err := builder.NewTxBuilder(chain)
+	require.NoError(err)
+	xcTx, err := txBuilder.NewTransfer(args, input)
+	require.NoError(err)
+	solTx := xcTx.(*tx.Tx).SolTx
+	require.Equal(nonce, solTx.Message.RecentBlockhash)
+	require.Len(solTx.Message.Instructions, 2)
+	// the nonce is advanced first
+	advance := solTx.Message.Instructions[0]
+	require.Equal(solana.SystemProgramID, solTx.Message.AccountKeys[advance.ProgramIDIndex])
+	require.Equal(nonceAccount, solTx.Message.AccountKeys[advance.Accounts[0]])
+	require.Len(xcTx.(*tx.Tx).GetSystemTransfers(), 1)
+
+	// the sender must be able to advance the nonce
+	txInput.DurableNonce.Authority = to
+	_, err = txBuilder.NewTransfer(args, input)
+	require.ErrorContains(err, "must be authorized to the sender")
+}
+
+func TestFetchNonceAccountNotInitialized(t *testing.T) {
+	require := require.New(t)
+	server, close := testtypes.MockJSONRPC(t, nonceAccountResponse(t, 0, solana.PublicKey{}, solana.Hash{}))
+	defer close()
+	client, err := client.NewClient(&xc_types.ChainConfig{Chain: xc_types.SOL, Client: &xc_types.ClientConfig{URL: server.URL}})
+	require.NoError(err)
+
+	_, err = client.FetchNonceAccount(context.Background(), solana.MustPublicKeyFromBase58("5qWgSWsCGUCbfYTsKhmNPFZwwYw8MJGR3bmyyjWYPpXF"))
+	require.ErrorContains(err, "not initialized")
+}
+
+func TestNewCreateNonceAccount(t *testing.T) {
+	require := require.New(t)
+	txBuilder, err := builder.NewTxBuilder(&xc_types.ChainConfig{Chain: xc_types.SOL})
+	require.NoError(err)
+	nonceKey, err := solana.NewRandomPrivateKey()
+	require.NoError(err)
+
+	from := xc_types.Address("DBomk9vPzgLWpDBvvQpJUAB1aFz8EHsPq6xEuA1cGMcV")
+	xcTx, err := txBuilder.NewCreateNonceAccount(from, from, &tx_input.CreateNonceAccountInput{
+		NonceKey: nonceKey,
+		Lamports: xc_types.NewBigIntFromUint64(1447680),
+	})
+	require.NoError(err)
+	solTx := xcTx.(*tx.Tx)
+	creates := solTx.GetCreateAccounts()
+	require.Len(creates, 1)
+	require.Equal(nonceKey.PublicKey(), creates[0].NewAccount)
+	require.EqualValues(1447680, creates[0].Lamports)
+	require.Len(solTx.SolTx.Message.Instructions, 2)
+
+	// signed by the sender and the new nonce account
+	require.NoError(xcTx.AddSignatures(make([]byte, 64)))
+	require.Len(xcTx.GetSignatures(), 2)
+}
@@ -13,6 +13,7 @@ import (
 	solana_sdk "github.com/gagliardetto/solana-go"
 	"github.com/gagliardetto/solana-go/programs/system"
 	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/openweb3-io/crosschain/factory/blockchains/registry"
 	"github.com/openweb3-io/crosschain/types"
 
 	compute_budget "github.com/gagliardetto/solana-go/programs/compute-budget"
@@ -23,6 +24,15 @@ type Tx struct {
 	ParsedSolTx      *rpc.ParsedTransaction
 	inputSignatures  []types.TxSignature
 	transientSigners []solana.PrivateKey
+	// Accounts of transient signers whose keys were not serialized with the transaction, and must be
+	// added again with AddTransientSigner before the signatures are added
+	transientAccounts []solana.PublicKey
+}
+
+var _ types.TxWithSigners = &Tx{}
+
+func init() {
+	registry.RegisterUnsignedTx(types.BlockchainSolana, &Tx{})
 }
 
 func (tx *Tx) Hash() types.TxHash {
@@ -33,8 +43,24 @@ func (tx *Tx) Hash() types.TxHash {
 	return types.TxHash("")
 }
 
-// Sighashes returns the tx payload to sign, aka sighashes
+// Sighashes returns the tx payload to sign, aka sighashes.  Every signer signs the same message, so
+// there is one per signer of the transaction that is not a transient signer.
 func (tx Tx) Sighashes() ([]types.TxDataToSign, error) {
+	requests, err := tx.SignatureRequests()
+	if err != nil {
+		return nil, err
+	}
+	sighashes := make([]types.TxDataToSign, len(requests))
+	for i, request := range requests {
+		sighashes[i] = request.Payload
+	}
+	return sighashes, nil
+}
+
+// SignatureRequests returns the message to sign for each signer, in the order of the accounts of the
+// transaction, so the fee payer comes first.  New accounts, like stake accounts, are signed for with
+// their transient keys when the signatures are added, so they are not requested.
+func (tx Tx) SignatureRequests() ([]*types.SignatureRequest, error) {
 	if tx.SolTx == nil {
 		return nil, errors.New("transaction not initialized")
 	}
@@ -42,41 +68,89 @@ func (tx Tx) Sighashes() ([]types.TxDataToSign, error) {
 	if err != nil {
 		return nil, fmt.Errorf("unable to encode message for signing: %w", err)
 	}
-	return []types.TxDataToSign{messageContent}, nil
+	requests := []*types.SignatureRequest{}
+	for _, signer := range tx.signers() {
+		if !tx.isTransient(signer) {
+			requests = append(requests, types.NewSignatureRequest(types.Address(signer.String()), messageContent, types.Ed255))
+		}
+	}
+	return requests, nil
+}
+
+// The accounts that must sign the transaction, in order
+func (tx Tx) signers() []solana.PublicKey {
+	numSigners := int(tx.SolTx.Message.Header.NumRequiredSignatures)
+	if numSigners > len(tx.SolTx.Message.AccountKeys) {
+		numSigners = len(tx.SolTx.Message.AccountKeys)
+	}
+	return tx.SolTx.Message.AccountKeys[:numSigners]
+}
+
+func (tx Tx) isTransient(account solana.PublicKey) bool {
+	return transientAccount(tx.transientAccounts, account) || tx.transientSigner(account) != nil
+}
+
+func (tx Tx) transientSigner(account solana.PublicKey) *solana.PrivateKey {
+	for _, transient := range tx.transientSigners {
+		if transient.PublicKey().Equals(account) {
+			return &transient
+		}
+	}
+	return nil
 }
 
 // Some instructions on solana require new accounts to sign the transaction
 // in addition to the funding account.  These are transient signers are not
-// sensitive and the key material only needs to live long enough to sign the transaction.
+// sensitive and the key material only needs to live long enough to sign the transaction, so it is
+// not serialized with the transaction and must be added again after decoding it.
 func (tx *Tx) AddTransientSigner(transientSigner solana.PrivateKey) {
 	tx.transientSigners = append(tx.transientSigners, transientSigner)
 }
 
-// AddSignatures adds a signature to Tx
+// AddSignatures adds a signature for each of the SignatureRequests, in the same order
 func (tx *Tx) AddSignatures(signatures ...types.TxSignature) error {
 	if tx.SolTx == nil {
 		return errors.New("transaction not initialized")
 	}
-	solSignatures := make([]solana.Signature, len(signatures))
-	for i, signature := range signatures {
+	for _, signature := range signatures {
 		if len(signature) != solana.SignatureLength {
 			return fmt.Errorf("invalid signature (%d): %x", len(signature), signature)
 		}
-		copy(solSignatures[i][:], signature)
 	}
-	tx.SolTx.Signatures = solSignatures
-	tx.inputSignatures = signatures
+	signers := tx.signers()
+	if len(signers) == 0 {
+		// not yet compiled to a message, keep the signatures as given
+		signers = make([]solana.PublicKey, len(signatures))
+	}
 
-	// add transient signers
-	for _, transient := range tx.transientSigners {
-		bz, _ := tx.SolTx.Message.MarshalBinary()
-		sig, err := transient.Sign(bz)
-		if err != nil {
-			return fmt.Errorf("unable to sign with transient signer: %v", err)
+	solSignatures := []solana.Signature{}
+	inputSignatures := []types.TxSignature{}
+	for _, signer := range signers {
+		var sig solana.Signature
+		if transient := tx.transientSigner(signer); transient != nil {
+			bz, _ := tx.SolTx.Message.MarshalBinary()
+			var err error
+			sig, err = transient.Sign(bz)
+			if err != nil {
+				return fmt.Errorf("unable to sign with transient signer: %v", err)
+			}
+		} else if tx.isTransient(signer) {
+			return fmt.Errorf("missing key of transient signer %s", signer)
+		} else {
+			if len(signatures) == 0 {
+				return fmt.Errorf("missing signature for %s", signer)
+			}
+			copy(sig[:], signatures[0])
+			signatures = signatures[1:]
 		}
-		tx.SolTx.Signatures = append(tx.SolTx.Signatures, sig)
-		tx.inputSignatures = append(tx.inputSignatures, sig[:])
+		solSignatures = append(solSignatures, sig)
+		inputSignatures = append(inputSignatures, sig[:])
+	}
+	if len(signatures) > 0 {
+		return fmt.Errorf("expected %d signatures, got %d more", len(solSignatures), len(signatures))
 	}
+	tx.SolTx.Signatures = solSignatures
+	tx.inputSignatures = inputSignatures
 	return nil
 }
 
@@ -0,0 +1,64 @@
+package tx
+
+import (
+	"encoding/json"
+
+	bin "github.com/gagliardetto/binary"
+	"github.com/gagliardetto/solana-go"
+)
+
+type txJSON struct {
+	SolTx []byte `json:"sol_tx"`
+	// Only the accounts of transient signers are kept, their keys must be added again before signing
+	TransientAccounts []solana.PublicKey `json:"transient_accounts,omitempty"`
+	Signatures        [][]byte           `json:"signatures,omitempty"`
+}
+
+func (tx Tx) MarshalJSON() ([]byte, error) {
+	encoded := txJSON{
+		TransientAccounts: tx.transientAccounts,
+	}
+	for _, transient := range tx.transientSigners {
+		if !transientAccount(tx.transientAccounts, transient.PublicKey()) {
+			encoded.TransientAccounts = append(encoded.TransientAccounts, transient.PublicKey())
+		}
+	}
+	for _, sig := range tx.inputSignatures {
+		encoded.Signatures = append(encoded.Signatures, sig)
+	}
+	if tx.SolTx != nil {
+		var err error
+		if encoded.SolTx, err = tx.SolTx.MarshalBinary(); err != nil {
+			return nil, err
+		}
+	}
+	return json.Marshal(encoded)
+}
+
+func (tx *Tx) UnmarshalJSON(data []byte) error {
+	var decoded txJSON
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	solTx, err := solana.TransactionFromDecoder(bin.NewBinDecoder(decoded.SolTx))
+	if err != nil {
+		return err
+	}
+	*tx = Tx{
+		SolTx:             solTx,
+		transientAccounts: decoded.TransientAccounts,
+	}
+	for _, sig := range decoded.Signatures {
+		tx.inputSignatures = append(tx.inputSignatures, sig)
+	}
+	return nil
+}
+
+func transientAccount(accounts []solana.PublicKey, account solana.PublicKey) bool {
+	for _, existing := range accounts {
+		if existing.Equals(account) {
+			return true
+		}
+	}
+	return false
+}
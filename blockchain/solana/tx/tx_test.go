@@ -1,9 +1,11 @@
 package tx_test
 
 import (
+	"encoding/json"
 	"testing"
 
 	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/programs/system"
 	"github.com/openweb3-io/crosschain/blockchain/solana/tx"
 	xc_types "github.com/openweb3-io/crosschain/types"
 	"github.com/test-go/testify/require"
@@ -59,3 +61,36 @@ func TestTxSerialize(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, serialized, []byte{0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0})
 }
+
+func TestTxJSONOmitsTransientKeys(t *testing.T) {
+	from := solana.NewWallet().PublicKey()
+	transient := solana.NewWallet().PrivateKey
+	solTx, err := solana.NewTransaction(
+		[]solana.Instruction{system.NewCreateAccountInstruction(1_000_000, 80, solana.SystemProgramID, from, transient.PublicKey()).Build()},
+		solana.Hash{},
+		solana.TransactionPayer(from),
+	)
+	require.NoError(t, err)
+	tx1 := tx.NewTxFrom(solTx)
+	tx1.AddTransientSigner(transient)
+
+	bz, err := json.Marshal(tx1)
+	require.NoError(t, err)
+	require.NotContains(t, string(bz), transient.String())
+	require.Contains(t, string(bz), transient.PublicKey().String())
+
+	decoded := &tx.Tx{}
+	require.NoError(t, json.Unmarshal(bz, decoded))
+	requests, err := decoded.SignatureRequests()
+	require.NoError(t, err)
+	require.Len(t, requests, 1)
+	require.EqualValues(t, from.String(), requests[0].Signer)
+
+	sig := make([]byte, 64)
+	err = decoded.AddSignatures(sig)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "missing key of transient signer")
+	decoded.AddTransientSigner(transient)
+	require.NoError(t, decoded.AddSignatures(sig))
+	require.Len(t, decoded.SolTx.Signatures, 2)
+}
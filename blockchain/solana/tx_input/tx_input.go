@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/gagliardetto/solana-go"
+	"github.com/openweb3-io/crosschain/factory/blockchains/registry"
 	xc_types "github.com/openweb3-io/crosschain/types"
 	"github.com/shopspring/decimal"
 )
@@ -23,6 +24,30 @@ type TxInput struct {
 	SourceTokenAccounts []*TokenAccount  `json:"source_token_accounts,omitempty"`
 	PrioritizationFee   xc_types.BigInt  `json:"prioritization_fee,omitempty"`
 	Timestamp           int64            `json:"timestamp,omitempty"`
+	// Set to build against a durable nonce rather than a recent blockhash, in which case
+	// RecentBlockHash is the nonce stored in the account.
+	DurableNonce *DurableNonce `json:"durable_nonce,omitempty"`
+}
+
+func init() {
+	registry.RegisterTxBaseInput(&TxInput{})
+}
+
+// A nonce account, whose nonce is used in place of a recent blockhash.  Transactions using it do not expire
+// until the nonce is advanced, which the transaction itself does as its first instruction.
+type DurableNonce struct {
+	Account solana.PublicKey `json:"account"`
+	// Must sign to advance the nonce
+	Authority solana.PublicKey `json:"authority"`
+}
+
+// Build against the nonce currently stored in the nonce account
+func (input *TxInput) UseDurableNonce(account solana.PublicKey, authority solana.PublicKey, nonce solana.Hash) {
+	input.DurableNonce = &DurableNonce{
+		Account:   account,
+		Authority: authority,
+	}
+	input.RecentBlockHash = nonce
 }
 
 func (input *TxInput) GetBlockchain() xc_types.Blockchain {
@@ -67,6 +92,10 @@ func (input *TxInput) GetLimitedPrioritizationFee(chain *xc_types.ChainConfig) u
 func (input *TxInput) IndependentOf(other xc_types.TxInput) (independent bool) {
 	// no conflicts on solana as txs are easily parallelizeable through
 	// the recent-block-hash mechanism.
+	// The exception is a nonce account, which only one transaction can advance.
+	if oldInput, ok := other.(*TxInput); ok && input.DurableNonce != nil && oldInput.DurableNonce != nil {
+		return !input.DurableNonce.Account.Equals(oldInput.DurableNonce.Account)
+	}
 	return true
 }
 
@@ -76,7 +105,14 @@ func (input *TxInput) SafeFromDoubleSend(others ...xc_types.TxInput) (safe bool)
 	}
 	for _, other := range others {
 		oldInput, ok := other.(*TxInput)
-		if ok {
+		if ok && oldInput.DurableNonce != nil {
+			// a durable nonce never expires, it can only be invalidated by advancing the nonce.
+			// Only safe if the nonce account has since moved on to a different nonce.
+			if input.DurableNonce == nil || !input.DurableNonce.Account.Equals(oldInput.DurableNonce.Account) ||
+				oldInput.RecentBlockHash.Equals(input.RecentBlockHash) {
+				return false
+			}
+		} else if ok {
 			diff := input.Timestamp - oldInput.Timestamp
 			// solana blockhash lasts only ~1 minute -> we'll require a 5 min period
 			// and different hash to consider it safe from double-send.
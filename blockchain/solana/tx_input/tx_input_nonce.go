@@ -0,0 +1,15 @@
+package tx_input
+
+import (
+	"github.com/gagliardetto/solana-go"
+	xc_types "github.com/openweb3-io/crosschain/types"
+)
+
+// Input to create and initialize a new durable nonce account
+type CreateNonceAccountInput struct {
+	TxInput
+	// The new nonce account to create.  Only needed to sign the creation, so it is never serialized.
+	NonceKey solana.PrivateKey `json:"-"`
+	// Funds the nonce account with, at least enough to be rent exempt
+	Lamports xc_types.BigInt `json:"lamports"`
+}
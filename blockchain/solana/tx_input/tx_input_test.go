@@ -79,6 +79,79 @@ func TestTxInputConflicts(t *testing.T) {
 			independent:     true,
 			doubleSpendSafe: false,
 		},
+		{
+			// a durable nonce does not expire
+			newInput: &TxInput{
+				RecentBlockHash: solana.Hash([32]byte{1}),
+				Timestamp:       startTime,
+			},
+			oldInput: &TxInput{
+				RecentBlockHash: solana.Hash([32]byte{2}),
+				Timestamp:       startTime - int64(SafetyTimeoutMargin.Seconds()) - 1,
+				DurableNonce:    &DurableNonce{Account: solana.PublicKey{1}},
+			},
+			independent:     true,
+			doubleSpendSafe: false,
+		},
+		{
+			// the nonce account has not advanced
+			newInput: &TxInput{
+				RecentBlockHash: solana.Hash([32]byte{2}),
+				Timestamp:       startTime,
+				DurableNonce:    &DurableNonce{Account: solana.PublicKey{1}},
+			},
+			oldInput: &TxInput{
+				RecentBlockHash: solana.Hash([32]byte{2}),
+				Timestamp:       startTime - int64(SafetyTimeoutMargin.Seconds()) - 1,
+				DurableNonce:    &DurableNonce{Account: solana.PublicKey{1}},
+			},
+			independent:     false,
+			doubleSpendSafe: false,
+		},
+		{
+			// the nonce account has advanced, so the old transaction can no longer be included
+			newInput: &TxInput{
+				RecentBlockHash: solana.Hash([32]byte{3}),
+				Timestamp:       startTime,
+				DurableNonce:    &DurableNonce{Account: solana.PublicKey{1}},
+			},
+			oldInput: &TxInput{
+				RecentBlockHash: solana.Hash([32]byte{2}),
+				Timestamp:       startTime,
+				DurableNonce:    &DurableNonce{Account: solana.PublicKey{1}},
+			},
+			independent:     false,
+			doubleSpendSafe: true,
+		},
+		{
+			// a different nonce account says nothing about the old one
+			newInput: &TxInput{
+				RecentBlockHash: solana.Hash([32]byte{3}),
+				Timestamp:       startTime,
+				DurableNonce:    &DurableNonce{Account: solana.PublicKey{2}},
+			},
+			oldInput: &TxInput{
+				RecentBlockHash: solana.Hash([32]byte{2}),
+				Timestamp:       startTime - int64(SafetyTimeoutMargin.Seconds()) - 1,
+				DurableNonce:    &DurableNonce{Account: solana.PublicKey{1}},
+			},
+			independent:     true,
+			doubleSpendSafe: false,
+		},
+		{
+			// an old blockhash transaction still expires when moving to a durable nonce
+			newInput: &TxInput{
+				RecentBlockHash: solana.Hash([32]byte{3}),
+				Timestamp:       startTime,
+				DurableNonce:    &DurableNonce{Account: solana.PublicKey{1}},
+			},
+			oldInput: &TxInput{
+				RecentBlockHash: solana.Hash([32]byte{2}),
+				Timestamp:       startTime - int64(SafetyTimeoutMargin.Seconds()) - 1,
+			},
+			independent:     true,
+			doubleSpendSafe: true,
+		},
 	}
 	for i, v := range vectors {
 		newBz, _ := json.Marshal(v.newInput)
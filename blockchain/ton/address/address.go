@@ -14,6 +14,13 @@ const DefaultSubwalletId = 698983191
 // Most stable TON wallet version
 const DefaultWalletVersion = wallet.V3
 
+// Address types of the wallet versions created by TON wallets, e.g. V4R2 by older Tonkeeper versions
+// and V5R1 by current ones
+const (
+	AddressTypeV4R2 xc_types.AddressType = "V4R2"
+	AddressTypeV5R1 xc_types.AddressType = "V5R1"
+)
+
 // AddressBuilder for Template
 type AddressBuilder struct {
 	cfg *xc_types.ChainConfig
@@ -38,15 +45,55 @@ func (ab AddressBuilder) GetAddressFromPublicKey(publicKeyBytes []byte) (xc_type
 	return xc_types.Address(addr.String()), nil
 }
 
+// GetAddressFromPublicKeyWithVersion returns the address of a wallet version, with the subwallet TON
+// wallets use for it
+func (ab AddressBuilder) GetAddressFromPublicKeyWithVersion(publicKeyBytes []byte, version wallet.Version) (xc_types.Address, error) {
+	var config wallet.VersionConfig = version
+	subwalletId := uint32(DefaultSubwalletId)
+	if version == wallet.V5R1Final {
+		networkId := int32(wallet.MainnetGlobalID)
+		if ab.cfg.Network == "testnet" {
+			networkId = wallet.TestnetGlobalID
+		}
+		config = wallet.ConfigV5R1Final{NetworkGlobalID: networkId, Workchain: 0}
+		subwalletId = 0
+	}
+	addr, err := wallet.AddressFromPubKey(publicKeyBytes, config, subwalletId)
+	if err != nil {
+		return "", err
+	}
+	if ab.cfg.Network == "testnet" {
+		addr.SetTestnetOnly(true)
+	}
+	return xc_types.Address(addr.String()), nil
+}
+
 // GetAllPossibleAddressesFromPublicKey returns all PossubleAddress(es) given a public key
 func (ab AddressBuilder) GetAllPossibleAddressesFromPublicKey(publicKeyBytes []byte) ([]xc_types.PossibleAddress, error) {
 	address, err := ab.GetAddressFromPublicKey(publicKeyBytes)
-	return []xc_types.PossibleAddress{
+	if err != nil {
+		return nil, err
+	}
+	possibles := []xc_types.PossibleAddress{
 		{
 			Address: address,
 			Type:    xc_types.AddressTypeDefault,
 		},
-	}, err
+	}
+	for _, v := range []struct {
+		version     wallet.Version
+		addressType xc_types.AddressType
+	}{
+		{wallet.V4R2, AddressTypeV4R2},
+		{wallet.V5R1Final, AddressTypeV5R1},
+	} {
+		address, err := ab.GetAddressFromPublicKeyWithVersion(publicKeyBytes, v.version)
+		if err != nil {
+			return nil, err
+		}
+		possibles = append(possibles, xc_types.PossibleAddress{Address: address, Type: v.addressType})
+	}
+	return possibles, nil
 }
 
 func ParseAddress(addr xc_types.Address, net string) (*address.Address, error) {
@@ -8,6 +8,7 @@ import (
 	"github.com/openweb3-io/crosschain/blockchain/ton/address"
 	xc_types "github.com/openweb3-io/crosschain/types"
 	"github.com/stretchr/testify/require"
+	"github.com/xssnick/tonutils-go/ton/wallet"
 )
 
 func TestNewAddressBuilder(t *testing.T) {
@@ -83,3 +84,33 @@ func TestGetAddressShard(t *testing.T) {
 	log.Printf("0x80 addr: %v, shard:0x%02x", addr.String(), addr.Data()[0])
 	require.NoError(t, err)
 }
+
+func TestGetAddressFromPublicKeyWithVersion(t *testing.T) {
+	builder, _ := address.NewAddressBuilder(&xc_types.ChainConfig{})
+	bytes, _ := hex.DecodeString("2ec75045db03e93322a55bba4f4c360d889cd488d3461b8585e245f0d722d6c8")
+
+	// wallets of the same key as created by tonutils-go, which TON wallets match
+	for _, v := range []struct {
+		version  wallet.Version
+		expected string
+	}{
+		{wallet.V3, "UQAKp6-cfbfG8_8MdKtIjp_a6nW3-R4lWUOoULPLrtC0JuXO"},
+		{wallet.V4R2, "UQCY3DTOLOGkiWq6VPRui-gB0K7JznrUdC_1893EH0XWtYOB"},
+		{wallet.V5R1Final, "UQDgYztyznUQqDnhkVWXphVFQkCS41V3jJJ1xjtURG_5Ctxl"},
+	} {
+		derived, err := builder.(address.AddressBuilder).GetAddressFromPublicKeyWithVersion(bytes, v.version)
+		require.NoError(t, err)
+		derivedAddr, err := address.ParseAddress(derived, "mainnet")
+		require.NoError(t, err)
+		expectedAddr, err := address.ParseAddress(xc_types.Address(v.expected), "mainnet")
+		require.NoError(t, err)
+		require.Equal(t, expectedAddr.Data(), derivedAddr.Data(), v.version.String())
+	}
+
+	addresses, err := builder.GetAllPossibleAddressesFromPublicKey(bytes)
+	require.NoError(t, err)
+	require.Len(t, addresses, 3)
+	require.Equal(t, xc_types.AddressTypeDefault, addresses[0].Type)
+	require.Equal(t, address.AddressTypeV4R2, addresses[1].Type)
+	require.Equal(t, address.AddressTypeV5R1, addresses[2].Type)
+}
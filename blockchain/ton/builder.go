@@ -23,115 +23,228 @@ type TxBuilder struct {
 	chain *xc_types.ChainConfig
 }
 
+var _ xcbuilder.TxBatchBuilder = &TxBuilder{}
+
 func NewTxBuilder(chain *xc_types.ChainConfig) (*TxBuilder, error) {
 	return &TxBuilder{
 		chain: chain,
 	}, nil
 }
 
-func (b *TxBuilder) NewTransfer(args *xcbuilder.TransferArgs, input xc_types.TxInput) (xc_types.Tx, error) {
-	ctx := context.Background()
+// Options for the wallet contract of the sender, set in the extra of the builder args
+type WalletOptions struct {
+	Version     wallet.Version
+	SubwalletID uint32
+	// Only used by highload v3 wallets, which are deployed with a fixed message TTL
+	MessageTTL uint32
+	// Required by highload v3 wallets, where each transaction needs a query id of 23 bits that was not
+	// used within the message TTL, e.g. from a counter kept by the caller
+	QueryID *uint32
+}
 
-	version := wallet.V4R2
-	subwalletID := uint32(tonaddress.DefaultSubwalletId)
-	extra, ok := args.GetExtra()
-	if ok {
-		if v, ok := extra["version"].(float64); ok {
-			version = wallet.Version(int(v))
-		}
+// Default message TTL of highload v3 wallets
+const DefaultHighloadV3MessageTTL = 60 * 60
 
-		if v, ok := extra["subwalletID"].(float64); ok {
-			subwalletID = uint32(v)
-		}
+// Query ids of highload v3 wallets are 23 bits
+const MaxHighloadV3QueryID = 1<<23 - 1
+
+func GetWalletOptions(extra map[string]any) WalletOptions {
+	opts := WalletOptions{
+		Version:     wallet.V4R2,
+		SubwalletID: uint32(tonaddress.DefaultSubwalletId),
+		MessageTTL:  DefaultHighloadV3MessageTTL,
+	}
+	if v, ok := extra["version"].(float64); ok {
+		opts.Version = wallet.Version(int(v))
+	}
+	if v, ok := extra["subwalletID"].(float64); ok {
+		opts.SubwalletID = uint32(v)
+	}
+	if v, ok := extra["messageTTL"].(float64); ok {
+		opts.MessageTTL = uint32(v)
+	}
+	if v, ok := extra["queryID"].(float64); ok {
+		queryID := uint32(v)
+		opts.QueryID = &queryID
 	}
+	return opts
+}
+
+// Highload wallets replay-protect with query ids rather than a seqno
+func (opts WalletOptions) IsHighload() bool {
+	switch opts.Version {
+	case wallet.HighloadV2R2, wallet.HighloadV2Verified, wallet.HighloadV3:
+		return true
+	}
+	return false
+}
 
+func (opts WalletOptions) versionConfig(txInput *TxInput) wallet.VersionConfig {
+	if opts.Version != wallet.HighloadV3 {
+		return opts.Version
+	}
+	return wallet.ConfigHighloadV3{
+		MessageTTL: opts.MessageTTL,
+		MessageBuilder: func(ctx context.Context, subWalletId uint32) (uint32, int64, error) {
+			return *opts.QueryID, txInput.Timestamp, nil
+		},
+	}
+}
+
+func getWalletOptions(args interface{ GetExtra() (map[string]any, bool) }) WalletOptions {
+	extra, _ := args.GetExtra()
+	return GetWalletOptions(extra)
+}
+
+func (b *TxBuilder) NewTransfer(args *xcbuilder.TransferArgs, input xc_types.TxInput) (xc_types.Tx, error) {
 	txInput := input.(*TxInput)
-	var stateInit *tlb.StateInit
-	var err error
-	if txInput.AccountStatus != AccountStatusActive {
-		if len(txInput.PublicKey) == 0 {
-			return nil, fmt.Errorf("did not set public-key in tx-input for new ton account %s", args.GetFrom())
-		}
-		stateInit, err = wallet.GetStateInit(
-			ed25519.PublicKey(txInput.PublicKey),
-			version,
-			subwalletID,
-		)
-		if err != nil {
-			return nil, err
-		}
+	if _, ok := args.GetFeePayer(); ok {
+		// wallet contracts pay their own fees from the balance of the sender
+		return nil, errors.New("not implemented: fee payer for TON transactions")
 	}
+	opts := getWalletOptions(args)
 
-	toAddr, err := address.ParseAddr(string(args.GetTo()))
+	fromAddr, err := address.ParseAddr(string(args.GetFrom()))
 	if err != nil {
-		return nil, errors.Wrapf(err, "invalid TON to address: %s", args.GetTo())
+		return nil, errors.Wrapf(err, "invalid TON address %s", args.GetFrom())
 	}
-	// TODO 应该在外部传入地址的时候决定 bounce
-	toAddr = toAddr.Bounce(false)
+
+	asset, _ := args.GetAsset()
+	memo, _ := args.GetMemo()
+
+	message, err := b.buildMessage(fromAddr, args.GetTo(), args.GetAmount(), asset, memo, txInput, 1)
+	if err != nil {
+		return nil, err
+	}
+	return b.newTx(fromAddr, []*wallet.Message{message}, opts, txInput)
+}
+
+// Pay every recipient with a separate message from the wallet.  Regular wallets can send up to 4
+// messages at once, highload wallets many more.
+func (b *TxBuilder) NewBatchTransfer(args *xcbuilder.BatchTransferArgs, input xc_types.TxInput) (xc_types.Tx, error) {
+	txInput := input.(*TxInput)
+	if _, ok := args.GetFeePayer(); ok {
+		// wallet contracts pay their own fees from the balance of the sender
+		return nil, errors.New("not implemented: fee payer for TON transactions")
+	}
+	opts := getWalletOptions(args)
+
 	fromAddr, err := address.ParseAddr(string(args.GetFrom()))
 	if err != nil {
 		return nil, errors.Wrapf(err, "invalid TON address %s", args.GetFrom())
 	}
 
-	var message *wallet.Message
-
 	asset, _ := args.GetAsset()
 	memo, _ := args.GetMemo()
 
-	if asset != nil && asset.GetContract() != "" {
-		tokenAddr, err := tonaddress.ParseAddress(txInput.TokenWallet, "")
+	recipients := args.GetRecipients()
+	messages := make([]*wallet.Message, len(recipients))
+	for i, recipient := range recipients {
+		messages[i], err = b.buildMessage(fromAddr, recipient.To, recipient.Amount, asset, memo, txInput, len(recipients))
 		if err != nil {
-			return nil, fmt.Errorf("invalid TON token address %s: %v", txInput.TokenWallet, err)
+			return nil, err
 		}
+	}
+	return b.newTx(fromAddr, messages, opts, txInput)
+}
+
+// Build the message paying a single recipient, out of count paid by the transaction
+func (b *TxBuilder) buildMessage(fromAddr *address.Address, to xc_types.Address, amount xc_types.BigInt, asset xc_types.IAsset, memo string, txInput *TxInput, count int) (*wallet.Message, error) {
+	toAddr, err := address.ParseAddr(string(to))
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid TON to address: %s", to)
+	}
+	// TODO 应该在外部传入地址的时候决定 bounce
+	toAddr = toAddr.Bounce(false)
 
-		amountTlb, err := tlb.FromNano(args.GetAmount().Int(), int(asset.GetDecimals()))
+	if asset == nil || asset.GetContract() == "" {
+		message, err := BuildTransfer(toAddr, tlb.FromNanoTON(amount.Int()), memo)
 		if err != nil {
-			return nil, err
+			return nil, errors.Wrap(err, "BuildTransfer failed")
 		}
+		return message, nil
+	}
 
-		// Spend max 0.2 TON per Jetton transfer.  If we don't have 0.05 TON, we should
-		// lower the max to our balance less max-fees.
-		maxJettonFee := xc_types.NewBigIntFromInt64(50000000)
-		remainingTonBal := txInput.TonBalance.Sub(&txInput.EstimatedMaxFee)
-		if maxJettonFee.Cmp(&remainingTonBal) > 0 && remainingTonBal.Cmp(&Zero) > 0 {
-			maxJettonFee = remainingTonBal
+	tokenAddr, err := tonaddress.ParseAddress(txInput.TokenWallet, "")
+	if err != nil {
+		return nil, fmt.Errorf("invalid TON token address %s: %v", txInput.TokenWallet, err)
+	}
+
+	amountTlb, err := tlb.FromNano(amount.Int(), int(asset.GetDecimals()))
+	if err != nil {
+		return nil, err
+	}
+
+	// Spend max 0.05 TON per Jetton transfer.  If we don't have 0.05 TON for each, we should
+	// lower the max to our balance less max-fees.
+	maxJettonFee := xc_types.NewBigIntFromInt64(50000000)
+	remainingTonBal := txInput.TonBalance.Sub(&txInput.EstimatedMaxFee)
+	countBig := xc_types.NewBigIntFromInt64(int64(count))
+	remainingTonBal = remainingTonBal.Div(&countBig)
+	if maxJettonFee.Cmp(&remainingTonBal) > 0 && remainingTonBal.Cmp(&Zero) > 0 {
+		maxJettonFee = remainingTonBal
+	}
+
+	return BuildJettonTransfer(
+		uint64(txInput.Timestamp),
+		fromAddr,
+		tokenAddr,
+		toAddr,
+		amountTlb,
+		tlb.FromNanoTON(maxJettonFee.Int()),
+		memo,
+	)
+}
+
+func (b *TxBuilder) newTx(fromAddr *address.Address, messages []*wallet.Message, opts WalletOptions, txInput *TxInput) (*tx.Tx, error) {
+	ctx := context.Background()
+	if opts.Version == wallet.HighloadV3 {
+		// a default derived from the time would repeat for transactions built in the same second, and
+		// the wallet drops a query id it has already processed
+		if opts.QueryID == nil {
+			return nil, errors.New("highload v3 transactions need a queryID in the extra that was not used within the message TTL")
+		}
+		if *opts.QueryID > MaxHighloadV3QueryID {
+			return nil, fmt.Errorf("queryID of highload v3 wallets must be at most %d, got %d", MaxHighloadV3QueryID, *opts.QueryID)
 		}
+	}
+	version := opts.versionConfig(txInput)
 
-		message, err = BuildJettonTransfer(
-			uint64(txInput.Timestamp),
-			fromAddr,
-			tokenAddr,
-			toAddr,
-			amountTlb,
-			tlb.FromNanoTON(maxJettonFee.Int()),
-			memo,
+	var stateInit *tlb.StateInit
+	var err error
+	if txInput.AccountStatus != AccountStatusActive {
+		if len(txInput.PublicKey) == 0 {
+			return nil, fmt.Errorf("did not set public-key in tx-input for new ton account %s", fromAddr)
+		}
+		stateInit, err = wallet.GetStateInit(
+			ed25519.PublicKey(txInput.PublicKey),
+			version,
+			opts.SubwalletID,
 		)
 		if err != nil {
 			return nil, err
 		}
-	} else {
-		message, err = BuildTransfer(toAddr, tlb.FromNanoTON(args.GetAmount().Int()), memo)
-		if err != nil {
-			return nil, errors.Wrap(err, "BuildTransfer failed")
-		}
 	}
 
 	seqnoFetcher := func(ctx context.Context, subWallet uint32) (uint32, error) {
 		return txInput.Seq, nil
 	}
 
-	w, err := wallet.FromAddress(seqnoFetcher, fromAddr, version, &subwalletID)
+	w, err := wallet.FromAddress(seqnoFetcher, fromAddr, version, &opts.SubwalletID)
 	if err != nil {
 		return nil, err
 	}
 
 	// initialized := acc.IsActive && acc.State.Status == tlb.AccountStatusActive
-	cellBuilder, err := w.BuildMessages(ctx, false, []*wallet.Message{message})
+	cellBuilder, err := w.BuildMessages(ctx, false, messages)
 	if err != nil {
 		return nil, err
 	}
 
-	return tx.NewTx(fromAddr, cellBuilder, stateInit), nil
+	newTx := tx.NewTx(fromAddr, cellBuilder, stateInit)
+	// highload v3 wallets expect the signed payload in a reference
+	newTx.PayloadInRef = opts.Version == wallet.HighloadV3
+	return newTx, nil
 }
 
 func BuildTransfer(
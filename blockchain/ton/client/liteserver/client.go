@@ -1,6 +1,7 @@
 package liteserver
 
 import (
+	"bytes"
 	"context"
 	"crypto/ed25519"
 	"encoding/hex"
@@ -26,6 +27,7 @@ import (
 	xcclient "github.com/openweb3-io/crosschain/client"
 	xc_types "github.com/openweb3-io/crosschain/types"
 	"github.com/xssnick/tonutils-go/address"
+	"github.com/xssnick/tonutils-go/tl"
 	"github.com/xssnick/tonutils-go/tlb"
 	_ton "github.com/xssnick/tonutils-go/ton"
 	"go.uber.org/zap"
@@ -42,6 +44,8 @@ type Client struct {
 }
 
 var _ xcclient.IClient = &Client{}
+var _ xcclient.HistoryClient = &Client{}
+var _ xcclient.BatchTransferClient = &Client{}
 
 func NewClient(cfg *xc_types.ChainConfig) (*Client, error) {
 	var url string
@@ -85,26 +89,30 @@ func (client *Client) FetchTransferInput(ctx context.Context, args *xcbuilder.Tr
 		return nil, err
 	}
 
-	seqResp, err := wrappedClient.RunGetMethod(ctx, b, fromAddr, "seqno")
-	if err != nil {
-		return nil, err
-	}
-
-	seq, err := seqResp.Int(0)
-	if err != nil {
-		return nil, err
-	}
-
 	balance := acc.State.Balance.Nano()
 
 	input := &ton.TxInput{
 		Timestamp:       time.Now().Unix(),
 		AccountStatus:   ton.AccountStatus(acc.State.Status),
 		TonBalance:      xc_types.BigInt(*balance),
-		Seq:             uint32(seq.Uint64()),
 		EstimatedMaxFee: xc_types.NewBigIntFromInt64(0), // TODO
 	}
 
+	// highload wallets have no seqno
+	extra, _ := args.GetExtra()
+	if !ton.GetWalletOptions(extra).IsHighload() {
+		seqResp, err := wrappedClient.RunGetMethod(ctx, b, fromAddr, "seqno")
+		if err != nil {
+			return nil, err
+		}
+
+		seq, err := seqResp.Int(0)
+		if err != nil {
+			return nil, err
+		}
+		input.Seq = uint32(seq.Uint64())
+	}
+
 	memo, _ := args.GetMemo()
 
 	asset, _ := args.GetAsset()
@@ -157,6 +165,11 @@ func (client *Client) FetchTransferInput(ctx context.Context, args *xcbuilder.Tr
 	return input, nil
 }
 
+// The input only depends on the sender and asset, so is the same as for a single transfer
+func (client *Client) FetchBatchTransferInput(ctx context.Context, args *xcbuilder.BatchTransferArgs) (xc_types.TxInput, error) {
+	return client.FetchTransferInput(ctx, args.FirstTransferArgs())
+}
+
 func (client *Client) GetJettonWallet(ctx context.Context, from xc_types.Address, contract xc_types.ContractAddress) (xc_types.Address, error) {
 	addr, err := address.ParseAddr(string(from))
 	if err != nil {
@@ -521,6 +534,276 @@ func (client *Client) FetchLegacyTxInfo(ctx context.Context, txHash xc_types.TxH
 	return info, nil
 }
 
+func (client *Client) FetchTxInfo(ctx context.Context, txHash xc_types.TxHash) (*xcclient.TxInfo, error) {
+	chainInfo, err := client.Client.CurrentMasterchainInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := client.FetchTonTxByHash(ctx, txHash)
+	if err != nil {
+		return nil, err
+	}
+	// listing the transaction again by its lt reports the shard block it was included in
+	txs, blocks, err := client.listTransactions(ctx, accountAddress(tx), 1, tx.LT, tx.Hash)
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch block of transaction: %v", err)
+	}
+	shards, err := client.Client.GetBlockShardsInfo(ctx, chainInfo)
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch shards: %v", err)
+	}
+	return client.toTxInfo(ctx, txs[0], tontx.Normalize(string(txHash)), blocks[0], shards)
+}
+
+// The account a transaction executed on, which is in the basechain unless its in-message says otherwise
+func accountAddress(tx *tlb.Transaction) *address.Address {
+	workchain := int32(0)
+	if tx.IO.In != nil {
+		if dst := tx.IO.In.Msg.DestAddr(); dst != nil {
+			workchain = dst.Workchain()
+		}
+	}
+	return address.NewAddress(0, byte(workchain), tx.AccountAddr)
+}
+
+// listTransactions is ListTransactions of the liteserver client, but also returns the shard block each
+// transaction was included in.  Transactions are returned oldest first.
+func (client *Client) listTransactions(ctx context.Context, addr *address.Address, limit uint32, lt uint64, txHash []byte) ([]*tlb.Transaction, []*_ton.BlockIDExt, error) {
+	var resp tl.Serializable
+	err := client.Client.Client().QueryLiteserver(ctx, _ton.GetTransactions{
+		Limit: int32(limit),
+		AccID: &_ton.AccountID{
+			Workchain: addr.Workchain(),
+			ID:        addr.Data(),
+		},
+		LT:     int64(lt),
+		TxHash: txHash,
+	}, &resp)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	switch t := resp.(type) {
+	case _ton.TransactionList:
+		if len(t.Transactions) == 0 {
+			return nil, nil, _ton.ErrNoTransactionsWereFound
+		}
+		txList, err := cell.FromBOCMultiRoot(t.Transactions)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to parse cell from transaction bytes: %w", err)
+		}
+		if len(t.IDs) != len(txList) {
+			return nil, nil, fmt.Errorf("expected a block for each of %d transactions, got %d", len(txList), len(t.IDs))
+		}
+		txs := make([]*tlb.Transaction, len(txList))
+		blocks := make([]*_ton.BlockIDExt, len(txList))
+		for i := range txList {
+			var tx tlb.Transaction
+			if err := tlb.LoadFromCell(&tx, txList[i].BeginParse()); err != nil {
+				return nil, nil, fmt.Errorf("failed to load transaction from cell: %w", err)
+			}
+			tx.Hash = txList[i].Hash()
+			// the transactions are a chain back from the requested hash
+			if !bytes.Equal(txHash, tx.Hash) {
+				return nil, nil, errors.New("incorrect transaction hash, not matches prev tx hash")
+			}
+			txHash = tx.PrevTxHash
+			txs[len(txList)-1-i] = &tx
+			blocks[len(txList)-1-i] = t.IDs[i]
+		}
+		return txs, blocks, nil
+	case _ton.LSError:
+		if t.Code == 0 {
+			return nil, nil, _ton.ErrNoTransactionsWereFound
+		}
+		return nil, nil, t
+	}
+	return nil, nil, errors.New("unknown response type")
+}
+
+// Number of blocks of the shard since the block, counting the block itself, by the current top block of
+// the shard.  Shards split and merge, so a block of a shard that no longer exists only counts itself.
+func confirmations(block *_ton.BlockIDExt, shards []*_ton.BlockIDExt) uint64 {
+	for _, shard := range shards {
+		if shard.Workchain == block.Workchain && shard.Shard == block.Shard && shard.SeqNo >= block.SeqNo {
+			return uint64(shard.SeqNo-block.SeqNo) + 1
+		}
+	}
+	return 1
+}
+
+func (client *Client) toTxInfo(ctx context.Context, tx *tlb.Transaction, txHash string, block *_ton.BlockIDExt, shards []*_ton.BlockIDExt) (*xcclient.TxInfo, error) {
+	chain := client.cfg.Chain
+
+	var errMsg *string
+	if desc, ok := tx.Description.Description.(tlb.TransactionDescriptionOrdinary); ok && desc.Aborted {
+		msg := "transaction aborted"
+		errMsg = &msg
+	}
+
+	txInfo := xcclient.NewTxInfo(
+		xcclient.NewBlock(uint64(block.SeqNo), hex.EncodeToString(block.RootHash), time.Unix(int64(tx.Now), 0)),
+		chain,
+		txHash,
+		confirmations(block, shards),
+		errMsg,
+	)
+
+	outMsgs, err := tx.IO.Out.ToSlice()
+	if err != nil {
+		return nil, err
+	}
+	for _, msg := range outMsgs {
+		if msg.MsgType != tlb.MsgTypeInternal {
+			continue
+		}
+		intMsg := msg.AsInternal()
+		if intMsg.Bounced {
+			// if the message bounced, do no add transfers
+			continue
+		}
+		if intMsg.SrcAddr == nil || intMsg.DstAddr == nil || intMsg.Amount.Nano().Sign() == 0 {
+			continue
+		}
+		from, err := tonaddress.ParseAddress(xc_types.Address(intMsg.SrcAddr.String()), "")
+		if err != nil {
+			return nil, fmt.Errorf("invalid address %v: %v", intMsg.SrcAddr, err)
+		}
+		to, err := tonaddress.ParseAddress(xc_types.Address(intMsg.DstAddr.String()), "")
+		if err != nil {
+			return nil, fmt.Errorf("invalid address %s: %v", intMsg.DstAddr.String(), err)
+		}
+		txInfo.AddSimpleTransfer(xc_types.Address(from.String()), xc_types.Address(to.String()), "", xc_types.BigInt(*intMsg.Amount.Nano()), nil, intMsg.Comment())
+	}
+
+	// fees are always paid by the account the transaction executed on, which is the destination of the in-message
+	var account *address.Address
+	if tx.IO.In != nil {
+		switch tx.IO.In.MsgType {
+		case tlb.MsgTypeInternal:
+			intMsg := tx.IO.In.AsInternal()
+			account = intMsg.DstAddr
+			if intMsg.SrcAddr != nil && intMsg.DstAddr != nil && intMsg.Amount.Nano().Sign() != 0 {
+				from, err := tonaddress.ParseAddress(xc_types.Address(intMsg.SrcAddr.String()), "")
+				if err != nil {
+					return nil, fmt.Errorf("invalid address %v: %v", intMsg.SrcAddr, err)
+				}
+				to, err := tonaddress.ParseAddress(xc_types.Address(intMsg.DstAddr.String()), "")
+				if err != nil {
+					return nil, fmt.Errorf("invalid address %v: %v", intMsg.DstAddr, err)
+				}
+				txInfo.AddSimpleTransfer(xc_types.Address(from.String()), xc_types.Address(to.String()), "", xc_types.BigInt(*intMsg.Amount.Nano()), nil, intMsg.Comment())
+			}
+		case tlb.MsgTypeExternalIn:
+			account = tx.IO.In.AsExternalIn().DstAddr
+
+			jettonSources, jettonDests, err := client.detectJettonMovements(ctx, tx)
+			if err != nil {
+				return nil, fmt.Errorf("could not detect jetton movements: %v", err)
+			}
+			for i, dest := range jettonDests {
+				if i >= len(jettonSources) {
+					break
+				}
+				txInfo.AddSimpleTransfer(jettonSources[i].Address, dest.Address, dest.ContractAddress, dest.Amount, nil, dest.Memo)
+			}
+		}
+	}
+	if account != nil {
+		payer, err := tonaddress.ParseAddress(xc_types.Address(account.String()), "")
+		if err != nil {
+			return nil, fmt.Errorf("invalid address %v: %v", account, err)
+		}
+		txInfo.AddFee(xc_types.Address(payer.String()), "", xc_types.BigInt(*tx.TotalFees.Coins.Nano()), nil)
+	}
+	txInfo.Fees = txInfo.CalculateFees()
+
+	return txInfo, nil
+}
+
+// The cursor is the logical time and hash of the next (older) transaction to list, formatted as "<lt>:<hash>".
+func (client *Client) FetchTransactionsByAddress(ctx context.Context, args xcclient.TransactionHistoryArgs) (*xcclient.TransactionHistoryPage, error) {
+	addr, err := address.ParseAddr(string(args.GetAddress()))
+	if err != nil {
+		return nil, err
+	}
+	chainInfo, err := client.Client.CurrentMasterchainInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var lt uint64
+	var ltHash []byte
+	if cursor, ok := args.GetCursor(); ok {
+		parts := strings.Split(cursor, ":")
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid cursor: %s", cursor)
+		}
+		lt, err = strconv.ParseUint(parts[0], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cursor: %s", cursor)
+		}
+		ltHash, err = hex.DecodeString(parts[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid cursor: %s", cursor)
+		}
+	} else {
+		account, err := client.Client.GetAccount(ctx, chainInfo, addr)
+		if err != nil {
+			return nil, errors.Wrap(err, "get account failed")
+		}
+		lt, ltHash = account.LastTxLT, account.LastTxHash
+	}
+	if lt == 0 {
+		// no transactions on the account
+		return xcclient.NewTransactionHistoryPage([]*xcclient.TxInfo{}, ""), nil
+	}
+
+	limit := args.GetLimit()
+	tonTxs, blocks, err := client.listTransactions(ctx, addr, uint32(limit), lt, ltHash)
+	if err != nil {
+		if errors.Is(err, _ton.ErrNoTransactionsWereFound) {
+			return xcclient.NewTransactionHistoryPage([]*xcclient.TxInfo{}, ""), nil
+		}
+		return nil, err
+	}
+	shards, err := client.Client.GetBlockShardsInfo(ctx, chainInfo)
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch shards: %v", err)
+	}
+
+	// transactions are listed oldest first
+	txs := []*xcclient.TxInfo{}
+	for i := len(tonTxs) - 1; i >= 0; i-- {
+		tx := tonTxs[i]
+		// transactions are looked up by the hash of their in-message, so report that where possible
+		txHash := hex.EncodeToString(tx.Hash)
+		if tx.IO.In != nil {
+			inMsg, err := tlb.ToCell(tx.IO.In)
+			if err != nil {
+				return nil, fmt.Errorf("could not serialize in-message of %x: %v", tx.Hash, err)
+			}
+			txHash = hex.EncodeToString(inMsg.Hash())
+		}
+		txInfo, err := client.toTxInfo(ctx, tx, txHash, blocks[i], shards)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse transaction %s: %v", txHash, err)
+		}
+		txs = append(txs, txInfo)
+	}
+	if contract, ok := args.GetContract(); ok {
+		// jetton movements can only be detected after parsing the transaction
+		txs = xcclient.FilterByContract(txs, contract)
+	}
+
+	nextCursor := ""
+	if oldest := tonTxs[0]; len(tonTxs) == limit && oldest.PrevTxLT != 0 {
+		nextCursor = fmt.Sprintf("%d:%s", oldest.PrevTxLT, hex.EncodeToString(oldest.PrevTxHash))
+	}
+	return xcclient.NewTransactionHistoryPage(txs, nextCursor), nil
+}
+
 // This detects any JettonMessage in the nest of "InternalMessage"
 // This may need to be expanded as Jetton transfer could be nested deeper in more 'InternalMessages'
 func (client *Client) detectJettonMovements(ctx context.Context, tx *tlb.Transaction) ([]*xc_types.LegacyTxInfoEndpoint, []*xc_types.LegacyTxInfoEndpoint, error) {
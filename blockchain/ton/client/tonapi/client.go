@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"math/big"
 	"net/url"
+	"strconv"
 	"strings"
 	"time"
 
@@ -61,6 +62,9 @@ type Client struct {
 }
 
 var _ xcclient.IClient = &Client{}
+var _ xcclient.HistoryClient = &Client{}
+var _ xcclient.BlockClient = &Client{}
+var _ xcclient.BatchTransferClient = &Client{}
 
 func NewClient(cfg *xc_types.ChainConfig) (*Client, error) {
 	var url = cfg.Client.URL
@@ -87,21 +91,26 @@ func (client *Client) FetchTransferInput(ctx context.Context, args *xcbuilder.Tr
 		return nil, err
 	}
 
-	seq, err := client.Client.GetAccountSeqno(ctx, _tonapi.GetAccountSeqnoParams{
-		AccountID: string(args.GetFrom()),
-	})
-	if err != nil {
-		return nil, err
-	}
-
 	input := &ton.TxInput{
 		Timestamp:       time.Now().Unix(),
 		AccountStatus:   ton.AccountStatus(acc.Status),
 		TonBalance:      xc_types.NewBigIntFromInt64(acc.GetBalance()),
-		Seq:             uint32(seq.Seqno),
 		EstimatedMaxFee: xc_types.NewBigIntFromInt64(0), // TODO
 	}
 
+	extra, _ := args.GetExtra()
+	walletOptions := ton.GetWalletOptions(extra)
+	// highload wallets have no seqno
+	if !walletOptions.IsHighload() {
+		seq, err := client.Client.GetAccountSeqno(ctx, _tonapi.GetAccountSeqnoParams{
+			AccountID: string(args.GetFrom()),
+		})
+		if err != nil {
+			return nil, err
+		}
+		input.Seq = uint32(seq.Seqno)
+	}
+
 	memo, _ := args.GetMemo()
 
 	asset, _ := args.GetAsset()
@@ -111,19 +120,6 @@ func (client *Client) FetchTransferInput(ctx context.Context, args *xcbuilder.Tr
 			return input, err
 		}
 
-		version := wallet.V4R2
-		subwalletID := uint32(tonaddress.DefaultSubwalletId)
-		extra, ok := args.GetExtra()
-		if ok {
-			if v, ok := extra["version"].(float64); ok {
-				version = wallet.Version(int(v))
-			}
-
-			if v, ok := extra["subwalletID"].(float64); ok {
-				subwalletID = uint32(v)
-			}
-		}
-
 		maxFee, err := client.EstimateMaxFee(
 			ctx,
 			args.GetFrom(),
@@ -132,8 +128,8 @@ func (client *Client) FetchTransferInput(ctx context.Context, args *xcbuilder.Tr
 			asset.GetDecimals(),
 			memo,
 			input.Seq,
-			subwalletID,
-			version,
+			walletOptions.SubwalletID,
+			walletOptions.Version,
 		)
 		if err != nil {
 			return input, err
@@ -165,6 +161,11 @@ func (client *Client) FetchTransferInput(ctx context.Context, args *xcbuilder.Tr
 	return input, nil
 }
 
+// The input only depends on the sender and asset, so is the same as for a single transfer
+func (client *Client) FetchBatchTransferInput(ctx context.Context, args *xcbuilder.BatchTransferArgs) (xc_types.TxInput, error) {
+	return client.FetchTransferInput(ctx, args.FirstTransferArgs())
+}
+
 func (client *Client) GetJettonWallet(ctx context.Context, from xc_types.Address, contract xc_types.ContractAddress) (xc_types.Address, error) {
 	// fromAddr, _ := address.ParseAddr(string(from))
 	// contractAddr, _ := address.ParseAddr(string(contract))
@@ -552,6 +553,215 @@ func (client *Client) FetchLegacyTxInfo(ctx context.Context, txHash xc_types.TxH
 	return info, nil
 }
 
+func (client *Client) FetchTxInfo(ctx context.Context, txHash xc_types.TxHash) (*xcclient.TxInfo, error) {
+	chainInfo, err := client.Client.GetRawMasterchainInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := client.FetchTonTxByHash(ctx, txHash)
+	if err != nil {
+		return nil, err
+	}
+	return client.toTxInfo(ctx, tx, chainInfo.Last.Seqno)
+}
+
+func (client *Client) toTxInfo(ctx context.Context, tx *_tonapi.Transaction, lastSeqno int32) (*xcclient.TxInfo, error) {
+	block, err := client.Client.GetBlockchainBlock(ctx, _tonapi.GetBlockchainBlockParams{
+		BlockID: tx.Block,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	confirmations := uint64(0)
+	if seqno := masterchainSeqno(block); lastSeqno >= seqno {
+		confirmations = uint64(lastSeqno-seqno) + 1
+	}
+	return client.toTxInfoInBlock(ctx, tx, xcclient.NewBlock(uint64(block.Seqno), block.RootHash, time.Unix(tx.Utime, 0)), confirmations)
+}
+
+// The seqno of the masterchain block that a block was committed in
+func masterchainSeqno(block *_tonapi.BlockchainBlock) int32 {
+	if block.WorkchainID == -1 || !block.MasterRef.IsSet() {
+		return block.Seqno
+	}
+	// formatted as (workchain,shard,seqno)
+	parts := strings.Split(strings.Trim(block.MasterRef.Value, "()"), ",")
+	seqno, err := strconv.ParseInt(parts[len(parts)-1], 10, 32)
+	if err != nil {
+		return block.Seqno
+	}
+	return int32(seqno)
+}
+
+func (client *Client) toTxInfoInBlock(ctx context.Context, tx *_tonapi.Transaction, block *xcclient.Block, confirmations uint64) (*xcclient.TxInfo, error) {
+	chain := client.cfg.Chain
+
+	var errMsg *string
+	if !tx.Success || tx.Aborted {
+		msg := "transaction failed"
+		errMsg = &msg
+	}
+	txInfo := xcclient.NewTxInfo(
+		block,
+		chain,
+		// Use the InMsg hash as this can be determined offline,
+		// whereas the tx.Hash is determined by the chain after submitting.
+		tontx.Normalize(tx.InMsg.Value.Hash),
+		confirmations,
+		errMsg,
+	)
+
+	for _, msg := range tx.OutMsgs {
+		if msg.Bounced {
+			// if the message bounced, do no add transfers
+			continue
+		}
+		if !msg.Source.IsSet() || msg.Source.Value.Address == "" || !msg.Destination.IsSet() || msg.Destination.Value.Address == "" || msg.Value == 0 {
+			continue
+		}
+		memo := ""
+		if msg.DecodedBody != nil && msg.DecodedOpName.Value == "text_comment" {
+			var body TextComment
+			_ = json.Unmarshal(msg.DecodedBody, &body)
+			memo = body.Text
+		}
+		from, err := tonaddress.ParseAddress(xc_types.Address(msg.Source.Value.Address), "")
+		if err != nil {
+			return nil, fmt.Errorf("invalid address %v: %v", msg.Source, err)
+		}
+		to, err := tonaddress.ParseAddress(xc_types.Address(msg.Destination.Value.Address), "")
+		if err != nil {
+			return nil, fmt.Errorf("invalid address %s: %v", msg.Destination.Value.Address, err)
+		}
+		txInfo.AddSimpleTransfer(xc_types.Address(from.String()), xc_types.Address(to.String()), "", xc_types.NewBigIntFromInt64(msg.Value), nil, memo)
+	}
+
+	if tx.InMsg.Value.MsgType == _tonapi.MessageMsgTypeIntMsg && tx.InMsg.Value.Value != 0 {
+		from, err := tonaddress.ParseAddress(xc_types.Address(tx.InMsg.Value.Source.Value.Address), "")
+		if err != nil {
+			return nil, fmt.Errorf("invalid address %v: %v", tx.InMsg.Value.Source, err)
+		}
+		to, err := tonaddress.ParseAddress(xc_types.Address(tx.InMsg.Value.Destination.Value.Address), "")
+		if err != nil {
+			return nil, fmt.Errorf("invalid address %v: %v", tx.InMsg.Value.Destination, err)
+		}
+		txInfo.AddSimpleTransfer(xc_types.Address(from.String()), xc_types.Address(to.String()), "", xc_types.NewBigIntFromInt64(tx.InMsg.Value.Value), nil, "")
+	}
+
+	jettonSources, jettonDests, err := client.detectJettonMovements(ctx, tx)
+	if err != nil {
+		return nil, fmt.Errorf("could not detect jetton movements: %v", err)
+	}
+	for i, dest := range jettonDests {
+		if i >= len(jettonSources) {
+			break
+		}
+		txInfo.AddSimpleTransfer(jettonSources[i].Address, dest.Address, dest.ContractAddress, dest.Amount, nil, dest.Memo)
+	}
+
+	// fees are always paid by the account the transaction executed on
+	account, err := tonaddress.ParseAddress(xc_types.Address(tx.Account.Address), "")
+	if err != nil {
+		return nil, fmt.Errorf("invalid address %s: %v", tx.Account.Address, err)
+	}
+	txInfo.AddFee(xc_types.Address(account.String()), "", xc_types.NewBigIntFromInt64(tx.TotalFees), nil)
+	txInfo.Fees = txInfo.CalculateFees()
+
+	return txInfo, nil
+}
+
+// Fetch the transactions of an account, most recent first.  The cursor is the logical time of the last transaction of the previous page.
+func (client *Client) FetchTransactionsByAddress(ctx context.Context, args xcclient.TransactionHistoryArgs) (*xcclient.TransactionHistoryPage, error) {
+	chainInfo, err := client.Client.GetRawMasterchainInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := args.GetLimit()
+	params := _tonapi.GetBlockchainAccountTransactionsParams{
+		AccountID: string(args.GetAddress()),
+		Limit:     _tonapi.NewOptInt32(int32(limit)),
+		SortOrder: _tonapi.NewOptGetBlockchainAccountTransactionsSortOrder(_tonapi.GetBlockchainAccountTransactionsSortOrderDesc),
+	}
+	if cursor, ok := args.GetCursor(); ok {
+		lt, err := strconv.ParseInt(cursor, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cursor: %s", cursor)
+		}
+		params.BeforeLt = _tonapi.NewOptInt64(lt)
+	}
+	resp, err := client.Client.GetBlockchainAccountTransactions(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	txs := []*xcclient.TxInfo{}
+	for i := range resp.Transactions {
+		txInfo, err := client.toTxInfo(ctx, &resp.Transactions[i], chainInfo.Last.Seqno)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse transaction %s: %v", resp.Transactions[i].Hash, err)
+		}
+		txs = append(txs, txInfo)
+	}
+	if contract, ok := args.GetContract(); ok {
+		// jetton movements can only be detected after parsing the transaction
+		txs = xcclient.FilterByContract(txs, contract)
+	}
+
+	nextCursor := ""
+	if len(resp.Transactions) == limit {
+		nextCursor = strconv.FormatInt(resp.Transactions[len(resp.Transactions)-1].Lt, 10)
+	}
+	return xcclient.NewTransactionHistoryPage(txs, nextCursor), nil
+}
+
+// The height of the chain is the seqno of the masterchain
+func (client *Client) FetchLatestHeight(ctx context.Context) (uint64, error) {
+	chainInfo, err := client.Client.GetRawMasterchainInfo(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return uint64(chainInfo.Last.Seqno), nil
+}
+
+// Fetch a masterchain block, including the transactions of all the shard blocks committed in it.
+// Masterchain blocks are final once produced, so no parent hash is reported.
+func (client *Client) FetchBlock(ctx context.Context, seqno uint64) (*xcclient.BlockWithTransactions, error) {
+	latestSeqno, err := client.FetchLatestHeight(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if seqno > latestSeqno {
+		return nil, fmt.Errorf("block %d is not yet available", seqno)
+	}
+
+	mcBlock, err := client.Client.GetBlockchainBlock(ctx, _tonapi.GetBlockchainBlockParams{
+		BlockID: fmt.Sprintf("(-1,8000000000000000,%d)", seqno),
+	})
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Client.GetBlockchainMasterchainTransactions(ctx, _tonapi.GetBlockchainMasterchainTransactionsParams{
+		MasterchainSeqno: int32(seqno),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	block := xcclient.NewBlock(seqno, mcBlock.RootHash, time.Unix(mcBlock.GenUtime, 0))
+	txs := []*xcclient.TxInfo{}
+	for i := range resp.Transactions {
+		txInfo, err := client.toTxInfoInBlock(ctx, &resp.Transactions[i], block, latestSeqno-seqno)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse transaction %s: %v", resp.Transactions[i].Hash, err)
+		}
+		txs = append(txs, txInfo)
+	}
+	return xcclient.NewBlockWithTransactions(block, "", txs), nil
+}
+
 // This detects any JettonMessage in the nest of "InternalMessage"
 // This may need to be expanded as Jetton transfer could be nested deeper in more 'InternalMessages'
 func (client *Client) detectJettonMovements(ctx context.Context, tx *_tonapi.Transaction) ([]*xc_types.LegacyTxInfoEndpoint, []*xc_types.LegacyTxInfoEndpoint, error) {
@@ -0,0 +1,201 @@
+package ton
+
+import (
+	"fmt"
+
+	"github.com/openweb3-io/crosschain/blockchain/ton/tx"
+	"github.com/openweb3-io/crosschain/blockchain/ton/wallet"
+	xclient "github.com/openweb3-io/crosschain/client"
+	xc_types "github.com/openweb3-io/crosschain/types"
+	"github.com/pkg/errors"
+	"github.com/xssnick/tonutils-go/address"
+	"github.com/xssnick/tonutils-go/tlb"
+	"github.com/xssnick/tonutils-go/ton/jetton"
+	"github.com/xssnick/tonutils-go/tvm/cell"
+)
+
+// Op code of the transfer message to a jetton wallet
+const JettonTransferOp = 0x0f8a7ea5
+
+type TxDecoder struct {
+	chain *xc_types.ChainConfig
+}
+
+var _ xclient.TxDecoder = &TxDecoder{}
+
+func NewTxDecoder(chain *xc_types.ChainConfig) (*TxDecoder, error) {
+	return &TxDecoder{
+		chain: chain,
+	}, nil
+}
+
+// DecodeTx decodes the internal messages sent by the wallet, whichever version it is.  Messages the wallet
+// sends to itself, like the batches of highload wallets, are decoded to the messages they carry.
+// Jetton transfers are of the jetton wallet of the sender, as its master can only be looked up on chain,
+// and the TON attached to them for gas is counted as a fee.
+func (decoder *TxDecoder) DecodeTx(xcTx xc_types.Tx) (*xclient.TxInfo, error) {
+	tonTx, ok := xcTx.(*tx.Tx)
+	if !ok {
+		return nil, fmt.Errorf("expected TON transaction, got %T", xcTx)
+	}
+	if tonTx.CellBuilder == nil || tonTx.ExternalMessage == nil || tonTx.ExternalMessage.DstAddr == nil {
+		return nil, errors.New("transaction not initialized")
+	}
+	walletAddr := tonTx.ExternalMessage.DstAddr
+	from := decoder.formatAddress(walletAddr, walletAddr.IsBounceable())
+	chain := decoder.chain.Chain
+	info := xclient.NewTxInfo(nil, chain, string(tonTx.Hash()), 0, nil)
+
+	messages := []*tlb.InternalMessage{}
+	if err := findMessages(tonTx.CellBuilder.EndCell(), walletAddr, true, &messages); err != nil {
+		return nil, err
+	}
+	if len(messages) == 0 {
+		return nil, errors.New("transaction sends no messages")
+	}
+	for _, msg := range messages {
+		to := decoder.formatAddress(msg.DstAddr, msg.Bounce)
+		amount := xc_types.BigInt(*msg.Amount.Nano())
+		if !isJettonTransfer(msg.Body) {
+			memo, ok := ParseComment(msg.Body)
+			if !ok && !isEmpty(msg.Body) {
+				// the body could call any contract, so the transfer alone would misrepresent it
+				return nil, fmt.Errorf("message to %s has a body that is neither a comment nor a jetton transfer", to)
+			}
+			info.AddSimpleTransfer(from, to, "", amount, nil, memo)
+			continue
+		}
+		payload := jetton.TransferPayload{}
+		if err := tlb.LoadFromCell(&payload, msg.Body.BeginParse()); err != nil {
+			return nil, errors.Wrap(err, "invalid jetton transfer")
+		}
+		memo, _ := ParseComment(payload.ForwardPayload)
+		contract := xc_types.ContractAddress(decoder.formatAddress(msg.DstAddr, true))
+		// the recipient is the owner of a jetton wallet, which is usually a wallet contract that cannot bounce
+		recipient := decoder.formatAddress(payload.Destination, false)
+		info.AddSimpleTransfer(from, recipient, contract, xc_types.BigInt(*payload.Amount.Nano()), nil, memo)
+		info.AddFee(from, "", amount, nil)
+	}
+	info.Fees = info.CalculateFees()
+	return info, nil
+}
+
+// Addresses in cells have no flags, so they are formatted for the network and whether messages to them bounce
+func (decoder *TxDecoder) formatAddress(addr *address.Address, bounce bool) xc_types.Address {
+	return xc_types.Address(addr.Bounce(bounce).Testnet(decoder.chain.Network == "testnet").String())
+}
+
+// Send modes the builder uses.  Other flags make the wallet send more than the amount of a message,
+// e.g. its whole balance with 128, so the amount would misrepresent what is sent.
+var allowedSendModes = map[uint8]bool{
+	wallet.PayGasSeparately:                       true,
+	wallet.PayGasSeparately + wallet.IgnoreErrors: true,
+}
+
+// Bits of the payload of highload v3 wallets: subwallet id, send mode, query id, creation time and ttl
+const highloadV3PayloadBits = 32 + 8 + 23 + 64 + 22
+
+// Collect the internal messages referenced from a cell, checking their send modes.  Identical messages are
+// each counted, as a wallet sends each reference it has to a message.
+func findMessages(c *cell.Cell, walletAddr *address.Address, root bool, messages *[]*tlb.InternalMessage) error {
+	refs := make([]*cell.Cell, c.RefsNum())
+	found := make([]*tlb.InternalMessage, c.RefsNum())
+	count := 0
+	for i := range refs {
+		ref, err := c.PeekRef(i)
+		if err != nil {
+			continue
+		}
+		refs[i] = ref
+		if msg, ok := loadInternalMessage(ref); ok {
+			found[i] = msg
+			count++
+		}
+	}
+	modes, err := sendModes(c, count, root)
+	if err != nil {
+		return err
+	}
+	for i, ref := range refs {
+		if ref == nil {
+			continue
+		}
+		msg := found[i]
+		if msg == nil {
+			if err := findMessages(ref, walletAddr, false, messages); err != nil {
+				return err
+			}
+			continue
+		}
+		mode := modes[0]
+		modes = modes[1:]
+		if !allowedSendModes[mode] {
+			return fmt.Errorf("message to %s has send mode %d, which can send more than its amount", msg.DstAddr.String(), mode)
+		}
+		if msg.DstAddr.Equals(walletAddr) && msg.Body != nil {
+			if err := findMessages(msg.Body, walletAddr, false, messages); err != nil {
+				return err
+			}
+			continue
+		}
+		*messages = append(*messages, msg)
+	}
+	return nil
+}
+
+// The send modes of the messages referenced by a cell, which wallets store next to the references: the
+// last byte of each message in order, as in the payloads of regular wallets, the dictionaries of
+// highload v2 and the action lists of v5 and highload v3, or right after the subwallet id in the
+// payload of highload v3.
+func sendModes(c *cell.Cell, count int, root bool) ([]uint8, error) {
+	if count == 0 {
+		return nil, nil
+	}
+	offset := int(c.BitsSize()) - 8*count
+	if root && count == 1 && c.BitsSize() == highloadV3PayloadBits {
+		offset = 32
+	}
+	if offset < 0 {
+		return nil, errors.New("messages have no send mode")
+	}
+	slice := c.BeginParse()
+	if _, err := slice.LoadSlice(uint(offset)); err != nil {
+		return nil, err
+	}
+	modes := make([]uint8, count)
+	for i := range modes {
+		mode, err := slice.LoadUInt(8)
+		if err != nil {
+			return nil, err
+		}
+		modes[i] = uint8(mode)
+	}
+	return modes, nil
+}
+
+// Messages sent by a wallet leave the source address to be filled in by the chain
+func loadInternalMessage(c *cell.Cell) (*tlb.InternalMessage, bool) {
+	msg := &tlb.InternalMessage{}
+	if err := tlb.LoadFromCell(msg, c.BeginParse()); err != nil {
+		return nil, false
+	}
+	if msg.SrcAddr != nil && msg.SrcAddr.Type() != address.NoneAddress {
+		return nil, false
+	}
+	if msg.DstAddr == nil || msg.DstAddr.Type() != address.StdAddress {
+		return nil, false
+	}
+	return msg, true
+}
+
+func isEmpty(body *cell.Cell) bool {
+	return body == nil || (body.BitsSize() == 0 && body.RefsNum() == 0)
+}
+
+func isJettonTransfer(body *cell.Cell) bool {
+	if body == nil {
+		return false
+	}
+	op, err := body.BeginParse().LoadUInt(32)
+	return err == nil && op == JettonTransferOp
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/openweb3-io/crosschain/factory/blockchains/registry"
 	xc_types "github.com/openweb3-io/crosschain/types"
 	"github.com/xssnick/tonutils-go/address"
 	"github.com/xssnick/tonutils-go/tlb"
@@ -15,7 +16,13 @@ import (
 type Tx struct {
 	CellBuilder     *cell.Builder
 	ExternalMessage *tlb.ExternalMessage
-	signatures      []xc_types.TxSignature
+	// Store the payload in a reference after the signature, rather than inline
+	PayloadInRef bool
+	signatures   []xc_types.TxSignature
+}
+
+func init() {
+	registry.RegisterUnsignedTx(xc_types.BlockchainTon, &Tx{})
 }
 
 func (tx *Tx) Serialize() ([]byte, error) {
@@ -62,8 +69,13 @@ func (tx *Tx) AddSignatures(sigs ...xc_types.TxSignature) error {
 	}
 
 	tx.signatures = sigs
-	msg := cell.BeginCell().MustStoreSlice(sigs[0], 512).MustStoreBuilder(tx.CellBuilder).EndCell()
-	tx.ExternalMessage.Body = msg
+	msg := cell.BeginCell().MustStoreSlice(sigs[0], 512)
+	if tx.PayloadInRef {
+		msg.MustStoreRef(tx.CellBuilder.EndCell())
+	} else {
+		msg.MustStoreBuilder(tx.CellBuilder)
+	}
+	tx.ExternalMessage.Body = msg.EndCell()
 	return nil
 }
 
@@ -0,0 +1,82 @@
+package tx
+
+import (
+	"encoding/json"
+
+	xc_types "github.com/openweb3-io/crosschain/types"
+	"github.com/xssnick/tonutils-go/address"
+	"github.com/xssnick/tonutils-go/tlb"
+	"github.com/xssnick/tonutils-go/tvm/cell"
+)
+
+type txJSON struct {
+	// The cell that is signed, as a BOC
+	Payload      []byte `json:"payload"`
+	PayloadInRef bool   `json:"payload_in_ref,omitempty"`
+	Destination  string `json:"destination"`
+	StateInit    []byte `json:"state_init,omitempty"`
+	// Set once signed
+	Body       []byte                 `json:"body,omitempty"`
+	Signatures []xc_types.TxSignature `json:"signatures,omitempty"`
+}
+
+func (tx Tx) MarshalJSON() ([]byte, error) {
+	encoded := txJSON{
+		PayloadInRef: tx.PayloadInRef,
+		Signatures:   tx.signatures,
+	}
+	if tx.CellBuilder != nil {
+		encoded.Payload = tx.CellBuilder.EndCell().ToBOC()
+	}
+	if tx.ExternalMessage != nil {
+		if tx.ExternalMessage.DstAddr != nil {
+			encoded.Destination = tx.ExternalMessage.DstAddr.String()
+		}
+		if tx.ExternalMessage.StateInit != nil {
+			stateInit, err := tlb.ToCell(tx.ExternalMessage.StateInit)
+			if err != nil {
+				return nil, err
+			}
+			encoded.StateInit = stateInit.ToBOC()
+		}
+		if tx.ExternalMessage.Body != nil {
+			encoded.Body = tx.ExternalMessage.Body.ToBOC()
+		}
+	}
+	return json.Marshal(encoded)
+}
+
+func (tx *Tx) UnmarshalJSON(data []byte) error {
+	var decoded txJSON
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	payload, err := cell.FromBOC(decoded.Payload)
+	if err != nil {
+		return err
+	}
+	dstAddr, err := address.ParseAddr(decoded.Destination)
+	if err != nil {
+		return err
+	}
+	var stateInit *tlb.StateInit
+	if len(decoded.StateInit) > 0 {
+		stateInitCell, err := cell.FromBOC(decoded.StateInit)
+		if err != nil {
+			return err
+		}
+		stateInit = &tlb.StateInit{}
+		if err := tlb.LoadFromCell(stateInit, stateInitCell.BeginParse()); err != nil {
+			return err
+		}
+	}
+	*tx = *NewTx(dstAddr, payload.ToBuilder(), stateInit)
+	tx.PayloadInRef = decoded.PayloadInRef
+	tx.signatures = decoded.Signatures
+	if len(decoded.Body) > 0 {
+		if tx.ExternalMessage.Body, err = cell.FromBOC(decoded.Body); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -5,6 +5,8 @@ import (
 	"testing"
 
 	"github.com/openweb3-io/crosschain/blockchain/ton"
+	tontx "github.com/openweb3-io/crosschain/blockchain/ton/tx"
+	"github.com/openweb3-io/crosschain/blockchain/ton/wallet"
 	xcbuilder "github.com/openweb3-io/crosschain/builder"
 	"github.com/openweb3-io/crosschain/types"
 	xc_types "github.com/openweb3-io/crosschain/types"
@@ -113,3 +115,61 @@ func TestTokenTx(t *testing.T) {
 		hex.EncodeToString(bz))
 
 }
+
+func TestBatchTx(t *testing.T) {
+	require := require.New(t)
+	builder, err := ton.NewTxBuilder(&xc_types.ChainConfig{Chain: xc_types.TON, Decimals: 9})
+	require.NoError(err)
+
+	from := xc_types.Address("EQAjflEZ_6KgKMxPlcnKN1ZoUvHdTT6hVwTW95EGVQfeSha2")
+	recipients := func(count int) []xcbuilder.Recipient {
+		recipients := []xcbuilder.Recipient{}
+		for i := 0; i < count; i++ {
+			recipients = append(recipients, xcbuilder.Recipient{
+				To:     "0QChotyiAtSPqs0BbPD851Mys9_LdMVM7N-atsFYvUMc48Jm",
+				Amount: xc_types.NewBigIntFromUint64(uint64(10 + i)),
+			})
+		}
+		return recipients
+	}
+	newBatch := func(count int, extra map[string]any) (types.Tx, error) {
+		args, err := xcbuilder.NewBatchTransferArgs(from, recipients(count), xcbuilder.WithExtra(extra))
+		require.NoError(err)
+		input := &ton.TxInput{AccountStatus: ton.AccountStatusActive, Timestamp: 1700000000}
+		return builder.NewBatchTransfer(args, input)
+	}
+	sign := func(tx types.Tx) {
+		require.NoError(tx.AddSignatures(make([]byte, 64)))
+		bz, err := tx.Serialize()
+		require.NoError(err)
+		require.NotEmpty(bz)
+	}
+
+	// regular wallets send up to 4 messages
+	tx, err := newBatch(4, nil)
+	require.NoError(err)
+	sign(tx)
+	_, err = newBatch(5, nil)
+	require.Error(err)
+
+	// highload wallets send many more
+	tx, err = newBatch(200, map[string]any{"version": float64(wallet.HighloadV2R2)})
+	require.NoError(err)
+	sign(tx)
+
+	// highload v3 wallets need a query id that is unique within the message TTL
+	_, err = newBatch(300, map[string]any{"version": float64(wallet.HighloadV3)})
+	require.ErrorContains(err, "queryID")
+	_, err = newBatch(300, map[string]any{"version": float64(wallet.HighloadV3), "queryID": float64(1 << 23)})
+	require.ErrorContains(err, "queryID")
+	tx, err = newBatch(300, map[string]any{"version": float64(wallet.HighloadV3), "queryID": float64(7)})
+	require.NoError(err)
+	sign(tx)
+	body := tx.(*tontx.Tx).ExternalMessage.Body
+	require.EqualValues(1, body.RefsNum())
+	payload, err := body.PeekRef(0)
+	require.NoError(err)
+	sighashes, err := tx.Sighashes()
+	require.NoError(err)
+	require.EqualValues(sighashes[0], payload.Hash())
+}
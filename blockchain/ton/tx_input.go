@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/openweb3-io/crosschain/factory/blockchains/registry"
 	xc_types "github.com/openweb3-io/crosschain/types"
 	"github.com/shopspring/decimal"
 )
@@ -29,6 +30,10 @@ type TxInput struct {
 	TonBalance      xc_types.BigInt
 }
 
+func init() {
+	registry.RegisterTxBaseInput(&TxInput{})
+}
+
 func NewTxInput() *TxInput {
 	return &TxInput{}
 }
@@ -1,9 +1,12 @@
 package wallet
 
 import (
+	"crypto/ed25519"
 	"crypto/hmac"
 	"crypto/rand"
 	"crypto/sha512"
+	"errors"
+	"fmt"
 	"math/big"
 	"strings"
 
@@ -17,14 +20,25 @@ const (
 	_PasswordSalt = "TON fast seed version"
 )
 
+// Number of words of the mnemonics of TON wallets, e.g. Tonkeeper and Tonhub
+const SeedWords = 24
+
+var (
+	ErrInvalidSeed      = errors.New("invalid TON mnemonic")
+	ErrPasswordRequired = errors.New("TON mnemonic requires a password")
+	ErrInvalidPassword  = errors.New("invalid password for TON mnemonic")
+)
+
 func NewSeed() []string {
 	return NewSeedWithPassword("")
 }
 
+// NewSeedWithPassword generates a mnemonic as TON wallets do: one that needs the password is not valid
+// without it.
 func NewSeedWithPassword(password string) []string {
 	for {
-		seed := make([]string, 24)
-		for i := 0; i < 24; i++ {
+		seed := make([]string, SeedWords)
+		for i := 0; i < SeedWords; i++ {
 			for {
 				x, err := rand.Int(rand.Reader, big.NewInt(int64(len(words))))
 				if err != nil {
@@ -36,24 +50,78 @@ func NewSeedWithPassword(password string) []string {
 			}
 		}
 
-		mac := hmac.New(sha512.New, []byte(strings.Join(seed, " ")))
-		mac.Write([]byte(password))
-		hash := mac.Sum(nil)
+		if len(password) > 0 && !isPasswordNeeded(seed) {
+			continue
+		}
+		if !isBasicSeed(seedEntropy(seed, password)) {
+			continue
+		}
+
+		return seed
+	}
+}
 
+// ValidateSeed checks that the words are a TON mnemonic, rather than a BIP-39 one, and that the
+// password is the one it was generated with, if it was generated with one.
+func ValidateSeed(seed []string, password string) error {
+	if len(seed) != SeedWords {
+		return fmt.Errorf("%w: expected %d words, got %d", ErrInvalidSeed, SeedWords, len(seed))
+	}
+	for _, word := range seed {
+		if !words[word] {
+			return fmt.Errorf("%w: unknown word '%s'", ErrInvalidSeed, word)
+		}
+	}
+	entropy := seedEntropy(seed, "")
+	switch {
+	case isBasicSeed(entropy):
 		if len(password) > 0 {
-			p := pbkdf2.Key(hash, []byte(_PasswordSalt), 1, 1, sha512.New)
-			if p[0] != 1 {
-				continue
-			}
-		} else {
-			p := pbkdf2.Key(hash, []byte(_BasicSalt), _Iterations/256, 1, sha512.New)
-			if p[0] != 0 {
-				continue
-			}
+			return fmt.Errorf("%w: the mnemonic has no password", ErrInvalidPassword)
 		}
+		return nil
+	case isPasswordSeed(entropy):
+		if len(password) == 0 {
+			return ErrPasswordRequired
+		}
+		if !isBasicSeed(seedEntropy(seed, password)) {
+			return ErrInvalidPassword
+		}
+		return nil
+	default:
+		return ErrInvalidSeed
+	}
+}
 
-		return seed
+// SeedToPrivateKey derives the ed25519 key of a TON mnemonic, which TON wallets use for every wallet
+// version of the mnemonic.
+func SeedToPrivateKey(seed []string, password string) (ed25519.PrivateKey, error) {
+	if err := ValidateSeed(seed, password); err != nil {
+		return nil, err
 	}
+	k := pbkdf2.Key(seedEntropy(seed, password), []byte(_Salt), _Iterations, ed25519.SeedSize, sha512.New)
+	return ed25519.NewKeyFromSeed(k), nil
+}
+
+func seedEntropy(seed []string, password string) []byte {
+	mac := hmac.New(sha512.New, []byte(strings.Join(seed, " ")))
+	mac.Write([]byte(password))
+	return mac.Sum(nil)
+}
+
+func isBasicSeed(entropy []byte) bool {
+	p := pbkdf2.Key(entropy, []byte(_BasicSalt), _Iterations/256, 1, sha512.New)
+	return p[0] == 0
+}
+
+func isPasswordSeed(entropy []byte) bool {
+	p := pbkdf2.Key(entropy, []byte(_PasswordSalt), 1, 1, sha512.New)
+	return p[0] == 1
+}
+
+// A mnemonic generated with a password is a password seed, but not a basic seed, without it
+func isPasswordNeeded(seed []string) bool {
+	entropy := seedEntropy(seed, "")
+	return isPasswordSeed(entropy) && !isBasicSeed(entropy)
 }
 
 type VersionConfig any
@@ -14,6 +14,7 @@ import (
 	xcbuilder "github.com/openweb3-io/crosschain/builder"
 	"github.com/openweb3-io/crosschain/types"
 	"golang.org/x/crypto/sha3"
+	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/anypb"
 )
 
@@ -76,10 +77,10 @@ func (b *TxBuilder) NewNativeTransfer(args *xcbuilder.TransferArgs, input types.
 		RefBlockNum: 0,
 	}
 
-	return &Tx{
+	return sponsor(&Tx{
 		TronTx: tx,
 		Args:   args,
-	}, nil
+	}, txInput)
 }
 
 func (b *TxBuilder) NewTokenTransfer(args *xcbuilder.TransferArgs, input types.TxInput) (types.Tx, error) {
@@ -168,10 +169,30 @@ func (b *TxBuilder) NewTokenTransfer(args *xcbuilder.TransferArgs, input types.T
 		tx.RawData.FeeLimit = 2000000000
 	}
 
-	return &Tx{
+	return sponsor(&Tx{
 		TronTx: tx,
 		Args:   args,
-	}, nil
+	}, txInput)
+}
+
+// Attach the delegation of resources from the fee payer, if one is set
+func sponsor(tx *Tx, txInput *tx_input.TxInput) (*Tx, error) {
+	feePayer, ok := tx.Args.GetFeePayer()
+	if !ok {
+		return tx, nil
+	}
+	sponsorship := txInput.Sponsorship
+	if sponsorship == nil || sponsorship.FeePayer != feePayer {
+		return nil, fmt.Errorf("input was not fetched to be sponsored by fee payer %s", feePayer)
+	}
+	rawData := &core.TransactionRaw{}
+	if err := proto.Unmarshal(sponsorship.DelegateRawData, rawData); err != nil {
+		return nil, fmt.Errorf("invalid delegating transaction: %v", err)
+	}
+	tx.Delegation = &core.Transaction{
+		RawData: rawData,
+	}
+	return tx, nil
 }
 
 func Signature(method string) []byte {
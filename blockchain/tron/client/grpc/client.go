@@ -14,6 +14,7 @@ import (
 
 	"github.com/btcsuite/btcutil/base58"
 	tronClient "github.com/fbsobreira/gotron-sdk/pkg/client"
+	"github.com/fbsobreira/gotron-sdk/pkg/common"
 	tronApi "github.com/fbsobreira/gotron-sdk/pkg/proto/api"
 	"github.com/fbsobreira/gotron-sdk/pkg/proto/core"
 	"github.com/openweb3-io/crosschain/blockchain/tron"
@@ -58,6 +59,9 @@ func NewClient(
 
 func (client *Client) FetchTransferInput(ctx context.Context, args *xcbuilder.TransferArgs) (xc_types.TxInput, error) {
 	input := new(tx_input.TxInput)
+	if _, ok := args.GetFeePayer(); ok {
+		return nil, errors.New("sponsored transactions are not supported by the grpc provider, use the rest provider")
+	}
 
 	asset, _ := args.GetAsset()
 	var err error
@@ -226,6 +230,92 @@ func (client *Client) FetchLegacyTxInfo(ctx context.Context, txHash xc_types.TxH
 	}, nil
 }
 
+func (client *Client) FetchTxInfo(ctx context.Context, txHash xc_types.TxHash) (*xcclient.TxInfo, error) {
+	tx, err := client.client.GetTransactionByID(string(txHash))
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := client.client.GetTransactionInfoByID(string(txHash))
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := client.client.GetBlockByNum(info.BlockNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	latestBlock, err := client.client.GetNowBlock()
+	if err != nil {
+		return nil, err
+	}
+	confirmations := uint64(0)
+	if latestNumber := latestBlock.BlockHeader.RawData.Number; latestNumber >= info.BlockNumber {
+		confirmations = uint64(latestNumber-info.BlockNumber) + 1
+	}
+
+	var errMsg *string
+	if info.Result == core.TransactionInfo_FAILED {
+		msg := "transaction failed"
+		if len(info.ResMessage) > 0 {
+			msg = string(info.ResMessage)
+		}
+		errMsg = &msg
+	}
+
+	txInfo := xcclient.NewTxInfo(
+		xcclient.NewBlock(uint64(info.BlockNumber), hex.EncodeToString(block.Blockid), time.UnixMilli(info.BlockTimeStamp)),
+		client.cfg.Chain,
+		string(txHash),
+		confirmations,
+		errMsg,
+	)
+
+	sources, destinations := deserialiseTransactionEvents(info.Log)
+	for i, dest := range destinations {
+		txInfo.AddSimpleTransfer(sources[i].Address, dest.Address, dest.ContractAddress, dest.Amount, nil, "")
+	}
+	// If there are no transaction events, the TX may be a native transfer
+	if len(destinations) == 0 && errMsg == nil {
+		from, to, amount, err := deserialiseNativeTransfer(tx)
+		if err == nil {
+			txInfo.AddSimpleTransfer(
+				xc_types.Address(common.EncodeCheck([]byte(from))),
+				xc_types.Address(common.EncodeCheck([]byte(to))),
+				"", amount, nil, "",
+			)
+		}
+	}
+
+	// fees are paid by the owner of the contract
+	if len(tx.RawData.Contract) > 0 && info.Fee > 0 {
+		if owner, ok := contractOwner(tx.RawData.Contract[0]); ok {
+			txInfo.AddFee(owner, "", xc_types.NewBigIntFromInt64(info.Fee), nil)
+		}
+	}
+	txInfo.Fees = txInfo.CalculateFees()
+
+	return txInfo, nil
+}
+
+// Every tron contract type identifies the account executing it as 'owner_address'
+func contractOwner(contract *core.Transaction_Contract) (xc_types.Address, bool) {
+	msg, err := contract.Parameter.UnmarshalNew()
+	if err != nil {
+		return "", false
+	}
+	field := msg.ProtoReflect().Descriptor().Fields().ByName("owner_address")
+	if field == nil {
+		return "", false
+	}
+	owner := msg.ProtoReflect().Get(field).Bytes()
+	if len(owner) == 0 {
+		return "", false
+	}
+	return xc_types.Address(common.EncodeCheck(owner)), true
+}
+
 func deserialiseTransactionEvents(log []*core.TransactionInfo_Log) ([]*xc_types.LegacyTxInfoEndpoint, []*xc_types.LegacyTxInfoEndpoint) {
 	sources := make([]*xc_types.LegacyTxInfoEndpoint, 0)
 	destinations := make([]*xc_types.LegacyTxInfoEndpoint, 0)
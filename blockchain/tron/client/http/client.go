@@ -12,6 +12,7 @@ import (
 	"github.com/fbsobreira/gotron-sdk/pkg/common"
 	"github.com/fbsobreira/gotron-sdk/pkg/proto/core"
 	"github.com/openweb3-io/crosschain/blockchain/tron/tx_input"
+	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/anypb"
 
 	"github.com/openweb3-io/crosschain/blockchain/tron"
@@ -25,10 +26,16 @@ import (
 )
 
 var _ xcclient.IClient = &Client{}
+var _ xcclient.HistoryClient = &Client{}
+var _ xcclient.BlockClient = &Client{}
+var _ xcclient.FinalityClient = &Client{}
 
 const TRANSFER_EVENT_HASH_HEX = "0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef"
 const TX_TIMEOUT = 2 * time.Hour
 
+// Bandwidth delegated to sponsor a native transfer, which is a little over its size
+const SponsoredBandwidth = int64(350)
+
 type Client struct {
 	cfg    *xc_types.ChainConfig
 	client *httpclient.Client
@@ -65,9 +72,72 @@ func (client *Client) FetchTransferInput(ctx context.Context, args *xcbuilder.Tr
 	input.Timestamp = time.Now().Unix()
 	input.Expiration = time.Now().Add(TX_TIMEOUT).Unix()
 
+	if feePayer, ok := args.GetFeePayer(); ok {
+		input.Sponsorship, err = client.fetchSponsorship(ctx, args, feePayer)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return input, nil
 }
 
+// Delegate enough resources from the fee payer for the sender to not burn TRX: energy for token
+// transfers and bandwidth for native transfers.
+func (client *Client) fetchSponsorship(ctx context.Context, args *xcbuilder.TransferArgs, feePayer xc_types.Address) (*tx_input.Sponsorship, error) {
+	resources, err := client.client.GetAccountResource(ctx, string(feePayer))
+	if err != nil {
+		return nil, errors.Wrap(err, "get fee payer resources")
+	}
+
+	resource := tx_input.ResourceBandwidth
+	usage := SponsoredBandwidth
+	totalLimit, totalWeight := resources.TotalNetLimit, resources.TotalNetWeight
+	if asset, _ := args.GetAsset(); asset != nil && asset.GetContract() != "" {
+		resource = tx_input.ResourceEnergy
+		params := []map[string]any{
+			{
+				"address": args.GetTo(),
+			},
+			{
+				"uint256": args.GetAmount().String(),
+			},
+		}
+		b, _ := json.Marshal(params)
+		estimate, err := client.client.EstimateEnergy(ctx, string(args.GetFrom()), string(asset.GetContract()), "transfer(address,uint256)", string(b), 0)
+		if err != nil {
+			return nil, err
+		}
+		usage = estimate.EnergyRequired
+		totalLimit, totalWeight = resources.TotalEnergyLimit, resources.TotalEnergyWeight
+	}
+	if totalLimit <= 0 {
+		return nil, fmt.Errorf("could not get the total %s limit of the network", resource)
+	}
+
+	// resources are shared in proportion to the TRX staked, round up to whole TRX
+	trx := (usage*totalWeight + totalLimit - 1) / totalLimit
+	if trx < 1 {
+		trx = 1
+	}
+	balance := xc_types.NewBigIntFromInt64(trx * 1_000_000)
+
+	delegation, err := client.FetchDelegatingTx(ctx, feePayer, args.GetFrom(), resource, balance)
+	if err != nil {
+		return nil, errors.Wrap(err, "fetch delegating tx")
+	}
+	rawData, err := proto.Marshal(delegation.(*tron.Tx).TronTx.RawData)
+	if err != nil {
+		return nil, err
+	}
+	return &tx_input.Sponsorship{
+		FeePayer:        feePayer,
+		Resource:        resource,
+		Balance:         balance,
+		DelegateRawData: rawData,
+	}, nil
+}
+
 func (client *Client) FetchLegacyTxInput(ctx context.Context, from xc_types.Address, to xc_types.Address, asset xc_types.IAsset) (xc_types.TxInput, error) {
 	// No way to pass the amount in the input using legacy interface, so we estimate using min amount.
 	args, _ := xcbuilder.NewTransferArgs(from, to, xc_types.NewBigIntFromUint64(1), xcbuilder.WithAsset(asset))
@@ -76,6 +146,16 @@ func (client *Client) FetchLegacyTxInput(ctx context.Context, from xc_types.Addr
 
 func (client *Client) BroadcastTx(ctx context.Context, _tx xc_types.Tx) error {
 	tx := _tx.(*tron.Tx)
+	if tx.Delegation != nil {
+		// the sender must have the resources before its transaction executes
+		bz, err := proto.Marshal(tx.Delegation)
+		if err != nil {
+			return err
+		}
+		if _, err := client.client.BroadcastHex(ctx, hex.EncodeToString(bz)); err != nil {
+			return errors.Wrap(err, "broadcast delegating tx")
+		}
+	}
 	bz, err := tx.Serialize()
 	if err != nil {
 		return err
@@ -155,13 +235,184 @@ func (client *Client) FetchLegacyTxInfo(ctx context.Context, txHash xc_types.TxH
 }
 
 func (client *Client) FetchTxInfo(ctx context.Context, txHashStr xc_types.TxHash) (*xcclient.TxInfo, error) {
-	legacyTx, err := client.FetchLegacyTxInfo(ctx, txHashStr)
+	tx, err := client.client.GetTransactionByID(ctx, string(txHashStr))
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := client.client.GetTransactionInfoByID(ctx, string(txHashStr))
 	if err != nil {
 		return nil, err
 	}
 
-	// remap to new tx
-	return xcclient.TxInfoFromLegacy(client.cfg.Chain, legacyTx, xcclient.Account), nil
+	block, err := client.client.GetBlockByNum(ctx, info.BlockNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	latestBlock, err := client.client.GetNowBlock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return client.toTxInfo(string(txHashStr), tx, info, block, latestBlock.BlockHeader.RawData.Number), nil
+}
+
+func (client *Client) toTxInfo(txHash string, tx *httpclient.GetTransactionIDResponse, info *httpclient.GetTransactionInfoById, block *httpclient.BlockResponse, latestBlock uint64) *xcclient.TxInfo {
+	confirmations := uint64(0)
+	if latestBlock >= info.BlockNumber {
+		confirmations = latestBlock - info.BlockNumber + 1
+	}
+
+	var errMsg *string
+	if info.Result == "FAILED" {
+		msg := "transaction failed"
+		if len(info.ResMessage) > 0 {
+			msg = string(info.ResMessage)
+		}
+		errMsg = &msg
+	}
+
+	txInfo := xcclient.NewTxInfo(
+		xcclient.NewBlock(info.BlockNumber, block.BlockId, time.UnixMilli(int64(info.BlockTimeStamp))),
+		client.cfg.Chain,
+		txHash,
+		confirmations,
+		errMsg,
+	)
+
+	sources, destinations := deserializeTransactionEvents(info.Logs)
+	for i, dest := range destinations {
+		txInfo.AddSimpleTransfer(sources[i].Address, dest.Address, dest.ContractAddress, dest.Amount, nil, "")
+	}
+	// If there are no transaction events, the TX may be a native transfer
+	if len(destinations) == 0 && errMsg == nil {
+		from, to, amount, err := deserializeNativeTransfer(tx)
+		if err == nil {
+			txInfo.AddSimpleTransfer(normalizeAddress(from), normalizeAddress(to), "", amount, nil, "")
+		}
+	}
+
+	// fees are paid by the owner of the contract
+	if len(tx.RawData.Contract) > 0 && info.Fee > 0 {
+		if owner, ok := tx.RawData.Contract[0].Parameter.Value["owner_address"].(string); ok {
+			txInfo.AddFee(normalizeAddress(xc_types.Address(owner)), "", xc_types.NewBigIntFromUint64(info.Fee), nil)
+		}
+	}
+	txInfo.Fees = txInfo.CalculateFees()
+
+	return txInfo
+}
+
+func (client *Client) FetchLatestHeight(ctx context.Context) (uint64, error) {
+	latestBlock, err := client.client.GetNowBlock(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return latestBlock.BlockHeader.RawData.Number, nil
+}
+
+func (client *Client) FetchFinalizedHeight(ctx context.Context) (uint64, error) {
+	solidBlock, err := client.client.GetNowSolidBlock(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return solidBlock.BlockHeader.RawData.Number, nil
+}
+
+func (client *Client) FetchBlock(ctx context.Context, height uint64) (*xcclient.BlockWithTransactions, error) {
+	latestHeight, err := client.FetchLatestHeight(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if height > latestHeight {
+		return nil, fmt.Errorf("block %d is not yet available", height)
+	}
+
+	block, err := client.client.GetBlockByNum(ctx, height)
+	if err != nil {
+		return nil, err
+	}
+	infos, err := client.client.GetTransactionInfoByBlockNum(ctx, height)
+	if err != nil {
+		return nil, err
+	}
+	infoById := map[string]*httpclient.GetTransactionInfoById{}
+	for _, info := range infos {
+		infoById[hex.EncodeToString(info.Id)] = info
+	}
+
+	txs := []*xcclient.TxInfo{}
+	for _, tx := range block.Transactions {
+		txHash := hex.EncodeToString(tx.TxID)
+		info, ok := infoById[txHash]
+		if !ok {
+			// transactions that did not consume any fee may not have any info reported
+			info = &httpclient.GetTransactionInfoById{
+				BlockNumber:    height,
+				BlockTimeStamp: block.BlockHeader.RawData.Timestamp,
+			}
+		}
+		txs = append(txs, client.toTxInfo(txHash, tx, info, block, latestHeight))
+	}
+	header := block.BlockHeader.RawData
+	return xcclient.NewBlockWithTransactions(
+		xcclient.NewBlock(height, block.BlockId, time.UnixMilli(int64(header.Timestamp))),
+		header.ParentHash,
+		txs,
+	), nil
+}
+
+// Lists transactions using the TronGrid v1 api, which is not available on all nodes.
+// When filtering by contract, the TRC-20 transfers of the account are listed instead.
+// The cursor is the fingerprint returned by TronGrid.
+func (client *Client) FetchTransactionsByAddress(ctx context.Context, args xcclient.TransactionHistoryArgs) (*xcclient.TransactionHistoryPage, error) {
+	address := string(args.GetAddress())
+	cursor, _ := args.GetCursor()
+	limit := args.GetLimit()
+
+	hashes := []string{}
+	nextCursor := ""
+	if contract, ok := args.GetContract(); ok {
+		resp, err := client.client.GetAccountTrc20Transactions(ctx, address, string(contract), limit, cursor)
+		if err != nil {
+			return nil, err
+		}
+		for _, transfer := range resp.Data {
+			// a transaction may include multiple transfers
+			if len(hashes) == 0 || hashes[len(hashes)-1] != transfer.TransactionID {
+				hashes = append(hashes, transfer.TransactionID)
+			}
+		}
+		nextCursor = resp.Meta.Fingerprint
+	} else {
+		resp, err := client.client.GetAccountTransactions(ctx, address, limit, cursor)
+		if err != nil {
+			return nil, err
+		}
+		for _, tx := range resp.Data {
+			hashes = append(hashes, tx.TxID)
+		}
+		nextCursor = resp.Meta.Fingerprint
+	}
+
+	txs := []*xcclient.TxInfo{}
+	for _, hash := range hashes {
+		txInfo, err := client.FetchTxInfo(ctx, xc_types.TxHash(hash))
+		if err != nil {
+			return nil, fmt.Errorf("could not fetch transaction %s: %v", hash, err)
+		}
+		txs = append(txs, txInfo)
+	}
+	return xcclient.NewTransactionHistoryPage(txs, nextCursor), nil
+}
+
+// The http api reports addresses as hex, which we convert to the usual base58 format.
+func normalizeAddress(addr xc_types.Address) xc_types.Address {
+	bz, err := hex.DecodeString(string(addr))
+	if err != nil {
+		return addr
+	}
+	return xc_types.Address(common.EncodeCheck(bz))
 }
 
 func (a *Client) FetchBalance(ctx context.Context, address xc_types.Address) (*xc_types.BigInt, error) {
@@ -192,7 +443,7 @@ func (client *Client) EstimateGasFee(ctx context.Context, tx xc_types.Tx) (amoun
 	txSize := int64(len(txRawData) - 1) // actual tx size is less than serialized size by 1 byte
 
 	// signatures also consume bandwidth, so we need to add them
-	signatures := len(tx.GetSignatures())
+	signatures := len(_tx.TronTx.Signature)
 	if signatures == 0 {
 		return nil, errors.New("transaction has no signatures")
 	}
@@ -475,7 +726,7 @@ func (client *Client) EstimateGasFeeIncludeResourceUsage(ctx context.Context, tx
 	txSize := int64(len(txRawData) - 1) // actual tx size is less than serialized size by 1 byte
 
 	// signatures also consume bandwidth, so we need to add them
-	signatures := len(tx.GetSignatures())
+	signatures := len(_tx.TronTx.Signature)
 	if signatures == 0 {
 		return nil, errors.New("transaction has no signatures")
 	}
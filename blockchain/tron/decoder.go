@@ -0,0 +1,102 @@
+package tron
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/fbsobreira/gotron-sdk/pkg/common"
+	"github.com/fbsobreira/gotron-sdk/pkg/proto/core"
+	xclient "github.com/openweb3-io/crosschain/client"
+	"github.com/openweb3-io/crosschain/types"
+)
+
+// TxDecoder for Tron
+type TxDecoder struct {
+	Chain *types.ChainConfig
+}
+
+var _ xclient.TxDecoder = &TxDecoder{}
+
+func NewTxDecoder(chain *types.ChainConfig) (*TxDecoder, error) {
+	return &TxDecoder{
+		Chain: chain,
+	}, nil
+}
+
+// DecodeTx decodes TRX and TRC20 transfers, staking, and the data of the transaction as its memo.
+// The fee of a contract call is its fee limit.  A delegation of resources from a fee payer is checked
+// to only delegate, as it moves no funds.
+func (decoder *TxDecoder) DecodeTx(xcTx types.Tx) (*xclient.TxInfo, error) {
+	tronTx, ok := xcTx.(*Tx)
+	if !ok {
+		return nil, fmt.Errorf("expected tron transaction, got %T", xcTx)
+	}
+	if tronTx.TronTx == nil || tronTx.TronTx.RawData == nil {
+		return nil, errors.New("transaction not initialized")
+	}
+	if tronTx.Delegation != nil {
+		for _, contract := range tronTx.Delegation.GetRawData().GetContract() {
+			if contract.Type != core.Transaction_Contract_DelegateResourceContract {
+				return nil, fmt.Errorf("cannot decode %s in the delegation of the fee payer", contract.Type)
+			}
+		}
+	}
+	chain := decoder.Chain.Chain
+	info := xclient.NewTxInfo(nil, chain, string(tronTx.Hash()), 0, nil)
+	rawData := tronTx.TronTx.RawData
+	memo := string(rawData.Data)
+
+	for _, contract := range rawData.Contract {
+		params, err := contract.GetParameter().UnmarshalNew()
+		if err != nil {
+			return nil, err
+		}
+		switch params := params.(type) {
+		case *core.TransferContract:
+			from := types.Address(common.EncodeCheck(params.OwnerAddress))
+			to := types.Address(common.EncodeCheck(params.ToAddress))
+			info.AddSimpleTransfer(from, to, "", types.NewBigIntFromInt64(params.Amount), nil, memo)
+		case *core.TriggerSmartContract:
+			from := types.Address(common.EncodeCheck(params.OwnerAddress))
+			contractAddress := types.ContractAddress(common.EncodeCheck(params.ContractAddress))
+			to, amount, err := decodeTrc20Transfer(params.Data)
+			if err != nil {
+				return nil, fmt.Errorf("cannot decode call to %s: %v", contractAddress, err)
+			}
+			if params.CallValue != 0 || params.CallTokenValue != 0 {
+				return nil, fmt.Errorf("cannot decode value sent to %s", contractAddress)
+			}
+			info.AddSimpleTransfer(from, to, contractAddress, amount, nil, memo)
+			info.AddFee(from, "", types.NewBigIntFromInt64(rawData.FeeLimit), nil)
+		case *core.FreezeBalanceV2Contract:
+			info.Stakes = append(info.Stakes, &xclient.Stake{
+				Balance: types.NewBigIntFromInt64(params.FrozenBalance),
+				Account: params.Resource.String(),
+				Address: common.EncodeCheck(params.OwnerAddress),
+			})
+		case *core.UnfreezeBalanceV2Contract:
+			info.Unstakes = append(info.Unstakes, &xclient.Unstake{
+				Balance: types.NewBigIntFromInt64(params.UnfreezeBalance),
+				Account: params.Resource.String(),
+				Address: common.EncodeCheck(params.OwnerAddress),
+			})
+		default:
+			return nil, fmt.Errorf("cannot decode %s", contract.Type)
+		}
+	}
+	info.Fees = info.CalculateFees()
+	return info, nil
+}
+
+// Decode the recipient and amount of transfer(address,uint256)
+func decodeTrc20Transfer(data []byte) (types.Address, types.BigInt, error) {
+	if len(data) != 4+32*2 || !bytes.Equal(data[:4], Signature("transfer(address,uint256)")) {
+		return "", types.BigInt{}, errors.New("payload is not TRC20.transfer(address,uint256)")
+	}
+	// tron addresses are the evm address with a prefix
+	to := append([]byte{0x41}, data[4+12:4+32]...)
+	amount := new(big.Int).SetBytes(data[4+32 : 4+2*32])
+	return types.Address(common.EncodeCheck(to)), types.BigInt(*amount), nil
+}
@@ -65,6 +65,8 @@ type ContractData[T any] struct {
 
 type Receipt struct {
 	NetFee uint64 `json:"net_fee"`
+	// e.g. SUCCESS, REVERT, OUT_OF_ENERGY
+	Result string `json:"result"`
 }
 
 type TransactionRawData[T any] struct {
@@ -99,6 +101,9 @@ type GetTransactionInfoById struct {
 	ContractResult  []string `json:"contractResult"`
 	Receipt         Receipt  `json:"receipt"`
 	ContractAddress string   `json:"contract_address"`
+	// set to FAILED if the transaction failed
+	Result     string `json:"result"`
+	ResMessage Bytes  `json:"resMessage"`
 
 	Logs                 []*Log                 `json:"log"`
 	InternalTransactions []*InternalTransaction `json:"internal_transactions"`
@@ -116,9 +121,10 @@ type InternalTransaction struct {
 	Note              Bytes `json:"note"`
 }
 type BlockHeaderRawData struct {
-	Number    uint64 `json:"number"`
-	Version   uint64 `json:"version"`
-	Timestamp uint64 `json:"timestamp"`
+	Number     uint64 `json:"number"`
+	Version    uint64 `json:"version"`
+	Timestamp  uint64 `json:"timestamp"`
+	ParentHash string `json:"parentHash"`
 	// other fields...
 }
 
@@ -131,6 +137,8 @@ type BlockResponse struct {
 	Error
 	BlockHeader BlockHeader `json:"block_header"`
 	BlockId     string      `json:"blockID"`
+	// only present when the block is fetched by number
+	Transactions []*GetTransactionIDResponse `json:"transactions"`
 }
 
 type TriggerConstantContractResponse struct {
@@ -364,6 +372,34 @@ type UnDelegateResourceContractParameterValue struct {
 	Balance         int64    `json:"balance"`
 }
 
+// Pagination metadata of the TronGrid v1 api
+type ListMeta struct {
+	At          int64  `json:"at"`
+	Fingerprint string `json:"fingerprint"`
+	PageSize    int    `json:"page_size"`
+}
+
+type AccountTransaction struct {
+	TxID           string `json:"txID"`
+	BlockNumber    uint64 `json:"blockNumber"`
+	BlockTimestamp int64  `json:"block_timestamp"`
+}
+
+type AccountTrc20Transaction struct {
+	TransactionID  string `json:"transaction_id"`
+	From           string `json:"from"`
+	To             string `json:"to"`
+	Value          string `json:"value"`
+	BlockTimestamp int64  `json:"block_timestamp"`
+}
+
+type ListResponse[T any] struct {
+	Error
+	Success bool     `json:"success"`
+	Data    []T      `json:"data"`
+	Meta    ListMeta `json:"meta"`
+}
+
 func NewHttpClient(baseUrl string) (*Client, error) {
 	baseUrl = strings.TrimSuffix(baseUrl, "/")
 	baseUrl = strings.TrimSuffix(baseUrl, "/wallet")
@@ -597,6 +633,86 @@ func (c *Client) GetBlockByNum(ctx context.Context, num uint64) (*BlockResponse,
 	return parsed, nil
 }
 
+// Fetch the info of every transaction in a block, in the same order as the transactions of the block.
+func (c *Client) GetTransactionInfoByBlockNum(ctx context.Context, num uint64) ([]*GetTransactionInfoById, error) {
+	req, err := postRequest(ctx, c.Url("wallet/gettransactioninfobyblocknum"), map[string]interface{}{
+		"num": num,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func(body io.ReadCloser) {
+		if body != nil {
+			_ = body.Close()
+		}
+	}(resp.Body)
+
+	bz, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	parsed := []*GetTransactionInfoById{}
+	if err = json.Unmarshal(bz, &parsed); err != nil {
+		// errors are reported as an object rather than a list
+		var errResponse Error
+		if json.Unmarshal(bz, &errResponse) == nil && checkError(errResponse) != nil {
+			return nil, checkError(errResponse)
+		}
+		return nil, err
+	}
+	for _, info := range parsed {
+		if err = checkError(info.Error); err != nil {
+			return nil, err
+		}
+	}
+
+	return parsed, nil
+}
+
+func (c *Client) GetNowBlock(ctx context.Context) (*BlockResponse, error) {
+	return c.getNowBlock(ctx, "wallet/getnowblock")
+}
+
+// Fetch the latest solidified block, which can no longer be rolled back.
+func (c *Client) GetNowSolidBlock(ctx context.Context) (*BlockResponse, error) {
+	return c.getNowBlock(ctx, "walletsolidity/getnowblock")
+}
+
+func (c *Client) getNowBlock(ctx context.Context, path string) (*BlockResponse, error) {
+	req, err := postRequest(ctx, c.Url(path), map[string]interface{}{})
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func(body io.ReadCloser) {
+		if body != nil {
+			_ = body.Close()
+		}
+	}(resp.Body)
+
+	parsed, err := parseResponse(resp, &BlockResponse{})
+	if err != nil {
+		return nil, err
+	}
+	if err = checkError(parsed.Error); err != nil {
+		return nil, err
+	}
+	if len(parsed.BlockId) == 0 {
+		return parsed, fmt.Errorf("could not find latest block")
+	}
+
+	return parsed, nil
+}
+
 func (c *Client) EstimateEnergy(
 	ctx context.Context,
 	ownerAddress string,
@@ -1101,3 +1217,57 @@ func (c *Client) UnDelegateResource(ctx context.Context, ownerAddress, receiverA
 
 	return parsed, nil
 }
+
+// Lists the transactions of an account, most recent first.  This is part of the TronGrid v1 api,
+// which is not available on all nodes.
+func (c *Client) GetAccountTransactions(ctx context.Context, address string, limit int, fingerprint string) (*ListResponse[AccountTransaction], error) {
+	query := url.Values{}
+	query.Set("limit", fmt.Sprint(limit))
+	if fingerprint != "" {
+		query.Set("fingerprint", fingerprint)
+	}
+	return getList[AccountTransaction](ctx, c, fmt.Sprintf("v1/accounts/%s/transactions", address), query)
+}
+
+// Lists the TRC-20 transfers of an account, most recent first, optionally for a single contract.
+func (c *Client) GetAccountTrc20Transactions(ctx context.Context, address string, contract string, limit int, fingerprint string) (*ListResponse[AccountTrc20Transaction], error) {
+	query := url.Values{}
+	query.Set("limit", fmt.Sprint(limit))
+	if contract != "" {
+		query.Set("contract_address", contract)
+	}
+	if fingerprint != "" {
+		query.Set("fingerprint", fingerprint)
+	}
+	return getList[AccountTrc20Transaction](ctx, c, fmt.Sprintf("v1/accounts/%s/transactions/trc20", address), query)
+}
+
+func getList[T any](ctx context.Context, c *Client, path string, query url.Values) (*ListResponse[T], error) {
+	req, err := getRequest(ctx, c.Url(path)+"?"+query.Encode())
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func(body io.ReadCloser) {
+		if body != nil {
+			_ = body.Close()
+		}
+	}(resp.Body)
+
+	parsed, err := parseResponse(resp, &ListResponse[T]{})
+	if err != nil {
+		return nil, err
+	}
+	if err = checkError(parsed.Error); err != nil {
+		return nil, err
+	}
+	if !parsed.Success {
+		return nil, fmt.Errorf("could not list %s", path)
+	}
+
+	return parsed, nil
+}
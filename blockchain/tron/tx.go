@@ -4,9 +4,12 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"errors"
+	"fmt"
 
+	"github.com/fbsobreira/gotron-sdk/pkg/common"
 	"github.com/fbsobreira/gotron-sdk/pkg/proto/core"
 	xcbuilder "github.com/openweb3-io/crosschain/builder"
+	"github.com/openweb3-io/crosschain/factory/blockchains/registry"
 	"github.com/openweb3-io/crosschain/types"
 	"google.golang.org/protobuf/proto"
 )
@@ -14,6 +17,14 @@ import (
 type Tx struct {
 	TronTx *core.Transaction
 	Args   *xcbuilder.TransferArgs
+	// Delegation of resources from a fee payer to the sender, which must be broadcast before TronTx
+	Delegation *core.Transaction
+}
+
+var _ types.TxWithSigners = &Tx{}
+
+func init() {
+	registry.RegisterUnsignedTx(types.BlockchainTron, &Tx{})
 }
 
 func (tx *Tx) Serialize() ([]byte, error) {
@@ -27,17 +38,42 @@ func (tx *Tx) Hash() types.TxHash {
 }
 
 func (tx Tx) Sighashes() ([]types.TxDataToSign, error) {
-	rawData, err := proto.Marshal(tx.TronTx.GetRawData())
+	requests, err := tx.SignatureRequests()
 	if err != nil {
-		return nil, errors.New("unable to get raw data")
+		return nil, err
 	}
-	hasher := sha256.New()
-	hasher.Write(rawData)
+	sighashes := make([]types.TxDataToSign, len(requests))
+	for i, request := range requests {
+		sighashes[i] = request.Payload
+	}
+	return sighashes, nil
+}
 
-	return []types.TxDataToSign{hasher.Sum(nil)}, nil
+// The delegation is signed by the fee payer first, then the transaction itself by the sender
+func (tx Tx) SignatureRequests() ([]*types.SignatureRequest, error) {
+	requests := []*types.SignatureRequest{}
+	for _, tronTx := range tx.transactions() {
+		sighash, err := sighash(tronTx)
+		if err != nil {
+			return nil, err
+		}
+		signer, err := owner(tronTx)
+		if err != nil {
+			return nil, err
+		}
+		requests = append(requests, types.NewSignatureRequest(signer, sighash, types.BlockchainTron.SignatureAlgorithm()))
+	}
+	return requests, nil
 }
 
 func (tx *Tx) AddSignatures(sigs ...types.TxSignature) error {
+	if tx.Delegation != nil {
+		if len(sigs) < 2 {
+			return fmt.Errorf("sponsored transaction needs signatures from the fee payer and sender, got %d", len(sigs))
+		}
+		tx.Delegation.Signature = [][]byte{sigs[0]}
+		sigs = sigs[1:]
+	}
 	for _, sig := range sigs {
 		tx.TronTx.Signature = append(tx.TronTx.Signature, sig)
 	}
@@ -46,10 +82,48 @@ func (tx *Tx) AddSignatures(sigs ...types.TxSignature) error {
 
 func (tx *Tx) GetSignatures() []types.TxSignature {
 	sigs := []types.TxSignature{}
-	if tx.TronTx != nil {
-		for _, sig := range tx.TronTx.Signature {
+	for _, tronTx := range tx.transactions() {
+		for _, sig := range tronTx.Signature {
 			sigs = append(sigs, sig)
 		}
 	}
 	return sigs
 }
+
+func (tx *Tx) transactions() []*core.Transaction {
+	transactions := []*core.Transaction{}
+	if tx.Delegation != nil {
+		transactions = append(transactions, tx.Delegation)
+	}
+	if tx.TronTx != nil {
+		transactions = append(transactions, tx.TronTx)
+	}
+	return transactions
+}
+
+func sighash(tronTx *core.Transaction) (types.TxDataToSign, error) {
+	rawData, err := proto.Marshal(tronTx.GetRawData())
+	if err != nil {
+		return nil, errors.New("unable to get raw data")
+	}
+	hasher := sha256.New()
+	hasher.Write(rawData)
+	return hasher.Sum(nil), nil
+}
+
+// The address that owns the contract of the transaction, which must sign it
+func owner(tronTx *core.Transaction) (types.Address, error) {
+	contracts := tronTx.GetRawData().GetContract()
+	if len(contracts) == 0 {
+		return "", errors.New("transaction has no contract")
+	}
+	params, err := contracts[0].GetParameter().UnmarshalNew()
+	if err != nil {
+		return "", fmt.Errorf("could not decode contract parameters: %v", err)
+	}
+	field := params.ProtoReflect().Descriptor().Fields().ByName("owner_address")
+	if field == nil {
+		return "", fmt.Errorf("contract %s has no owner address", contracts[0].GetType())
+	}
+	return types.Address(common.EncodeCheck(params.ProtoReflect().Get(field).Bytes())), nil
+}
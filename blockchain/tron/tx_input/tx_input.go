@@ -1,6 +1,7 @@
 package tx_input
 
 import (
+	"github.com/openweb3-io/crosschain/factory/blockchains/registry"
 	xc_types "github.com/openweb3-io/crosschain/types"
 )
 
@@ -16,6 +17,25 @@ type TxInput struct {
 	RefBlockHash  []byte
 	Expiration    int64
 	Timestamp     int64
+
+	// Set when a fee payer sponsors the transaction
+	Sponsorship *Sponsorship
+}
+
+func init() {
+	registry.RegisterTxBaseInput(&TxInput{})
+}
+
+// Resources delegated from a fee payer to the sender, so the sender does not burn TRX for the
+// energy or bandwidth of the transaction.  The delegation stays in place afterwards and may be
+// reclaimed by the fee payer with an undelegating transaction.
+type Sponsorship struct {
+	FeePayer xc_types.Address
+	Resource Resource
+	// Amount of staked TRX delegated, in SUN
+	Balance xc_types.BigInt
+	// Raw data of the delegating transaction, which the fee payer signs and is broadcast first
+	DelegateRawData []byte
 }
 
 func (input *TxInput) GetBlockchain() xc_types.Blockchain {
@@ -0,0 +1,51 @@
+package tron
+
+import (
+	"encoding/json"
+
+	"github.com/fbsobreira/gotron-sdk/pkg/proto/core"
+	"google.golang.org/protobuf/proto"
+)
+
+type txJSON struct {
+	TronTx     []byte `json:"tron_tx"`
+	Delegation []byte `json:"delegation,omitempty"`
+}
+
+// MarshalJSON encodes the transactions to sign.  The transfer arguments are left out, as they
+// are only used to estimate fees.
+func (tx Tx) MarshalJSON() ([]byte, error) {
+	var err error
+	encoded := txJSON{}
+	if tx.TronTx != nil {
+		if encoded.TronTx, err = proto.Marshal(tx.TronTx); err != nil {
+			return nil, err
+		}
+	}
+	if tx.Delegation != nil {
+		if encoded.Delegation, err = proto.Marshal(tx.Delegation); err != nil {
+			return nil, err
+		}
+	}
+	return json.Marshal(encoded)
+}
+
+func (tx *Tx) UnmarshalJSON(data []byte) error {
+	var decoded txJSON
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	*tx = Tx{
+		TronTx: &core.Transaction{},
+	}
+	if err := proto.Unmarshal(decoded.TronTx, tx.TronTx); err != nil {
+		return err
+	}
+	if len(decoded.Delegation) > 0 {
+		tx.Delegation = &core.Transaction{}
+		if err := proto.Unmarshal(decoded.Delegation, tx.Delegation); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package tron_test
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/fbsobreira/gotron-sdk/pkg/common"
+	"github.com/fbsobreira/gotron-sdk/pkg/proto/core"
+	"github.com/openweb3-io/crosschain/blockchain/tron"
+	"github.com/openweb3-io/crosschain/blockchain/tron/tx_input"
+	xcbuilder "github.com/openweb3-io/crosschain/builder"
+	xc_types "github.com/openweb3-io/crosschain/types"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+const from = "T9yD14Nponncw9JuyVhbJRzw1NajGHLh6e"
+const feePayer = "T9yD14NvTrTHi7ZCMikU5iJj6xR9CZABjm"
+
+func delegateRawData(t *testing.T) []byte {
+	owner, _ := common.DecodeCheck(feePayer)
+	receiver, _ := common.DecodeCheck(from)
+	param, err := anypb.New(&core.DelegateResourceContract{
+		OwnerAddress:    owner,
+		ReceiverAddress: receiver,
+		Resource:        core.ResourceCode_ENERGY,
+		Balance:         10_000_000,
+	})
+	require.NoError(t, err)
+	bz, err := proto.Marshal(&core.TransactionRaw{
+		Contract: []*core.Transaction_Contract{{
+			Type:      core.Transaction_Contract_DelegateResourceContract,
+			Parameter: param,
+		}},
+	})
+	require.NoError(t, err)
+	return bz
+}
+
+func TestSponsoredTransfer(t *testing.T) {
+	require := require.New(t)
+	builder, _ := tron.NewTxBuilder(&xc_types.ChainConfig{})
+	args, err := xcbuilder.NewTransferArgs(
+		from,
+		"T9yD14P27v7xV5oUjwoLrzcXCYFZBt7AZ2",
+		xc_types.NewBigIntFromUint64(1_000_000),
+		xcbuilder.WithAsset(&xc_types.TokenAssetConfig{Contract: "TR7NHqjeKQxGTCi8q8ZY4pL8otSzgjLj6t", Decimals: 6}),
+		xcbuilder.WithFeePayer(feePayer),
+	)
+	require.NoError(err)
+
+	_, err = builder.NewTransfer(args, &tx_input.TxInput{})
+	require.ErrorContains(err, "input was not fetched to be sponsored")
+
+	input := &tx_input.TxInput{
+		Sponsorship: &tx_input.Sponsorship{
+			FeePayer:        feePayer,
+			Resource:        tx_input.ResourceEnergy,
+			Balance:         xc_types.NewBigIntFromUint64(10_000_000),
+			DelegateRawData: delegateRawData(t),
+		},
+	}
+	tx, err := builder.NewTransfer(args, input)
+	require.NoError(err)
+	tronTx := tx.(*tron.Tx)
+	require.NotNil(tronTx.Delegation)
+
+	// the fee payer signs the delegation first
+	requests, err := tronTx.SignatureRequests()
+	require.NoError(err)
+	require.Len(requests, 2)
+	require.EqualValues(feePayer, requests[0].Signer)
+	require.EqualValues(from, requests[1].Signer)
+	sighashes, err := tx.Sighashes()
+	require.NoError(err)
+	require.Equal(requests[1].Payload, sighashes[1])
+	require.EqualValues(tx.Hash(), xc_types.TxHash(hex.EncodeToString(sighashes[1])))
+
+	require.ErrorContains(tx.AddSignatures([]byte{1}), "needs signatures from the fee payer and sender")
+	require.NoError(tx.AddSignatures([]byte{1}, []byte{2}))
+	require.Equal([][]byte{{1}}, tronTx.Delegation.Signature)
+	require.Equal([][]byte{{2}}, tronTx.TronTx.Signature)
+	require.Len(tx.GetSignatures(), 2)
+}
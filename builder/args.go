@@ -1,6 +1,8 @@
 package builder
 
 import (
+	"errors"
+
 	xc_types "github.com/openweb3-io/crosschain/types"
 	"go.uber.org/zap"
 )
@@ -20,6 +22,8 @@ type builderOptions struct {
 	stakeAccount *string
 
 	asset *xc_types.IAsset
+
+	feePayer *xc_types.Address
 }
 
 // All ArgumentBuilders should provide base arguments for transactions
@@ -59,6 +63,8 @@ func (opts *builderOptions) GetStakeAccount() (string, bool)         { return ge
 
 func (opts *builderOptions) GetAsset() (xc_types.IAsset, bool) { return get(opts.asset) }
 
+func (opts *builderOptions) GetFeePayer() (xc_types.Address, bool) { return get(opts.feePayer) }
+
 type BuilderOption func(opts *builderOptions) error
 
 func WithMemo(memo string) BuilderOption {
@@ -123,6 +129,18 @@ func WithAsset(asset xc_types.IAsset) BuilderOption {
 	}
 }
 
+// Have another account pay the fees of the transaction, e.g. a treasury sponsoring senders that
+// hold no native asset.  The fee payer must also sign the transaction.
+func WithFeePayer(feePayer xc_types.Address) BuilderOption {
+	return func(opts *builderOptions) error {
+		if feePayer == "" {
+			return errors.New("fee payer must not be empty")
+		}
+		opts.feePayer = &feePayer
+		return nil
+	}
+}
+
 // Previously the crosschain abstraction would require callers to set options
 // directly on the transaction input, if the interface was implemented on the input type.
 // However, this is very clear or easy to use.  This function bridges the gap, to allow
@@ -0,0 +1,86 @@
+package builder
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/openweb3-io/crosschain/types"
+)
+
+// A single payment within a batch transfer
+type Recipient struct {
+	To     types.Address `json:"to"`
+	Amount types.BigInt  `json:"amount"`
+}
+
+// Arguments to pay many recipients the same asset in a single transaction
+type BatchTransferArgs struct {
+	options    builderOptions
+	from       types.Address
+	recipients []Recipient
+}
+
+var _ TransactionOptions = &BatchTransferArgs{}
+
+func NewBatchTransferArgs(from types.Address, recipients []Recipient, options ...BuilderOption) (*BatchTransferArgs, error) {
+	builderOptions := builderOptions{}
+	args := &BatchTransferArgs{
+		options:    builderOptions,
+		from:       from,
+		recipients: recipients,
+	}
+	for _, opt := range options {
+		err := opt(&args.options)
+		if err != nil {
+			return args, err
+		}
+	}
+	if len(recipients) == 0 {
+		return args, errors.New("a batch transfer needs at least one recipient")
+	}
+	for i, recipient := range recipients {
+		if recipient.To == "" {
+			return args, fmt.Errorf("recipient %d has no address", i)
+		}
+		if recipient.Amount.Sign() <= 0 {
+			return args, fmt.Errorf("recipient %d must be paid a positive amount", i)
+		}
+	}
+	return args, nil
+}
+
+func (args *BatchTransferArgs) GetFrom() types.Address     { return args.from }
+func (args *BatchTransferArgs) GetRecipients() []Recipient { return args.recipients }
+
+// The sum paid to all recipients
+func (args *BatchTransferArgs) GetTotalAmount() types.BigInt {
+	total := types.NewBigIntFromUint64(0)
+	for _, recipient := range args.recipients {
+		total = total.Add(&recipient.Amount)
+	}
+	return total
+}
+
+// Exposed options
+func (args *BatchTransferArgs) GetMemo() (string, bool)     { return args.options.GetMemo() }
+func (args *BatchTransferArgs) GetTimestamp() (int64, bool) { return args.options.GetTimestamp() }
+func (args *BatchTransferArgs) GetPriority() (types.GasFeePriority, bool) {
+	return args.options.GetPriority()
+}
+func (args *BatchTransferArgs) GetPublicKey() ([]byte, bool)     { return args.options.GetPublicKey() }
+func (args *BatchTransferArgs) GetAsset() (types.IAsset, bool)   { return args.options.GetAsset() }
+func (args *BatchTransferArgs) GetExtra() (map[string]any, bool) { return args.options.GetExtra() }
+func (args *BatchTransferArgs) GetFeePayer() (types.Address, bool) {
+	return args.options.GetFeePayer()
+}
+
+// A single transfer of the total amount to the first recipient, with the same options.  Useful for
+// chains where the input of a transaction does not depend on the number of recipients.
+func (args *BatchTransferArgs) FirstTransferArgs() *TransferArgs {
+	return &TransferArgs{
+		options: args.options,
+		from:    args.from,
+		to:      args.recipients[0].To,
+		amount:  args.GetTotalAmount(),
+	}
+}
@@ -9,6 +9,11 @@ type TxBuilder interface {
 	NewTransfer(args *TransferArgs, input types.TxInput) (types.Tx, error)
 }
 
+// Optional interface for builders that can pay many recipients in a single transaction
+type TxBatchBuilder interface {
+	NewBatchTransfer(args *BatchTransferArgs, input types.TxInput) (types.Tx, error)
+}
+
 // TxTokenBuilder is a Builder that can transfer token assets, in addition to native assets
 // This interface is soon being removed.
 type TxTokenBuilder interface {
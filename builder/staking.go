@@ -33,6 +33,9 @@ func (args *StakeArgs) GetStakeOwner() (xc_types.Address, bool) { return args.op
 func (args *StakeArgs) GetStakeAccount() (string, bool)         { return args.options.GetStakeAccount() }
 
 func (args *StakeArgs) GetAsset() (xc_types.IAsset, bool) { return args.options.GetAsset() }
+func (args *StakeArgs) GetFeePayer() (xc_types.Address, bool) {
+	return args.options.GetFeePayer()
+}
 
 func NewStakeArgs(chain xc_types.NativeAsset, from xc_types.Address, amount xc_types.BigInt, options ...BuilderOption) (StakeArgs, error) {
 	builderOptions := builderOptions{}
@@ -81,3 +81,7 @@ func (args *TransferArgs) GetPublicKey() ([]byte, bool) {
 func (args *TransferArgs) GetExtra() (map[string]any, bool) {
 	return args.options.GetExtra()
 }
+
+func (args *TransferArgs) GetFeePayer() (types.Address, bool) {
+	return args.options.GetFeePayer()
+}
@@ -1,6 +1,10 @@
 package client
 
-import xc_types "github.com/openweb3-io/crosschain/types"
+import (
+	"fmt"
+
+	xc_types "github.com/openweb3-io/crosschain/types"
+)
 
 type StakedBalanceArgs struct {
 	from      xc_types.Address
@@ -44,6 +48,77 @@ func StakeBalanceOptionAccount(account string) StakedBalanceOption {
 	}
 }
 
+const DefaultTransactionHistoryLimit = 25
+
+type TransactionHistoryArgs struct {
+	address  xc_types.Address
+	contract *xc_types.ContractAddress
+	cursor   *string
+	limit    *int
+}
+type TransactionHistoryOption func(opts *TransactionHistoryArgs) error
+
+func (opts *TransactionHistoryArgs) GetAddress() xc_types.Address { return opts.address }
+func (opts *TransactionHistoryArgs) GetContract() (xc_types.ContractAddress, bool) {
+	return get(opts.contract)
+}
+func (opts *TransactionHistoryArgs) GetCursor() (string, bool) { return get(opts.cursor) }
+
+// The maximum number of transactions to return in a page
+func (opts *TransactionHistoryArgs) GetLimit() int {
+	if limit, ok := get(opts.limit); ok {
+		return limit
+	}
+	return DefaultTransactionHistoryLimit
+}
+
+func NewTransactionHistoryArgs(address xc_types.Address, options ...TransactionHistoryOption) (TransactionHistoryArgs, error) {
+	var contract *xc_types.ContractAddress
+	var cursor *string
+	var limit *int
+	args := TransactionHistoryArgs{
+		address,
+		contract,
+		cursor,
+		limit,
+	}
+	for _, opt := range options {
+		err := opt(&args)
+		if err != nil {
+			return args, err
+		}
+	}
+	return args, nil
+}
+
+// Only include transactions that move the given token contract
+func TransactionHistoryOptionContract(contract xc_types.ContractAddress) TransactionHistoryOption {
+	return func(opts *TransactionHistoryArgs) error {
+		opts.contract = &contract
+		return nil
+	}
+}
+
+// Continue from the cursor returned by a previous page
+func TransactionHistoryOptionCursor(cursor string) TransactionHistoryOption {
+	return func(opts *TransactionHistoryArgs) error {
+		if cursor != "" {
+			opts.cursor = &cursor
+		}
+		return nil
+	}
+}
+
+func TransactionHistoryOptionLimit(limit int) TransactionHistoryOption {
+	return func(opts *TransactionHistoryArgs) error {
+		if limit <= 0 {
+			return fmt.Errorf("limit must be positive, got %d", limit)
+		}
+		opts.limit = &limit
+		return nil
+	}
+}
+
 func get[T any](arg *T) (T, bool) {
 	if arg == nil {
 		var zero T
@@ -0,0 +1,24 @@
+package client
+
+import (
+	"errors"
+)
+
+// Returned by BlockClient.FetchBlock when there is no block at a height, e.g. a skipped slot on Solana.
+var ErrBlockSkipped = errors.New("no block produced at height")
+
+type BlockWithTransactions struct {
+	Block
+	// optional: the hash of the parent block, used to detect reorgs
+	ParentHash string `json:"parent_hash,omitempty"`
+	// all of the transactions in the block, normalized in the same way as FetchTxInfo
+	Transactions []*TxInfo `json:"transactions"`
+}
+
+func NewBlockWithTransactions(block *Block, parentHash string, transactions []*TxInfo) *BlockWithTransactions {
+	if transactions == nil {
+		// avoid serializing null's in json
+		transactions = []*TxInfo{}
+	}
+	return &BlockWithTransactions{*block, parentHash, transactions}
+}
@@ -15,6 +15,9 @@ type IClient interface {
 	// Fetching transaction info - legacy endpoint
 	FetchLegacyTxInfo(ctx context.Context, txHash xc_types.TxHash) (*xc_types.LegacyTxInfo, error)
 
+	// Fetching transaction info, with all balance movements normalized into transfers
+	FetchTxInfo(ctx context.Context, txHash xc_types.TxHash) (*TxInfo, error)
+
 	/**
 	 * get balance
 	 */
@@ -49,6 +52,41 @@ type StakingClient interface {
 	FetchWithdrawInput(ctx context.Context, args builder.StakeArgs) (xc_types.WithdrawTxInput, error)
 }
 
+// Optional interface for clients of chains that can pay many recipients in a single transaction
+type BatchTransferClient interface {
+	// Fetch the input for a transaction built with builder.TxBatchBuilder
+	FetchBatchTransferInput(ctx context.Context, args *builder.BatchTransferArgs) (xc_types.TxInput, error)
+}
+
+// Optional interface for clients that can list the past transactions of an address
+type HistoryClient interface {
+	// Fetch a page of transactions involving an address, most recent first.
+	// Continue to the next page by passing the returned cursor, until it is empty.
+	FetchTransactionsByAddress(ctx context.Context, args TransactionHistoryArgs) (*TransactionHistoryPage, error)
+}
+
+// Optional interface for clients that can follow the head of the chain and decode entire blocks
+type BlockClient interface {
+	// Fetch the height of the most recent block
+	FetchLatestHeight(ctx context.Context) (uint64, error)
+
+	// Fetch a block with all of its transactions.  Returns ErrBlockSkipped if no block was produced at the height.
+	FetchBlock(ctx context.Context, height uint64) (*BlockWithTransactions, error)
+}
+
+// Optional interface for clients of chains that report which blocks can no longer be reorged
+type FinalityClient interface {
+	// Fetch the height of the most recent final block, according to the native finality policy of the chain
+	FetchFinalizedHeight(ctx context.Context) (uint64, error)
+}
+
+// Optional interface for clients of chains that order the transactions of an address by a nonce or sequence
+type NonceClient interface {
+	// Fetch the nonce for the next transaction from the address.  Where the node exposes its mempool,
+	// this accounts for pending transactions.
+	FetchNonce(ctx context.Context, address xc_types.Address) (uint64, error)
+}
+
 // Special 3rd-party interface for Ethereum as ethereum doesn't understand delegated staking
 type ManualUnstakingClient interface {
 	CompleteManualUnstaking(ctx context.Context, unstake *Unstake) error
@@ -0,0 +1,46 @@
+package client
+
+import (
+	"github.com/openweb3-io/crosschain/normalize"
+	xc_types "github.com/openweb3-io/crosschain/types"
+)
+
+type TransactionHistoryPage struct {
+	Transactions []*TxInfo `json:"transactions"`
+	// opaque cursor to fetch the next page with; empty if there are no more pages
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+func NewTransactionHistoryPage(transactions []*TxInfo, nextCursor string) *TransactionHistoryPage {
+	if transactions == nil {
+		// avoid serializing null's in json
+		transactions = []*TxInfo{}
+	}
+	return &TransactionHistoryPage{transactions, nextCursor}
+}
+
+// Returns true if any of the transfers in the transaction move the given contract
+func (info *TxInfo) HasContract(contract xc_types.ContractAddress) bool {
+	want := normalize.Normalize(string(contract), info.Chain)
+	for _, tf := range info.Transfers {
+		for _, changes := range [][]*BalanceChange{tf.From, tf.To} {
+			for _, change := range changes {
+				if normalize.Normalize(string(change.Contract), info.Chain) == want {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// Drop any transactions that do not move the given contract
+func FilterByContract(transactions []*TxInfo, contract xc_types.ContractAddress) []*TxInfo {
+	filtered := []*TxInfo{}
+	for _, info := range transactions {
+		if info.HasContract(contract) {
+			filtered = append(filtered, info)
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,13 @@
+package client
+
+import (
+	xc_types "github.com/openweb3-io/crosschain/types"
+)
+
+// TxDecoder reads what an unsigned transaction does, so a signer can check it before signing,
+// e.g. after receiving it with UnmarshalUnsignedTx.  Only the contents covered by the signature are
+// decoded, never the arguments the transaction was built from.  The fees are the most the
+// transaction can pay, and there is no block or confirmations.
+type TxDecoder interface {
+	DecodeTx(tx xc_types.Tx) (*TxInfo, error)
+}
@@ -154,7 +154,8 @@ type TxInfo struct {
 	Stakes   []*Stake   `json:"stakes,omitempty"`
 	Unstakes []*Unstake `json:"unstakes,omitempty"`
 
-	// required: set the confirmations at time of querying the info
+	// required: set the confirmations at time of querying the info.  The block including the
+	// transaction counts as the first confirmation; a transaction not yet in a block has 0.
 	Confirmations uint64 `json:"confirmations"`
 	// optional: set the error of the transaction if there was an error
 	Error *string `json:"error,omitempty"`
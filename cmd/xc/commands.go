@@ -4,11 +4,25 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"os"
 
+	xcbuilder "github.com/openweb3-io/crosschain/builder"
+	xclient "github.com/openweb3-io/crosschain/client"
 	"github.com/openweb3-io/crosschain/cmd/xc/setup"
+	"github.com/openweb3-io/crosschain/config"
+	"github.com/openweb3-io/crosschain/factory"
+	"github.com/openweb3-io/crosschain/factory/signer"
+	xc "github.com/openweb3-io/crosschain/types"
+	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 )
 
+// The environment variable the private key or mnemonic to sign with, or a reference to it, is read from
+const PrivateKeyEnv = "PRIVATE_KEY"
+
+// The environment variable the password of a TON mnemonic, or the passphrase of a BIP-39 one, is read from
+const MnemonicPasswordEnv = "MNEMONIC_PASSWORD"
+
 func CmdChains() *cobra.Command {
 	return &cobra.Command{
 		Use:   "chains",
@@ -66,3 +80,303 @@ func CmdTxInput() *cobra.Command {
 	cmd.Flags().String("to", "", "Optional destination address")
 	return cmd
 }
+
+func CmdAddress() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "address",
+		Short: "Derive an address from the PRIVATE_KEY environment variable, or from an extended public key.",
+		Args:  cobra.ExactArgs(0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			xcFactory := setup.UnwrapXc(cmd.Context())
+			chain := setup.UnwrapChain(cmd.Context())
+			xpub, _ := cmd.Flags().GetString("xpub")
+
+			if xpub != "" {
+				// watch-only, the path is relative to the xpub
+				path, _ := cmd.Flags().GetString("hd-path")
+				if path == "" {
+					index, _ := cmd.Flags().GetUint32("hd-index")
+					path = fmt.Sprintf("0/%d", index)
+				}
+				address, err := xcFactory.GetAddressFromXPub(chain, xpub, path)
+				if err != nil {
+					return fmt.Errorf("could not derive address: %v", err)
+				}
+				fmt.Println(address)
+				return nil
+			}
+
+			_, from, _, err := loadSigner(cmd, xcFactory, chain)
+			if err != nil {
+				return err
+			}
+			fmt.Println(from)
+			return nil
+		},
+	}
+	cmd.Flags().String("xpub", "", "Extended public key of an account to derive a receive address from, by --hd-index or a relative --hd-path")
+	return cmd
+}
+
+func CmdBalance() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "balance <address>",
+		Short: "Check balance of an asset.  Reported as big integer, not accounting for any decimals.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			xcFactory := setup.UnwrapXc(cmd.Context())
+			chain := setup.UnwrapChain(cmd.Context())
+			contract, _ := cmd.Flags().GetString("contract")
+
+			client, err := xcFactory.NewClient(chain)
+			if err != nil {
+				return err
+			}
+			address := xc.Address(args[0])
+			var balance *xc.BigInt
+			if contract != "" {
+				balance, err = client.FetchBalanceForAsset(cmd.Context(), address, xc.ContractAddress(contract))
+			} else {
+				balance, err = client.FetchBalance(cmd.Context(), address)
+			}
+			if err != nil {
+				return fmt.Errorf("could not fetch balance for address %s: %v", address, err)
+			}
+			fmt.Println(balance.String())
+			return nil
+		},
+	}
+	cmd.Flags().String("contract", "", "Optional contract of token asset")
+	return cmd
+}
+
+func CmdTxInfo() *cobra.Command {
+	return &cobra.Command{
+		Use:     "tx-info <hash>",
+		Aliases: []string{"tx"},
+		Short:   "Check an existing transaction on chain.",
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			xcFactory := setup.UnwrapXc(cmd.Context())
+			chain := setup.UnwrapChain(cmd.Context())
+
+			client, err := xcFactory.NewClient(chain)
+			if err != nil {
+				return err
+			}
+			info, err := client.FetchTxInfo(cmd.Context(), xc.TxHash(args[0]))
+			if err != nil {
+				return fmt.Errorf("could not fetch tx info: %v", err)
+			}
+			printJson(info)
+			return nil
+		},
+	}
+}
+
+func CmdTransfer() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "transfer <to> <amount>",
+		Short: "Create and broadcast a new transaction transferring funds. The amount should be a decimal amount.",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			xcFactory := setup.UnwrapXc(cmd.Context())
+			chain := setup.UnwrapChain(cmd.Context())
+			contract, _ := cmd.Flags().GetString("contract")
+			memo, _ := cmd.Flags().GetString("memo")
+
+			priority, err := priorityFromCmd(cmd)
+			if err != nil {
+				return err
+			}
+			decimals, err := decimalsFromCmd(cmd, chain, contract)
+			if err != nil {
+				return err
+			}
+			amount, err := amountFromStr(args[1], decimals)
+			if err != nil {
+				return err
+			}
+			txSigner, from, publicKey, err := loadSigner(cmd, xcFactory, chain)
+			if err != nil {
+				return err
+			}
+			to := xc.Address(args[0])
+
+			options := []xcbuilder.BuilderOption{xcbuilder.WithPublicKey(publicKey)}
+			if memo != "" {
+				options = append(options, xcbuilder.WithMemo(memo))
+			}
+			if contract != "" {
+				options = append(options, xcbuilder.WithAsset(assetConfig(chain, xc.ContractAddress(contract), decimals)))
+			}
+			transferArgs, err := xcbuilder.NewTransferArgs(from, to, amount, options...)
+			if err != nil {
+				return err
+			}
+
+			client, err := xcFactory.NewClient(chain)
+			if err != nil {
+				return err
+			}
+			input, err := client.FetchTransferInput(cmd.Context(), transferArgs)
+			if err != nil {
+				return fmt.Errorf("could not fetch transfer input: %v", err)
+			}
+			if err := applyPriority(input, priority); err != nil {
+				return err
+			}
+			logrus.WithFields(logrus.Fields{
+				"from":   from,
+				"to":     to,
+				"amount": amount.String(),
+			}).Debug("transfer")
+
+			txBuilder, err := xcFactory.NewTxBuilder(chain)
+			if err != nil {
+				return err
+			}
+			tx, err := txBuilder.NewTransfer(transferArgs, input)
+			if err != nil {
+				return fmt.Errorf("could not build transfer: %v", err)
+			}
+			return signAndBroadcast(cmd.Context(), client, txSigner, tx)
+		},
+	}
+	cmd.Flags().String("contract", "", "Optional contract of token asset")
+	cmd.Flags().Int32("decimals", 0, "Decimals of the asset, required for tokens")
+	cmd.Flags().String("memo", "", "Optional memo to attach")
+	cmd.Flags().String("priority", "", "Optional fee priority, e.g. low, market, aggressive, very-aggressive or a multiplier like 1.5")
+	return cmd
+}
+
+// Loads the signer of PRIVATE_KEY, with its address and public key.  PRIVATE_KEY may also be a secret
+// reference, e.g. keystore:~/.cordial/keystore/cold.json
+func loadSigner(cmd *cobra.Command, xcFactory *factory.Factory, chain *xc.ChainConfig) (*signer.Signer, xc.Address, []byte, error) {
+	secret := os.Getenv(PrivateKeyEnv)
+	if secret == "" {
+		return nil, "", nil, fmt.Errorf("must set env %s", PrivateKeyEnv)
+	}
+	if config.HasTypePrefix(secret) {
+		var err error
+		secret, err = config.GetSecret(secret)
+		if err != nil {
+			return nil, "", nil, fmt.Errorf("could not load %s: %v", PrivateKeyEnv, err)
+		}
+	}
+	return signerFromSecret(xcFactory, chain, secret, derivationFromCmd(cmd)...)
+}
+
+func signerFromSecret(xcFactory *factory.Factory, chain *xc.ChainConfig, secret string, options ...signer.Option) (*signer.Signer, xc.Address, []byte, error) {
+	txSigner, err := xcFactory.NewSigner(chain, secret, options...)
+	if err != nil {
+		return nil, "", nil, fmt.Errorf("could not import private key: %v", err)
+	}
+	publicKey, err := txSigner.PublicKey()
+	if err != nil {
+		return nil, "", nil, fmt.Errorf("could not create public key: %v", err)
+	}
+	from, err := xcFactory.GetAddressFromPublicKey(chain, publicKey)
+	if err != nil {
+		return nil, "", nil, fmt.Errorf("could not derive address: %v", err)
+	}
+	return txSigner, from, publicKey, nil
+}
+
+// The derivation options of the --hd-* flags and MNEMONIC_PASSWORD, which only apply to mnemonics
+func derivationFromCmd(cmd *cobra.Command) []signer.Option {
+	options := []signer.Option{}
+	if password := os.Getenv(MnemonicPasswordEnv); password != "" {
+		options = append(options, signer.WithPassword(password))
+	}
+	if cmd.Flags().Changed("hd-account") {
+		account, _ := cmd.Flags().GetUint32("hd-account")
+		options = append(options, signer.WithAccount(account))
+	}
+	if cmd.Flags().Changed("hd-index") {
+		index, _ := cmd.Flags().GetUint32("hd-index")
+		options = append(options, signer.WithAddressIndex(index))
+	}
+	if path, _ := cmd.Flags().GetString("hd-path"); path != "" {
+		options = append(options, signer.WithPath(path))
+	}
+	if bip39, _ := cmd.Flags().GetBool("hd-bip39"); bip39 {
+		options = append(options, signer.WithBIP39())
+	}
+	if legacy, _ := cmd.Flags().GetBool("hd-legacy"); legacy {
+		options = append(options, signer.WithLegacyDerivation())
+	}
+	return options
+}
+
+// The decimals of the asset, which must be given for tokens as they are not in the chain config
+func decimalsFromCmd(cmd *cobra.Command, chain *xc.ChainConfig, contract string) (int32, error) {
+	decimals, _ := cmd.Flags().GetInt32("decimals")
+	if cmd.Flags().Changed("decimals") {
+		return decimals, nil
+	}
+	if contract != "" {
+		return 0, fmt.Errorf("--decimals is required for token transfers")
+	}
+	if chain.Decimals == 0 {
+		return 0, fmt.Errorf("decimals of %s are not configured, set --decimals", chain.Chain)
+	}
+	return chain.Decimals, nil
+}
+
+func amountFromStr(amountStr string, decimals int32) (xc.BigInt, error) {
+	amount, err := xc.NewAmountHumanReadableFromStr(amountStr)
+	if err != nil {
+		return xc.BigInt{}, fmt.Errorf("invalid amount %s: %v", amountStr, err)
+	}
+	amountBlockchain := amount.ToBlockchain(decimals)
+	if amountBlockchain.Sign() <= 0 {
+		return xc.BigInt{}, fmt.Errorf("amount must be positive, got %s", amountStr)
+	}
+	return amountBlockchain, nil
+}
+
+// The --priority flag, empty if not set
+func priorityFromCmd(cmd *cobra.Command) (xc.GasFeePriority, error) {
+	priorityStr, _ := cmd.Flags().GetString("priority")
+	if priorityStr == "" {
+		return "", nil
+	}
+	priority, err := xc.NewPriority(priorityStr)
+	if err != nil {
+		return "", fmt.Errorf("invalid priority %s: %v", priorityStr, err)
+	}
+	return priority, nil
+}
+
+func applyPriority(input xc.TxInput, priority xc.GasFeePriority) error {
+	if priority == "" {
+		return nil
+	}
+	withPriority, ok := input.(xc.TxInputGasFeeMultiplier)
+	if !ok {
+		return fmt.Errorf("fee priority is not supported for %T", input)
+	}
+	return withPriority.SetGasFeePriority(priority)
+}
+
+func signAndBroadcast(ctx context.Context, client xclient.IClient, txSigner *signer.Signer, tx xc.Tx) error {
+	signatures, err := txSigner.SignTx(tx)
+	if err != nil {
+		return fmt.Errorf("could not sign: %v", err)
+	}
+	if err := tx.AddSignatures(signatures...); err != nil {
+		return fmt.Errorf("could not add signatures: %v", err)
+	}
+	if err := client.BroadcastTx(ctx, tx); err != nil {
+		return fmt.Errorf("could not broadcast: %v", err)
+	}
+	logrus.Info("submitted tx, check its status with tx-info")
+	printJson(map[string]any{"hash": tx.Hash()})
+	return nil
+}
+
+func printJson(v any) {
+	bz, _ := json.MarshalIndent(v, "", "  ")
+	fmt.Println(string(bz))
+}
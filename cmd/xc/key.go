@@ -0,0 +1,212 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/openweb3-io/crosschain/cmd/xc/setup"
+	"github.com/openweb3-io/crosschain/config/constants"
+	"github.com/openweb3-io/crosschain/signer/keystore"
+	xc "github.com/openweb3-io/crosschain/types"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+func CmdKey() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "key",
+		Short: "Manage the encrypted keys of a keystore directory.  Sign with a key by setting PRIVATE_KEY=keystore:<path>.",
+		// only importing needs a chain
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if chain, _ := cmd.Flags().GetString("chain"); chain != "" {
+				return loadContext(cmd)
+			}
+			verbosity, _ := cmd.Flags().GetCount("verbose")
+			setup.ConfigureLogger(&setup.RpcArgs{Verbosity: verbosity})
+			return nil
+		},
+	}
+	cmd.PersistentFlags().String("keystore", filepath.Join(constants.DefaultHome, "keystore"), "Keystore directory")
+
+	cmd.AddCommand(CmdKeyImport())
+	cmd.AddCommand(CmdKeyExport())
+	cmd.AddCommand(CmdKeyList())
+	return cmd
+}
+
+func CmdKeyImport() *cobra.Command {
+	return &cobra.Command{
+		Use:   "import <name>",
+		Short: "Encrypt the private key or mnemonic of PRIVATE_KEY, or read from the terminal, into the keystore.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			chain, ok := cmd.Context().Value(setup.ContextChain).(*xc.ChainConfig)
+			if !ok {
+				return fmt.Errorf("--chain required")
+			}
+			xcFactory := setup.UnwrapXc(cmd.Context())
+			path, err := keyPath(cmd, args[0])
+			if err != nil {
+				return err
+			}
+			if _, err := os.Stat(path); err == nil {
+				return fmt.Errorf("key %s already exists", path)
+			}
+
+			secret := os.Getenv(PrivateKeyEnv)
+			if secret == "" {
+				secret, err = readTerminal("Private key or mnemonic: ")
+				if err != nil {
+					return err
+				}
+			}
+			_, address, _, err := signerFromSecret(xcFactory, chain, secret, derivationFromCmd(cmd)...)
+			if err != nil {
+				return err
+			}
+			passphrase, err := readPassphrase(true)
+			if err != nil {
+				return err
+			}
+			file, err := keystore.EncryptSecret(secret, chain.Blockchain.SignatureAlgorithm(), passphrase, keystore.WithAddress(chain.Chain, address))
+			if err != nil {
+				return err
+			}
+			if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+				return err
+			}
+			if err := file.Save(path); err != nil {
+				return fmt.Errorf("could not save key: %v", err)
+			}
+			logrus.WithField("address", address).Info("imported key, sign with it by setting " + PrivateKeyEnv + "=keystore:" + path)
+			printJson(keyInfo(args[0], file))
+			return nil
+		},
+	}
+}
+
+func CmdKeyExport() *cobra.Command {
+	return &cobra.Command{
+		Use:   "export <name>",
+		Short: "Decrypt a key of the keystore and print it.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, err := keyPath(cmd, args[0])
+			if err != nil {
+				return err
+			}
+			file, err := keystore.Load(path)
+			if err != nil {
+				return err
+			}
+			passphrase, err := readPassphrase(false)
+			if err != nil {
+				return err
+			}
+			secret, err := file.DecryptSecret(passphrase)
+			if err != nil {
+				return fmt.Errorf("could not decrypt %s: %v", path, err)
+			}
+			logrus.Warn("printing the unencrypted key")
+			fmt.Println(secret)
+			return nil
+		},
+	}
+}
+
+func CmdKeyList() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List the keys of the keystore, without decrypting them.",
+		Args:  cobra.ExactArgs(0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dir, _ := cmd.Flags().GetString("keystore")
+			paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+			if err != nil {
+				return err
+			}
+			sort.Strings(paths)
+			keys := []map[string]any{}
+			for _, path := range paths {
+				file, err := keystore.Load(path)
+				if err != nil {
+					logrus.WithError(err).Warn("skipping file")
+					continue
+				}
+				keys = append(keys, keyInfo(strings.TrimSuffix(filepath.Base(path), ".json"), file))
+			}
+			printJson(keys)
+			return nil
+		},
+	}
+}
+
+// The path of a key of the keystore, by its name
+func keyPath(cmd *cobra.Command, name string) (string, error) {
+	if name == "" || strings.ContainsAny(name, `/\`) || strings.HasPrefix(name, ".") {
+		return "", fmt.Errorf("invalid key name: %s", name)
+	}
+	dir, _ := cmd.Flags().GetString("keystore")
+	return filepath.Join(dir, name+".json"), nil
+}
+
+func keyInfo(name string, file *keystore.File) map[string]any {
+	return map[string]any{
+		"name":     name,
+		"chain":    file.Chain,
+		"address":  file.Address,
+		"key_type": file.Type(),
+	}
+}
+
+// The passphrase of KEYSTORE_PASSPHRASE, or read from the terminal
+func readPassphrase(confirm bool) (string, error) {
+	if passphrase, ok := os.LookupEnv(keystore.PassphraseEnv); ok {
+		return passphrase, nil
+	}
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		return "", fmt.Errorf("must set env %s", keystore.PassphraseEnv)
+	}
+	passphrase, err := readTerminal("Passphrase: ")
+	if err != nil {
+		return "", err
+	}
+	if confirm {
+		if passphrase == "" {
+			return "", errors.New("passphrase must not be empty")
+		}
+		repeated, err := readTerminal("Repeat passphrase: ")
+		if err != nil {
+			return "", err
+		}
+		if repeated != passphrase {
+			return "", errors.New("passphrases do not match")
+		}
+	}
+	return passphrase, nil
+}
+
+// Reads a line from the terminal without echoing it, or from stdin if it is not a terminal
+func readTerminal(prompt string) (string, error) {
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && line == "" {
+			return "", fmt.Errorf("could not read %s%v", strings.ToLower(prompt), err)
+		}
+		return strings.TrimSpace(line), nil
+	}
+	fmt.Fprint(os.Stderr, prompt)
+	bz, err := term.ReadPassword(fd)
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(bz)), nil
+}
@@ -1,6 +1,8 @@
 package main
 
 import (
+	"os"
+
 	"github.com/openweb3-io/crosschain/cmd/xc/setup"
 	"github.com/openweb3-io/crosschain/types"
 	xc "github.com/openweb3-io/crosschain/types"
@@ -15,39 +17,61 @@ func main() {
 		Args:         cobra.ExactArgs(0),
 		SilenceUsage: true,
 		PersistentPreRunE: func(cmd *cobra.Command, _ []string) error {
-			args, err := setup.RpcArgsFromCmd(cmd)
-			if err != nil {
-				return err
-			}
+			return loadContext(cmd)
+		},
+	}
 
-			xcFactory, err := setup.LoadFactory(args)
-			if err != nil {
-				return err
-			}
+	setup.AddRpcArgs(cmd)
+	cmd.PersistentFlags().Uint32("hd-account", 0, "Account to derive from a mnemonic PRIVATE_KEY")
+	cmd.PersistentFlags().Uint32("hd-index", 0, "Address index to derive from a mnemonic PRIVATE_KEY, or from --xpub")
+	cmd.PersistentFlags().String("hd-path", "", "Derivation path overriding the hd_path of the chain, e.g. m/84'/0'/{account}'/0/{index}")
+	cmd.PersistentFlags().Bool("hd-bip39", false, "Derive the key of a mnemonic PRIVATE_KEY with BIP-39 on TON, even if it is also a valid TON mnemonic")
+	cmd.PersistentFlags().Bool("hd-legacy", false, "Derive the key of a mnemonic PRIVATE_KEY as earlier versions did, a secp256k1 key at m/44'/<coin>'/0'/0/0 on every chain")
 
-			chainConfig, err := setup.LoadChain(xcFactory, args.Chain)
-			if err != nil {
-				return err
-			}
+	cmd.AddCommand(CmdAddress())
+	cmd.AddCommand(CmdBalance())
+	cmd.AddCommand(CmdBroadcast())
+	cmd.AddCommand(CmdBuild())
+	cmd.AddCommand(CmdChains())
+	cmd.AddCommand(CmdKey())
+	cmd.AddCommand(CmdSign())
+	cmd.AddCommand(CmdStaking())
+	cmd.AddCommand(CmdTransfer())
+	cmd.AddCommand(CmdTxInfo())
+	cmd.AddCommand(CmdTxInput())
 
-			ctx := setup.CreateContext(xcFactory, chainConfig)
-			logrus.WithFields(logrus.Fields{
-				"rpc": chainConfig.Client.URL,
-				// "network": chainConfig.Network,
-				"chain": chainConfig.Chain,
-			}).Info("chain")
+	if err := cmd.Execute(); err != nil {
+		os.Exit(1)
+	}
+}
 
-			cmd.SetContext(ctx)
-			return nil
-		},
+// Loads the factory and the chain of --chain into the context of the command
+func loadContext(cmd *cobra.Command) error {
+	args, err := setup.RpcArgsFromCmd(cmd)
+	if err != nil {
+		return err
 	}
+	setup.ConfigureLogger(args)
 
-	setup.AddRpcArgs(cmd)
+	xcFactory, err := setup.LoadFactory(args)
+	if err != nil {
+		return err
+	}
 
-	cmd.AddCommand(CmdTxInput())
-	cmd.AddCommand(CmdChains())
+	chainConfig, err := setup.LoadChain(xcFactory, args)
+	if err != nil {
+		return err
+	}
+
+	ctx := setup.CreateContext(xcFactory, chainConfig)
+	logrus.WithFields(logrus.Fields{
+		"rpc": chainConfig.Client.URL,
+		// "network": chainConfig.Network,
+		"chain": chainConfig.Chain,
+	}).Info("chain")
 
-	_ = cmd.Execute()
+	cmd.SetContext(ctx)
+	return nil
 }
 
 func assetConfig(chain *xc.ChainConfig, contractMaybe xc.ContractAddress, decimals int32) types.IAsset {
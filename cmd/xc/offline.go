@@ -0,0 +1,427 @@
+package main
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/openweb3-io/crosschain/blockchain/ton"
+	tonaddress "github.com/openweb3-io/crosschain/blockchain/ton/address"
+	xcbuilder "github.com/openweb3-io/crosschain/builder"
+	xclient "github.com/openweb3-io/crosschain/client"
+	"github.com/openweb3-io/crosschain/cmd/xc/setup"
+	"github.com/openweb3-io/crosschain/factory/blockchains"
+	"github.com/openweb3-io/crosschain/normalize"
+	xc "github.com/openweb3-io/crosschain/types"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// A transfer passed between the build, sign and broadcast commands, so that it can be signed
+// on a machine without network access.
+type OfflineTx struct {
+	Chain xc.NativeAsset `json:"chain"`
+	Args  OfflineArgs    `json:"args"`
+	// The TxInputEnvelope fetched by build
+	Input json.RawMessage `json:"input"`
+	// The unsigned transaction, as built from the args and input
+	Tx json.RawMessage `json:"tx"`
+	// Hex encoded signatures, set by sign
+	Signatures []string `json:"signatures,omitempty"`
+}
+
+type OfflineArgs struct {
+	From xc.Address `json:"from"`
+	To   xc.Address `json:"to"`
+	// In blockchain units
+	Amount    xc.BigInt          `json:"amount"`
+	Contract  xc.ContractAddress `json:"contract,omitempty"`
+	Decimals  int32              `json:"decimals"`
+	Memo      string             `json:"memo,omitempty"`
+	PublicKey string             `json:"public_key,omitempty"`
+}
+
+func CmdBuild() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "build <to> <amount>",
+		Short: "Fetch the inputs for a transfer and write the unsigned transaction to a file, to be signed offline with sign.",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			xcFactory := setup.UnwrapXc(cmd.Context())
+			chain := setup.UnwrapChain(cmd.Context())
+			from, _ := cmd.Flags().GetString("from")
+			publicKeyHex, _ := cmd.Flags().GetString("public-key")
+			contract, _ := cmd.Flags().GetString("contract")
+			memo, _ := cmd.Flags().GetString("memo")
+			output, _ := cmd.Flags().GetString("output")
+
+			if from == "" {
+				return fmt.Errorf("--from is required")
+			}
+			priority, err := priorityFromCmd(cmd)
+			if err != nil {
+				return err
+			}
+			decimals, err := decimalsFromCmd(cmd, chain, contract)
+			if err != nil {
+				return err
+			}
+			amount, err := amountFromStr(args[1], decimals)
+			if err != nil {
+				return err
+			}
+			offlineTx := &OfflineTx{
+				Chain: chain.Chain,
+				Args: OfflineArgs{
+					From:      xc.Address(from),
+					To:        xc.Address(args[0]),
+					Amount:    amount,
+					Contract:  xc.ContractAddress(contract),
+					Decimals:  decimals,
+					Memo:      memo,
+					PublicKey: publicKeyHex,
+				},
+			}
+
+			transferArgs, err := offlineTx.Args.transferArgs(chain)
+			if err != nil {
+				return err
+			}
+			client, err := xcFactory.NewClient(chain)
+			if err != nil {
+				return err
+			}
+			input, err := client.FetchTransferInput(cmd.Context(), transferArgs)
+			if err != nil {
+				return fmt.Errorf("could not fetch transfer input: %v", err)
+			}
+			if err := applyPriority(input, priority); err != nil {
+				return err
+			}
+			offlineTx.Input, err = blockchains.MarshalTxInput(input)
+			if err != nil {
+				return fmt.Errorf("could not serialize input: %v", err)
+			}
+
+			txBuilder, err := xcFactory.NewTxBuilder(chain)
+			if err != nil {
+				return err
+			}
+			tx, err := txBuilder.NewTransfer(transferArgs, input)
+			if err != nil {
+				return fmt.Errorf("could not build transfer: %v", err)
+			}
+			offlineTx.Tx, err = blockchains.MarshalUnsignedTx(tx)
+			if err != nil {
+				return fmt.Errorf("could not serialize transaction: %v", err)
+			}
+			return writeOfflineTx(output, offlineTx)
+		},
+	}
+	cmd.Flags().String("from", "", "Address to send from")
+	cmd.Flags().String("public-key", "", "Hex public key of the sender, required on chains that need it to build a transaction")
+	cmd.Flags().String("contract", "", "Optional contract of token asset")
+	cmd.Flags().Int32("decimals", 0, "Decimals of the asset, required for tokens")
+	cmd.Flags().String("memo", "", "Optional memo to attach")
+	cmd.Flags().String("priority", "", "Optional fee priority, e.g. low, market, aggressive, very-aggressive or a multiplier like 1.5")
+	cmd.Flags().StringP("output", "o", "", "File to write to, by default stdout")
+	return cmd
+}
+
+func CmdSign() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sign <file>",
+		Short: "Sign a transaction written by build with PRIVATE_KEY.  Does not access the network.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			xcFactory := setup.UnwrapXc(cmd.Context())
+			chain := setup.UnwrapChain(cmd.Context())
+			output, _ := cmd.Flags().GetString("output")
+			force, _ := cmd.Flags().GetBool("force")
+			if output == "" {
+				output = args[0]
+			}
+
+			offlineTx, err := readOfflineTx(args[0], chain)
+			if err != nil {
+				return err
+			}
+			txSigner, from, _, err := loadSigner(cmd, xcFactory, chain)
+			if err != nil {
+				return err
+			}
+			if from != offlineTx.Args.From {
+				return fmt.Errorf("PRIVATE_KEY is for %s, but the transaction is from %s", from, offlineTx.Args.From)
+			}
+			maxFee, err := maxFeeFromCmd(cmd, chain)
+			if err != nil {
+				return err
+			}
+			tx, err := blockchains.UnmarshalUnsignedTx(offlineTx.Tx)
+			if err != nil {
+				return fmt.Errorf("could not read transaction: %v", err)
+			}
+			if err := reviewTx(chain, offlineTx, tx, maxFee); err != nil {
+				if !force {
+					return fmt.Errorf("refusing to sign: %v, sign anyway with --force", err)
+				}
+				logrus.WithError(err).Warn("signing even though the transaction does not match its args")
+			}
+
+			signatures, err := txSigner.SignTx(tx)
+			if err != nil {
+				return fmt.Errorf("could not sign: %v", err)
+			}
+			offlineTx.Signatures = make([]string, len(signatures))
+			for i, sig := range signatures {
+				offlineTx.Signatures[i] = hex.EncodeToString(sig)
+			}
+			return writeOfflineTx(output, offlineTx)
+		},
+	}
+	cmd.Flags().StringP("output", "o", "", "File to write to, by default the file that is signed")
+	cmd.Flags().String("max-fee", "", "The most the transaction may pay in fees, in the native asset, e.g. 0.01")
+	cmd.Flags().Bool("force", false, "Sign even if the transaction cannot be decoded, does not send what its args say or pays more than --max-fee")
+	return cmd
+}
+
+func CmdBroadcast() *cobra.Command {
+	return &cobra.Command{
+		Use:   "broadcast <file>",
+		Short: "Broadcast a transaction signed by sign.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			xcFactory := setup.UnwrapXc(cmd.Context())
+			chain := setup.UnwrapChain(cmd.Context())
+
+			offlineTx, err := readOfflineTx(args[0], chain)
+			if err != nil {
+				return err
+			}
+			if len(offlineTx.Signatures) == 0 {
+				return fmt.Errorf("%s is not signed", args[0])
+			}
+			tx, err := blockchains.UnmarshalUnsignedTx(offlineTx.Tx)
+			if err != nil {
+				return fmt.Errorf("could not read transaction: %v", err)
+			}
+			signatures := make([]xc.TxSignature, len(offlineTx.Signatures))
+			for i, sigHex := range offlineTx.Signatures {
+				signatures[i], err = hex.DecodeString(sigHex)
+				if err != nil {
+					return fmt.Errorf("invalid signature: %v", err)
+				}
+			}
+			if err := tx.AddSignatures(signatures...); err != nil {
+				return fmt.Errorf("could not add signatures: %v", err)
+			}
+
+			client, err := xcFactory.NewClient(chain)
+			if err != nil {
+				return err
+			}
+			if err := client.BroadcastTx(cmd.Context(), tx); err != nil {
+				return fmt.Errorf("could not broadcast: %v", err)
+			}
+			logrus.Info("submitted tx, check its status with tx-info")
+			printJson(map[string]any{"hash": tx.Hash()})
+			return nil
+		},
+	}
+}
+
+func (args *OfflineArgs) transferArgs(chain *xc.ChainConfig) (*xcbuilder.TransferArgs, error) {
+	options := []xcbuilder.BuilderOption{}
+	if args.PublicKey != "" {
+		publicKey, err := hex.DecodeString(args.PublicKey)
+		if err != nil {
+			return nil, fmt.Errorf("invalid public key: %v", err)
+		}
+		options = append(options, xcbuilder.WithPublicKey(publicKey))
+	}
+	if args.Memo != "" {
+		options = append(options, xcbuilder.WithMemo(args.Memo))
+	}
+	if args.Contract != "" {
+		options = append(options, xcbuilder.WithAsset(assetConfig(chain, args.Contract, args.Decimals)))
+	}
+	return xcbuilder.NewTransferArgs(args.From, args.To, args.Amount, options...)
+}
+
+// The --max-fee flag in blockchain units, nil if not set
+func maxFeeFromCmd(cmd *cobra.Command, chain *xc.ChainConfig) (*xc.BigInt, error) {
+	maxFeeStr, _ := cmd.Flags().GetString("max-fee")
+	if maxFeeStr == "" {
+		return nil, nil
+	}
+	if chain.Decimals == 0 {
+		return nil, fmt.Errorf("decimals of %s are not configured", chain.Chain)
+	}
+	maxFee, err := amountFromStr(maxFeeStr, chain.Decimals)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --max-fee: %v", err)
+	}
+	return &maxFee, nil
+}
+
+// Logs what the transaction does, as decoded from the transaction itself rather than the args, and
+// checks that it sends what the args say and pays at most maxFee, as the transaction in the file
+// could have been changed after it was built.
+func reviewTx(chain *xc.ChainConfig, offlineTx *OfflineTx, tx xc.Tx, maxFee *xc.BigInt) error {
+	logrus.WithFields(logrus.Fields{
+		"from":     offlineTx.Args.From,
+		"to":       offlineTx.Args.To,
+		"amount":   offlineTx.Args.Amount.ToHuman(offlineTx.Args.Decimals).String(),
+		"contract": offlineTx.Args.Contract,
+	}).Info("signing transfer")
+
+	decoder, err := blockchains.NewTxDecoder(chain)
+	if err != nil {
+		return fmt.Errorf("transaction cannot be decoded for review: %v", err)
+	}
+	info, err := decoder.DecodeTx(tx)
+	if err != nil {
+		return fmt.Errorf("transaction cannot be decoded for review: %v", err)
+	}
+	for _, transfer := range info.Transfers {
+		for _, to := range transfer.To {
+			logrus.WithFields(logrus.Fields{
+				"to":      to.Address,
+				"asset":   to.Asset,
+				"balance": to.Balance.String(),
+			}).Info("decoded transfer")
+		}
+	}
+	for _, fee := range info.Fees {
+		logrus.WithFields(logrus.Fields{
+			"asset":   fee.Asset,
+			"balance": fee.Balance.String(),
+		}).Info("decoded fee")
+	}
+	contract, err := offlineTx.decodedContract(chain)
+	if err != nil {
+		return err
+	}
+	if err := offlineTx.Args.checkTransfers(chain, contract, info); err != nil {
+		return err
+	}
+	return checkFees(chain, info, maxFee)
+}
+
+// The contract that the decoder reports for the asset of the args.  TON jetton transfers are decoded as
+// transfers of the jetton wallet of the sender, which is looked up by build.
+func (offlineTx *OfflineTx) decodedContract(chain *xc.ChainConfig) (xc.ContractAddress, error) {
+	if offlineTx.Args.Contract == "" {
+		return xc.ContractAddress(chain.Chain), nil
+	}
+	if chain.Chain.Blockchain() != xc.BlockchainTon {
+		return offlineTx.Args.Contract, nil
+	}
+	input, err := blockchains.UnmarshalTxInput(offlineTx.Input)
+	if err != nil {
+		return "", fmt.Errorf("could not read input: %v", err)
+	}
+	tonInput, ok := input.(*ton.TxInput)
+	if !ok || tonInput.TokenWallet == "" {
+		return "", errors.New("input has no jetton wallet")
+	}
+	return xc.ContractAddress(tonInput.TokenWallet), nil
+}
+
+// Checks that the decoded transaction sends exactly the amount of the args to the recipient of the
+// args, and nothing to anyone else but change back to the sender.
+func (args *OfflineArgs) checkTransfers(chain *xc.ChainConfig, contract xc.ContractAddress, info *xclient.TxInfo) error {
+	recipient := addressKey(chain, string(args.To))
+	asset := addressKey(chain, string(contract))
+	sent := new(big.Int)
+	for _, transfer := range info.Transfers {
+		senders := map[xclient.AddressName]bool{}
+		for _, from := range transfer.From {
+			senders[from.Address] = true
+		}
+		for _, to := range transfer.To {
+			if senders[to.Address] {
+				continue
+			}
+			address := addressFromName(chain, to.Address)
+			if addressKey(chain, address) != recipient {
+				return fmt.Errorf("transaction sends to %s, not %s", address, args.To)
+			}
+			if addressKey(chain, string(to.Contract)) != asset {
+				return fmt.Errorf("transaction sends %s, not %s", to.Contract, contract)
+			}
+			sent.Add(sent, to.Balance.Int())
+		}
+	}
+	if sent.Cmp(args.Amount.Int()) != 0 {
+		return fmt.Errorf("transaction sends %s to %s, not %s", sent.String(), args.To, args.Amount.String())
+	}
+	return nil
+}
+
+// Checks that the decoded fees are all paid in the native asset, and add up to at most maxFee
+func checkFees(chain *xc.ChainConfig, info *xclient.TxInfo, maxFee *xc.BigInt) error {
+	if maxFee == nil {
+		return errors.New("the fee is not bounded, set --max-fee")
+	}
+	native := xclient.NewAssetName(chain.Chain, "")
+	total := new(big.Int)
+	for _, fee := range info.Fees {
+		if fee.Asset != native {
+			return fmt.Errorf("transaction pays a fee in %s", fee.Contract)
+		}
+		total.Add(total, fee.Balance.Int())
+	}
+	if total.Cmp(maxFee.Int()) > 0 {
+		totalFee := xc.BigInt(*total)
+		return fmt.Errorf("transaction pays a fee of %s, more than %s",
+			totalFee.ToHuman(chain.Decimals).String(), maxFee.ToHuman(chain.Decimals).String())
+	}
+	return nil
+}
+
+// Addresses are compared in a canonical form, as e.g. TON addresses have flags that don't change the account
+func addressKey(chain *xc.ChainConfig, address string) string {
+	if chain.Chain.Blockchain() == xc.BlockchainTon {
+		if addr, err := tonaddress.ParseAddress(xc.Address(address), ""); err == nil {
+			return addr.Bounce(true).Testnet(false).String()
+		}
+	}
+	return normalize.Normalize(address, chain.Chain)
+}
+
+func addressFromName(chain *xc.ChainConfig, name xclient.AddressName) string {
+	return strings.TrimPrefix(string(name), filepath.Join("chains", string(chain.Chain), "addresses")+"/")
+}
+
+func readOfflineTx(path string, chain *xc.ChainConfig) (*OfflineTx, error) {
+	bz, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	offlineTx := &OfflineTx{}
+	if err := json.Unmarshal(bz, offlineTx); err != nil {
+		return nil, fmt.Errorf("invalid transaction file %s: %v", path, err)
+	}
+	if offlineTx.Chain != chain.Chain {
+		return nil, fmt.Errorf("transaction is for %s, not %s", offlineTx.Chain, chain.Chain)
+	}
+	return offlineTx, nil
+}
+
+func writeOfflineTx(path string, offlineTx *OfflineTx) error {
+	bz, err := json.MarshalIndent(offlineTx, "", "  ")
+	if err != nil {
+		return err
+	}
+	if path == "" {
+		fmt.Println(string(bz))
+		return nil
+	}
+	return os.WriteFile(path, append(bz, '\n'), 0o600)
+}
@@ -3,10 +3,14 @@ package setup
 import (
 	"context"
 	"fmt"
+	"os"
 	"strings"
 
+	"github.com/openweb3-io/crosschain/config"
+	"github.com/openweb3-io/crosschain/config/constants"
 	"github.com/openweb3-io/crosschain/factory"
 	"github.com/openweb3-io/crosschain/types"
+	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 )
 
@@ -38,63 +42,136 @@ func UnwrapChain(ctx context.Context) *types.ChainConfig {
 func CreateContext(xcFactory *factory.Factory, chain *types.ChainConfig) context.Context {
 	ctx := context.Background()
 	ctx = WrapXc(ctx, xcFactory)
-	// ctx = WrapChain(ctx, chain)
+	ctx = WrapChain(ctx, chain)
 	return ctx
 }
 
+// The section of the config file chains are loaded from
+const ConfigSection = "crosschain"
+
+// The chains of the config file, e.g.
+//
+//	crosschain:
+//	  chains:
+//	    - chain: ETH
+//	      blockchain: evm
+//	      chain_id: 1
+//	      decimals: 18
+//	      client:
+//	        blockchain: evm
+//	        url: https://...
+type Config struct {
+	Chains []*types.ChainConfig `yaml:"chains"`
+}
+
 type RpcArgs struct {
-	Chain string
-	Rpc   string
+	Chain      string
+	Rpc        string
+	Provider   string
+	ConfigPath string
+	NotMainnet bool
+	Verbosity  int
 }
 
 func AddRpcArgs(cmd *cobra.Command) {
 	cmd.PersistentFlags().String("rpc", "", "RPC url to use. Optional.")
 	cmd.PersistentFlags().String("chain", "", "Chain to use. Required.")
-
+	cmd.PersistentFlags().String("config", "", "Path to config.yaml configuration file.")
+	cmd.PersistentFlags().Bool("not-mainnet", false, "Do not use mainnets, instead use a test or dev network.")
+	cmd.PersistentFlags().String("provider", "", "Provider to use for chain client.  Only valid for BTC chains.")
+	cmd.PersistentFlags().CountP("verbose", "v", "Set verbosity.")
 }
 
 func RpcArgsFromCmd(cmd *cobra.Command) (*RpcArgs, error) {
 	chain, _ := cmd.Flags().GetString("chain")
 	rpc, _ := cmd.Flags().GetString("rpc")
+	provider, _ := cmd.Flags().GetString("provider")
+	configPath, _ := cmd.Flags().GetString("config")
+	notMainnet, _ := cmd.Flags().GetBool("not-mainnet")
+	verbosity, _ := cmd.Flags().GetCount("verbose")
 	if chain == "" {
 		return nil, fmt.Errorf("--chain required")
 	}
 
 	return &RpcArgs{
-		Chain: chain,
-		Rpc:   rpc,
+		Chain:      chain,
+		Rpc:        rpc,
+		Provider:   provider,
+		ConfigPath: configPath,
+		NotMainnet: notMainnet,
+		Verbosity:  verbosity,
 	}, nil
 }
 
+func ConfigureLogger(args *RpcArgs) {
+	switch {
+	case args.Verbosity >= 2:
+		logrus.SetLevel(logrus.TraceLevel)
+	case args.Verbosity == 1:
+		logrus.SetLevel(logrus.DebugLevel)
+	default:
+		logrus.SetLevel(logrus.InfoLevel)
+	}
+}
+
+// LoadFactory loads the chains of the config file, if there is one, into a new factory
 func LoadFactory(rcpArgs *RpcArgs) (*factory.Factory, error) {
-	// if rcpArgs.ConfigPath != "" {
-	// 	// currently only way to set config file is via env
-	// 	_ = os.Setenv(constants.ConfigEnv, rcpArgs.ConfigPath)
-	// }
+	if rcpArgs.ConfigPath != "" {
+		// currently only way to set config file is via env
+		_ = os.Setenv(constants.ConfigEnv, rcpArgs.ConfigPath)
+	}
 	xcFactory := factory.NewDefaultFactory()
-	/*
-		if rcpArgs.NotMainnet {
-			xcFactory = factory.NewNotMainnetsFactory(&factory.FactoryOptions{})
+
+	cfg := &Config{}
+	if err := config.RequireConfig(ConfigSection, cfg, &Config{}); err != nil {
+		if rcpArgs.ConfigPath != "" {
+			return nil, err
 		}
-	*/
-
-	/*
-		if rcpArgs.Rpc != "" {
-			if existing, ok := rcpArgs.Overrides[strings.ToLower(rcpArgs.Chain)]; ok {
-				existing.Rpc = rcpArgs.Rpc
-			} else {
-				rcpArgs.Overrides[strings.ToLower(rcpArgs.Chain)] = &ChainOverride{
-					Rpc: rcpArgs.Rpc,
-				}
-			}
+		// no config file is needed when the rpc is given on the command line
+		logrus.WithError(err).Debug("no config loaded")
+	}
+	for _, chain := range cfg.Chains {
+		if _, err := xcFactory.PutAssetConfig(chain); err != nil {
+			return nil, err
 		}
-	*/
-
-	// OverwriteCrosschainSettings(rcpArgs.Overrides, xcFactory)
+	}
 	return xcFactory, nil
 }
 
-func LoadChain(xcFactory *factory.Factory, chain string) (*types.ChainConfig, error) {
+// LoadChain finds the config of the chain, then applies the overrides of the command line
+func LoadChain(xcFactory *factory.Factory, args *RpcArgs) (*types.ChainConfig, error) {
+	chainCfg, err := loadChain(xcFactory, args.Chain)
+	if err != nil {
+		return nil, err
+	}
+	// make a copy so overrides do not persist in the factory
+	copied := *chainCfg
+	chainCfg = &copied
+	if chainCfg.Blockchain == "" {
+		chainCfg.Blockchain = chainCfg.Chain.Blockchain()
+	}
+	client := types.ClientConfig{}
+	if chainCfg.Client != nil {
+		client = *chainCfg.Client
+	}
+	if client.Blockchain == "" {
+		client.Blockchain = chainCfg.Blockchain
+	}
+	if args.Rpc != "" {
+		client.URL = args.Rpc
+	}
+	if args.Provider != "" {
+		client.Provider = args.Provider
+	}
+	if args.NotMainnet {
+		chainCfg.Network = "testnet"
+		client.Network = "testnet"
+	}
+	chainCfg.Client = &client
+	return chainCfg, nil
+}
+
+func loadChain(xcFactory *factory.Factory, chain string) (*types.ChainConfig, error) {
 	var nativeAsset types.NativeAsset
 	for _, chainOption := range types.NativeAssetList {
 		if strings.EqualFold(string(chainOption), chain) {
@@ -107,7 +184,11 @@ func LoadChain(xcFactory *factory.Factory, chain string) (*types.ChainConfig, er
 
 	chainConfig, err := xcFactory.GetAssetConfig("", nativeAsset)
 	if err != nil {
-		return nil, err
+		// not in the config file, which is fine for chains without settings beyond the rpc
+		return &types.ChainConfig{
+			Chain:      nativeAsset,
+			Blockchain: nativeAsset.Blockchain(),
+		}, nil
 	}
 	chainCfg := chainConfig.(*types.ChainConfig)
 	return chainCfg, nil
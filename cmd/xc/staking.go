@@ -0,0 +1,195 @@
+package main
+
+import (
+	"fmt"
+
+	xcbuilder "github.com/openweb3-io/crosschain/builder"
+	xclient "github.com/openweb3-io/crosschain/client"
+	"github.com/openweb3-io/crosschain/cmd/xc/setup"
+	xc "github.com/openweb3-io/crosschain/types"
+	"github.com/spf13/cobra"
+)
+
+type stakingOperation string
+
+const (
+	stakeOperation    stakingOperation = "stake"
+	unstakeOperation  stakingOperation = "unstake"
+	withdrawOperation stakingOperation = "withdraw"
+)
+
+func CmdStaking() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "staking",
+		Short: "Staking commands",
+	}
+	cmd.PersistentFlags().String("validator", "", "Validator address, required on some chains")
+	cmd.PersistentFlags().String("account", "", "Stake account, used on some chains")
+
+	cmd.AddCommand(cmdStakingTx(stakeOperation, "Stake an asset."))
+	cmd.AddCommand(cmdStakingTx(unstakeOperation, "Unstake an asset."))
+	cmd.AddCommand(cmdStakingTx(withdrawOperation, "Withdraw an unstaked asset, on chains that do not return it on unstaking."))
+	cmd.AddCommand(CmdStakedBalance())
+	return cmd
+}
+
+func cmdStakingTx(operation stakingOperation, short string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   string(operation),
+		Short: short,
+		Args:  cobra.ExactArgs(0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			xcFactory := setup.UnwrapXc(cmd.Context())
+			chain := setup.UnwrapChain(cmd.Context())
+			amountStr, _ := cmd.Flags().GetString("amount")
+			validator, _ := cmd.Flags().GetString("validator")
+			account, _ := cmd.Flags().GetString("account")
+
+			if amountStr == "" {
+				return fmt.Errorf("--amount is required")
+			}
+			priority, err := priorityFromCmd(cmd)
+			if err != nil {
+				return err
+			}
+			decimals, err := decimalsFromCmd(cmd, chain, "")
+			if err != nil {
+				return err
+			}
+			amount, err := amountFromStr(amountStr, decimals)
+			if err != nil {
+				return err
+			}
+			txSigner, from, publicKey, err := loadSigner(cmd, xcFactory, chain)
+			if err != nil {
+				return err
+			}
+
+			options := []xcbuilder.BuilderOption{xcbuilder.WithPublicKey(publicKey)}
+			if validator != "" {
+				options = append(options, xcbuilder.WithValidator(validator))
+			}
+			if account != "" {
+				options = append(options, xcbuilder.WithStakeAccount(account))
+			}
+			stakeArgs, err := xcbuilder.NewStakeArgs(chain.Chain, from, amount, options...)
+			if err != nil {
+				return err
+			}
+
+			client, err := xcFactory.NewClient(chain)
+			if err != nil {
+				return err
+			}
+			stakingClient, ok := client.(xclient.StakingClient)
+			if !ok {
+				return fmt.Errorf("staking is not supported for %s", chain.Chain)
+			}
+			txBuilder, err := xcFactory.NewTxBuilder(chain)
+			if err != nil {
+				return err
+			}
+			stakingBuilder, ok := txBuilder.(xcbuilder.Staking)
+			if !ok {
+				return fmt.Errorf("staking is not supported for %s", chain.Chain)
+			}
+
+			var tx xc.Tx
+			switch operation {
+			case stakeOperation:
+				input, err := stakingClient.FetchStakingInput(cmd.Context(), stakeArgs)
+				if err != nil {
+					return fmt.Errorf("could not fetch staking input: %v", err)
+				}
+				if err := applyPriority(input, priority); err != nil {
+					return err
+				}
+				tx, err = stakingBuilder.Stake(stakeArgs, input)
+				if err != nil {
+					return fmt.Errorf("could not build stake: %v", err)
+				}
+			case unstakeOperation:
+				input, err := stakingClient.FetchUnstakingInput(cmd.Context(), stakeArgs)
+				if err != nil {
+					return fmt.Errorf("could not fetch unstaking input: %v", err)
+				}
+				if err := applyPriority(input, priority); err != nil {
+					return err
+				}
+				tx, err = stakingBuilder.Unstake(stakeArgs, input)
+				if err != nil {
+					return fmt.Errorf("could not build unstake: %v", err)
+				}
+			case withdrawOperation:
+				input, err := stakingClient.FetchWithdrawInput(cmd.Context(), stakeArgs)
+				if err != nil {
+					return fmt.Errorf("could not fetch withdraw input: %v", err)
+				}
+				if err := applyPriority(input, priority); err != nil {
+					return err
+				}
+				tx, err = stakingBuilder.Withdraw(stakeArgs, input)
+				if err != nil {
+					return fmt.Errorf("could not build withdraw: %v", err)
+				}
+			}
+			return signAndBroadcast(cmd.Context(), client, txSigner, tx)
+		},
+	}
+	cmd.Flags().String("amount", "", "Decimal amount to "+string(operation))
+	cmd.Flags().Int32("decimals", 0, "Decimals of the asset, if not configured for the chain")
+	cmd.Flags().String("priority", "", "Optional fee priority, e.g. low, market, aggressive, very-aggressive or a multiplier like 1.5")
+	return cmd
+}
+
+func CmdStakedBalance() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "balance",
+		Short: "Check the staked balances of an address, by default the address of PRIVATE_KEY.",
+		Args:  cobra.ExactArgs(0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			xcFactory := setup.UnwrapXc(cmd.Context())
+			chain := setup.UnwrapChain(cmd.Context())
+			address, _ := cmd.Flags().GetString("address")
+			validator, _ := cmd.Flags().GetString("validator")
+			account, _ := cmd.Flags().GetString("account")
+
+			from := xc.Address(address)
+			if from == "" {
+				var err error
+				_, from, _, err = loadSigner(cmd, xcFactory, chain)
+				if err != nil {
+					return err
+				}
+			}
+			options := []xclient.StakedBalanceOption{}
+			if validator != "" {
+				options = append(options, xclient.StakeBalanceOptionValidator(validator))
+			}
+			if account != "" {
+				options = append(options, xclient.StakeBalanceOptionAccount(account))
+			}
+			balanceArgs, err := xclient.NewStakeBalanceArgs(from, options...)
+			if err != nil {
+				return err
+			}
+
+			client, err := xcFactory.NewClient(chain)
+			if err != nil {
+				return err
+			}
+			stakingClient, ok := client.(xclient.StakingClient)
+			if !ok {
+				return fmt.Errorf("staking is not supported for %s", chain.Chain)
+			}
+			balances, err := stakingClient.FetchStakeBalance(cmd.Context(), balanceArgs)
+			if err != nil {
+				return fmt.Errorf("could not fetch staked balances: %v", err)
+			}
+			printJson(balances)
+			return nil
+		},
+	}
+	cmd.Flags().String("address", "", "Address to check instead of the address of PRIVATE_KEY")
+	return cmd
+}
@@ -13,6 +13,7 @@ import (
 	"cloud.google.com/go/secretmanager/apiv1/secretmanagerpb"
 	vault "github.com/hashicorp/vault/api"
 	"github.com/openweb3-io/crosschain/config/constants"
+	"github.com/openweb3-io/crosschain/signer/keystore"
 	"github.com/spf13/viper"
 	"google.golang.org/api/iterator"
 	"gopkg.in/yaml.v3"
@@ -231,6 +232,24 @@ func GetSecret(uri string) (string, error) {
 			}
 		}
 		return "", fmt.Errorf("could not find a gsm secret by name %s", name)
+	case Keystore:
+		// the passphrase is read from an environment variable, KEYSTORE_PASSPHRASE by default
+		if len(args) > 2 {
+			return "", errors.New("keystore secret has up to 2 comma separated arguments (path,passphrase_env)")
+		}
+		path := args[0]
+		if len(path) > 1 && path[0] == '~' {
+			path = strings.Replace(path, "~", os.Getenv("HOME"), 1)
+		}
+		passphraseEnv := keystore.PassphraseEnv
+		if len(args) == 2 {
+			passphraseEnv = args[1]
+		}
+		passphrase, ok := os.LookupEnv(passphraseEnv)
+		if !ok {
+			return "", fmt.Errorf("must set env %s to the passphrase of the keystore", passphraseEnv)
+		}
+		return keystore.DecryptFile(path, passphrase)
 	case Raw:
 		return strings.Join(splits[1:], ":"), nil
 	}
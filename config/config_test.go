@@ -5,11 +5,14 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
 	vault "github.com/hashicorp/vault/api"
 	"github.com/openweb3-io/crosschain/config/constants"
+	"github.com/openweb3-io/crosschain/signer/keystore"
+	xc "github.com/openweb3-io/crosschain/types"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 )
@@ -225,6 +228,28 @@ func (s *CrosschainTestSuite) TestGetSecretFileTrimmed() {
 	require.Equal("MY SECRET", sec)
 }
 
+func (s *CrosschainTestSuite) TestGetSecretKeystore() {
+	require := s.Require()
+	secret := "289c2857d4598e37fb9647507e47a309d6133539bf21a8b9cb6df88fd5232032"
+	file, err := keystore.EncryptSecret(secret, xc.K256Keccak, "passphrase", keystore.WithScrypt(keystore.LightScryptN, keystore.LightScryptP))
+	require.NoError(err)
+	path := filepath.Join(s.T().TempDir(), "key.json")
+	require.NoError(file.Save(path))
+
+	s.T().Setenv(keystore.PassphraseEnv, "passphrase")
+	sec, err := GetSecret("keystore:" + path)
+	require.NoError(err)
+	require.Equal(secret, sec)
+
+	s.T().Setenv("XCTEST_PASSPHRASE", "wrong")
+	_, err = GetSecret("keystore:" + path + ",XCTEST_PASSPHRASE")
+	require.ErrorContains(err, "could not decrypt")
+
+	_, err = GetSecret("keystore:" + path + ",XCTEST_PASSPHRASE_MISSING")
+	require.ErrorContains(err, "must set env XCTEST_PASSPHRASE_MISSING")
+	require.True(HasTypePrefix("keystore:" + path))
+}
+
 type TestHobby struct {
 	Type    string   `yaml:"type,omitempty"`
 	Actions []string `yaml:"actions"`
@@ -14,6 +14,7 @@ var Vault SecretType = "vault"
 var Raw SecretType = "raw"
 var File SecretType = "file"
 var GoogleSecretManager SecretType = "gsm"
+var Keystore SecretType = "keystore"
 
 func (s Secret) Load() (string, error) {
 	return GetSecret(string(s))
@@ -29,7 +30,7 @@ func NewRawSecret(secret string) Secret {
 
 func HasTypePrefix(secretRef string) bool {
 	switch SecretType(strings.Split(secretRef, ":")[0]) {
-	case Env, Vault, Raw, File, GoogleSecretManager:
+	case Env, Vault, Raw, File, GoogleSecretManager, Keystore:
 		return true
 	}
 	return false
@@ -128,8 +128,8 @@ func NewAddressBuilder(cfg *xc.ChainConfig) (xc.AddressBuilder, error) {
 	return nil, errors.New("no address builder defined for: " + string(cfg.ID()))
 }
 
-func NewSigner(cfg *xc.ChainConfig, secret string) (*signer.Signer, error) {
-	return signer.New(cfg.Blockchain, secret, cfg)
+func NewSigner(cfg *xc.ChainConfig, secret string, options ...signer.Option) (*signer.Signer, error) {
+	return signer.New(cfg.Blockchain, secret, cfg, options...)
 }
 
 func NewTxBuilder(cfg *xc.ChainConfig) (xcbuilder.TxBuilder, error) {
@@ -159,3 +159,23 @@ func NewTxBuilder(cfg *xc.ChainConfig) (xcbuilder.TxBuilder, error) {
 	}
 	return nil, errors.New("no tx-builder defined for: " + string(cfg.ID()))
 }
+
+func NewTxDecoder(cfg *xc.ChainConfig) (xc_client.TxDecoder, error) {
+	switch xc.Blockchain(cfg.Blockchain) {
+	case xc.BlockchainEVM, xc.BlockchainEVMLegacy:
+		return evmbuilder.NewTxDecoder(cfg)
+	case xc.BlockchainCosmos, xc.BlockchainCosmosEvmos:
+		return cosmosbuilder.NewTxDecoder(cfg)
+	case xc.BlockchainSolana:
+		return solanabuilder.NewTxDecoder(cfg)
+	case xc.BlockchainBtc, xc.BlockchainBtcLegacy:
+		return btc.NewTxDecoder(cfg)
+	case xc.BlockchainBtcCash:
+		return btc_cash.NewTxDecoder(cfg)
+	case xc.BlockchainTron:
+		return tron.NewTxDecoder(cfg)
+	case xc.BlockchainTon:
+		return ton.NewTxDecoder(cfg)
+	}
+	return nil, errors.New("no tx-decoder defined for: " + string(cfg.ID()))
+}
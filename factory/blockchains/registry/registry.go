@@ -8,6 +8,7 @@ import (
 
 var supportedBaseInputTx = []xc.TxInput{}
 var supportedVariantTx = []xc.TxVariantInput{}
+var supportedUnsignedTx = map[xc.Blockchain]xc.Tx{}
 
 func RegisterTxBaseInput(txInput xc.TxInput) {
 	for _, existing := range supportedBaseInputTx {
@@ -40,3 +41,16 @@ func RegisterTxVariantInput(variant xc.TxVariantInput) {
 func GetSupportedTxVariants() []xc.TxVariantInput {
 	return supportedVariantTx
 }
+
+// RegisterUnsignedTx registers the Tx of a blockchain, which must be able to encode itself to JSON and back
+// before it is signed.  Other kinds of transactions of a blockchain are registered under their own type.
+func RegisterUnsignedTx(blockchain xc.Blockchain, tx xc.Tx) {
+	if existing, ok := supportedUnsignedTx[blockchain]; ok {
+		panic(fmt.Sprintf("unsigned tx %T blockchain %s duplicates %T", tx, blockchain, existing))
+	}
+	supportedUnsignedTx[blockchain] = tx
+}
+
+func GetSupportedUnsignedTxs() map[xc.Blockchain]xc.Tx {
+	return supportedUnsignedTx
+}
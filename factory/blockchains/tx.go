@@ -0,0 +1,63 @@
+package blockchains
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/openweb3-io/crosschain/factory/blockchains/registry"
+	xc "github.com/openweb3-io/crosschain/types"
+)
+
+// MarshalUnsignedTx encodes a transaction that has been built but not signed in a TxEnvelope, so
+// it can be moved to a signer on another host.
+func MarshalUnsignedTx(tx xc.Tx) ([]byte, error) {
+	for blockchain, registered := range registry.GetSupportedUnsignedTxs() {
+		if reflect.TypeOf(registered) != reflect.TypeOf(tx) {
+			continue
+		}
+		txBz, err := json.Marshal(tx)
+		if err != nil {
+			return nil, err
+		}
+		env := xc.NewTxEnvelope(blockchain)
+		env.Tx = txBz
+		return json.Marshal(env)
+	}
+	return nil, fmt.Errorf("no unsigned tx mapped for %T", tx)
+}
+
+func NewUnsignedTx(blockchain xc.Blockchain) (xc.Tx, error) {
+	supported := registry.GetSupportedUnsignedTxs()
+	// aliases for fork chains
+	switch blockchain {
+	case xc.BlockchainBtcLegacy, xc.BlockchainBtcCash:
+		blockchain = xc.BlockchainBtc
+	case xc.BlockchainCosmosEvmos:
+		blockchain = xc.BlockchainCosmos
+	case xc.BlockchainEVMLegacy:
+		blockchain = xc.BlockchainEVM
+	}
+	if tx, ok := supported[blockchain]; ok {
+		return makeCopy(tx), nil
+	}
+	return nil, fmt.Errorf("no unsigned tx mapped for driver %s", blockchain)
+}
+
+// UnmarshalUnsignedTx decodes a transaction encoded by MarshalUnsignedTx, which can then be signed.
+func UnmarshalUnsignedTx(data []byte) (xc.Tx, error) {
+	var env xc.TxEnvelope
+	err := json.Unmarshal(data, &env)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := NewUnsignedTx(env.Type)
+	if err != nil {
+		return nil, err
+	}
+	err = json.Unmarshal(env.Tx, tx)
+	if err != nil {
+		return nil, err
+	}
+	return tx, nil
+}
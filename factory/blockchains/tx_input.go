@@ -11,22 +11,19 @@ import (
 
 const SerializedInputTypeKey = "type"
 
+// MarshalTxInput encodes a tx-input in a TxInputEnvelope, so it can be decoded with UnmarshalTxInput,
+// e.g. on the host that builds the transaction.
 func MarshalTxInput(methodInput xc.TxInput) ([]byte, error) {
-	data := map[string]interface{}{}
 	methodBz, err := json.Marshal(methodInput)
 	if err != nil {
 		return nil, err
 	}
-	_ = json.Unmarshal(methodBz, &data)
-	// force union with method type envelope
+	env := xc.NewTxInputEnvelope(methodInput.GetBlockchain())
 	if variant, ok := methodInput.(xc.TxVariantInput); ok {
-		data[SerializedInputTypeKey] = variant.GetVariant()
-	} else {
-		data[SerializedInputTypeKey] = methodInput.GetBlockchain()
+		env.Type = xc.Blockchain(variant.GetVariant())
 	}
-
-	bz, _ := json.Marshal(data)
-	return bz, nil
+	env.TxInput = methodBz
+	return json.Marshal(env)
 }
 
 // Create a copy of a interface object, to avoid modifying the original
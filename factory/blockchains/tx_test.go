@@ -0,0 +1,511 @@
+package blockchains_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/fbsobreira/gotron-sdk/pkg/proto/core"
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/programs/stake"
+	"github.com/gagliardetto/solana-go/programs/system"
+	"github.com/gagliardetto/solana-go/programs/token"
+	btcinput "github.com/openweb3-io/crosschain/blockchain/btc/tx_input"
+	cosmostx "github.com/openweb3-io/crosschain/blockchain/cosmos/tx"
+	cosmosinput "github.com/openweb3-io/crosschain/blockchain/cosmos/tx_input"
+	"github.com/openweb3-io/crosschain/blockchain/evm/abi/forwarder"
+	evmtx "github.com/openweb3-io/crosschain/blockchain/evm/tx"
+	evminput "github.com/openweb3-io/crosschain/blockchain/evm/tx_input"
+	solanatx "github.com/openweb3-io/crosschain/blockchain/solana/tx"
+	solanainput "github.com/openweb3-io/crosschain/blockchain/solana/tx_input"
+	"github.com/openweb3-io/crosschain/blockchain/ton"
+	tontx "github.com/openweb3-io/crosschain/blockchain/ton/tx"
+	tonwallet "github.com/openweb3-io/crosschain/blockchain/ton/wallet"
+	"github.com/openweb3-io/crosschain/blockchain/tron"
+	troninput "github.com/openweb3-io/crosschain/blockchain/tron/tx_input"
+	xcbuilder "github.com/openweb3-io/crosschain/builder"
+	xclient "github.com/openweb3-io/crosschain/client"
+	"github.com/openweb3-io/crosschain/factory/blockchains"
+	xc "github.com/openweb3-io/crosschain/types"
+	tonaddress "github.com/xssnick/tonutils-go/address"
+	"github.com/xssnick/tonutils-go/tlb"
+	"github.com/xssnick/tonutils-go/tvm/cell"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+func (s *BlockchainTestSuite) TestUnsignedTxEnvelope() {
+	require := s.Require()
+	tonPublicKey, _ := hex.DecodeString("c1172b7926116d2a396bd7d69b9880cc0657e8ba2db9f62b4c210c518321c8b1")
+	cosmosPublicKey, _ := hex.DecodeString("02e8445082a72f29b75ca48748a914df60622a609cacfce8ed0e35804560741d29")
+
+	for _, v := range []struct {
+		chain     *xc.ChainConfig
+		from      xc.Address
+		to        xc.Address
+		input     xc.TxInput
+		signature []byte
+	}{
+		{
+			chain: &xc.ChainConfig{Chain: xc.BTC, Blockchain: xc.BlockchainBtc, Network: "testnet"},
+			from:  "tb1qhymp5maj7x2rqxsj02exqn26v5jcqm0q3x3pz4",
+			to:    "tb1qtguj96eqjtzt2fywyqdgmuw6wtpdsuahheqja6",
+			input: &btcinput.TxInput{
+				UnspentOutputs: []btcinput.Output{{
+					Outpoint: btcinput.Outpoint{Hash: bytes.Repeat([]byte{1}, 32), Index: 1},
+					Value:    xc.NewBigIntFromUint64(10_000),
+				}},
+				GasPricePerByte: xc.NewBigIntFromUint64(1),
+			},
+			signature: bytes.Repeat([]byte{1}, 64),
+		},
+		{
+			chain:     &xc.ChainConfig{Chain: xc.ETH, Blockchain: xc.BlockchainEVM, ChainID: 1},
+			from:      "0x724435CC1B2821362c2CD425F2744Bd7347bf299",
+			to:        "0x3ad57b83B2E3dC5648F32e98e386935A9B10bb9F",
+			input:     evminput.NewTxInput(),
+			signature: append(bytes.Repeat([]byte{1}, 64), 0),
+		},
+		{
+			chain:     &xc.ChainConfig{Chain: "XPLA", Blockchain: xc.BlockchainCosmos, ChainCoin: "axpla", ChainPrefix: "xpla", ChainIDStr: "dimension_37-1"},
+			from:      "xpla1hdvf6vv5amc7wp84js0ls27apekwxpr0ge96kg",
+			to:        "xpla1q8hwmpvyv7mh6qyvctsdms5flwxvfa3j9v3rd4",
+			input:     &cosmosinput.TxInput{AssetType: cosmosinput.BANK, LegacyFromPublicKey: cosmosPublicKey},
+			signature: bytes.Repeat([]byte{1}, 64),
+		},
+		{
+			chain:     &xc.ChainConfig{Chain: xc.SOL, Blockchain: xc.BlockchainSolana},
+			from:      "Hzn3n914JaSpnxo5mBbmuCDmGL6mxWN9Ac2HzEXFSGtb",
+			to:        "BWbmXj5ckAaWCAtzMZ97qnJhBAKegoXtgNrv9BUpAB11",
+			input:     &solanainput.TxInput{},
+			signature: bytes.Repeat([]byte{1}, 64),
+		},
+		{
+			chain:     &xc.ChainConfig{Chain: xc.TRX, Blockchain: xc.BlockchainTron},
+			from:      "T9yD14Nponncw9JuyVhbJRzw1NajGHLh6e",
+			to:        "T9yD14P27v7xV5oUjwoLrzcXCYFZBt7AZ2",
+			input:     &troninput.TxInput{},
+			signature: bytes.Repeat([]byte{1}, 65),
+		},
+		{
+			chain:     &xc.ChainConfig{Chain: xc.TON, Blockchain: xc.BlockchainTon, Decimals: 9},
+			from:      "EQAjflEZ_6KgKMxPlcnKN1ZoUvHdTT6hVwTW95EGVQfeSha2",
+			to:        "0QChotyiAtSPqs0BbPD851Mys9_LdMVM7N-atsFYvUMc48Jm",
+			input:     &ton.TxInput{PublicKey: tonPublicKey},
+			signature: bytes.Repeat([]byte{1}, 64),
+		},
+	} {
+		builder, err := blockchains.NewTxBuilder(v.chain)
+		require.NoError(err)
+		args, err := xcbuilder.NewTransferArgs(v.from, v.to, xc.NewBigIntFromUint64(1000))
+		require.NoError(err)
+		tx, err := builder.NewTransfer(args, v.input)
+		require.NoError(err, v.chain.Chain)
+
+		bz, err := blockchains.MarshalUnsignedTx(tx)
+		require.NoError(err, v.chain.Chain)
+		env := xc.TxEnvelope{}
+		require.NoError(json.Unmarshal(bz, &env))
+		require.Equal(v.chain.Blockchain, env.Type)
+
+		decoded, err := blockchains.UnmarshalUnsignedTx(bz)
+		require.NoError(err, v.chain.Chain)
+		require.IsType(tx, decoded)
+
+		// the offline signer signs the same payloads, and the signatures give the same transaction
+		sighashes, err := tx.Sighashes()
+		require.NoError(err)
+		decodedSighashes, err := decoded.Sighashes()
+		require.NoError(err)
+		require.Equal(sighashes, decodedSighashes, v.chain.Chain)
+
+		signatures := make([]xc.TxSignature, len(sighashes))
+		for i := range signatures {
+			signatures[i] = v.signature
+		}
+		require.NoError(tx.AddSignatures(signatures...))
+		require.NoError(decoded.AddSignatures(signatures...))
+		serialized, err := tx.Serialize()
+		require.NoError(err)
+		decodedSerialized, err := decoded.Serialize()
+		require.NoError(err)
+		require.Equal(serialized, decodedSerialized, v.chain.Chain)
+		require.Equal(tx.Hash(), decoded.Hash())
+	}
+
+	// forward requests have their own envelope type
+	forwardTx := &evmtx.ForwardTx{
+		Domain: forwarder.Domain{Name: "ERC2771Forwarder", Version: "1", ChainId: big.NewInt(1), VerifyingContract: common.HexToAddress("0x5FbDB2315678afecb367f032d93F642f64180aa3")},
+		Request: evmtx.ForwardRequest{
+			From:     common.HexToAddress("0x724435CC1B2821362c2CD425F2744Bd7347bf299"),
+			To:       common.HexToAddress("0xdAC17F958D2ee523a2206206994597C13D831ec7"),
+			Value:    big.NewInt(0),
+			Gas:      65_000,
+			Nonce:    big.NewInt(3),
+			Deadline: 1_700_000_000,
+			Data:     []byte{0xa9, 0x05, 0x9c, 0xbb},
+		},
+	}
+	bz, err := blockchains.MarshalUnsignedTx(forwardTx)
+	require.NoError(err)
+	env := xc.TxEnvelope{}
+	require.NoError(json.Unmarshal(bz, &env))
+	require.Equal(evmtx.ForwardTxType, env.Type)
+	decoded, err := blockchains.UnmarshalUnsignedTx(bz)
+	require.NoError(err)
+	require.Equal(forwardTx, decoded)
+
+	_, err = blockchains.UnmarshalUnsignedTx([]byte(`{"type":"unknown","tx":{}}`))
+	require.ErrorContains(err, "no unsigned tx mapped")
+}
+
+func (s *BlockchainTestSuite) TestDecodeUnsignedTx() {
+	require := s.Require()
+	tonPublicKey, _ := hex.DecodeString("c1172b7926116d2a396bd7d69b9880cc0657e8ba2db9f62b4c210c518321c8b1")
+	cosmosPublicKey, _ := hex.DecodeString("02e8445082a72f29b75ca48748a914df60622a609cacfce8ed0e35804560741d29")
+
+	for _, v := range []struct {
+		chain    *xc.ChainConfig
+		from     xc.Address
+		to       xc.Address
+		contract xc.ContractAddress
+		input    xc.TxInput
+		// the recipient, as formatted by the decoder
+		decodedTo xc.Address
+		// the sender is not part of the transaction until it is signed
+		unknownFrom bool
+		memo        string
+		fee         xc.BigInt
+	}{
+		{
+			chain: &xc.ChainConfig{Chain: xc.BTC, Blockchain: xc.BlockchainBtc, Network: "testnet"},
+			from:  "tb1qhymp5maj7x2rqxsj02exqn26v5jcqm0q3x3pz4",
+			to:    "tb1qtguj96eqjtzt2fywyqdgmuw6wtpdsuahheqja6",
+			input: &btcinput.TxInput{
+				UnspentOutputs: []btcinput.Output{{
+					Outpoint: btcinput.Outpoint{Hash: bytes.Repeat([]byte{1}, 32), Index: 1},
+					Value:    xc.NewBigIntFromUint64(10_000),
+				}},
+				GasPricePerByte: xc.NewBigIntFromUint64(1),
+			},
+		},
+		{
+			chain:       &xc.ChainConfig{Chain: xc.ETH, Blockchain: xc.BlockchainEVM, ChainID: 1},
+			from:        "0x724435CC1B2821362c2CD425F2744Bd7347bf299",
+			to:          "0x3ad57b83B2E3dC5648F32e98e386935A9B10bb9F",
+			input:       &evminput.TxInput{GasLimit: 21_000, GasFeeCap: xc.NewBigIntFromUint64(10)},
+			fee:         xc.NewBigIntFromUint64(210_000),
+			unknownFrom: true,
+		},
+		{
+			chain:       &xc.ChainConfig{Chain: xc.ETH, Blockchain: xc.BlockchainEVM, ChainID: 1},
+			from:        "0x724435CC1B2821362c2CD425F2744Bd7347bf299",
+			to:          "0x3ad57b83B2E3dC5648F32e98e386935A9B10bb9F",
+			contract:    "0xdAC17F958D2ee523a2206206994597C13D831ec7",
+			input:       &evminput.TxInput{GasLimit: 50_000, GasFeeCap: xc.NewBigIntFromUint64(10)},
+			fee:         xc.NewBigIntFromUint64(500_000),
+			unknownFrom: true,
+		},
+		{
+			chain: &xc.ChainConfig{Chain: "XPLA", Blockchain: xc.BlockchainCosmos, ChainCoin: "axpla", ChainPrefix: "xpla", ChainIDStr: "dimension_37-1"},
+			from:  "xpla1hdvf6vv5amc7wp84js0ls27apekwxpr0ge96kg",
+			to:    "xpla1q8hwmpvyv7mh6qyvctsdms5flwxvfa3j9v3rd4",
+			input: &cosmosinput.TxInput{AssetType: cosmosinput.BANK, LegacyFromPublicKey: cosmosPublicKey, LegacyMemo: "memo"},
+			memo:  "memo",
+		},
+		{
+			chain: &xc.ChainConfig{Chain: xc.SOL, Blockchain: xc.BlockchainSolana},
+			from:  "Hzn3n914JaSpnxo5mBbmuCDmGL6mxWN9Ac2HzEXFSGtb",
+			to:    "BWbmXj5ckAaWCAtzMZ97qnJhBAKegoXtgNrv9BUpAB11",
+			input: &solanainput.TxInput{},
+			fee:   xc.NewBigIntFromUint64(5000),
+		},
+		{
+			chain: &xc.ChainConfig{Chain: xc.TRX, Blockchain: xc.BlockchainTron},
+			from:  "T9yD14Nponncw9JuyVhbJRzw1NajGHLh6e",
+			to:    "T9yD14P27v7xV5oUjwoLrzcXCYFZBt7AZ2",
+			input: &troninput.TxInput{},
+		},
+		{
+			chain:    &xc.ChainConfig{Chain: xc.TRX, Blockchain: xc.BlockchainTron, ChainMaxGasPrice: 100_000_000},
+			from:     "T9yD14Nponncw9JuyVhbJRzw1NajGHLh6e",
+			to:       "T9yD14P27v7xV5oUjwoLrzcXCYFZBt7AZ2",
+			contract: "TR7NHqjeKQxGTCi8q8ZY4pL8otSzgjLj6t",
+			input:    &troninput.TxInput{},
+			fee:      xc.NewBigIntFromUint64(100_000_000),
+		},
+		{
+			chain:     &xc.ChainConfig{Chain: xc.TON, Blockchain: xc.BlockchainTon, Decimals: 9},
+			from:      "EQAjflEZ_6KgKMxPlcnKN1ZoUvHdTT6hVwTW95EGVQfeSha2",
+			to:        "0QChotyiAtSPqs0BbPD851Mys9_LdMVM7N-atsFYvUMc48Jm",
+			input:     &ton.TxInput{PublicKey: tonPublicKey},
+			decodedTo: "UQChotyiAtSPqs0BbPD851Mys9_LdMVM7N-atsFYvUMc43ns",
+			memo:      "memo",
+		},
+		{
+			chain:     &xc.ChainConfig{Chain: xc.TON, Blockchain: xc.BlockchainTon, Decimals: 9},
+			from:      "EQAjflEZ_6KgKMxPlcnKN1ZoUvHdTT6hVwTW95EGVQfeSha2",
+			to:        "0QChotyiAtSPqs0BbPD851Mys9_LdMVM7N-atsFYvUMc48Jm",
+			contract:  "EQCxE6mUtQJKFnGfaROTKOt1lZbDiiX1kCixRv7Nw2Id_sDs",
+			input:     &ton.TxInput{PublicKey: tonPublicKey, TokenWallet: "EQCxE6mUtQJKFnGfaROTKOt1lZbDiiX1kCixRv7Nw2Id_sDs", TonBalance: xc.NewBigIntFromUint64(1_000_000_000)},
+			decodedTo: "UQChotyiAtSPqs0BbPD851Mys9_LdMVM7N-atsFYvUMc43ns",
+			memo:      "memo",
+			fee:       xc.NewBigIntFromUint64(50_000_000),
+		},
+	} {
+		builder, err := blockchains.NewTxBuilder(v.chain)
+		require.NoError(err)
+		options := []xcbuilder.BuilderOption{xcbuilder.WithMemo(v.memo)}
+		if v.contract != "" {
+			options = append(options, xcbuilder.WithAsset(&xc.TokenAssetConfig{Contract: v.contract, Decimals: 6}))
+		}
+		args, err := xcbuilder.NewTransferArgs(v.from, v.to, xc.NewBigIntFromUint64(1000), options...)
+		require.NoError(err)
+		tx, err := builder.NewTransfer(args, v.input)
+		require.NoError(err, v.chain.Chain)
+
+		// the signer decodes the transaction it was sent
+		bz, err := blockchains.MarshalUnsignedTx(tx)
+		require.NoError(err, v.chain.Chain)
+		unsigned, err := blockchains.UnmarshalUnsignedTx(bz)
+		require.NoError(err, v.chain.Chain)
+		decoder, err := blockchains.NewTxDecoder(v.chain)
+		require.NoError(err)
+		info, err := decoder.DecodeTx(unsigned)
+		require.NoError(err, v.chain.Chain)
+
+		decodedTo := v.to
+		if v.decodedTo != "" {
+			decodedTo = v.decodedTo
+		}
+		decodedContract := v.contract
+		if decodedContract == "" {
+			decodedContract = xc.ContractAddress(v.chain.Chain)
+		}
+		var transfer *xclient.Transfer
+		for _, tf := range info.Transfers {
+			for _, to := range tf.To {
+				if to.Address == xclient.NewAddressName(v.chain.Chain, string(decodedTo)) {
+					transfer = tf
+				}
+			}
+		}
+		require.NotNil(transfer, "%s: no transfer to %s", v.chain.Chain, decodedTo)
+		decodedFrom := v.from
+		if v.unknownFrom {
+			decodedFrom = ""
+		}
+		require.Equal(xclient.NewAddressName(v.chain.Chain, string(decodedFrom)), transfer.From[0].Address, v.chain.Chain)
+		require.Equal(decodedContract, transfer.To[0].Contract, v.chain.Chain)
+		require.Equal("1000", transfer.To[0].Balance.String(), v.chain.Chain)
+		require.Equal(v.memo, transfer.Memo, v.chain.Chain)
+
+		if v.fee.Sign() > 0 {
+			found := false
+			for _, fee := range info.Fees {
+				found = found || (fee.Contract == xc.ContractAddress(v.chain.Chain) && fee.Balance.String() == v.fee.String())
+			}
+			require.True(found, "%s: fees %+v", v.chain.Chain, info.Fees)
+		}
+	}
+
+	// a forward request sends from its signer rather than the relayer, which pays the fee
+	ethChain := &xc.ChainConfig{Chain: xc.ETH, Blockchain: xc.BlockchainEVM, ChainID: 1}
+	recipient := common.HexToAddress("0x3ad57b83B2E3dC5648F32e98e386935A9B10bb9F")
+	forwardTx := &evmtx.ForwardTx{
+		Domain: forwarder.Domain{Name: "ERC2771Forwarder", Version: "1", ChainId: big.NewInt(1), VerifyingContract: common.HexToAddress("0x5FbDB2315678afecb367f032d93F642f64180aa3")},
+		Request: evmtx.ForwardRequest{
+			From:     common.HexToAddress("0x724435CC1B2821362c2CD425F2744Bd7347bf299"),
+			To:       common.HexToAddress("0xdAC17F958D2ee523a2206206994597C13D831ec7"),
+			Value:    big.NewInt(0),
+			Gas:      65_000,
+			Nonce:    big.NewInt(3),
+			Deadline: 1_700_000_000,
+			Data:     append([]byte{0xa9, 0x05, 0x9c, 0xbb}, append(common.LeftPadBytes(recipient.Bytes(), 32), common.LeftPadBytes(big.NewInt(1000).Bytes(), 32)...)...),
+		},
+	}
+	evmDecoder, err := blockchains.NewTxDecoder(ethChain)
+	require.NoError(err)
+	info, err := evmDecoder.DecodeTx(forwardTx)
+	require.NoError(err)
+	require.Len(info.Transfers, 1)
+	require.Equal(xclient.NewAddressName(xc.ETH, "0x724435CC1B2821362c2CD425F2744Bd7347bf299"), info.Transfers[0].From[0].Address)
+	require.Equal(xclient.NewAddressName(xc.ETH, recipient.String()), info.Transfers[0].To[0].Address)
+	require.Equal(xc.ContractAddress("0xdAC17F958D2ee523a2206206994597C13D831ec7"), info.Transfers[0].To[0].Contract)
+	require.Equal("1000", info.Transfers[0].To[0].Balance.String())
+	require.Empty(info.Fees)
+	forwardTx.Request.Data[0] = 0x09
+	_, err = evmDecoder.DecodeTx(forwardTx)
+	require.ErrorContains(err, "cannot decode call")
+
+	// contract calls that are not transfers are not decoded
+	decoder, err := blockchains.NewTxDecoder(&xc.ChainConfig{Chain: xc.SOL, Blockchain: xc.BlockchainSolana})
+	require.NoError(err)
+	_, err = decoder.DecodeTx(&evmtx.Tx{})
+	require.ErrorContains(err, "expected solana transaction")
+}
+
+func (s *BlockchainTestSuite) TestDecodeUnsupportedTx() {
+	require := s.Require()
+	cosmosPublicKey, _ := hex.DecodeString("02e8445082a72f29b75ca48748a914df60622a609cacfce8ed0e35804560741d29")
+	build := func(chain *xc.ChainConfig, from xc.Address, to xc.Address, contract xc.ContractAddress, input xc.TxInput) xc.Tx {
+		builder, err := blockchains.NewTxBuilder(chain)
+		require.NoError(err)
+		options := []xcbuilder.BuilderOption{}
+		if contract != "" {
+			options = append(options, xcbuilder.WithAsset(&xc.TokenAssetConfig{Contract: contract, Decimals: 6}))
+		}
+		args, err := xcbuilder.NewTransferArgs(from, to, xc.NewBigIntFromUint64(1000), options...)
+		require.NoError(err)
+		tx, err := builder.NewTransfer(args, input)
+		require.NoError(err)
+		return tx
+	}
+	decode := func(chain *xc.ChainConfig, tx xc.Tx) error {
+		decoder, err := blockchains.NewTxDecoder(chain)
+		require.NoError(err)
+		_, err = decoder.DecodeTx(tx)
+		return err
+	}
+
+	// an erc20 approve rather than a transfer
+	ethChain := &xc.ChainConfig{Chain: xc.ETH, Blockchain: xc.BlockchainEVM, ChainID: 1}
+	erc20Tx := build(ethChain, "0x724435CC1B2821362c2CD425F2744Bd7347bf299", "0x3ad57b83B2E3dC5648F32e98e386935A9B10bb9F", "0xdAC17F958D2ee523a2206206994597C13D831ec7",
+		&evminput.TxInput{GasLimit: 50_000, GasFeeCap: xc.NewBigIntFromUint64(10)}).(*evmtx.Tx)
+	data := append([]byte{0x09, 0x5e, 0xa7, 0xb3}, erc20Tx.EthTx.Data()[4:]...)
+	approveTx := &evmtx.Tx{
+		EthTx:  ethtypes.NewTx(&ethtypes.DynamicFeeTx{ChainID: big.NewInt(1), To: erc20Tx.EthTx.To(), Gas: 50_000, GasFeeCap: big.NewInt(10), Data: data}),
+		Signer: erc20Tx.Signer,
+	}
+	require.ErrorContains(decode(ethChain, approveTx), "cannot decode call")
+
+	// a trc20 approve rather than a transfer
+	tronChain := &xc.ChainConfig{Chain: xc.TRX, Blockchain: xc.BlockchainTron, ChainMaxGasPrice: 100_000_000}
+	trc20Tx := build(tronChain, "T9yD14Nponncw9JuyVhbJRzw1NajGHLh6e", "T9yD14P27v7xV5oUjwoLrzcXCYFZBt7AZ2", "TR7NHqjeKQxGTCi8q8ZY4pL8otSzgjLj6t", &troninput.TxInput{}).(*tron.Tx)
+	trigger := &core.TriggerSmartContract{}
+	require.NoError(trc20Tx.TronTx.RawData.Contract[0].Parameter.UnmarshalTo(trigger))
+	trigger.Data = append(tron.Signature("approve(address,uint256)"), trigger.Data[4:]...)
+	trc20Tx.TronTx.RawData.Contract[0].Parameter, _ = anypb.New(trigger)
+	require.ErrorContains(decode(tronChain, trc20Tx), "cannot decode call")
+
+	// a redelegation, which moves no funds the decoder can show
+	cosmosChain := &xc.ChainConfig{Chain: "XPLA", Blockchain: xc.BlockchainCosmos, ChainCoin: "axpla", ChainPrefix: "xpla", ChainIDStr: "dimension_37-1"}
+	cosmosTx := build(cosmosChain, "xpla1hdvf6vv5amc7wp84js0ls27apekwxpr0ge96kg", "xpla1q8hwmpvyv7mh6qyvctsdms5flwxvfa3j9v3rd4", "",
+		&cosmosinput.TxInput{AssetType: cosmosinput.BANK, LegacyFromPublicKey: cosmosPublicKey}).(*cosmostx.Tx)
+	require.NoError(cosmosTx.CosmosTxBuilder.SetMsgs(&stakingtypes.MsgBeginRedelegate{DelegatorAddress: "xpla1hdvf6vv5amc7wp84js0ls27apekwxpr0ge96kg"}))
+	cosmosTx.CosmosTx = cosmosTx.CosmosTxBuilder.GetTx()
+	require.ErrorContains(decode(cosmosChain, cosmosTx), "cannot decode message")
+
+	// solana instructions that give away the control of accounts next to a transfer, and a stake
+	// account that someone else may withdraw
+	solanaChain := &xc.ChainConfig{Chain: xc.SOL, Blockchain: xc.BlockchainSolana}
+	solanaTx := build(solanaChain, "Hzn3n914JaSpnxo5mBbmuCDmGL6mxWN9Ac2HzEXFSGtb", "BWbmXj5ckAaWCAtzMZ97qnJhBAKegoXtgNrv9BUpAB11", "", &solanainput.TxInput{}).(*solanatx.Tx)
+	owner := solana.MustPublicKeyFromBase58("Hzn3n914JaSpnxo5mBbmuCDmGL6mxWN9Ac2HzEXFSGtb")
+	other := solana.MustPublicKeyFromBase58("BWbmXj5ckAaWCAtzMZ97qnJhBAKegoXtgNrv9BUpAB11")
+	stakeAccount := solana.MustPublicKeyFromBase58("CCTFhyxoUHGmdQvuUxFquyYMK4H5hdqwCCN7XAXtK9HC")
+	transfer := system.NewTransferInstruction(1000, owner, other).Build()
+	for _, instructions := range [][]solana.Instruction{
+		{transfer, system.NewAssignInstruction(other, owner).Build()},
+		{transfer, token.NewApproveInstruction(1000, stakeAccount, other, owner, nil).Build()},
+		{transfer, token.NewCloseAccountInstruction(stakeAccount, other, owner, nil).Build()},
+		{transfer, solana.NewInstruction(other, solana.AccountMetaSlice{solana.Meta(owner).SIGNER()}, []byte{1})},
+		{
+			system.NewCreateAccountInstruction(1000, 200, solana.StakeProgramID, owner, stakeAccount).Build(),
+			stake.NewInitializeInstruction(owner, other, stakeAccount).Build(),
+			stake.NewDelegateStakeInstruction(other, owner, stakeAccount).Build(),
+		},
+	} {
+		tx, err := solana.NewTransaction(instructions, solanaTx.SolTx.Message.RecentBlockhash, solana.TransactionPayer(owner))
+		require.NoError(err)
+		require.ErrorContains(decode(solanaChain, solanatx.NewTxFrom(tx)), "cannot decode instruction")
+	}
+
+	// a message with a body that may call any contract
+	tonChain := &xc.ChainConfig{Chain: xc.TON, Blockchain: xc.BlockchainTon, Decimals: 9}
+	wallet := tonaddress.MustParseAddr("EQAjflEZ_6KgKMxPlcnKN1ZoUvHdTT6hVwTW95EGVQfeSha2")
+	msg, err := tlb.ToCell(&tlb.InternalMessage{
+		Bounce:  true,
+		DstAddr: tonaddress.MustParseAddr("EQCxE6mUtQJKFnGfaROTKOt1lZbDiiX1kCixRv7Nw2Id_sDs"),
+		Amount:  tlb.MustFromTON("1"),
+		Body:    cell.BeginCell().MustStoreUInt(0xdeadbeef, 32).EndCell(),
+	})
+	require.NoError(err)
+	payload := cell.BeginCell().MustStoreUInt(3, 8).MustStoreRef(msg)
+	tonTx := &tontx.Tx{CellBuilder: payload, ExternalMessage: &tlb.ExternalMessage{DstAddr: wallet, Body: payload.EndCell()}}
+	require.ErrorContains(decode(tonChain, tonTx), "neither a comment nor a jetton transfer")
+
+	// messages that send the whole balance or the incoming value rather than their amount, from the
+	// payload of a regular wallet and of a highload v3 wallet
+	msg, err = tlb.ToCell(&tlb.InternalMessage{
+		DstAddr: tonaddress.MustParseAddr("EQCxE6mUtQJKFnGfaROTKOt1lZbDiiX1kCixRv7Nw2Id_sDs"),
+		Amount:  tlb.MustFromTON("0.01"),
+	})
+	require.NoError(err)
+	for _, mode := range []uint64{128, 64, 128 + 3, 32 + 3} {
+		for _, payload := range []*cell.Builder{
+			cell.BeginCell().MustStoreUInt(698983191, 32).MustStoreUInt(1700000000, 32).MustStoreUInt(1, 32).MustStoreUInt(0, 8).MustStoreUInt(mode, 8).MustStoreRef(msg),
+			cell.BeginCell().MustStoreUInt(698983191, 32).MustStoreRef(msg).MustStoreUInt(mode, 8).MustStoreUInt(7, 23).MustStoreUInt(1700000000, 64).MustStoreUInt(60, 22),
+		} {
+			tonTx := &tontx.Tx{CellBuilder: payload, ExternalMessage: &tlb.ExternalMessage{DstAddr: wallet, Body: payload.EndCell()}}
+			require.ErrorContains(decode(tonChain, tonTx), "send mode", mode)
+		}
+	}
+}
+
+func (s *BlockchainTestSuite) TestDecodeTonBatch() {
+	require := s.Require()
+	tonPublicKey, _ := hex.DecodeString("c1172b7926116d2a396bd7d69b9880cc0657e8ba2db9f62b4c210c518321c8b1")
+	chain := &xc.ChainConfig{Chain: xc.TON, Blockchain: xc.BlockchainTon, Decimals: 9}
+	builder, err := blockchains.NewTxBuilder(chain)
+	require.NoError(err)
+	decoder, err := blockchains.NewTxDecoder(chain)
+	require.NoError(err)
+
+	for _, v := range []struct {
+		count   int
+		version tonwallet.Version
+	}{
+		{4, tonwallet.V4R2},
+		{200, tonwallet.HighloadV2R2},
+		{1, tonwallet.HighloadV3},
+		{300, tonwallet.HighloadV3},
+	} {
+		recipients := []xcbuilder.Recipient{}
+		for i := 0; i < v.count; i++ {
+			recipients = append(recipients, xcbuilder.Recipient{To: "0QChotyiAtSPqs0BbPD851Mys9_LdMVM7N-atsFYvUMc48Jm", Amount: xc.NewBigIntFromUint64(10)})
+		}
+		args, err := xcbuilder.NewBatchTransferArgs("EQAjflEZ_6KgKMxPlcnKN1ZoUvHdTT6hVwTW95EGVQfeSha2", recipients,
+			xcbuilder.WithExtra(map[string]any{"version": float64(v.version), "queryID": float64(7)}))
+		require.NoError(err)
+		tx, err := builder.(xcbuilder.TxBatchBuilder).NewBatchTransfer(args, &ton.TxInput{AccountStatus: ton.AccountStatusActive, PublicKey: tonPublicKey, Timestamp: 1700000000})
+		require.NoError(err, v.version)
+
+		info, err := decoder.DecodeTx(tx)
+		require.NoError(err, v.version)
+		require.Len(info.Transfers, v.count, v.version)
+	}
+}
+
+func (s *BlockchainTestSuite) TestTxInputEnvelope() {
+	require := s.Require()
+	for _, input := range []xc.TxInput{
+		&btcinput.TxInput{GasPricePerByte: xc.NewBigIntFromUint64(5)},
+		&evminput.TxInput{GasLimit: 21_000, GasFeeCap: xc.NewBigIntFromUint64(10), Nonce: 7},
+		&solanainput.TxInput{},
+		&troninput.TxInput{},
+		&ton.TxInput{AccountStatus: ton.AccountStatusActive, Seq: 3},
+	} {
+		bz, err := blockchains.MarshalTxInput(input)
+		require.NoError(err)
+		env := xc.TxInputEnvelope{}
+		require.NoError(json.Unmarshal(bz, &env))
+		require.Equal(input.GetBlockchain(), env.Type)
+
+		decoded, err := blockchains.UnmarshalTxInput(bz)
+		require.NoError(err)
+		require.Equal(input, decoded)
+	}
+}
@@ -9,6 +9,7 @@ import (
 	xc_client "github.com/openweb3-io/crosschain/client"
 	"github.com/openweb3-io/crosschain/factory/blockchains"
 	"github.com/openweb3-io/crosschain/factory/signer"
+	"github.com/openweb3-io/crosschain/factory/signer/derivation"
 	"github.com/openweb3-io/crosschain/types"
 	xc "github.com/openweb3-io/crosschain/types"
 )
@@ -16,7 +17,7 @@ import (
 type IFactory interface {
 	NewClient(cfg *types.ChainConfig) (xc_client.IClient, error)
 	NewTxBuilder(cfg *types.ChainConfig) (builder.TxBuilder, error)
-	NewSigner(cfg *types.ChainConfig, secret string) (*signer.Signer, error)
+	NewSigner(cfg *types.ChainConfig, secret string, options ...signer.Option) (*signer.Signer, error)
 }
 
 type Factory struct {
@@ -124,6 +125,23 @@ func (f *Factory) GetAddressFromPublicKey(cfg *types.ChainConfig, publicKey []by
 	return getAddressFromPublicKey(cfg, publicKey)
 }
 
+// GetAddressFromXPub returns the address of a path relative to an extended public key, e.g. 0/5, to
+// generate deposit addresses without the private key.  Only secp256k1 chains, e.g. BTC, support it.
+func (f *Factory) GetAddressFromXPub(cfg *types.ChainConfig, xpub string, path string) (types.Address, error) {
+	if cfg.Blockchain.SignatureAlgorithm() == types.Ed255 {
+		return "", fmt.Errorf("%s keys cannot be derived from an extended public key", cfg.Blockchain)
+	}
+	relativePath, err := derivation.ParsePath(path)
+	if err != nil {
+		return "", err
+	}
+	publicKey, err := derivation.DeriveXPub(xpub, relativePath)
+	if err != nil {
+		return "", err
+	}
+	return getAddressFromPublicKey(cfg, publicKey)
+}
+
 func getAddressFromPublicKey(cfg *types.ChainConfig, publicKey []byte) (types.Address, error) {
 	builder, err := blockchains.NewAddressBuilder(cfg)
 	if err != nil {
@@ -142,7 +160,7 @@ func (f *Factory) NewTxBuilder(cfg *types.ChainConfig) (builder.TxBuilder, error
 	return blockchains.NewTxBuilder(cfg)
 }
 
-// NewSigner creates a new Signer
-func (f *Factory) NewSigner(cfg *types.ChainConfig, secret string) (*signer.Signer, error) {
-	return blockchains.NewSigner(cfg, secret)
+// NewSigner creates a new Signer.  The options select the account and address index of a mnemonic.
+func (f *Factory) NewSigner(cfg *types.ChainConfig, secret string, options ...signer.Option) (*signer.Signer, error) {
+	return blockchains.NewSigner(cfg, secret, options...)
 }
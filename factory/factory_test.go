@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/openweb3-io/crosschain/factory"
+	"github.com/openweb3-io/crosschain/factory/signer"
 	xc "github.com/openweb3-io/crosschain/types"
 	"github.com/stretchr/testify/suite"
 )
@@ -74,6 +75,34 @@ func (s *CrosschainTestSuite) TestNewSigner() {
 	require.ErrorContains(err, "unsupported signing alg")
 }
 
+func (s *CrosschainTestSuite) TestGetAddressFromXPub() {
+	require := s.Require()
+	mnemonic := "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+	// the account xpub of m/84'/0'/0' of the mnemonic
+	xpub := "xpub6CatWdiZiodmUeTDp8LT5or8nmbKNcuyvz7WyksVFkKB4RHwCD3XyuvPEbvqAQY3rAPshWcMLoP2fMFMKHPJ4ZeZXYVUhLv1VMrjPC7PW6V"
+	chain := &xc.ChainConfig{Chain: xc.BTC, Blockchain: xc.BlockchainBtc, Network: "mainnet", HDPath: "m/84'/{coin}'/{account}'/0/{index}"}
+
+	for index := uint32(0); index < 3; index++ {
+		signer, err := s.Factory.NewSigner(chain, mnemonic, signer.WithAddressIndex(index))
+		require.NoError(err)
+		expected, err := s.Factory.GetAddressFromPublicKey(chain, signer.MustPublicKey())
+		require.NoError(err)
+
+		address, err := s.Factory.GetAddressFromXPub(chain, xpub, fmt.Sprintf("0/%d", index))
+		require.NoError(err)
+		require.Equal(expected, address)
+	}
+	// BIP-84 test vector
+	address, err := s.Factory.GetAddressFromXPub(chain, xpub, "0/0")
+	require.NoError(err)
+	require.EqualValues("bc1qcr8te4kr609gcawutmrza0j4xv80jy8z306fyu", address)
+
+	_, err = s.Factory.GetAddressFromXPub(chain, xpub, "0'/0")
+	require.ErrorContains(err, "hardened")
+	_, err = s.Factory.GetAddressFromXPub(&xc.ChainConfig{Chain: xc.SOL, Blockchain: xc.BlockchainSolana}, xpub, "0/0")
+	require.ErrorContains(err, "cannot be derived")
+}
+
 func (s *CrosschainTestSuite) TestNewAddressBuilder() {
 	require := s.Require()
 	for _, cfg := range s.TestChainConfigs {
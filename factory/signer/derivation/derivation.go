@@ -0,0 +1,162 @@
+// Package derivation derives keys from mnemonics: BIP-32 for secp256k1 keys, and SLIP-10 for ed25519
+// keys, which only supports hardened derivation.  Public keys can be derived from an extended public key
+// (xpub), to generate addresses without the private key.
+package derivation
+
+import (
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/btcsuite/btcd/btcutil/hdkeychain"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/cosmos/go-bip39"
+)
+
+const HardenedOffset = hdkeychain.HardenedKeyStart
+
+// Variables of path templates
+const (
+	CoinVar    = "{coin}"
+	AccountVar = "{account}"
+	IndexVar   = "{index}"
+)
+
+// The default path templates.  The ed25519 one has the layout used by Solana wallets, as SLIP-10 needs
+// every level to be hardened.
+const (
+	DefaultSecp256k1Template = "m/44'/{coin}'/{account}'/0/{index}"
+	DefaultEd25519Template   = "m/44'/{coin}'/{account}'/{index}'"
+)
+
+// A derivation path, e.g. m/44'/60'/0'/0/0, where hardened levels are offset by HardenedOffset
+type Path []uint32
+
+// ParsePath parses a path starting with m, marking hardened levels with ' or h.  Relative paths, without
+// the leading m, are also accepted, e.g. 0/1 to derive from an xpub.
+func ParsePath(path string) (Path, error) {
+	path = strings.TrimSpace(path)
+	if path == "m" || path == "" {
+		return Path{}, nil
+	}
+	parsed := Path{}
+	for _, level := range strings.Split(strings.TrimPrefix(path, "m/"), "/") {
+		hardened := false
+		if strings.HasSuffix(level, "'") || strings.HasSuffix(level, "h") || strings.HasSuffix(level, "H") {
+			hardened = true
+			level = level[:len(level)-1]
+		}
+		index, err := strconv.ParseUint(level, 10, 32)
+		if err != nil || index >= HardenedOffset {
+			return nil, fmt.Errorf("invalid derivation path %s: invalid level %s", path, level)
+		}
+		if hardened {
+			index += HardenedOffset
+		}
+		parsed = append(parsed, uint32(index))
+	}
+	return parsed, nil
+}
+
+// ExpandTemplate sets the variables of a path template, e.g. m/44'/{coin}'/{account}'/0/{index}
+func ExpandTemplate(template string, coin uint32, account uint32, index uint32) (Path, error) {
+	path := strings.NewReplacer(
+		CoinVar, strconv.FormatUint(uint64(coin), 10),
+		AccountVar, strconv.FormatUint(uint64(account), 10),
+		IndexVar, strconv.FormatUint(uint64(index), 10),
+	).Replace(template)
+	return ParsePath(path)
+}
+
+func (path Path) String() string {
+	levels := []string{"m"}
+	for _, index := range path {
+		if index >= HardenedOffset {
+			levels = append(levels, strconv.FormatUint(uint64(index-HardenedOffset), 10)+"'")
+		} else {
+			levels = append(levels, strconv.FormatUint(uint64(index), 10))
+		}
+	}
+	return strings.Join(levels, "/")
+}
+
+// NewSeed returns the BIP-39 seed of a mnemonic, after checking its checksum
+func NewSeed(mnemonic string, passphrase string) ([]byte, error) {
+	mnemonic = strings.Join(strings.Fields(mnemonic), " ")
+	seed, err := bip39.NewSeedWithErrorChecking(mnemonic, passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("invalid mnemonic: %v", err)
+	}
+	return seed, nil
+}
+
+// DeriveSecp256k1 derives a 32 byte secp256k1 private key with BIP-32
+func DeriveSecp256k1(seed []byte, path Path) ([]byte, error) {
+	key, err := hdkeychain.NewMaster(seed, &chaincfg.MainNetParams)
+	if err != nil {
+		return nil, err
+	}
+	for _, index := range path {
+		key, err = key.Derive(index)
+		if err != nil {
+			return nil, err
+		}
+	}
+	privateKey, err := key.ECPrivKey()
+	if err != nil {
+		return nil, err
+	}
+	return privateKey.Serialize(), nil
+}
+
+// DeriveEd25519 derives the 32 byte seed of an ed25519 private key with SLIP-10
+func DeriveEd25519(seed []byte, path Path) ([]byte, error) {
+	mac := hmac.New(sha512.New, []byte("ed25519 seed"))
+	mac.Write(seed)
+	sum := mac.Sum(nil)
+	key, chainCode := sum[:32], sum[32:]
+	for _, index := range path {
+		if index < HardenedOffset {
+			return nil, fmt.Errorf("ed25519 derivation only supports hardened paths, got %s", path)
+		}
+		data := make([]byte, 0, 37)
+		data = append(data, 0)
+		data = append(data, key...)
+		data = binary.BigEndian.AppendUint32(data, index)
+		mac := hmac.New(sha512.New, chainCode)
+		mac.Write(data)
+		sum := mac.Sum(nil)
+		key, chainCode = sum[:32], sum[32:]
+	}
+	return key, nil
+}
+
+// DeriveXPub derives the compressed secp256k1 public key of a path relative to an extended public key,
+// e.g. 0/5 for the sixth receive address of an account.  Any version is accepted, e.g. xpub, tpub or zpub.
+func DeriveXPub(xpub string, path Path) ([]byte, error) {
+	key, err := hdkeychain.NewKeyFromString(strings.TrimSpace(xpub))
+	if err != nil {
+		return nil, fmt.Errorf("invalid extended public key: %v", err)
+	}
+	if key.IsPrivate() {
+		return nil, errors.New("expected an extended public key, not a private one")
+	}
+	for _, index := range path {
+		if index >= HardenedOffset {
+			return nil, fmt.Errorf("cannot derive hardened levels from an extended public key, got %s", path)
+		}
+		key, err = key.Derive(index)
+		if err != nil {
+			return nil, err
+		}
+	}
+	publicKey, err := key.ECPubKey()
+	if err != nil {
+		return nil, err
+	}
+	return publicKey.SerializeCompressed(), nil
+}
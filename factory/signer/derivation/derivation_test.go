@@ -0,0 +1,123 @@
+package derivation_test
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcutil/hdkeychain"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/openweb3-io/crosschain/factory/signer/derivation"
+	"github.com/stretchr/testify/require"
+)
+
+// test vector 1 of BIP-32 and SLIP-10
+var testSeed, _ = hex.DecodeString("000102030405060708090a0b0c0d0e0f")
+
+func TestParsePath(t *testing.T) {
+	path, err := derivation.ParsePath("m/44'/60h/0H/0/1")
+	require.NoError(t, err)
+	require.Equal(t, derivation.Path{44 + derivation.HardenedOffset, 60 + derivation.HardenedOffset, derivation.HardenedOffset, 0, 1}, path)
+	require.Equal(t, "m/44'/60'/0'/0/1", path.String())
+
+	path, err = derivation.ParsePath("0/5")
+	require.NoError(t, err)
+	require.Equal(t, derivation.Path{0, 5}, path)
+
+	path, err = derivation.ParsePath("m")
+	require.NoError(t, err)
+	require.Empty(t, path)
+
+	for _, invalid := range []string{"m/a", "m/44''", "m//0", "m/2147483648"} {
+		_, err = derivation.ParsePath(invalid)
+		require.Error(t, err, invalid)
+	}
+}
+
+func TestExpandTemplate(t *testing.T) {
+	path, err := derivation.ExpandTemplate(derivation.DefaultSecp256k1Template, 118, 2, 7)
+	require.NoError(t, err)
+	require.Equal(t, "m/44'/118'/2'/0/7", path.String())
+
+	path, err = derivation.ExpandTemplate(derivation.DefaultEd25519Template, 501, 0, 3)
+	require.NoError(t, err)
+	require.Equal(t, "m/44'/501'/0'/3'", path.String())
+
+	_, err = derivation.ExpandTemplate("m/44'/{unknown}'", 0, 0, 0)
+	require.Error(t, err)
+}
+
+func TestDeriveSecp256k1(t *testing.T) {
+	for _, v := range []struct {
+		path       string
+		privateKey string
+	}{
+		{"m", "e8f32e723decf4051aefac8e2c93c9c5b214313817cdb01a1494b917c8436b35"},
+		{"m/0'/1/2'/2/1000000000", "471b76e389e528d6de6d816857e012c5455051cad6660850e58372a6c3e6e7c8"},
+	} {
+		path, err := derivation.ParsePath(v.path)
+		require.NoError(t, err)
+		privateKey, err := derivation.DeriveSecp256k1(testSeed, path)
+		require.NoError(t, err)
+		require.Equal(t, v.privateKey, hex.EncodeToString(privateKey), v.path)
+	}
+}
+
+func TestDeriveEd25519(t *testing.T) {
+	for _, v := range []struct {
+		path       string
+		privateKey string
+	}{
+		{"m", "2b4be7f19ee27bbf30c667b642d5f4aa69fd169872f8fc3059c08ebae2eb19e7"},
+		{"m/0'/1'/2'/2'/1000000000'", "8f94d394a8e8fd6b1bc2f3f49f5c47e385281d5c17e65324b0f62483e37e8793"},
+	} {
+		path, err := derivation.ParsePath(v.path)
+		require.NoError(t, err)
+		privateKey, err := derivation.DeriveEd25519(testSeed, path)
+		require.NoError(t, err)
+		require.Equal(t, v.privateKey, hex.EncodeToString(privateKey), v.path)
+	}
+
+	_, err := derivation.DeriveEd25519(testSeed, derivation.Path{0})
+	require.ErrorContains(t, err, "only supports hardened")
+}
+
+func TestDeriveXPub(t *testing.T) {
+	account, err := derivation.ParsePath("m/84'/0'/0'")
+	require.NoError(t, err)
+	key, err := hdkeychain.NewMaster(testSeed, &chaincfg.MainNetParams)
+	require.NoError(t, err)
+	for _, index := range account {
+		key, err = key.Derive(index)
+		require.NoError(t, err)
+	}
+	xpub, err := key.Neuter()
+	require.NoError(t, err)
+
+	// the public key of the private key at the same path
+	privateKey, err := derivation.DeriveSecp256k1(testSeed, append(account, 0, 5))
+	require.NoError(t, err)
+	expected, err := crypto.ToECDSA(privateKey)
+	require.NoError(t, err)
+
+	publicKey, err := derivation.DeriveXPub(xpub.String(), derivation.Path{0, 5})
+	require.NoError(t, err)
+	require.Equal(t, crypto.CompressPubkey(&expected.PublicKey), publicKey)
+
+	_, err = derivation.DeriveXPub(xpub.String(), derivation.Path{derivation.HardenedOffset})
+	require.ErrorContains(t, err, "hardened")
+	_, err = derivation.DeriveXPub(key.String(), derivation.Path{0})
+	require.ErrorContains(t, err, "not a private one")
+	_, err = derivation.DeriveXPub("xpub", derivation.Path{0})
+	require.ErrorContains(t, err, "invalid extended public key")
+}
+
+func TestNewSeed(t *testing.T) {
+	seed, err := derivation.NewSeed("abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about", "TREZOR")
+	require.NoError(t, err)
+	// BIP-39 test vector
+	require.Equal(t, "c55257c360c07c72029aebc1b53c05ed0362ada38ead3e3e9efa3708e53495531f09a6987599d18264c1e1c92f2cf141630c7a3c4ab7c81b2f001698e7463b04", hex.EncodeToString(seed))
+
+	_, err = derivation.NewSeed("abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon", "")
+	require.ErrorContains(t, err, "invalid mnemonic")
+}
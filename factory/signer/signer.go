@@ -7,13 +7,15 @@ import (
 	"fmt"
 	"strings"
 
+	btcec "github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/txscript"
 	"github.com/btcsuite/btcutil/base58"
-	cosmoscrypto "github.com/cosmos/cosmos-sdk/crypto"
-	"github.com/cosmos/cosmos-sdk/crypto/hd"
-	"github.com/cosmos/cosmos-sdk/crypto/keyring"
 	"github.com/ethereum/go-ethereum/crypto"
-	cosmostypes "github.com/openweb3-io/crosschain/blockchain/cosmos/types"
+	tonwallet "github.com/openweb3-io/crosschain/blockchain/ton/wallet"
+	"github.com/openweb3-io/crosschain/factory/signer/derivation"
 	xc "github.com/openweb3-io/crosschain/types"
+	"github.com/xssnick/tonutils-go/adnl"
 )
 
 // Reference implementation to sign transactions - not meant to be used for production
@@ -28,57 +30,207 @@ type PrivateKey []byte
 // PublicKey is a public key
 type PublicKey []byte
 
-func fromMnemonic(privateKeyOrMnemonic string, hdPathNum uint32) (PrivateKey, error) {
-	if strings.Contains(privateKeyOrMnemonic, " ") {
-		if len(privateKeyOrMnemonic) < 16 {
-			return nil, errors.New("invalid mnemonic")
+// SLIP-44 coin types of ed25519 chains, for chains that do not configure chain_coin_hd_path.  Keys of
+// secp256k1 chains keep being derived with the configured coin type, even if it is zero.
+var defaultEd25519Coins = map[xc.Blockchain]uint32{
+	xc.BlockchainSolana: 501,
+	xc.BlockchainTon:    607,
+}
+
+type derivationOptions struct {
+	account  uint32
+	index    uint32
+	path     string
+	password string
+	legacy   bool
+	bip39    bool
+}
+
+// Option of deriving the key of a mnemonic
+type Option func(opts *derivationOptions) error
+
+// WithAccount derives the key of another account of the mnemonic, the {account} of the path template
+func WithAccount(account uint32) Option {
+	return func(opts *derivationOptions) error {
+		opts.account = account
+		return nil
+	}
+}
+
+// WithAddressIndex derives the key of another address of the account, the {index} of the path template
+func WithAddressIndex(index uint32) Option {
+	return func(opts *derivationOptions) error {
+		opts.index = index
+		return nil
+	}
+}
+
+// WithPath derives the key of a path or path template, instead of the hd_path of the chain
+func WithPath(path string) Option {
+	return func(opts *derivationOptions) error {
+		if _, err := derivation.ExpandTemplate(path, 0, 0, 0); err != nil {
+			return err
 		}
-		codec := cosmostypes.MakeCosmosConfig().Marshaler
-		kb := keyring.NewInMemory(codec)
-		// common path, will not be correct for all chains
-		hdPath := hd.CreateHDPath(hdPathNum, 0, 0).String()
-		_, err := kb.NewAccount("test", privateKeyOrMnemonic, keyring.DefaultBIP39Passphrase, hdPath, hd.Secp256k1)
-		if err != nil {
-			return PrivateKey{}, err
+		opts.path = path
+		return nil
+	}
+}
+
+// WithPassword sets the BIP-39 passphrase of the mnemonic, or the password of a TON mnemonic
+func WithPassword(password string) Option {
+	return func(opts *derivationOptions) error {
+		opts.password = password
+		return nil
+	}
+}
+
+// WithBIP39 derives the key of a TON mnemonic as a BIP-39 mnemonic.  About 1 in 256 BIP-39 mnemonics of 24
+// words are also valid TON mnemonics, and are otherwise taken to be TON mnemonics.
+func WithBIP39() Option {
+	return func(opts *derivationOptions) error {
+		opts.bip39 = true
+		return nil
+	}
+}
+
+// WithLegacyDerivation derives the key of a mnemonic as earlier versions did on every chain: a secp256k1
+// key with BIP-32 at m/44'/{coin}'/{account}'/0/{index}, with the chain_coin_hd_path of the chain or 118
+// without a chain, ignoring hd_path.  On ed25519 chains the secp256k1 key is the ed25519 seed, so keys of
+// Solana and TON mnemonics created before SLIP-10 derivation can still be used.
+func WithLegacyDerivation() Option {
+	return func(opts *derivationOptions) error {
+		opts.legacy = true
+		return nil
+	}
+}
+
+func newDerivationOptions(options ...Option) (*derivationOptions, error) {
+	opts := &derivationOptions{}
+	for _, opt := range options {
+		if err := opt(opts); err != nil {
+			return nil, err
 		}
-		armored, err := kb.ExportPrivKeyArmor("test", keyring.DefaultBIP39Passphrase)
-		if err != nil {
-			return PrivateKey{}, err
+	}
+	return opts, nil
+}
+
+// DerivationPath is the path the key of a mnemonic is derived from, by default m/44'/{coin}'/{account}'/0/{index}
+// for secp256k1 chains and m/44'/{coin}'/{account}'/{index}' for ed25519 chains.  The coin type is the
+// chain_coin_hd_path of the chain, and the template can be changed with its hd_path.
+func DerivationPath(driver xc.Blockchain, cfgMaybe *xc.ChainConfig, options ...Option) (derivation.Path, error) {
+	opts, err := newDerivationOptions(options...)
+	if err != nil {
+		return nil, err
+	}
+	alg := driver.SignatureAlgorithm()
+	if opts.legacy {
+		coin := uint32(118)
+		if cfgMaybe != nil {
+			coin = cfgMaybe.ChainCoinHDPath
 		}
-		privkey, _, err := cosmoscrypto.UnarmorDecryptPrivKey(armored, keyring.DefaultBIP39Passphrase)
-		if err != nil {
-			return PrivateKey{}, err
+		template := derivation.DefaultSecp256k1Template
+		if opts.path != "" {
+			template = opts.path
 		}
-		return PrivateKey(privkey.Bytes()), nil
-	} else {
-		return nil, errors.New("invalid mnemonic")
+		return derivation.ExpandTemplate(template, coin, opts.account, opts.index)
+	}
+	template := derivation.DefaultSecp256k1Template
+	if alg == xc.Ed255 {
+		template = derivation.DefaultEd25519Template
+	}
+	var coin uint32
+	if cfgMaybe != nil {
+		coin = cfgMaybe.ChainCoinHDPath
+		if cfgMaybe.HDPath != "" {
+			template = cfgMaybe.HDPath
+		}
+	} else if alg != xc.Ed255 {
+		coin = 118
+	}
+	if coin == 0 && alg == xc.Ed255 {
+		coin = defaultEd25519Coins[driver]
 	}
+	if opts.path != "" {
+		template = opts.path
+	}
+	return derivation.ExpandTemplate(template, coin, opts.account, opts.index)
 }
 
-func fromString(secret string, hdNumMaybe uint32) ([]byte, error) {
-	// try mnemonic first
-	bz, err := fromMnemonic(secret, hdNumMaybe)
+func fromMnemonic(mnemonic string, driver xc.Blockchain, cfgMaybe *xc.ChainConfig, options ...Option) (PrivateKey, error) {
+	opts, err := newDerivationOptions(options...)
 	if err != nil {
-		// Try hex next
-		bz, err := hex.DecodeString(secret)
-		if err != nil {
-			// try base58
-			base58bz := base58.Decode(secret)
-			return base58bz, nil
+		return nil, err
+	}
+	if driver == xc.BlockchainTon && !opts.legacy && !opts.bip39 {
+		// TON wallets use their own mnemonics of BIP-39 words, which are checked for first
+		words := strings.Fields(mnemonic)
+		tonErr := tonwallet.ValidateSeed(words, opts.password)
+		if tonErr == nil {
+			return fromTonMnemonic(words, opts)
 		}
-		return bz, nil
+		if _, err := derivation.NewSeed(mnemonic, ""); err != nil && !errors.Is(tonErr, tonwallet.ErrInvalidSeed) {
+			// e.g. a TON mnemonic with the wrong password
+			return nil, tonErr
+		}
+	}
+	path, err := DerivationPath(driver, cfgMaybe, options...)
+	if err != nil {
+		return nil, err
+	}
+	seed, err := derivation.NewSeed(mnemonic, opts.password)
+	if err != nil {
+		return nil, err
+	}
+	if opts.legacy {
+		return derivation.DeriveSecp256k1(seed, path)
+	}
+	switch alg := driver.SignatureAlgorithm(); alg {
+	case xc.Ed255:
+		return derivation.DeriveEd25519(seed, path)
+	case xc.K256Keccak, xc.K256Sha256:
+		return derivation.DeriveSecp256k1(seed, path)
+	default:
+		return nil, fmt.Errorf("unsupported signing alg: %v", alg)
 	}
-	return bz, nil
 }
 
-func New(driver xc.Blockchain, secret string, cfgMaybe *xc.ChainConfig) (*Signer, error) {
-	hdNum := uint32(118)
-	if cfgMaybe != nil {
-		hdNum = cfgMaybe.ChainCoinHDPath
+// The key of a TON mnemonic, which is the same for every wallet version and has no derivation path
+func fromTonMnemonic(words []string, opts *derivationOptions) (PrivateKey, error) {
+	if opts.account != 0 || opts.index != 0 || opts.path != "" {
+		return nil, errors.New("TON mnemonics have a single key, and cannot be derived by account, index or path")
 	}
-	secretBz, err := fromString(secret, hdNum)
+	key, err := tonwallet.SeedToPrivateKey(words, opts.password)
 	if err != nil {
-		return nil, fmt.Errorf("expected private key to be a hex or base58 string")
+		return nil, err
+	}
+	return PrivateKey(key), nil
+}
+
+func fromString(secret string) []byte {
+	// Try hex first
+	bz, err := hex.DecodeString(secret)
+	if err != nil {
+		// try base58
+		return base58.Decode(secret)
+	}
+	return bz
+}
+
+// New creates a signer of a private key, or of a key derived from a mnemonic.  Options select the key to
+// derive, and are only valid for mnemonics.
+func New(driver xc.Blockchain, secret string, cfgMaybe *xc.ChainConfig, options ...Option) (*Signer, error) {
+	var secretBz []byte
+	if strings.Contains(strings.TrimSpace(secret), " ") {
+		var err error
+		secretBz, err = fromMnemonic(secret, driver, cfgMaybe, options...)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		if len(options) > 0 {
+			return nil, errors.New("key derivation options are only supported for mnemonics")
+		}
+		secretBz = fromString(secret)
 	}
 	alg := driver.SignatureAlgorithm()
 	switch alg {
@@ -103,7 +255,23 @@ func New(driver xc.Blockchain, secret string, cfgMaybe *xc.ChainConfig) (*Signer
 }
 
 func (s *Signer) Sign(data xc.TxDataToSign) (xc.TxSignature, error) {
-	switch s.blockchain.SignatureAlgorithm() {
+	return s.SignWithType(s.blockchain.SignatureAlgorithm(), data)
+}
+
+// Sign with a specific algorithm, for transactions that mix signature types.  Schnorr signatures are
+// for bitcoin taproot key path spends, so the key is tweaked as in BIP-86.
+func (s *Signer) SignWithType(alg xc.SignatureType, data xc.TxDataToSign) (xc.TxSignature, error) {
+	switch alg {
+	case xc.Schnorr:
+		if s.blockchain.SignatureAlgorithm() != xc.K256Sha256 {
+			return nil, fmt.Errorf("unsupported signing alg for driver: %v", alg)
+		}
+		privateKey, _ := btcec.PrivKeyFromBytes(s.privateKey)
+		signature, err := schnorr.Sign(txscript.TweakTaprootPrivKey(*privateKey, nil), []byte(data))
+		if err != nil {
+			return nil, err
+		}
+		return xc.TxSignature(signature.Serialize()), nil
 	case xc.Ed255:
 		signatureRaw := ed25519.Sign(ed25519.PrivateKey(s.privateKey), []byte(data))
 		return xc.TxSignature(signatureRaw), nil
@@ -119,6 +287,34 @@ func (s *Signer) Sign(data xc.TxDataToSign) (xc.TxSignature, error) {
 	}
 }
 
+// Sign all of the sighashes of a transaction, using the signature type of each sighash if the transaction has mixed types.
+func (s *Signer) SignTx(tx xc.Tx) ([]xc.TxSignature, error) {
+	data, err := tx.Sighashes()
+	if err != nil {
+		return nil, err
+	}
+	withTypes, ok := tx.(xc.TxWithSignatureTypes)
+	if !ok {
+		return s.SignAll(data)
+	}
+	types, err := withTypes.SignatureTypes()
+	if err != nil {
+		return nil, err
+	}
+	if len(types) != len(data) {
+		return nil, fmt.Errorf("expected %d signature types, got %d", len(data), len(types))
+	}
+	signatures := make([]xc.TxSignature, len(data))
+	for i, d := range data {
+		sig, err := s.SignWithType(types[i], d)
+		if err != nil {
+			return nil, err
+		}
+		signatures[i] = sig
+	}
+	return signatures, nil
+}
+
 func (s *Signer) SignAll(data []xc.TxDataToSign) ([]xc.TxSignature, error) {
 	signatures := make([]xc.TxSignature, len(data))
 	for i, d := range data {
@@ -161,6 +357,20 @@ func (s *Signer) PublicKey() (PublicKey, error) {
 		return nil, fmt.Errorf("unsupported alg for driver: %v", s.blockchain)
 	}
 }
+
+// SharedKey derives the key shared with the holder of theirKey, as TON uses to encrypt comments.
+// Only ed25519 keys are supported.
+func (s *Signer) SharedKey(theirKey []byte) ([]byte, error) {
+	if s.blockchain.SignatureAlgorithm() != xc.Ed255 {
+		return nil, fmt.Errorf("shared keys are not supported for %s keys", s.blockchain.SignatureAlgorithm())
+	}
+	sharedKey, err := adnl.SharedKey(ed25519.PrivateKey(s.privateKey), theirKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute shared key: %w", err)
+	}
+	return sharedKey, nil
+}
+
 func (s *Signer) MustPublicKey() PublicKey {
 	pub, err := s.PublicKey()
 	if err != nil {
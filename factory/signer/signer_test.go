@@ -1,9 +1,16 @@
 package signer_test
 
 import (
+	"crypto/ed25519"
 	"encoding/hex"
 	"testing"
 
+	cosmoscrypto "github.com/cosmos/cosmos-sdk/crypto"
+	"github.com/cosmos/cosmos-sdk/crypto/hd"
+	"github.com/cosmos/cosmos-sdk/crypto/keyring"
+	cosmostypes "github.com/openweb3-io/crosschain/blockchain/cosmos/types"
+	tonwallet "github.com/openweb3-io/crosschain/blockchain/ton/wallet"
+	"github.com/openweb3-io/crosschain/factory/blockchains"
 	"github.com/openweb3-io/crosschain/factory/signer"
 	xc "github.com/openweb3-io/crosschain/types"
 	"github.com/stretchr/testify/require"
@@ -84,3 +91,157 @@ func TestSign(t *testing.T) {
 		require.Equal(t, v.pub, hex.EncodeToString(pub))
 	}
 }
+
+func TestNewSignerFromMnemonic(t *testing.T) {
+	mnemonic := "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+	evm := &xc.ChainConfig{Chain: xc.ETH, Blockchain: xc.BlockchainEVM, ChainCoinHDPath: 60}
+	sol := &xc.ChainConfig{Chain: xc.SOL, Blockchain: xc.BlockchainSolana}
+
+	for _, v := range []struct {
+		chain   *xc.ChainConfig
+		options []signer.Option
+		address string
+	}{
+		// m/44'/60'/0'/0/0
+		{chain: evm, address: "0x9858EfFD232B4033E47d90003D41EC34EcaEda94"},
+		// m/44'/60'/0'/0/1
+		{chain: evm, options: []signer.Option{signer.WithAddressIndex(1)}, address: "0x6Fac4D18c912343BF86fa7049364Dd4E424Ab9C0"},
+		// m/44'/501'/0'/0', as Solana wallets derive it
+		{chain: sol, address: "HAgk14JpMQLgt6rVgv7cBQFJWFto5Dqxi472uT3DKpqk"},
+	} {
+		s, err := signer.New(v.chain.Blockchain, mnemonic, v.chain, v.options...)
+		require.NoError(t, err)
+		builder, err := blockchains.NewAddressBuilder(v.chain)
+		require.NoError(t, err)
+		address, err := builder.GetAddressFromPublicKey(s.MustPublicKey())
+		require.NoError(t, err)
+		require.Equal(t, v.address, string(address))
+	}
+}
+
+func TestDerivationPath(t *testing.T) {
+	path, err := signer.DerivationPath(xc.BlockchainCosmos, nil, signer.WithAccount(2), signer.WithAddressIndex(3))
+	require.NoError(t, err)
+	require.Equal(t, "m/44'/118'/2'/0/3", path.String())
+
+	path, err = signer.DerivationPath(xc.BlockchainTon, &xc.ChainConfig{Chain: xc.TON, Blockchain: xc.BlockchainTon}, signer.WithAddressIndex(1))
+	require.NoError(t, err)
+	require.Equal(t, "m/44'/607'/0'/1'", path.String())
+
+	chain := &xc.ChainConfig{Chain: xc.BTC, Blockchain: xc.BlockchainBtc, HDPath: "m/84'/{coin}'/{account}'/0/{index}"}
+	path, err = signer.DerivationPath(chain.Blockchain, chain, signer.WithAddressIndex(4))
+	require.NoError(t, err)
+	require.Equal(t, "m/84'/0'/0'/0/4", path.String())
+
+	path, err = signer.DerivationPath(chain.Blockchain, chain, signer.WithPath("m/86'/0'/0'/0/{index}"))
+	require.NoError(t, err)
+	require.Equal(t, "m/86'/0'/0'/0/0", path.String())
+
+	_, err = signer.DerivationPath(chain.Blockchain, chain, signer.WithPath("m/x"))
+	require.Error(t, err)
+}
+
+func TestNewSignerDerivationErrors(t *testing.T) {
+	privateKey := "289c2857d4598e37fb9647507e47a309d6133539bf21a8b9cb6df88fd5232032"
+	_, err := signer.New(xc.BlockchainEVM, privateKey, nil, signer.WithAddressIndex(1))
+	require.ErrorContains(t, err, "only supported for mnemonics")
+
+	_, err = signer.New(xc.BlockchainEVM, "not a valid mnemonic", nil)
+	require.ErrorContains(t, err, "invalid mnemonic")
+
+	// ed25519 keys can only be derived from hardened paths
+	mnemonic := "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+	_, err = signer.New(xc.BlockchainSolana, mnemonic, nil, signer.WithPath("m/44'/501'/0'/0"))
+	require.ErrorContains(t, err, "only supports hardened")
+}
+
+func TestNewSignerFromTonMnemonic(t *testing.T) {
+	mnemonic := "legend cat copy alert pact lab share notable another cricket useless involve open river cute goose roast hurt favorite another example myth shadow arm"
+	tonSigner, err := signer.New(xc.BlockchainTon, mnemonic, nil)
+	require.NoError(t, err)
+	require.Equal(t, "2ec75045db03e93322a55bba4f4c360d889cd488d3461b8585e245f0d722d6c8", hex.EncodeToString(tonSigner.MustPublicKey()))
+
+	_, err = signer.New(xc.BlockchainTon, mnemonic, nil, signer.WithAddressIndex(1))
+	require.ErrorContains(t, err, "single key")
+	_, err = signer.New(xc.BlockchainTon, mnemonic, nil, signer.WithPassword("secret"))
+	require.ErrorIs(t, err, tonwallet.ErrInvalidPassword)
+
+	// generated with a password
+	mnemonic = "increase keep wait ship become able fiber opinion leader soul around globe puzzle black visual poem august worry forward crack profit sail dove embody"
+	tonSigner, err = signer.New(xc.BlockchainTon, mnemonic, nil, signer.WithPassword("secret"))
+	require.NoError(t, err)
+	require.Equal(t, "78d5253a0d2cc842bf808da6dfee901b1348a66c8ab3e021cbded505d99f6ba2", hex.EncodeToString(tonSigner.MustPublicKey()))
+	_, err = signer.New(xc.BlockchainTon, mnemonic, nil)
+	require.ErrorIs(t, err, tonwallet.ErrPasswordRequired)
+	_, err = signer.New(xc.BlockchainTon, mnemonic, nil, signer.WithPassword("wrong"))
+	require.ErrorIs(t, err, tonwallet.ErrInvalidPassword)
+
+	// BIP-39 mnemonics are still derived with SLIP-10
+	mnemonic = "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+	tonSigner, err = signer.New(xc.BlockchainTon, mnemonic, nil)
+	require.NoError(t, err)
+	expected, err := signer.New(xc.BlockchainTon, mnemonic, nil, signer.WithPath("m/44'/607'/0'/0'"))
+	require.NoError(t, err)
+	require.Equal(t, expected.MustPublicKey(), tonSigner.MustPublicKey())
+
+	// a BIP-39 mnemonic that is also a valid TON mnemonic
+	mnemonic = "burst sleep reward two fork decline situate loyal piece bubble use resemble load hip meat cash deny tape side hard since craft gold close"
+	tonSigner, err = signer.New(xc.BlockchainTon, mnemonic, nil)
+	require.NoError(t, err)
+	bip39Signer, err := signer.New(xc.BlockchainTon, mnemonic, nil, signer.WithBIP39())
+	require.NoError(t, err)
+	require.NotEqual(t, tonSigner.MustPublicKey(), bip39Signer.MustPublicKey())
+	expected, err = signer.New(xc.BlockchainSolana, mnemonic, nil, signer.WithPath("m/44'/607'/0'/0'"))
+	require.NoError(t, err)
+	require.Equal(t, expected.MustPublicKey(), bip39Signer.MustPublicKey())
+
+	// only TON derives keys from TON mnemonics
+	_, err = signer.New(xc.BlockchainSolana, "legend cat copy alert pact lab share notable another cricket useless involve open river cute goose roast hurt favorite another example myth shadow arm", nil)
+	require.ErrorContains(t, err, "invalid mnemonic")
+}
+
+// The key earlier versions derived from a mnemonic on every chain, with the Cosmos keyring
+func keyringKey(t *testing.T, mnemonic string, coin uint32) []byte {
+	kb := keyring.NewInMemory(cosmostypes.MakeCosmosConfig().Marshaler)
+	_, err := kb.NewAccount("test", mnemonic, keyring.DefaultBIP39Passphrase, hd.CreateHDPath(coin, 0, 0).String(), hd.Secp256k1)
+	require.NoError(t, err)
+	armored, err := kb.ExportPrivKeyArmor("test", keyring.DefaultBIP39Passphrase)
+	require.NoError(t, err)
+	privKey, _, err := cosmoscrypto.UnarmorDecryptPrivKey(armored, keyring.DefaultBIP39Passphrase)
+	require.NoError(t, err)
+	return privKey.Bytes()
+}
+
+func TestNewSignerWithLegacyDerivation(t *testing.T) {
+	mnemonic := "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+	for _, v := range []struct {
+		blockchain xc.Blockchain
+		chain      *xc.ChainConfig
+		coin       uint32
+	}{
+		{blockchain: xc.BlockchainSolana, coin: 118},
+		{blockchain: xc.BlockchainSolana, chain: &xc.ChainConfig{Chain: xc.SOL, Blockchain: xc.BlockchainSolana, ChainCoinHDPath: 501}, coin: 501},
+		// hd_path did not exist, and chains without a coin type used 0
+		{blockchain: xc.BlockchainTon, chain: &xc.ChainConfig{Chain: xc.TON, Blockchain: xc.BlockchainTon, HDPath: "m/44'/{coin}'/{account}'/{index}'"}, coin: 0},
+		{blockchain: xc.BlockchainEVM, chain: &xc.ChainConfig{Chain: xc.ETH, Blockchain: xc.BlockchainEVM, ChainCoinHDPath: 60}, coin: 60},
+	} {
+		legacySigner, err := signer.New(v.blockchain, mnemonic, v.chain, signer.WithLegacyDerivation())
+		require.NoError(t, err)
+		key := keyringKey(t, mnemonic, v.coin)
+		if v.blockchain.SignatureAlgorithm() == xc.Ed255 {
+			require.Equal(t, []byte(ed25519.NewKeyFromSeed(key).Public().(ed25519.PublicKey)), []byte(legacySigner.MustPublicKey()), v.blockchain)
+			// the default is now SLIP-10
+			current, err := signer.New(v.blockchain, mnemonic, v.chain)
+			require.NoError(t, err)
+			require.NotEqual(t, legacySigner.MustPublicKey(), current.MustPublicKey())
+		} else {
+			expected, err := signer.New(v.blockchain, hex.EncodeToString(key), v.chain)
+			require.NoError(t, err)
+			require.Equal(t, expected.MustPublicKey(), legacySigner.MustPublicKey(), v.blockchain)
+		}
+	}
+
+	path, err := signer.DerivationPath(xc.BlockchainTon, nil, signer.WithLegacyDerivation(), signer.WithAddressIndex(2))
+	require.NoError(t, err)
+	require.Equal(t, "m/44'/118'/0'/0/2", path.String())
+}
@@ -0,0 +1,268 @@
+package noncemanager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	xclient "github.com/openweb3-io/crosschain/client"
+	xc_types "github.com/openweb3-io/crosschain/types"
+	"github.com/sirupsen/logrus"
+)
+
+var ErrNotReserved = errors.New("nonce is no longer reserved")
+
+// Allocates sequential nonces to concurrent senders from the same address, so transactions can be
+// built without fetching the nonce from the chain each time.  Nonces are fetched once per address and
+// then handed out locally; nonces of transactions that fail to broadcast are given back to be reused.
+type Manager struct {
+	client     xclient.NonceClient
+	chain      xc_types.NativeAsset
+	store      Store
+	gapTimeout time.Duration
+
+	lock     sync.Mutex
+	accounts map[xc_types.Address]*account
+}
+
+type account struct {
+	lock sync.Mutex
+	// nil until loaded from the store or the chain
+	state *State
+}
+
+func New(client xclient.NonceClient, chain *xc_types.ChainConfig, options ...Option) (*Manager, error) {
+	blockchain := chain.Blockchain
+	if blockchain == "" {
+		blockchain = chain.Chain.Blockchain()
+	}
+	if blockchain == xc_types.BlockchainTon {
+		// seqno wallets only accept the message carrying the current seqno, so nonces cannot be
+		// reserved ahead of the chain; highload wallets replay-protect with query ids instead.
+		return nil, fmt.Errorf("nonce management is not supported on %s", blockchain)
+	}
+	m := &Manager{
+		client:     client,
+		chain:      chain.Chain,
+		store:      NewMemoryStore(),
+		gapTimeout: DefaultGapTimeout,
+		accounts:   map[xc_types.Address]*account{},
+	}
+	for _, opt := range options {
+		if err := opt(m); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+// A nonce handed out to a single transaction.  Exactly one of Commit or Release should be called
+// once the transaction is broadcast or has failed to.
+type Reservation struct {
+	Address xc_types.Address
+	Nonce   uint64
+
+	manager *Manager
+	done    bool
+}
+
+// Reserve the next nonce for a transaction from the address
+func (m *Manager) Reserve(ctx context.Context, address xc_types.Address) (*Reservation, error) {
+	var nonce uint64
+	err := m.update(ctx, address, func(state *State) error {
+		if len(state.Released) > 0 {
+			nonce = state.Released[0]
+			state.Released = state.Released[1:]
+		} else {
+			nonce = state.Next
+			state.Next++
+		}
+		state.InFlight[nonce] = time.Now()
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &Reservation{
+		Address: address,
+		Nonce:   nonce,
+		manager: m,
+	}, nil
+}
+
+// Set the nonce on the input of the transaction
+func (r *Reservation) Apply(input xc_types.TxInput) error {
+	withNonce, ok := input.(xc_types.TxInputWithNonce)
+	if !ok {
+		return fmt.Errorf("%T does not use a nonce", input)
+	}
+	withNonce.SetNonce(r.Nonce)
+	return nil
+}
+
+// Record that the transaction was broadcast.  Fails if Sync has since reclaimed the nonce as a gap,
+// in which case the nonce may be handed out again.
+func (r *Reservation) Commit(ctx context.Context) error {
+	if r.done {
+		return errors.New("reservation is already committed or released")
+	}
+	err := r.manager.update(ctx, r.Address, func(state *State) error {
+		if _, ok := state.InFlight[r.Nonce]; !ok {
+			return fmt.Errorf("%w: %d", ErrNotReserved, r.Nonce)
+		}
+		state.InFlight[r.Nonce] = time.Now()
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	r.done = true
+	return nil
+}
+
+// Give the nonce back after the transaction failed to broadcast, so the next reservation reuses it
+// rather than leaving a gap that would block later transactions.
+func (r *Reservation) Release(ctx context.Context) error {
+	if r.done {
+		return errors.New("reservation is already committed or released")
+	}
+	err := r.manager.update(ctx, r.Address, func(state *State) error {
+		if _, ok := state.InFlight[r.Nonce]; !ok {
+			// already reclaimed by Sync
+			return nil
+		}
+		delete(state.InFlight, r.Nonce)
+		release(state, r.Nonce)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	r.done = true
+	return nil
+}
+
+// Reconcile with the nonce on chain.  Nonces used by transactions sent from elsewhere are skipped, and
+// nonces that have been in flight for longer than the gap timeout without the chain accounting for
+// them are released to be reused.  Returns those gaps, whose transactions were likely dropped and will
+// not be included.
+func (m *Manager) Sync(ctx context.Context, address xc_types.Address) ([]uint64, error) {
+	onChain, err := m.client.FetchNonce(ctx, address)
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch nonce of %s: %v", address, err)
+	}
+	gaps := []uint64{}
+	err = m.update(ctx, address, func(state *State) error {
+		if onChain > state.Next {
+			logrus.WithFields(logrus.Fields{
+				"chain":    m.chain,
+				"address":  address,
+				"next":     state.Next,
+				"on_chain": onChain,
+			}).Warn("nonces were used outside of the nonce manager")
+			state.Next = onChain
+		}
+		released := []uint64{}
+		for _, nonce := range state.Released {
+			if nonce >= onChain {
+				released = append(released, nonce)
+			}
+		}
+		state.Released = released
+		for nonce := range state.InFlight {
+			if nonce < onChain {
+				delete(state.InFlight, nonce)
+			}
+		}
+
+		for nonce := onChain; nonce < state.Next; nonce++ {
+			if at, ok := state.InFlight[nonce]; ok && time.Since(at) < m.gapTimeout {
+				continue
+			}
+			if isReleased(state, nonce) {
+				continue
+			}
+			gaps = append(gaps, nonce)
+		}
+		for _, nonce := range gaps {
+			delete(state.InFlight, nonce)
+			release(state, nonce)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return gaps, nil
+}
+
+func (m *Manager) key(address xc_types.Address) Key {
+	return Key{Chain: m.chain, Address: address}
+}
+
+func (m *Manager) account(address xc_types.Address) *account {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	a, ok := m.accounts[address]
+	if !ok {
+		a = &account{}
+		m.accounts[address] = a
+	}
+	return a
+}
+
+// Apply a change to the state of the address and persist it, leaving the state unchanged if either fails
+func (m *Manager) update(ctx context.Context, address xc_types.Address, change func(state *State) error) error {
+	a := m.account(address)
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	key := m.key(address)
+	if a.state == nil {
+		state, err := m.store.Load(ctx, key)
+		if err != nil {
+			return fmt.Errorf("could not load nonce state of %s: %v", address, err)
+		}
+		if state == nil {
+			nonce, err := m.client.FetchNonce(ctx, address)
+			if err != nil {
+				return fmt.Errorf("could not fetch nonce of %s: %v", address, err)
+			}
+			state = &State{Next: nonce}
+		}
+		if state.InFlight == nil {
+			state.InFlight = map[uint64]time.Time{}
+		}
+		a.state = state
+	}
+
+	state := a.state.clone()
+	if err := change(state); err != nil {
+		return err
+	}
+	if err := m.store.Save(ctx, key, state); err != nil {
+		return fmt.Errorf("could not save nonce state of %s: %v", address, err)
+	}
+	a.state = state
+	return nil
+}
+
+func isReleased(state *State, nonce uint64) bool {
+	i := sort.Search(len(state.Released), func(i int) bool { return state.Released[i] >= nonce })
+	return i < len(state.Released) && state.Released[i] == nonce
+}
+
+// Add the nonce to the released ones, then lower Next past any released nonces at the end
+func release(state *State, nonce uint64) {
+	if !isReleased(state, nonce) {
+		state.Released = append(state.Released, nonce)
+		sort.Slice(state.Released, func(i, j int) bool { return state.Released[i] < state.Released[j] })
+	}
+	for len(state.Released) > 0 && state.Released[len(state.Released)-1] == state.Next-1 {
+		state.Released = state.Released[:len(state.Released)-1]
+		state.Next--
+	}
+}
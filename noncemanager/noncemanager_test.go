@@ -0,0 +1,167 @@
+package noncemanager_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	evminput "github.com/openweb3-io/crosschain/blockchain/evm/tx_input"
+	xclient "github.com/openweb3-io/crosschain/client"
+	"github.com/openweb3-io/crosschain/noncemanager"
+	xc_types "github.com/openweb3-io/crosschain/types"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeClient struct {
+	nonce   uint64
+	fetches int
+}
+
+var _ xclient.NonceClient = &fakeClient{}
+
+func (c *fakeClient) FetchNonce(ctx context.Context, address xc_types.Address) (uint64, error) {
+	c.fetches++
+	return c.nonce, nil
+}
+
+type failingStore struct {
+	noncemanager.Store
+}
+
+func (s *failingStore) Save(ctx context.Context, key noncemanager.Key, state *noncemanager.State) error {
+	return errors.New("store is down")
+}
+
+var chain = &xc_types.ChainConfig{Chain: xc_types.ETH}
+
+const addr = xc_types.Address("0x724435CC1B2821362c2CD425F2744Bd7347bf299")
+
+func reserve(t *testing.T, m *noncemanager.Manager) *noncemanager.Reservation {
+	r, err := m.Reserve(context.Background(), addr)
+	require.NoError(t, err)
+	return r
+}
+
+func TestNewRefusesTon(t *testing.T) {
+	_, err := noncemanager.New(&fakeClient{}, &xc_types.ChainConfig{Chain: xc_types.TON})
+	require.ErrorContains(t, err, "not supported")
+}
+
+func TestReserveSequential(t *testing.T) {
+	require := require.New(t)
+	client := &fakeClient{nonce: 5}
+	m, err := noncemanager.New(client, chain)
+	require.NoError(err)
+
+	wg := sync.WaitGroup{}
+	nonces := make(chan uint64, 100)
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r := reserve(t, m)
+			nonces <- r.Nonce
+			require.NoError(r.Commit(context.Background()))
+		}()
+	}
+	wg.Wait()
+	close(nonces)
+
+	seen := map[uint64]bool{}
+	for nonce := range nonces {
+		require.False(seen[nonce], "nonce %d reserved twice", nonce)
+		seen[nonce] = true
+	}
+	for nonce := uint64(5); nonce < 105; nonce++ {
+		require.True(seen[nonce])
+	}
+	// only fetched once
+	require.Equal(1, client.fetches)
+
+	input := evminput.NewTxInput()
+	r := reserve(t, m)
+	require.NoError(r.Apply(input))
+	require.EqualValues(105, input.Nonce)
+}
+
+func TestRelease(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+	store := noncemanager.NewMemoryStore()
+	m, err := noncemanager.New(&fakeClient{nonce: 0}, chain, noncemanager.WithStore(store))
+	require.NoError(err)
+
+	r0, r1, r2 := reserve(t, m), reserve(t, m), reserve(t, m)
+	require.EqualValues([]uint64{0, 1, 2}, []uint64{r0.Nonce, r1.Nonce, r2.Nonce})
+
+	// a failed broadcast in the middle is reused first
+	require.NoError(r0.Commit(ctx))
+	require.NoError(r1.Release(ctx))
+	require.Error(r1.Commit(ctx))
+	require.EqualValues(1, reserve(t, m).Nonce)
+
+	// releasing the last nonce lowers the next one
+	require.NoError(r2.Release(ctx))
+	require.EqualValues(2, reserve(t, m).Nonce)
+
+	state, err := store.Load(ctx, noncemanager.Key{Chain: xc_types.ETH, Address: addr})
+	require.NoError(err)
+	require.EqualValues(3, state.Next)
+	require.Len(state.InFlight, 3)
+
+	// resumes from the store
+	m, err = noncemanager.New(&fakeClient{nonce: 0}, chain, noncemanager.WithStore(store))
+	require.NoError(err)
+	require.EqualValues(3, reserve(t, m).Nonce)
+}
+
+func TestSyncDetectsGaps(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+	client := &fakeClient{nonce: 10}
+	m, err := noncemanager.New(client, chain, noncemanager.WithGapTimeout(time.Hour))
+	require.NoError(err)
+
+	for i := 0; i < 4; i++ {
+		require.NoError(reserve(t, m).Commit(ctx))
+	}
+	// 10 and 11 are included, 12 and 13 are still in flight
+	client.nonce = 12
+	gaps, err := m.Sync(ctx, addr)
+	require.NoError(err)
+	require.Empty(gaps)
+	require.EqualValues(14, reserve(t, m).Nonce)
+
+	// after the timeout, everything not included is a gap and reused
+	m, err = noncemanager.New(client, chain, noncemanager.WithGapTimeout(time.Nanosecond))
+	require.NoError(err)
+	r := reserve(t, m)
+	require.EqualValues(12, r.Nonce)
+	reserve(t, m)
+	time.Sleep(time.Millisecond)
+	gaps, err = m.Sync(ctx, addr)
+	require.NoError(err)
+	require.Equal([]uint64{12, 13}, gaps)
+	require.ErrorIs(r.Commit(ctx), noncemanager.ErrNotReserved)
+	require.EqualValues(12, reserve(t, m).Nonce)
+
+	// nonces used by another sender are skipped
+	client.nonce = 20
+	gaps, err = m.Sync(ctx, addr)
+	require.NoError(err)
+	require.Empty(gaps)
+	require.EqualValues(20, reserve(t, m).Nonce)
+}
+
+func TestStoreFailureLeavesStateUnchanged(t *testing.T) {
+	require := require.New(t)
+	m, err := noncemanager.New(&fakeClient{nonce: 3}, chain, noncemanager.WithStore(&failingStore{noncemanager.NewMemoryStore()}))
+	require.NoError(err)
+	_, err = m.Reserve(context.Background(), addr)
+	require.ErrorContains(err, "store is down")
+
+	_, err = noncemanager.New(&fakeClient{}, chain, noncemanager.WithGapTimeout(0))
+	require.Error(err)
+}
@@ -0,0 +1,34 @@
+package noncemanager
+
+import (
+	"errors"
+	"time"
+)
+
+// How long a nonce may be in flight without the chain accounting for it, before it is considered a gap
+const DefaultGapTimeout = 10 * time.Minute
+
+type Option func(m *Manager) error
+
+// Where the state of each address is persisted, defaults to memory
+func WithStore(store Store) Option {
+	return func(m *Manager) error {
+		if store == nil {
+			return errors.New("store must not be nil")
+		}
+		m.store = store
+		return nil
+	}
+}
+
+// How long a nonce may be reserved or broadcast without the chain accounting for it, before Sync
+// reports it as a gap.  This should cover the time to sign and confirm a transaction.
+func WithGapTimeout(timeout time.Duration) Option {
+	return func(m *Manager) error {
+		if timeout <= 0 {
+			return errors.New("gap timeout must be positive")
+		}
+		m.gapTimeout = timeout
+		return nil
+	}
+}
@@ -0,0 +1,74 @@
+package noncemanager
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	xc_types "github.com/openweb3-io/crosschain/types"
+)
+
+type Key struct {
+	Chain   xc_types.NativeAsset `json:"chain"`
+	Address xc_types.Address     `json:"address"`
+}
+
+// The nonces handed out for an address, which may be persisted to keep allocating after a restart.
+type State struct {
+	// the lowest nonce that was never reserved
+	Next uint64 `json:"next"`
+	// nonces below Next that were given back, to be reserved again before Next, in increasing order
+	Released []uint64 `json:"released,omitempty"`
+	// nonces below Next that are reserved or broadcast, with when that last happened, until the chain accounts for them
+	InFlight map[uint64]time.Time `json:"in_flight,omitempty"`
+}
+
+// Persists the state of each address.  Save is called after every change, while holding the lock for the address.
+type Store interface {
+	// Returns nil if nothing was saved for the key
+	Load(ctx context.Context, key Key) (*State, error)
+	Save(ctx context.Context, key Key, state *State) error
+}
+
+// Keeps the state in memory only, so nonces are fetched from the chain again after a restart
+type MemoryStore struct {
+	lock   sync.Mutex
+	states map[Key]*State
+}
+
+var _ Store = &MemoryStore{}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		states: map[Key]*State{},
+	}
+}
+
+func (s *MemoryStore) Load(ctx context.Context, key Key) (*State, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	state, ok := s.states[key]
+	if !ok {
+		return nil, nil
+	}
+	return state.clone(), nil
+}
+
+func (s *MemoryStore) Save(ctx context.Context, key Key, state *State) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.states[key] = state.clone()
+	return nil
+}
+
+func (state *State) clone() *State {
+	clone := &State{
+		Next:     state.Next,
+		Released: append([]uint64{}, state.Released...),
+		InFlight: make(map[uint64]time.Time, len(state.InFlight)),
+	}
+	for nonce, at := range state.InFlight {
+		clone.InFlight[nonce] = at
+	}
+	return clone
+}
@@ -0,0 +1,90 @@
+package policy
+
+import (
+	"github.com/openweb3-io/crosschain/config"
+	xc_types "github.com/openweb3-io/crosschain/types"
+)
+
+// The section of the config file the policy is loaded from
+const ConfigSection = "policy"
+
+// Rules for the transactions of each chain.  Rules are lists rather than maps keyed by address, as
+// the config loader lowercases map keys.
+//
+//	policy:
+//	  chains:
+//	    - chain: TON
+//	      daily_limits:
+//	        - asset: TON
+//	          amount: "1000000000000"
+//	        - asset: "EQ..."
+//	          amount: "1000000000"
+//	      token_wallets:
+//	        - wallet: "EQ..."
+//	          contract: "EQ..."
+//	      denylist: ["EQ..."]
+//	      max_fee_ratio: 0.01
+//	      required_memos:
+//	        - address: "EQ..."
+//	          pattern: "[0-9]{6,12}"
+type Config struct {
+	Chains []*ChainPolicy `yaml:"chains"`
+}
+
+type ChainPolicy struct {
+	Chain xc_types.NativeAsset `yaml:"chain"`
+	// The most of each asset that may be sent each day (UTC), across all senders.  Once any are set,
+	// assets without a limit may not be sent.
+	DailyLimits []DailyLimit `yaml:"daily_limits,omitempty"`
+	// The tokens of token wallets, e.g. of the jetton wallets of the senders on TON, as their transfers
+	// report the jetton wallet of the sender rather than the jetton master
+	TokenWallets []TokenWallet `yaml:"token_wallets,omitempty"`
+	// If set, transfers may only be sent to these addresses
+	Allowlist []xc_types.Address `yaml:"allowlist,omitempty"`
+	// Transfers may never be sent to these addresses
+	Denylist []xc_types.Address `yaml:"denylist,omitempty"`
+	// The most the fee of a transaction may be, as a fraction of the amount it sends of the asset the fee
+	// is paid in.  Not checked if the transaction sends none of that asset, e.g. a token transfer.
+	MaxFeeRatio float64 `yaml:"max_fee_ratio,omitempty"`
+	// Token contracts that may not be transferred or called
+	ForbiddenContracts []xc_types.ContractAddress `yaml:"forbidden_contracts,omitempty"`
+	// Recipients, like exchange deposit addresses, that need a memo of a certain format
+	RequiredMemos []MemoRule `yaml:"required_memos,omitempty"`
+}
+
+type DailyLimit struct {
+	// The contract of a token, or the chain for the native asset
+	Asset xc_types.ContractAddress `yaml:"asset"`
+	// In the smallest unit of the asset, e.g. wei
+	Amount string `yaml:"amount"`
+}
+
+type TokenWallet struct {
+	Wallet xc_types.ContractAddress `yaml:"wallet"`
+	// The token the wallet holds, as in the daily limits and forbidden contracts
+	Contract xc_types.ContractAddress `yaml:"contract"`
+}
+
+type MemoRule struct {
+	Address xc_types.Address `yaml:"address"`
+	// A regular expression the whole memo must match
+	Pattern string `yaml:"pattern"`
+}
+
+// LoadConfig loads the policy from the config file, found as for the chain configs
+func LoadConfig() (*Config, error) {
+	cfg := &Config{}
+	if err := config.RequireConfig(ConfigSection, cfg, nil); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+func (cfg *Config) GetChain(chain xc_types.NativeAsset) (*ChainPolicy, bool) {
+	for _, chainPolicy := range cfg.Chains {
+		if chainPolicy.Chain == chain {
+			return chainPolicy, true
+		}
+	}
+	return nil, false
+}
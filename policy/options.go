@@ -0,0 +1,30 @@
+package policy
+
+import (
+	"errors"
+	"time"
+)
+
+type Option func(e *Engine) error
+
+// Where the daily usage of each asset is persisted, defaults to memory
+func WithStore(store Store) Option {
+	return func(e *Engine) error {
+		if store == nil {
+			return errors.New("store must not be nil")
+		}
+		e.store = store
+		return nil
+	}
+}
+
+// The clock that decides which day a transaction counts toward, defaults to the system clock
+func WithClock(now func() time.Time) Option {
+	return func(e *Engine) error {
+		if now == nil {
+			return errors.New("clock must not be nil")
+		}
+		e.now = now
+		return nil
+	}
+}
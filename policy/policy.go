@@ -0,0 +1,323 @@
+package policy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	tonaddress "github.com/openweb3-io/crosschain/blockchain/ton/address"
+	xclient "github.com/openweb3-io/crosschain/client"
+	"github.com/openweb3-io/crosschain/factory/blockchains"
+	"github.com/openweb3-io/crosschain/factory/signer"
+	"github.com/openweb3-io/crosschain/normalize"
+	xc_types "github.com/openweb3-io/crosschain/types"
+)
+
+type Rule string
+
+const (
+	RuleNoPolicy          Rule = "no_policy"
+	RuleUndecodable       Rule = "undecodable"
+	RuleDenylist          Rule = "denylist"
+	RuleAllowlist         Rule = "allowlist"
+	RuleForbiddenContract Rule = "forbidden_contract"
+	RuleRequiredMemo      Rule = "required_memo"
+	RuleMaxFeeRatio       Rule = "max_fee_ratio"
+	RuleDailyLimit        Rule = "daily_limit"
+	RuleUnknownAsset      Rule = "unknown_asset"
+)
+
+// A transaction was refused as it breaks a rule of the policy
+type Violation struct {
+	Rule    Rule
+	Message string
+}
+
+func (v *Violation) Error() string {
+	return fmt.Sprintf("policy violation (%s): %s", v.Rule, v.Message)
+}
+
+func violation(rule Rule, format string, args ...any) *Violation {
+	return &Violation{Rule: rule, Message: fmt.Sprintf(format, args...)}
+}
+
+// Signs every sighash of a transaction
+type TxSigner interface {
+	SignTx(tx xc_types.Tx) ([]xc_types.TxSignature, error)
+}
+
+var _ TxSigner = &signer.Signer{}
+
+// Checks transactions against the policy of their chain before they are signed, so the limits that
+// each service used to check for itself are enforced in one place.  Chains without a policy are refused.
+type Engine struct {
+	chains map[xc_types.NativeAsset]*chainRules
+	store  Store
+	now    func() time.Time
+}
+
+type chainRules struct {
+	chain       xc_types.NativeAsset
+	limits      map[xclient.AssetName]xc_types.BigInt
+	tokens      map[string]string
+	allowlist   map[string]bool
+	denylist    map[string]bool
+	forbidden   map[xclient.AssetName]bool
+	memos       map[string]*regexp.Regexp
+	maxFeeRatio float64
+}
+
+func New(cfg *Config, options ...Option) (*Engine, error) {
+	e := &Engine{
+		chains: map[xc_types.NativeAsset]*chainRules{},
+		store:  NewMemoryStore(),
+		now:    time.Now,
+	}
+	for _, chainPolicy := range cfg.Chains {
+		if _, ok := e.chains[chainPolicy.Chain]; ok {
+			return nil, fmt.Errorf("duplicate policy for chain %s", chainPolicy.Chain)
+		}
+		rules, err := newChainRules(chainPolicy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid policy for chain %s: %v", chainPolicy.Chain, err)
+		}
+		e.chains[chainPolicy.Chain] = rules
+	}
+	for _, opt := range options {
+		if err := opt(e); err != nil {
+			return nil, err
+		}
+	}
+	return e, nil
+}
+
+func newChainRules(chainPolicy *ChainPolicy) (*chainRules, error) {
+	chain := chainPolicy.Chain
+	rules := &chainRules{
+		chain:       chain,
+		limits:      map[xclient.AssetName]xc_types.BigInt{},
+		tokens:      map[string]string{},
+		allowlist:   map[string]bool{},
+		denylist:    map[string]bool{},
+		forbidden:   map[xclient.AssetName]bool{},
+		memos:       map[string]*regexp.Regexp{},
+		maxFeeRatio: chainPolicy.MaxFeeRatio,
+	}
+	if chainPolicy.MaxFeeRatio < 0 {
+		return nil, fmt.Errorf("max fee ratio must not be negative, got %v", chainPolicy.MaxFeeRatio)
+	}
+	for _, tokenWallet := range chainPolicy.TokenWallets {
+		rules.tokens[rules.addressKey(string(tokenWallet.Wallet))] = rules.addressKey(string(tokenWallet.Contract))
+	}
+	for _, limit := range chainPolicy.DailyLimits {
+		amount, ok := new(big.Int).SetString(limit.Amount, 10)
+		if !ok || amount.Sign() < 0 {
+			return nil, fmt.Errorf("invalid daily limit of %s: %q", limit.Asset, limit.Amount)
+		}
+		rules.limits[rules.assetName(limit.Asset)] = xc_types.BigInt(*amount)
+	}
+	for _, address := range chainPolicy.Allowlist {
+		rules.allowlist[rules.addressKey(string(address))] = true
+	}
+	for _, address := range chainPolicy.Denylist {
+		rules.denylist[rules.addressKey(string(address))] = true
+	}
+	for _, contract := range chainPolicy.ForbiddenContracts {
+		rules.forbidden[rules.assetName(contract)] = true
+	}
+	for _, memo := range chainPolicy.RequiredMemos {
+		pattern, err := regexp.Compile("^(?:" + memo.Pattern + ")$")
+		if err != nil {
+			return nil, fmt.Errorf("invalid memo pattern for %s: %v", memo.Address, err)
+		}
+		rules.memos[rules.addressKey(string(memo.Address))] = pattern
+	}
+	return rules, nil
+}
+
+// Addresses are compared in a canonical form, as e.g. TON addresses have flags that don't change the account
+func (rules *chainRules) addressKey(address string) string {
+	if rules.chain.Blockchain() == xc_types.BlockchainTon {
+		if addr, err := tonaddress.ParseAddress(xc_types.Address(address), ""); err == nil {
+			return addr.Bounce(true).Testnet(false).String()
+		}
+	}
+	return normalize.Normalize(address, rules.chain)
+}
+
+// The asset that transfers of a contract count as.  Contracts are compared in the canonical form of
+// addresses, and token wallets count as the token they hold.
+func (rules *chainRules) assetName(contract xc_types.ContractAddress) xclient.AssetName {
+	if contract == "" || string(contract) == string(rules.chain) {
+		return xclient.NewAssetName(rules.chain, "")
+	}
+	key := rules.addressKey(string(contract))
+	if token, ok := rules.tokens[key]; ok {
+		key = token
+	}
+	return xclient.NewAssetName(rules.chain, key)
+}
+
+// What a transaction that passed the policy sends, reserved toward the daily limits.  Exactly one of
+// Commit or Release should be called once the transaction is signed or has failed to be.
+type Reservation struct {
+	release ReleaseFunc
+	done    bool
+}
+
+// Keep the reserved amounts counted toward the daily limits
+func (r *Reservation) Commit() error {
+	if r.done {
+		return errors.New("reservation is already committed or released")
+	}
+	r.done = true
+	return nil
+}
+
+// Give the reserved amounts back, as the transaction was not signed
+func (r *Reservation) Release(ctx context.Context) error {
+	if r.done {
+		return errors.New("reservation is already committed or released")
+	}
+	if r.release != nil {
+		if err := r.release(ctx); err != nil {
+			return err
+		}
+	}
+	r.done = true
+	return nil
+}
+
+// SignTx checks the transaction against the policy, and signs it only if it passes.  What it sends
+// only counts toward the daily limits if it is signed.
+func (e *Engine) SignTx(ctx context.Context, chain *xc_types.ChainConfig, tx xc_types.Tx, txSigner TxSigner) ([]xc_types.TxSignature, error) {
+	reservation, err := e.CheckTx(ctx, chain, tx)
+	if err != nil {
+		return nil, err
+	}
+	signatures, err := txSigner.SignTx(tx)
+	if err != nil {
+		if releaseErr := reservation.Release(ctx); releaseErr != nil {
+			return nil, fmt.Errorf("%v; could not release policy usage: %v", err, releaseErr)
+		}
+		return nil, err
+	}
+	return signatures, reservation.Commit()
+}
+
+// CheckTx decodes a built transaction and checks it against the policy of its chain.  A transaction
+// that cannot be decoded, e.g. a call to an unknown contract, is refused.
+func (e *Engine) CheckTx(ctx context.Context, chain *xc_types.ChainConfig, tx xc_types.Tx) (*Reservation, error) {
+	decoder, err := blockchains.NewTxDecoder(chain)
+	if err != nil {
+		return nil, err
+	}
+	info, err := decoder.DecodeTx(tx)
+	if err != nil {
+		return nil, violation(RuleUndecodable, "%v", err)
+	}
+	return e.Check(ctx, info)
+}
+
+// Check checks a decoded transaction against the policy of its chain.  Once it passes, what it sends
+// is reserved toward the daily limits, until the returned reservation is released.
+func (e *Engine) Check(ctx context.Context, info *xclient.TxInfo) (*Reservation, error) {
+	rules, ok := e.chains[info.Chain]
+	if !ok {
+		return nil, violation(RuleNoPolicy, "no policy for chain %s", info.Chain)
+	}
+
+	// what is sent to others, not counting change returned to the sender
+	sent := map[xclient.AssetName]*big.Int{}
+	for _, tf := range info.Transfers {
+		senders := map[xclient.AddressName]bool{}
+		for _, from := range tf.From {
+			if rules.forbidden[rules.assetName(from.Contract)] {
+				return nil, violation(RuleForbiddenContract, "contract %s is forbidden", from.Contract)
+			}
+			senders[from.Address] = true
+		}
+		for _, to := range tf.To {
+			asset := rules.assetName(to.Contract)
+			if rules.forbidden[asset] {
+				return nil, violation(RuleForbiddenContract, "contract %s is forbidden", to.Contract)
+			}
+			if senders[to.Address] {
+				continue
+			}
+			address := addressFromName(info.Chain, to.Address)
+			key := rules.addressKey(address)
+			if rules.denylist[key] {
+				return nil, violation(RuleDenylist, "recipient %s is denied", address)
+			}
+			if len(rules.allowlist) > 0 && !rules.allowlist[key] {
+				return nil, violation(RuleAllowlist, "recipient %s is not allowed", address)
+			}
+			if pattern, ok := rules.memos[key]; ok && !pattern.MatchString(tf.Memo) {
+				return nil, violation(RuleRequiredMemo, "memo %q to %s does not match %s", tf.Memo, address, pattern.String())
+			}
+			if _, ok := sent[asset]; !ok {
+				sent[asset] = new(big.Int)
+			}
+			sent[asset].Add(sent[asset], to.Balance.Int())
+		}
+	}
+
+	if rules.maxFeeRatio > 0 {
+		for _, fee := range info.Fees {
+			amount, ok := sent[fee.Asset]
+			if !ok || amount.Sign() == 0 {
+				continue
+			}
+			ratio, _ := new(big.Float).Quo(new(big.Float).SetInt(fee.Balance.Int()), new(big.Float).SetInt(amount)).Float64()
+			if ratio > rules.maxFeeRatio {
+				return nil, violation(RuleMaxFeeRatio, "fee of %s is %.4f of the amount %s, more than %v", fee.Balance.String(), ratio, amount.String(), rules.maxFeeRatio)
+			}
+		}
+	}
+
+	assets := []xclient.AssetName{}
+	for asset := range sent {
+		assets = append(assets, asset)
+	}
+	sort.Slice(assets, func(i, j int) bool { return assets[i] < assets[j] })
+	if len(rules.limits) == 0 {
+		return &Reservation{}, nil
+	}
+	// once there are limits, assets without one may not be sent
+	for _, asset := range assets {
+		if _, ok := rules.limits[asset]; !ok {
+			return nil, violation(RuleUnknownAsset, "%s has no daily limit", asset)
+		}
+	}
+	if len(assets) == 0 {
+		return &Reservation{}, nil
+	}
+	day := e.now().UTC().Format(time.DateOnly)
+	spends := make([]*Spend, len(assets))
+	for i, asset := range assets {
+		spends[i] = &Spend{
+			Key:    UsageKey{Chain: info.Chain, Asset: asset, Day: day},
+			Amount: xc_types.BigInt(*sent[asset]),
+			Limit:  rules.limits[asset],
+		}
+	}
+	exceeded, release, err := e.store.Spend(ctx, spends)
+	if err != nil {
+		return nil, err
+	}
+	if exceeded != nil {
+		return nil, violation(RuleDailyLimit, "sending %s of %s would go over the daily limit of %s", exceeded.Amount.String(), exceeded.Key.Asset, exceeded.Limit.String())
+	}
+	return &Reservation{release: release}, nil
+}
+
+func addressFromName(chain xc_types.NativeAsset, name xclient.AddressName) string {
+	return strings.TrimPrefix(string(name), filepath.Join("chains", string(chain), "addresses")+"/")
+}
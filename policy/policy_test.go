@@ -0,0 +1,341 @@
+package policy_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	evminput "github.com/openweb3-io/crosschain/blockchain/evm/tx_input"
+	xcbuilder "github.com/openweb3-io/crosschain/builder"
+	xclient "github.com/openweb3-io/crosschain/client"
+	"github.com/openweb3-io/crosschain/config/constants"
+	"github.com/openweb3-io/crosschain/factory/blockchains"
+	"github.com/openweb3-io/crosschain/policy"
+	xc_types "github.com/openweb3-io/crosschain/types"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	from      = xc_types.Address("0x724435CC1B2821362c2CD425F2744Bd7347bf299")
+	to        = xc_types.Address("0x3ad57b83B2E3dC5648F32e98e386935A9B10bb9F")
+	exchange  = xc_types.Address("0x8e1b6e1f2a1e3e0a2d0a4bc9a1b8f1e6b9a4c3d2")
+	usdt      = xc_types.ContractAddress("0xdAC17F958D2ee523a2206206994597C13D831ec7")
+	forbidden = xc_types.ContractAddress("0x0000000000000000000000000000000000000bad")
+)
+
+type countingSigner struct {
+	calls int
+	err   error
+}
+
+func (s *countingSigner) SignTx(tx xc_types.Tx) ([]xc_types.TxSignature, error) {
+	s.calls++
+	if s.err != nil {
+		return nil, s.err
+	}
+	return []xc_types.TxSignature{{}}, nil
+}
+
+func newEngine(t *testing.T, chainPolicy *policy.ChainPolicy, options ...policy.Option) *policy.Engine {
+	engine, err := policy.New(&policy.Config{Chains: []*policy.ChainPolicy{chainPolicy}}, options...)
+	require.NoError(t, err)
+	return engine
+}
+
+func transferInfo(to xc_types.Address, contract xc_types.ContractAddress, amount uint64, fee uint64, memo string) *xclient.TxInfo {
+	info := xclient.NewTxInfo(nil, xc_types.ETH, "", 0, nil)
+	info.AddSimpleTransfer(from, to, contract, xc_types.NewBigIntFromUint64(amount), nil, memo)
+	info.AddFee(from, "", xc_types.NewBigIntFromUint64(fee), nil)
+	info.Fees = info.CalculateFees()
+	return info
+}
+
+// Check the transaction and keep what it sends counted, as if it was signed
+func check(ctx context.Context, engine *policy.Engine, info *xclient.TxInfo) error {
+	reservation, err := engine.Check(ctx, info)
+	if err != nil {
+		return err
+	}
+	return reservation.Commit()
+}
+
+func requireViolation(t *testing.T, err error, rule policy.Rule) {
+	violation := &policy.Violation{}
+	require.ErrorAs(t, err, &violation)
+	require.Equal(t, rule, violation.Rule)
+}
+
+func TestNoPolicy(t *testing.T) {
+	engine := newEngine(t, &policy.ChainPolicy{Chain: xc_types.SOL})
+	err := check(context.Background(), engine, transferInfo(to, "", 100, 1, ""))
+	requireViolation(t, err, policy.RuleNoPolicy)
+}
+
+func TestDenylist(t *testing.T) {
+	ctx := context.Background()
+	// lowercase in the config still matches the checksum address
+	engine := newEngine(t, &policy.ChainPolicy{Chain: xc_types.ETH, Denylist: []xc_types.Address{"0x3ad57b83b2e3dc5648f32e98e386935a9b10bb9f"}})
+	requireViolation(t, check(ctx, engine, transferInfo(to, "", 100, 1, "")), policy.RuleDenylist)
+	require.NoError(t, check(ctx, engine, transferInfo(exchange, "", 100, 1, "")))
+}
+
+func TestAllowlist(t *testing.T) {
+	ctx := context.Background()
+	engine := newEngine(t, &policy.ChainPolicy{Chain: xc_types.ETH, Allowlist: []xc_types.Address{to}})
+	require.NoError(t, check(ctx, engine, transferInfo(to, "", 100, 1, "")))
+	requireViolation(t, check(ctx, engine, transferInfo(exchange, "", 100, 1, "")), policy.RuleAllowlist)
+
+	// change back to the sender is not a recipient
+	info := xclient.NewTxInfo(nil, xc_types.ETH, "", 0, nil)
+	tf := xclient.NewTransfer(xc_types.ETH)
+	tf.AddSource(from, "", xc_types.NewBigIntFromUint64(150), nil)
+	tf.AddDestination(to, "", xc_types.NewBigIntFromUint64(100), nil)
+	tf.AddDestination(from, "", xc_types.NewBigIntFromUint64(50), nil)
+	info.AddTransfer(tf)
+	require.NoError(t, check(ctx, engine, info))
+}
+
+func TestForbiddenContract(t *testing.T) {
+	ctx := context.Background()
+	engine := newEngine(t, &policy.ChainPolicy{Chain: xc_types.ETH, ForbiddenContracts: []xc_types.ContractAddress{forbidden}})
+	requireViolation(t, check(ctx, engine, transferInfo(to, forbidden, 100, 1, "")), policy.RuleForbiddenContract)
+	require.NoError(t, check(ctx, engine, transferInfo(to, usdt, 100, 1, "")))
+}
+
+func TestRequiredMemo(t *testing.T) {
+	ctx := context.Background()
+	engine := newEngine(t, &policy.ChainPolicy{
+		Chain:         xc_types.ETH,
+		RequiredMemos: []policy.MemoRule{{Address: exchange, Pattern: "[0-9]{6}"}},
+	})
+	require.NoError(t, check(ctx, engine, transferInfo(exchange, "", 100, 1, "123456")))
+	requireViolation(t, check(ctx, engine, transferInfo(exchange, "", 100, 1, "")), policy.RuleRequiredMemo)
+	// the whole memo must match
+	requireViolation(t, check(ctx, engine, transferInfo(exchange, "", 100, 1, "1234567")), policy.RuleRequiredMemo)
+	// other recipients need no memo
+	require.NoError(t, check(ctx, engine, transferInfo(to, "", 100, 1, "")))
+}
+
+func TestMaxFeeRatio(t *testing.T) {
+	ctx := context.Background()
+	engine := newEngine(t, &policy.ChainPolicy{Chain: xc_types.ETH, MaxFeeRatio: 0.01})
+	require.NoError(t, check(ctx, engine, transferInfo(to, "", 1000, 10, "")))
+	requireViolation(t, check(ctx, engine, transferInfo(to, "", 1000, 11, "")), policy.RuleMaxFeeRatio)
+	// the fee is paid in another asset than a token transfer
+	require.NoError(t, check(ctx, engine, transferInfo(to, usdt, 1000, 1000, "")))
+}
+
+func TestDailyLimit(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 1, 1, 23, 0, 0, 0, time.UTC)
+	store := policy.NewMemoryStore()
+	engine := newEngine(t, &policy.ChainPolicy{
+		Chain: xc_types.ETH,
+		DailyLimits: []policy.DailyLimit{
+			{Asset: xc_types.ContractAddress(xc_types.ETH), Amount: "1000"},
+			{Asset: usdt, Amount: "500"},
+		},
+	}, policy.WithStore(store), policy.WithClock(func() time.Time { return now }))
+
+	require.NoError(t, check(ctx, engine, transferInfo(to, "", 600, 1, "")))
+	require.NoError(t, check(ctx, engine, transferInfo(to, "", 400, 1, "")))
+	requireViolation(t, check(ctx, engine, transferInfo(to, "", 1, 1, "")), policy.RuleDailyLimit)
+	usage := store.Usage(policy.UsageKey{Chain: xc_types.ETH, Asset: xclient.NewAssetName(xc_types.ETH, string(xc_types.ETH)), Day: "2024-01-01"})
+	require.Equal(t, "1000", usage.String())
+
+	// tokens have their own limit
+	require.NoError(t, check(ctx, engine, transferInfo(to, usdt, 500, 1, "")))
+	requireViolation(t, check(ctx, engine, transferInfo(to, usdt, 1, 1, "")), policy.RuleDailyLimit)
+
+	// the limits start over each day
+	now = now.Add(2 * time.Hour)
+	require.NoError(t, check(ctx, engine, transferInfo(to, "", 1000, 1, "")))
+}
+
+func TestUnknownAsset(t *testing.T) {
+	ctx := context.Background()
+	engine := newEngine(t, &policy.ChainPolicy{
+		Chain:       xc_types.ETH,
+		DailyLimits: []policy.DailyLimit{{Asset: xc_types.ContractAddress(xc_types.ETH), Amount: "1000"}},
+	})
+	require.NoError(t, check(ctx, engine, transferInfo(to, "", 100, 1, "")))
+	requireViolation(t, check(ctx, engine, transferInfo(to, usdt, 100, 1, "")), policy.RuleUnknownAsset)
+
+	// without limits, any asset may be sent
+	engine = newEngine(t, &policy.ChainPolicy{Chain: xc_types.ETH})
+	require.NoError(t, check(ctx, engine, transferInfo(to, usdt, 100, 1, "")))
+}
+
+func TestNewInvalidConfig(t *testing.T) {
+	for _, chainPolicy := range []*policy.ChainPolicy{
+		{Chain: xc_types.ETH, DailyLimits: []policy.DailyLimit{{Asset: "ETH", Amount: "1.5"}}},
+		{Chain: xc_types.ETH, RequiredMemos: []policy.MemoRule{{Address: to, Pattern: "("}}},
+		{Chain: xc_types.ETH, MaxFeeRatio: -1},
+	} {
+		_, err := policy.New(&policy.Config{Chains: []*policy.ChainPolicy{chainPolicy}})
+		require.Error(t, err)
+	}
+	_, err := policy.New(&policy.Config{}, policy.WithStore(nil))
+	require.Error(t, err)
+}
+
+func TestTonAddressFlags(t *testing.T) {
+	// the same account, bounceable and not
+	engine := newEngine(t, &policy.ChainPolicy{Chain: xc_types.TON, Denylist: []xc_types.Address{"EQChotyiAtSPqs0BbPD851Mys9_LdMVM7N-atsFYvUMc4yQp"}})
+	info := xclient.NewTxInfo(nil, xc_types.TON, "", 0, nil)
+	info.AddSimpleTransfer("EQAjflEZ_6KgKMxPlcnKN1ZoUvHdTT6hVwTW95EGVQfeSha2", "UQChotyiAtSPqs0BbPD851Mys9_LdMVM7N-atsFYvUMc43ns", "", xc_types.NewBigIntFromUint64(100), nil, "")
+	requireViolation(t, check(context.Background(), engine, info), policy.RuleDenylist)
+}
+
+func TestTonTokenWallets(t *testing.T) {
+	ctx := context.Background()
+	const (
+		jettonMaster = xc_types.ContractAddress("EQCxE6mUtQJKFnGfaROTKOt1lZbDiiX1kCixRv7Nw2Id_sDs")
+		jettonWallet = xc_types.ContractAddress("EQBaWlpaWlpaWlpaWlpaWlpaWlpaWlpaWlpaWlpaWlpaWlvq")
+	)
+	jettonTransfer := func(amount uint64) *xclient.TxInfo {
+		info := xclient.NewTxInfo(nil, xc_types.TON, "", 0, nil)
+		// the decoder reports the jetton wallet of the sender, in either form
+		info.AddSimpleTransfer("EQAjflEZ_6KgKMxPlcnKN1ZoUvHdTT6hVwTW95EGVQfeSha2", "UQChotyiAtSPqs0BbPD851Mys9_LdMVM7N-atsFYvUMc43ns",
+			"UQBaWlpaWlpaWlpaWlpaWlpaWlpaWlpaWlpaWlpaWlpaWgYv", xc_types.NewBigIntFromUint64(amount), nil, "")
+		return info
+	}
+
+	engine := newEngine(t, &policy.ChainPolicy{
+		Chain:        xc_types.TON,
+		DailyLimits:  []policy.DailyLimit{{Asset: jettonMaster, Amount: "1000"}},
+		TokenWallets: []policy.TokenWallet{{Wallet: jettonWallet, Contract: jettonMaster}},
+	})
+	require.NoError(t, check(ctx, engine, jettonTransfer(1000)))
+	requireViolation(t, check(ctx, engine, jettonTransfer(1)), policy.RuleDailyLimit)
+
+	engine = newEngine(t, &policy.ChainPolicy{
+		Chain:              xc_types.TON,
+		ForbiddenContracts: []xc_types.ContractAddress{jettonMaster},
+		TokenWallets:       []policy.TokenWallet{{Wallet: jettonWallet, Contract: jettonMaster}},
+	})
+	requireViolation(t, check(ctx, engine, jettonTransfer(1)), policy.RuleForbiddenContract)
+
+	// an unknown jetton wallet has no limit
+	engine = newEngine(t, &policy.ChainPolicy{
+		Chain:       xc_types.TON,
+		DailyLimits: []policy.DailyLimit{{Asset: jettonMaster, Amount: "1000"}},
+	})
+	requireViolation(t, check(ctx, engine, jettonTransfer(1)), policy.RuleUnknownAsset)
+}
+
+func TestSignTx(t *testing.T) {
+	ctx := context.Background()
+	chain := &xc_types.ChainConfig{Chain: xc_types.ETH, Blockchain: xc_types.BlockchainEVM, ChainID: 1}
+	builder, err := blockchains.NewTxBuilder(chain)
+	require.NoError(t, err)
+	args, err := xcbuilder.NewTransferArgs(from, to, xc_types.NewBigIntFromUint64(1000))
+	require.NoError(t, err)
+	tx, err := builder.NewTransfer(args, &evminput.TxInput{GasLimit: 21_000, GasFeeCap: xc_types.NewBigIntFromUint64(10)})
+	require.NoError(t, err)
+
+	signer := &countingSigner{}
+	engine := newEngine(t, &policy.ChainPolicy{Chain: xc_types.ETH, Allowlist: []xc_types.Address{exchange}})
+	_, err = engine.SignTx(ctx, chain, tx, signer)
+	requireViolation(t, err, policy.RuleAllowlist)
+	require.Equal(t, 0, signer.calls)
+
+	engine = newEngine(t, &policy.ChainPolicy{Chain: xc_types.ETH, Allowlist: []xc_types.Address{to}})
+	sigs, err := engine.SignTx(ctx, chain, tx, signer)
+	require.NoError(t, err)
+	require.Len(t, sigs, 1)
+	require.Equal(t, 1, signer.calls)
+
+	// only signed transactions count toward the daily limits
+	store := policy.NewMemoryStore()
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	engine = newEngine(t, &policy.ChainPolicy{
+		Chain:       xc_types.ETH,
+		DailyLimits: []policy.DailyLimit{{Asset: xc_types.ContractAddress(xc_types.ETH), Amount: "1000"}},
+	}, policy.WithStore(store), policy.WithClock(func() time.Time { return now }))
+	key := policy.UsageKey{Chain: xc_types.ETH, Asset: xclient.NewAssetName(xc_types.ETH, string(xc_types.ETH)), Day: "2024-01-01"}
+	_, err = engine.SignTx(ctx, chain, tx, &countingSigner{err: errors.New("signer is down")})
+	require.ErrorContains(t, err, "signer is down")
+	require.EqualValues(t, "0", store.Usage(key).String())
+	_, err = engine.SignTx(ctx, chain, tx, signer)
+	require.NoError(t, err)
+	require.EqualValues(t, "1000", store.Usage(key).String())
+	_, err = engine.SignTx(ctx, chain, tx, signer)
+	requireViolation(t, err, policy.RuleDailyLimit)
+}
+
+func TestReservation(t *testing.T) {
+	ctx := context.Background()
+	engine := newEngine(t, &policy.ChainPolicy{
+		Chain:       xc_types.ETH,
+		DailyLimits: []policy.DailyLimit{{Asset: xc_types.ContractAddress(xc_types.ETH), Amount: "1000"}},
+	})
+	reservation, err := engine.Check(ctx, transferInfo(to, "", 1000, 1, ""))
+	require.NoError(t, err)
+	// reserved until released
+	_, err = engine.Check(ctx, transferInfo(to, "", 1, 1, ""))
+	requireViolation(t, err, policy.RuleDailyLimit)
+	require.NoError(t, reservation.Release(ctx))
+	require.Error(t, reservation.Commit())
+
+	reservation, err = engine.Check(ctx, transferInfo(to, "", 1000, 1, ""))
+	require.NoError(t, err)
+	require.NoError(t, reservation.Commit())
+	require.Error(t, reservation.Release(ctx))
+	_, err = engine.Check(ctx, transferInfo(to, "", 1, 1, ""))
+	requireViolation(t, err, policy.RuleDailyLimit)
+}
+
+type failingStore struct{}
+
+func (s *failingStore) Spend(ctx context.Context, spends []*policy.Spend) (*policy.Spend, policy.ReleaseFunc, error) {
+	return nil, nil, errors.New("store is down")
+}
+
+func TestStoreError(t *testing.T) {
+	engine := newEngine(t, &policy.ChainPolicy{
+		Chain:       xc_types.ETH,
+		DailyLimits: []policy.DailyLimit{{Asset: xc_types.ContractAddress(xc_types.ETH), Amount: "1000"}},
+	}, policy.WithStore(&failingStore{}))
+	err := check(context.Background(), engine, transferInfo(to, "", 100, 1, ""))
+	require.ErrorContains(t, err, "store is down")
+}
+
+func TestLoadConfig(t *testing.T) {
+	file, err := os.CreateTemp(t.TempDir(), "xctest")
+	require.NoError(t, err)
+	_, err = file.Write([]byte(`
+policy:
+  chains:
+    - chain: ETH
+      daily_limits:
+        - asset: ETH
+          amount: "1000000000000000000"
+      denylist: ["0x3ad57b83B2E3dC5648F32e98e386935A9B10bb9F"]
+      max_fee_ratio: 0.05
+      required_memos:
+        - address: "0x8e1b6e1f2a1e3e0a2d0a4bc9a1b8f1e6b9a4c3d2"
+          pattern: "[0-9]+"
+`))
+	require.NoError(t, err)
+	os.Setenv(constants.ConfigEnv, file.Name())
+	defer os.Unsetenv(constants.ConfigEnv)
+
+	cfg, err := policy.LoadConfig()
+	require.NoError(t, err)
+	chainPolicy, ok := cfg.GetChain(xc_types.ETH)
+	require.True(t, ok)
+	require.Equal(t, "1000000000000000000", chainPolicy.DailyLimits[0].Amount)
+	// addresses keep their case
+	require.Equal(t, to, chainPolicy.Denylist[0])
+	require.Equal(t, 0.05, chainPolicy.MaxFeeRatio)
+	require.Equal(t, "[0-9]+", chainPolicy.RequiredMemos[0].Pattern)
+	_, ok = cfg.GetChain(xc_types.SOL)
+	require.False(t, ok)
+
+	engine, err := policy.New(cfg)
+	require.NoError(t, err)
+	requireViolation(t, check(context.Background(), engine, transferInfo(to, "", 100, 1, "")), policy.RuleDenylist)
+}
@@ -0,0 +1,108 @@
+package policy
+
+import (
+	"context"
+	"math/big"
+	"sync"
+
+	xclient "github.com/openweb3-io/crosschain/client"
+	xc_types "github.com/openweb3-io/crosschain/types"
+)
+
+// The usage of an asset on a day
+type UsageKey struct {
+	Chain xc_types.NativeAsset `json:"chain"`
+	Asset xclient.AssetName    `json:"asset"`
+	// The day in UTC, as 2006-01-02
+	Day string `json:"day"`
+}
+
+// An amount to add to the usage of an asset, which may not go over the limit
+type Spend struct {
+	Key    UsageKey
+	Amount xc_types.BigInt
+	Limit  xc_types.BigInt
+}
+
+// Takes the amounts of a spend back off the usage, for a transaction that was not signed after all
+type ReleaseFunc func(ctx context.Context) error
+
+// Persists how much of each asset was sent each day, which may be shared by several signers.
+type Store interface {
+	// Add each amount to the usage of its key, unless any usage would go over its limit, in which case
+	// nothing is added and the spend that would go over is returned.  This must be atomic.  Otherwise
+	// the returned func releases the amounts again.
+	Spend(ctx context.Context, spends []*Spend) (*Spend, ReleaseFunc, error)
+}
+
+// Keeps the usage in memory only, so it starts over after a restart
+type MemoryStore struct {
+	lock  sync.Mutex
+	usage map[UsageKey]xc_types.BigInt
+}
+
+var _ Store = &MemoryStore{}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		usage: map[UsageKey]xc_types.BigInt{},
+	}
+}
+
+func (s *MemoryStore) Spend(ctx context.Context, spends []*Spend) (*Spend, ReleaseFunc, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	totals := map[UsageKey]xc_types.BigInt{}
+	for _, spend := range spends {
+		total, ok := totals[spend.Key]
+		if !ok {
+			total = s.usage[spend.Key]
+		}
+		// BigInt.Add would write to the stored usage in place
+		total = xc_types.BigInt(*new(big.Int).Add(total.Int(), spend.Amount.Int()))
+		if total.Cmp(&spend.Limit) > 0 {
+			return spend, nil, nil
+		}
+		totals[spend.Key] = total
+	}
+	for key, total := range totals {
+		s.usage[key] = total
+	}
+	// only today's usage is needed
+	for key := range s.usage {
+		for _, spend := range spends {
+			if key.Chain == spend.Key.Chain && key.Day < spend.Key.Day {
+				delete(s.usage, key)
+				break
+			}
+		}
+	}
+	return nil, func(ctx context.Context) error {
+		s.release(spends)
+		return nil
+	}, nil
+}
+
+func (s *MemoryStore) release(spends []*Spend) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	for _, spend := range spends {
+		// usage of a past day may already be dropped
+		total, ok := s.usage[spend.Key]
+		if !ok {
+			continue
+		}
+		total = xc_types.BigInt(*new(big.Int).Sub(total.Int(), spend.Amount.Int()))
+		if total.Sign() < 0 {
+			total = xc_types.NewBigIntFromUint64(0)
+		}
+		s.usage[spend.Key] = total
+	}
+}
+
+// The usage of an asset so far
+func (s *MemoryStore) Usage(key UsageKey) xc_types.BigInt {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.usage[key]
+}
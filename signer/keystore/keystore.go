@@ -0,0 +1,355 @@
+// Package keystore encrypts private keys and mnemonics with a passphrase, in the JSON format of
+// Ethereum v3 keystores.
+//
+// secp256k1 keys are stored exactly as Ethereum does (scrypt and aes-128-ctr), so the files can be
+// used with geth or other Ethereum wallets and vice versa.  ed25519 keys and mnemonics, which Ethereum
+// keystores cannot hold, are stored in the same layout with the key type set, and encrypted with
+// scrypt and aes-256-gcm.
+package keystore
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/btcsuite/btcutil/base58"
+	ethkeystore "github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/google/uuid"
+	xc "github.com/openweb3-io/crosschain/types"
+	"golang.org/x/crypto/scrypt"
+)
+
+// The environment variable the passphrase of a keystore is read from, unless another is given
+const PassphraseEnv = "KEYSTORE_PASSPHRASE"
+
+const version = 3
+
+const (
+	cipherAesCtr = "aes-128-ctr"
+	cipherAesGcm = "aes-256-gcm"
+	kdfScrypt    = "scrypt"
+	scryptR      = 8
+	scryptDKLen  = 32
+)
+
+// The scrypt parameters of Ethereum keystores.  The light parameters are much faster to decrypt.
+const (
+	StandardScryptN = ethkeystore.StandardScryptN
+	StandardScryptP = ethkeystore.StandardScryptP
+	LightScryptN    = ethkeystore.LightScryptN
+	LightScryptP    = ethkeystore.LightScryptP
+)
+
+type KeyType string
+
+const (
+	Secp256k1 KeyType = "secp256k1"
+	Ed25519   KeyType = "ed25519"
+	Mnemonic  KeyType = "mnemonic"
+)
+
+// An encrypted key
+type File struct {
+	Version int    `json:"version"`
+	ID      string `json:"id"`
+	Address string `json:"address,omitempty"`
+	// Not set in Ethereum keystores, which only hold secp256k1 keys
+	KeyType KeyType `json:"key_type,omitempty"`
+	// The chain the address is for
+	Chain  xc.NativeAsset         `json:"chain,omitempty"`
+	Crypto ethkeystore.CryptoJSON `json:"crypto"`
+}
+
+type encryptOptions struct {
+	scryptN int
+	scryptP int
+	address xc.Address
+	chain   xc.NativeAsset
+}
+
+type Option func(opts *encryptOptions) error
+
+// WithScrypt sets the cost of deriving the encryption key
+func WithScrypt(scryptN int, scryptP int) Option {
+	return func(opts *encryptOptions) error {
+		if scryptN <= 1 || scryptN&(scryptN-1) != 0 {
+			return fmt.Errorf("scrypt N must be a power of 2, got %d", scryptN)
+		}
+		if scryptP <= 0 {
+			return fmt.Errorf("scrypt P must be positive, got %d", scryptP)
+		}
+		opts.scryptN = scryptN
+		opts.scryptP = scryptP
+		return nil
+	}
+}
+
+// WithAddress records the address of the key, to list keys without decrypting them
+func WithAddress(chain xc.NativeAsset, address xc.Address) Option {
+	return func(opts *encryptOptions) error {
+		opts.chain = chain
+		opts.address = address
+		return nil
+	}
+}
+
+// Encrypt encrypts a private key of the given type, or the words of a mnemonic
+func Encrypt(keyType KeyType, secret []byte, passphrase string, options ...Option) (*File, error) {
+	opts := &encryptOptions{
+		scryptN: StandardScryptN,
+		scryptP: StandardScryptP,
+	}
+	for _, opt := range options {
+		if err := opt(opts); err != nil {
+			return nil, err
+		}
+	}
+	if err := validate(keyType, secret); err != nil {
+		return nil, err
+	}
+
+	file := &File{
+		Version: version,
+		ID:      uuid.New().String(),
+		Address: string(opts.address),
+		Chain:   opts.chain,
+	}
+	var err error
+	if keyType == Secp256k1 {
+		file.Crypto, err = ethkeystore.EncryptDataV3(secret, []byte(passphrase), opts.scryptN, opts.scryptP)
+	} else {
+		file.KeyType = keyType
+		file.Crypto, err = encryptGcm(secret, []byte(passphrase), opts.scryptN, opts.scryptP)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return file, nil
+}
+
+// EncryptSecret encrypts a secret in any of the forms accepted by factory/signer: a mnemonic, or a hex
+// or base58 private key for the signature algorithm.
+func EncryptSecret(secret string, alg xc.SignatureType, passphrase string, options ...Option) (*File, error) {
+	secret = strings.TrimSpace(secret)
+	if strings.Contains(secret, " ") {
+		return Encrypt(Mnemonic, []byte(secret), passphrase, options...)
+	}
+	secretBz, err := hex.DecodeString(strings.TrimPrefix(secret, "0x"))
+	if err != nil {
+		secretBz = base58.Decode(secret)
+	}
+	switch alg {
+	case xc.Ed255:
+		return Encrypt(Ed25519, secretBz, passphrase, options...)
+	case xc.K256Keccak, xc.K256Sha256:
+		return Encrypt(Secp256k1, secretBz, passphrase, options...)
+	default:
+		return nil, fmt.Errorf("unsupported signing alg: %v", alg)
+	}
+}
+
+// Decrypt returns the private key or the words of the mnemonic
+func (f *File) Decrypt(passphrase string) ([]byte, error) {
+	var secret []byte
+	var err error
+	switch f.Crypto.Cipher {
+	case cipherAesCtr:
+		secret, err = ethkeystore.DecryptDataV3(f.Crypto, passphrase)
+	case cipherAesGcm:
+		secret, err = decryptGcm(f.Crypto, []byte(passphrase))
+	default:
+		return nil, fmt.Errorf("unsupported cipher: %s", f.Crypto.Cipher)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := validate(f.Type(), secret); err != nil {
+		return nil, err
+	}
+	return secret, nil
+}
+
+// DecryptSecret returns the key in a form accepted by factory/signer, i.e. hex for private keys
+func (f *File) DecryptSecret(passphrase string) (string, error) {
+	secret, err := f.Decrypt(passphrase)
+	if err != nil {
+		return "", err
+	}
+	if f.Type() == Mnemonic {
+		return string(secret), nil
+	}
+	return hex.EncodeToString(secret), nil
+}
+
+func (f *File) Type() KeyType {
+	if f.KeyType == "" {
+		return Secp256k1
+	}
+	return f.KeyType
+}
+
+// Load reads a keystore file, including those written by Ethereum wallets
+func Load(path string) (*File, error) {
+	bz, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	file := &File{}
+	if err := json.Unmarshal(bz, file); err != nil {
+		return nil, fmt.Errorf("invalid keystore %s: %v", path, err)
+	}
+	if file.Version != version {
+		return nil, fmt.Errorf("unsupported keystore version %d of %s", file.Version, path)
+	}
+	return file, nil
+}
+
+// Save writes the keystore, readable only by the current user.  Existing files are not overwritten.
+func (f *File) Save(path string) error {
+	bz, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return err
+	}
+	out, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := out.Write(append(bz, '\n')); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// DecryptFile loads and decrypts a keystore, returning the key as DecryptSecret does
+func DecryptFile(path string, passphrase string) (string, error) {
+	file, err := Load(path)
+	if err != nil {
+		return "", err
+	}
+	secret, err := file.DecryptSecret(passphrase)
+	if err != nil {
+		return "", fmt.Errorf("could not decrypt %s: %v", path, err)
+	}
+	return secret, nil
+}
+
+func validate(keyType KeyType, secret []byte) error {
+	switch keyType {
+	case Secp256k1:
+		if _, err := crypto.ToECDSA(secret); err != nil {
+			return fmt.Errorf("invalid secp256k1 key: %v", err)
+		}
+	case Ed25519:
+		if len(secret) != ed25519.SeedSize && len(secret) != ed25519.PrivateKeySize {
+			return errors.New("expected ed25519 key to be 64 or 32 bytes")
+		}
+	case Mnemonic:
+		if !strings.Contains(string(secret), " ") {
+			return errors.New("invalid mnemonic")
+		}
+	default:
+		return fmt.Errorf("unsupported key type: %s", keyType)
+	}
+	return nil
+}
+
+func encryptGcm(data []byte, passphrase []byte, scryptN int, scryptP int) (ethkeystore.CryptoJSON, error) {
+	salt := make([]byte, 32)
+	if _, err := rand.Read(salt); err != nil {
+		return ethkeystore.CryptoJSON{}, err
+	}
+	derivedKey, err := scrypt.Key(passphrase, salt, scryptN, scryptR, scryptP, scryptDKLen)
+	if err != nil {
+		return ethkeystore.CryptoJSON{}, err
+	}
+	aead, err := newGcm(derivedKey)
+	if err != nil {
+		return ethkeystore.CryptoJSON{}, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return ethkeystore.CryptoJSON{}, err
+	}
+	cryptoJson := ethkeystore.CryptoJSON{
+		Cipher:     cipherAesGcm,
+		CipherText: hex.EncodeToString(aead.Seal(nil, nonce, data, nil)),
+		KDF:        kdfScrypt,
+		KDFParams: map[string]interface{}{
+			"n":     scryptN,
+			"r":     scryptR,
+			"p":     scryptP,
+			"dklen": scryptDKLen,
+			"salt":  hex.EncodeToString(salt),
+		},
+	}
+	cryptoJson.CipherParams.IV = hex.EncodeToString(nonce)
+	return cryptoJson, nil
+}
+
+func decryptGcm(cryptoJson ethkeystore.CryptoJSON, passphrase []byte) ([]byte, error) {
+	if cryptoJson.KDF != kdfScrypt {
+		return nil, fmt.Errorf("unsupported kdf: %s", cryptoJson.KDF)
+	}
+	params := cryptoJson.KDFParams
+	salt, err := hex.DecodeString(fmt.Sprint(params["salt"]))
+	if err != nil {
+		return nil, fmt.Errorf("invalid salt: %v", err)
+	}
+	n, r, p, dkLen := intParam(params["n"]), intParam(params["r"]), intParam(params["p"]), intParam(params["dklen"])
+	if dkLen != scryptDKLen {
+		return nil, fmt.Errorf("expected dklen %d for %s, got %d", scryptDKLen, cipherAesGcm, dkLen)
+	}
+	derivedKey, err := scrypt.Key(passphrase, salt, n, r, p, dkLen)
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := hex.DecodeString(cryptoJson.CipherParams.IV)
+	if err != nil {
+		return nil, fmt.Errorf("invalid iv: %v", err)
+	}
+	cipherText, err := hex.DecodeString(cryptoJson.CipherText)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ciphertext: %v", err)
+	}
+	aead, err := newGcm(derivedKey)
+	if err != nil {
+		return nil, err
+	}
+	if len(nonce) != aead.NonceSize() {
+		return nil, fmt.Errorf("expected a %d byte iv, got %d", aead.NonceSize(), len(nonce))
+	}
+	plainText, err := aead.Open(nil, nonce, cipherText, nil)
+	if err != nil {
+		// same error as for Ethereum keystores
+		return nil, ethkeystore.ErrDecrypt
+	}
+	return plainText, nil
+}
+
+func newGcm(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// JSON numbers decode as float64
+func intParam(v interface{}) int {
+	switch v := v.(type) {
+	case float64:
+		return int(v)
+	case int:
+		return v
+	}
+	return 0
+}
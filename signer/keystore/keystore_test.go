@@ -0,0 +1,133 @@
+package keystore_test
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	ethkeystore "github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/google/uuid"
+	factorysigner "github.com/openweb3-io/crosschain/factory/signer"
+	"github.com/openweb3-io/crosschain/signer/keystore"
+	xc "github.com/openweb3-io/crosschain/types"
+	"github.com/stretchr/testify/require"
+)
+
+const secp256k1Secret = "289c2857d4598e37fb9647507e47a309d6133539bf21a8b9cb6df88fd5232032"
+
+var light = keystore.WithScrypt(keystore.LightScryptN, keystore.LightScryptP)
+
+func TestEthereumCompatible(t *testing.T) {
+	// written by us, read by geth
+	file, err := keystore.EncryptSecret(secp256k1Secret, xc.K256Keccak, "passphrase", light)
+	require.NoError(t, err)
+	require.Empty(t, file.KeyType)
+	bz, err := json.Marshal(file)
+	require.NoError(t, err)
+	key, err := ethkeystore.DecryptKey(bz, "passphrase")
+	require.NoError(t, err)
+	require.Equal(t, secp256k1Secret, hex.EncodeToString(crypto.FromECDSA(key.PrivateKey)))
+
+	// written by geth, read by us
+	privateKey, err := crypto.HexToECDSA(secp256k1Secret)
+	require.NoError(t, err)
+	bz, err = ethkeystore.EncryptKey(&ethkeystore.Key{
+		Id:         uuid.New(),
+		Address:    crypto.PubkeyToAddress(privateKey.PublicKey),
+		PrivateKey: privateKey,
+	}, "passphrase", keystore.LightScryptN, keystore.LightScryptP)
+	require.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "geth.json")
+	require.NoError(t, os.WriteFile(path, bz, 0o600))
+	secret, err := keystore.DecryptFile(path, "passphrase")
+	require.NoError(t, err)
+	require.Equal(t, secp256k1Secret, secret)
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	seed := crypto.Keccak256([]byte("seed"))
+	for _, v := range []struct {
+		secret   string
+		alg      xc.SignatureType
+		keyType  keystore.KeyType
+		expected string
+	}{
+		{secret: "0x" + secp256k1Secret, alg: xc.K256Sha256, keyType: keystore.Secp256k1, expected: secp256k1Secret},
+		{secret: hex.EncodeToString(seed), alg: xc.Ed255, keyType: keystore.Ed25519, expected: hex.EncodeToString(seed)},
+		{
+			secret:   hex.EncodeToString(ed25519.NewKeyFromSeed(seed)),
+			alg:      xc.Ed255,
+			keyType:  keystore.Ed25519,
+			expected: hex.EncodeToString(ed25519.NewKeyFromSeed(seed)),
+		},
+		{
+			secret:   "  test test test test test test test test test test test junk\n",
+			alg:      xc.K256Keccak,
+			keyType:  keystore.Mnemonic,
+			expected: "test test test test test test test test test test test junk",
+		},
+	} {
+		file, err := keystore.EncryptSecret(v.secret, v.alg, "passphrase", light, keystore.WithAddress(xc.SOL, "address"))
+		require.NoError(t, err)
+		require.Equal(t, v.keyType, file.Type())
+		require.Equal(t, "address", file.Address)
+		require.Equal(t, xc.SOL, file.Chain)
+		if v.keyType != keystore.Secp256k1 {
+			require.Equal(t, "aes-256-gcm", file.Crypto.Cipher)
+		}
+
+		path := filepath.Join(t.TempDir(), "key.json")
+		require.NoError(t, file.Save(path))
+		require.ErrorIs(t, file.Save(path), os.ErrExist)
+		info, err := os.Stat(path)
+		require.NoError(t, err)
+		require.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+		secret, err := keystore.DecryptFile(path, "passphrase")
+		require.NoError(t, err)
+		require.Equal(t, v.expected, secret)
+
+		_, err = keystore.DecryptFile(path, "wrong")
+		require.ErrorContains(t, err, ethkeystore.ErrDecrypt.Error())
+	}
+}
+
+func TestDecryptedSecretSigns(t *testing.T) {
+	seed := crypto.Keccak256([]byte("seed"))
+	file, err := keystore.EncryptSecret(hex.EncodeToString(seed), xc.Ed255, "passphrase", light)
+	require.NoError(t, err)
+	secret, err := file.DecryptSecret("passphrase")
+	require.NoError(t, err)
+
+	expected, err := factorysigner.New(xc.BlockchainSolana, hex.EncodeToString(seed), nil)
+	require.NoError(t, err)
+	decrypted, err := factorysigner.New(xc.BlockchainSolana, secret, nil)
+	require.NoError(t, err)
+	require.Equal(t, expected.MustPublicKey(), decrypted.MustPublicKey())
+}
+
+func TestEncryptErrors(t *testing.T) {
+	_, err := keystore.EncryptSecret("1234", xc.K256Keccak, "passphrase", light)
+	require.ErrorContains(t, err, "invalid secp256k1 key")
+	_, err = keystore.EncryptSecret("1234", xc.Ed255, "passphrase", light)
+	require.ErrorContains(t, err, "ed25519 key")
+	_, err = keystore.Encrypt("unknown", []byte{1}, "passphrase", light)
+	require.ErrorContains(t, err, "unsupported key type")
+	_, err = keystore.Encrypt(keystore.Secp256k1, []byte{1}, "passphrase", keystore.WithScrypt(1000, 1))
+	require.ErrorContains(t, err, "power of 2")
+}
+
+func TestLoadErrors(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "v1.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"version":1}`), 0o600))
+	_, err := keystore.Load(path)
+	require.ErrorContains(t, err, "unsupported keystore version")
+
+	_, err = keystore.Load(filepath.Join(dir, "missing.json"))
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
@@ -0,0 +1,153 @@
+//go:build pkcs11 && cgo
+
+package pkcs11
+
+import (
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	p11 "github.com/miekg/pkcs11"
+	xc "github.com/openweb3-io/crosschain/types"
+)
+
+// EdDSA mechanism of PKCS#11 3.0, which github.com/miekg/pkcs11 does not define
+const CKM_EDDSA = 0x1057
+
+// A token of a PKCS#11 module, e.g. /usr/lib/softhsm/libsofthsm2.so, with a logged in session.
+// PKCS#11 sessions may not be used concurrently, so calls are serialized.
+type Module struct {
+	lock    sync.Mutex
+	ctx     *p11.Ctx
+	session p11.SessionHandle
+}
+
+var _ Token = &Module{}
+
+// Open loads the module, and logs into the token with the given label as the user
+func Open(modulePath string, tokenLabel string, pin string) (*Module, error) {
+	ctx := p11.New(modulePath)
+	if ctx == nil {
+		return nil, fmt.Errorf("could not load pkcs11 module %s", modulePath)
+	}
+	if err := ctx.Initialize(); err != nil {
+		ctx.Destroy()
+		return nil, fmt.Errorf("could not initialize pkcs11 module: %v", err)
+	}
+	m := &Module{ctx: ctx}
+	if err := m.login(tokenLabel, pin); err != nil {
+		m.finalize()
+		return nil, err
+	}
+	return m, nil
+}
+
+func (m *Module) login(tokenLabel string, pin string) error {
+	slots, err := m.ctx.GetSlotList(true)
+	if err != nil {
+		return err
+	}
+	for _, slot := range slots {
+		info, err := m.ctx.GetTokenInfo(slot)
+		if err != nil {
+			return err
+		}
+		if strings.TrimSpace(info.Label) != tokenLabel {
+			continue
+		}
+		m.session, err = m.ctx.OpenSession(slot, p11.CKF_SERIAL_SESSION)
+		if err != nil {
+			return fmt.Errorf("could not open session: %v", err)
+		}
+		if err := m.ctx.Login(m.session, p11.CKU_USER, pin); err != nil {
+			m.ctx.CloseSession(m.session)
+			return fmt.Errorf("could not log into token %s: %v", tokenLabel, err)
+		}
+		return nil
+	}
+	return fmt.Errorf("token %s not found", tokenLabel)
+}
+
+// Close logs out and unloads the module
+func (m *Module) Close() error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	_ = m.ctx.Logout(m.session)
+	_ = m.ctx.CloseSession(m.session)
+	m.finalize()
+	return nil
+}
+
+func (m *Module) finalize() {
+	_ = m.ctx.Finalize()
+	m.ctx.Destroy()
+}
+
+func (m *Module) PublicKey(label string, alg xc.SignatureType) ([]byte, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	handle, err := m.findKey(p11.CKO_PUBLIC_KEY, label)
+	if err != nil {
+		return nil, err
+	}
+	attrs, err := m.ctx.GetAttributeValue(m.session, handle, []*p11.Attribute{
+		p11.NewAttribute(p11.CKA_EC_POINT, nil),
+	})
+	if err != nil {
+		return nil, err
+	}
+	// the point is wrapped in a DER octet string
+	point := []byte{}
+	if _, err := asn1.Unmarshal(attrs[0].Value, &point); err != nil {
+		return nil, fmt.Errorf("invalid EC point of %s: %v", label, err)
+	}
+	return point, nil
+}
+
+func (m *Module) Sign(label string, alg xc.SignatureType, data []byte) ([]byte, error) {
+	var mechanism uint
+	switch alg {
+	case xc.K256Keccak, xc.K256Sha256:
+		mechanism = p11.CKM_ECDSA
+	case xc.Ed255:
+		mechanism = CKM_EDDSA
+	default:
+		return nil, fmt.Errorf("unsupported signing alg: %v", alg)
+	}
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	handle, err := m.findKey(p11.CKO_PRIVATE_KEY, label)
+	if err != nil {
+		return nil, err
+	}
+	if err := m.ctx.SignInit(m.session, []*p11.Mechanism{p11.NewMechanism(mechanism, nil)}, handle); err != nil {
+		return nil, err
+	}
+	return m.ctx.Sign(m.session, data)
+}
+
+func (m *Module) findKey(class uint, label string) (p11.ObjectHandle, error) {
+	template := []*p11.Attribute{
+		p11.NewAttribute(p11.CKA_CLASS, class),
+		p11.NewAttribute(p11.CKA_LABEL, label),
+	}
+	if err := m.ctx.FindObjectsInit(m.session, template); err != nil {
+		return 0, err
+	}
+	handles, _, err := m.ctx.FindObjects(m.session, 2)
+	if finalErr := m.ctx.FindObjectsFinal(m.session); err == nil {
+		err = finalErr
+	}
+	if err != nil {
+		return 0, err
+	}
+	if len(handles) == 0 {
+		return 0, errors.New("no key found with label " + label)
+	}
+	if len(handles) > 1 {
+		return 0, errors.New("more than one key found with label " + label)
+	}
+	return handles[0], nil
+}
@@ -0,0 +1,153 @@
+//go:build pkcs11 && cgo
+
+package pkcs11_test
+
+import (
+	"context"
+	"crypto/ed25519"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	p11 "github.com/miekg/pkcs11"
+	"github.com/openweb3-io/crosschain/signer/pkcs11"
+	xc "github.com/openweb3-io/crosschain/types"
+	"github.com/stretchr/testify/require"
+)
+
+// EdDSA key generation mechanism of PKCS#11 3.0
+const CKM_EC_EDWARDS_KEY_PAIR_GEN = 0x1055
+
+var (
+	// DER encoded object identifiers of the curves
+	secp256k1Params = []byte{0x06, 0x05, 0x2b, 0x81, 0x04, 0x00, 0x0a}
+	ed25519Params   = []byte{0x06, 0x03, 0x2b, 0x65, 0x70}
+)
+
+func openModule(t *testing.T) (string, string, string) {
+	modulePath := os.Getenv("PKCS11_MODULE")
+	if modulePath == "" {
+		t.Skip("PKCS11_MODULE is not set")
+	}
+	return modulePath, os.Getenv("PKCS11_TOKEN"), os.Getenv("PKCS11_PIN")
+}
+
+// Generates key pairs on the token with a raw session, as Module only uses existing keys.  The
+// session is closed before Module is opened, as a module may only be initialized once at a time.
+func generateKeys(t *testing.T, modulePath, tokenLabel, pin, label string) {
+	withSession(t, modulePath, tokenLabel, pin, func(ctx *p11.Ctx, session p11.SessionHandle) {
+		for _, key := range []struct {
+			mechanism uint
+			params    []byte
+			label     string
+		}{
+			{p11.CKM_EC_KEY_PAIR_GEN, secp256k1Params, label + "-secp256k1"},
+			{CKM_EC_EDWARDS_KEY_PAIR_GEN, ed25519Params, label + "-ed25519"},
+		} {
+			_, _, err := ctx.GenerateKeyPair(session,
+				[]*p11.Mechanism{p11.NewMechanism(key.mechanism, nil)},
+				[]*p11.Attribute{
+					p11.NewAttribute(p11.CKA_TOKEN, true),
+					p11.NewAttribute(p11.CKA_VERIFY, true),
+					p11.NewAttribute(p11.CKA_EC_PARAMS, key.params),
+					p11.NewAttribute(p11.CKA_LABEL, key.label),
+				},
+				[]*p11.Attribute{
+					p11.NewAttribute(p11.CKA_TOKEN, true),
+					p11.NewAttribute(p11.CKA_PRIVATE, true),
+					p11.NewAttribute(p11.CKA_SIGN, true),
+					p11.NewAttribute(p11.CKA_LABEL, key.label),
+				},
+			)
+			require.NoError(t, err, key.label)
+		}
+	})
+}
+
+func destroyKeys(t *testing.T, modulePath, tokenLabel, pin, label string) {
+	withSession(t, modulePath, tokenLabel, pin, func(ctx *p11.Ctx, session p11.SessionHandle) {
+		for _, keyLabel := range []string{label + "-secp256k1", label + "-ed25519"} {
+			require.NoError(t, ctx.FindObjectsInit(session, []*p11.Attribute{p11.NewAttribute(p11.CKA_LABEL, keyLabel)}))
+			handles, _, err := ctx.FindObjects(session, 10)
+			require.NoError(t, err)
+			require.NoError(t, ctx.FindObjectsFinal(session))
+			for _, handle := range handles {
+				require.NoError(t, ctx.DestroyObject(session, handle))
+			}
+		}
+	})
+}
+
+func withSession(t *testing.T, modulePath, tokenLabel, pin string, f func(*p11.Ctx, p11.SessionHandle)) {
+	ctx := p11.New(modulePath)
+	require.NotNil(t, ctx, "could not load %s", modulePath)
+	require.NoError(t, ctx.Initialize())
+	defer func() {
+		_ = ctx.Finalize()
+		ctx.Destroy()
+	}()
+	slots, err := ctx.GetSlotList(true)
+	require.NoError(t, err)
+	for _, slot := range slots {
+		info, err := ctx.GetTokenInfo(slot)
+		require.NoError(t, err)
+		if strings.TrimSpace(info.Label) != tokenLabel {
+			continue
+		}
+		session, err := ctx.OpenSession(slot, p11.CKF_SERIAL_SESSION|p11.CKF_RW_SESSION)
+		require.NoError(t, err)
+		defer ctx.CloseSession(session)
+		require.NoError(t, ctx.Login(session, p11.CKU_USER, pin))
+		defer ctx.Logout(session)
+		f(ctx, session)
+		return
+	}
+	require.Fail(t, "token not found", tokenLabel)
+}
+
+// Runs against a real PKCS#11 module, e.g. SoftHSM:
+//
+//	softhsm2-util --init-token --free --label test --pin 1234 --so-pin 1234
+//	PKCS11_MODULE=/usr/lib/softhsm/libsofthsm2.so PKCS11_TOKEN=test PKCS11_PIN=1234 \
+//		go test -tags pkcs11 ./signer/pkcs11/
+func TestModule(t *testing.T) {
+	modulePath, tokenLabel, pin := openModule(t)
+	label := fmt.Sprintf("crosschain-test-%d", time.Now().UnixNano())
+	generateKeys(t, modulePath, tokenLabel, pin, label)
+	defer destroyKeys(t, modulePath, tokenLabel, pin, label)
+
+	module, err := pkcs11.Open(modulePath, tokenLabel, pin)
+	require.NoError(t, err)
+	defer module.Close()
+
+	digest := crypto.Keccak256([]byte("payload"))
+	for _, blockchain := range []xc.Blockchain{xc.BlockchainEVM, xc.BlockchainCosmos} {
+		hsmSigner, err := pkcs11.NewSigner(module, label+"-secp256k1", blockchain)
+		require.NoError(t, err)
+		publicKey, err := hsmSigner.PublicKey(context.Background())
+		require.NoError(t, err)
+		sig, err := hsmSigner.Sign(digest)
+		require.NoError(t, err)
+		require.Len(t, sig, 65)
+
+		recovered, err := crypto.SigToPub(digest, sig)
+		require.NoError(t, err)
+		if blockchain.PublicKeyFormat() == xc.Compressed {
+			require.Equal(t, publicKey, crypto.CompressPubkey(recovered), blockchain)
+		} else {
+			require.Equal(t, publicKey, crypto.FromECDSAPub(recovered), blockchain)
+		}
+	}
+
+	hsmSigner, err := pkcs11.NewSigner(module, label+"-ed25519", xc.BlockchainSolana)
+	require.NoError(t, err)
+	publicKey, err := hsmSigner.PublicKey(context.Background())
+	require.NoError(t, err)
+	require.Len(t, publicKey, ed25519.PublicKeySize)
+	sig, err := hsmSigner.Sign([]byte("any message"))
+	require.NoError(t, err)
+	require.True(t, ed25519.Verify(publicKey, []byte("any message"), sig))
+}
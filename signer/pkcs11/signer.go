@@ -0,0 +1,203 @@
+// Package pkcs11 signs with keys that never leave an HSM, through the PKCS#11 interface of the HSM.
+//
+// The signing logic works with any Token.  The PKCS#11 token itself, Module, needs cgo and is only
+// built with the pkcs11 build tag, e.g. `go build -tags pkcs11`.  SoftHSM can stand in for an HSM
+// locally, see TestModule.
+package pkcs11
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/openweb3-io/crosschain/signer"
+	xc "github.com/openweb3-io/crosschain/types"
+)
+
+// The keys of an HSM, found by their label
+type Token interface {
+	// The public key of a key pair: the EC point of a secp256k1 key (compressed or not), or the
+	// 32 bytes of an ed25519 key
+	PublicKey(label string, alg xc.SignatureType) ([]byte, error)
+	// Sign with the private key of a key pair: plain ECDSA over a digest for secp256k1 (CKM_ECDSA), or
+	// EdDSA over the message for ed25519 (CKM_EDDSA).  ECDSA signatures may be r || s or DER encoded.
+	Sign(label string, alg xc.SignatureType, data []byte) ([]byte, error)
+}
+
+// Signs with a key of an HSM, returning signatures in the same form as factory/signer, i.e. the
+// 65 byte recoverable form for secp256k1.
+type Signer struct {
+	token      Token
+	label      string
+	blockchain xc.Blockchain
+
+	lock sync.Mutex
+	// the uncompressed public key, cached to compute recovery ids
+	publicKey []byte
+}
+
+var _ signer.Signer = &Signer{}
+
+// NewSigner signs with the key pair of the given label, using the signature algorithm of the blockchain
+func NewSigner(token Token, label string, blockchain xc.Blockchain) (*Signer, error) {
+	switch alg := blockchain.SignatureAlgorithm(); alg {
+	case xc.K256Keccak, xc.K256Sha256, xc.Ed255:
+	default:
+		return nil, fmt.Errorf("unsupported signing alg: %v", alg)
+	}
+	if label == "" {
+		return nil, errors.New("key label is required")
+	}
+	return &Signer{
+		token:      token,
+		label:      label,
+		blockchain: blockchain,
+	}, nil
+}
+
+// NewSignerCreator creates signers for a network of a signer.SignerProvider, where the key given to
+// the provider is the label of the key pair in the HSM.
+func NewSignerCreator(token Token, blockchain xc.Blockchain) signer.SignerCreator {
+	return func(ctx context.Context, appId, key string) (signer.Signer, error) {
+		return NewSigner(token, key, blockchain)
+	}
+}
+
+func (s *Signer) PublicKey(ctx context.Context) ([]byte, error) {
+	if s.blockchain.SignatureAlgorithm() == xc.Ed255 {
+		return s.ed25519PublicKey()
+	}
+	publicKey, err := s.secp256k1PublicKey()
+	if err != nil {
+		return nil, err
+	}
+	if s.blockchain.PublicKeyFormat() == xc.Compressed {
+		pub, err := crypto.UnmarshalPubkey(publicKey)
+		if err != nil {
+			return nil, err
+		}
+		return crypto.CompressPubkey(pub), nil
+	}
+	return publicKey, nil
+}
+
+func (s *Signer) SharedKey(theirKey []byte) ([]byte, error) {
+	return nil, errors.New("shared key is not supported by the pkcs11 signer")
+}
+
+func (s *Signer) Sign(payload xc.TxDataToSign) (xc.TxSignature, error) {
+	alg := s.blockchain.SignatureAlgorithm()
+	switch alg {
+	case xc.Ed255:
+		sig, err := s.token.Sign(s.label, alg, payload)
+		if err != nil {
+			return nil, err
+		}
+		if len(sig) != ed25519.SignatureSize {
+			return nil, fmt.Errorf("expected ed25519 signature to be %d bytes, got %d", ed25519.SignatureSize, len(sig))
+		}
+		return xc.TxSignature(sig), nil
+	default:
+		if len(payload) != 32 {
+			return nil, fmt.Errorf("expected a 32 byte digest to sign, got %d bytes", len(payload))
+		}
+		publicKey, err := s.secp256k1PublicKey()
+		if err != nil {
+			return nil, err
+		}
+		sig, err := s.token.Sign(s.label, alg, payload)
+		if err != nil {
+			return nil, err
+		}
+		return NewRecoverableSignature(sig, payload, publicKey)
+	}
+}
+
+func (s *Signer) ed25519PublicKey() ([]byte, error) {
+	publicKey, err := s.token.PublicKey(s.label, xc.Ed255)
+	if err != nil {
+		return nil, err
+	}
+	if len(publicKey) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("expected ed25519 public key to be %d bytes, got %d", ed25519.PublicKeySize, len(publicKey))
+	}
+	return publicKey, nil
+}
+
+func (s *Signer) secp256k1PublicKey() ([]byte, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.publicKey != nil {
+		return s.publicKey, nil
+	}
+	point, err := s.token.PublicKey(s.label, s.blockchain.SignatureAlgorithm())
+	if err != nil {
+		return nil, err
+	}
+	var pub *ecdsa.PublicKey
+	if len(point) == 33 {
+		pub, err = crypto.DecompressPubkey(point)
+	} else {
+		pub, err = crypto.UnmarshalPubkey(point)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid secp256k1 public key for %s: %v", s.label, err)
+	}
+	s.publicKey = crypto.FromECDSAPub(pub)
+	return s.publicKey, nil
+}
+
+// NewRecoverableSignature turns an ECDSA signature over a digest, as r || s or DER encoded, into the
+// 65 byte r || s || v form used by EVM, Tron and Cosmos.  S is made low as EIP-2 requires, and the
+// recovery id v is found by recovering the uncompressed public key.
+func NewRecoverableSignature(sig []byte, digest []byte, publicKey []byte) (xc.TxSignature, error) {
+	r, sValue, err := parseECDSASignature(sig)
+	if err != nil {
+		return nil, err
+	}
+	n := crypto.S256().Params().N
+	if r.Sign() <= 0 || r.Cmp(n) >= 0 || sValue.Sign() <= 0 || sValue.Cmp(n) >= 0 {
+		return nil, errors.New("ecdsa signature is out of range")
+	}
+	halfN := new(big.Int).Rsh(n, 1)
+	if sValue.Cmp(halfN) > 0 {
+		sValue = new(big.Int).Sub(n, sValue)
+	}
+
+	recoverable := make([]byte, 65)
+	r.FillBytes(recoverable[0:32])
+	sValue.FillBytes(recoverable[32:64])
+	for v := byte(0); v < 2; v++ {
+		recoverable[64] = v
+		recovered, err := crypto.Ecrecover(digest, recoverable)
+		if err == nil && bytes.Equal(recovered, publicKey) {
+			return xc.TxSignature(recoverable), nil
+		}
+	}
+	return nil, errors.New("signature does not recover to the public key of the signer")
+}
+
+func parseECDSASignature(sig []byte) (*big.Int, *big.Int, error) {
+	if len(sig) == 64 {
+		return new(big.Int).SetBytes(sig[:32]), new(big.Int).SetBytes(sig[32:]), nil
+	}
+	der := struct {
+		R *big.Int
+		S *big.Int
+	}{}
+	rest, err := asn1.Unmarshal(sig, &der)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid ecdsa signature: %v", err)
+	}
+	if len(rest) > 0 {
+		return nil, nil, errors.New("invalid ecdsa signature: trailing data")
+	}
+	return der.R, der.S, nil
+}
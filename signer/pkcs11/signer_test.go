@@ -0,0 +1,154 @@
+package pkcs11_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"encoding/asn1"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	factorysigner "github.com/openweb3-io/crosschain/factory/signer"
+	"github.com/openweb3-io/crosschain/signer"
+	"github.com/openweb3-io/crosschain/signer/pkcs11"
+	xc "github.com/openweb3-io/crosschain/types"
+	"github.com/stretchr/testify/require"
+)
+
+const secret = "a3e4de6e2a1d5fa3e4c6f0ad5e2bd4e0e6b7ac6d1ac4f1d3c0d5b7a9e8f6c4d2"
+
+// Stands in for an HSM, signing the way tokens do: ECDSA without a recovery id, as DER or r || s,
+// with S not necessarily low.
+type softToken struct {
+	ecdsaKey   *ecdsa.PrivateKey
+	ed25519Key ed25519.PrivateKey
+	der        bool
+	highS      bool
+}
+
+var _ pkcs11.Token = &softToken{}
+
+func newSoftToken(t *testing.T) *softToken {
+	ecdsaKey, err := crypto.HexToECDSA(secret)
+	require.NoError(t, err)
+	seed := crypto.Keccak256([]byte(secret))
+	return &softToken{ecdsaKey: ecdsaKey, ed25519Key: ed25519.NewKeyFromSeed(seed)}
+}
+
+func (t *softToken) PublicKey(label string, alg xc.SignatureType) ([]byte, error) {
+	if label != "key" {
+		return nil, errors.New("no key found with label " + label)
+	}
+	if alg == xc.Ed255 {
+		return t.ed25519Key.Public().(ed25519.PublicKey), nil
+	}
+	return crypto.CompressPubkey(&t.ecdsaKey.PublicKey), nil
+}
+
+func (t *softToken) Sign(label string, alg xc.SignatureType, data []byte) ([]byte, error) {
+	if alg == xc.Ed255 {
+		return ed25519.Sign(t.ed25519Key, data), nil
+	}
+	sig, err := crypto.Sign(data, t.ecdsaKey)
+	if err != nil {
+		return nil, err
+	}
+	r := new(big.Int).SetBytes(sig[:32])
+	s := new(big.Int).SetBytes(sig[32:64])
+	if t.highS {
+		s = new(big.Int).Sub(crypto.S256().Params().N, s)
+	}
+	if t.der {
+		return asn1.Marshal(struct{ R, S *big.Int }{r, s})
+	}
+	raw := make([]byte, 64)
+	r.FillBytes(raw[:32])
+	s.FillBytes(raw[32:])
+	return raw, nil
+}
+
+func TestSignSecp256k1(t *testing.T) {
+	token := newSoftToken(t)
+	digest := crypto.Keccak256([]byte("payload"))
+	for _, blockchain := range []xc.Blockchain{xc.BlockchainEVM, xc.BlockchainTron, xc.BlockchainCosmos, xc.BlockchainBtc} {
+		local, err := factorysigner.New(blockchain, secret, nil)
+		require.NoError(t, err)
+		expected, err := local.Sign(digest)
+		require.NoError(t, err)
+
+		hsmSigner, err := pkcs11.NewSigner(token, "key", blockchain)
+		require.NoError(t, err)
+		publicKey, err := hsmSigner.PublicKey(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, []byte(local.MustPublicKey()), publicKey, blockchain)
+
+		for _, format := range []struct{ der, highS bool }{{false, false}, {true, false}, {false, true}, {true, true}} {
+			token.der = format.der
+			token.highS = format.highS
+			sig, err := hsmSigner.Sign(digest)
+			require.NoError(t, err)
+			require.Equal(t, expected, sig, blockchain)
+		}
+	}
+}
+
+func TestSignEd25519(t *testing.T) {
+	token := newSoftToken(t)
+	hsmSigner, err := pkcs11.NewSigner(token, "key", xc.BlockchainSolana)
+	require.NoError(t, err)
+	publicKey, err := hsmSigner.PublicKey(context.Background())
+	require.NoError(t, err)
+	require.Len(t, publicKey, ed25519.PublicKeySize)
+
+	sig, err := hsmSigner.Sign([]byte("any message"))
+	require.NoError(t, err)
+	require.True(t, ed25519.Verify(publicKey, []byte("any message"), sig))
+}
+
+func TestNewRecoverableSignatureErrors(t *testing.T) {
+	token := newSoftToken(t)
+	digest := crypto.Keccak256([]byte("payload"))
+	sig, err := token.Sign("key", xc.K256Keccak, digest)
+	require.NoError(t, err)
+
+	// a different key
+	other, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	_, err = pkcs11.NewRecoverableSignature(sig, digest, crypto.FromECDSAPub(&other.PublicKey))
+	require.ErrorContains(t, err, "does not recover")
+
+	_, err = pkcs11.NewRecoverableSignature([]byte{0x30, 0x01}, digest, crypto.FromECDSAPub(&token.ecdsaKey.PublicKey))
+	require.ErrorContains(t, err, "invalid ecdsa signature")
+	_, err = pkcs11.NewRecoverableSignature(make([]byte, 64), digest, crypto.FromECDSAPub(&token.ecdsaKey.PublicKey))
+	require.ErrorContains(t, err, "out of range")
+}
+
+func TestSignerErrors(t *testing.T) {
+	token := newSoftToken(t)
+	_, err := pkcs11.NewSigner(token, "key", xc.Blockchain("unknown"))
+	require.Error(t, err)
+	_, err = pkcs11.NewSigner(token, "", xc.BlockchainEVM)
+	require.Error(t, err)
+
+	hsmSigner, err := pkcs11.NewSigner(token, "key", xc.BlockchainEVM)
+	require.NoError(t, err)
+	_, err = hsmSigner.Sign([]byte("not a digest"))
+	require.ErrorContains(t, err, "32 byte digest")
+
+	hsmSigner, err = pkcs11.NewSigner(token, "missing", xc.BlockchainEVM)
+	require.NoError(t, err)
+	_, err = hsmSigner.Sign(make([]byte, 32))
+	require.ErrorContains(t, err, "no key found")
+}
+
+func TestSignerProvider(t *testing.T) {
+	provider := signer.NewSignerProvider()
+	provider.Register(string(xc.ETH), pkcs11.NewSignerCreator(newSoftToken(t), xc.BlockchainEVM))
+	hsmSigner, err := provider.Provide(context.Background(), "app", string(xc.ETH), "key")
+	require.NoError(t, err)
+	sig, err := hsmSigner.Sign(make([]byte, 32))
+	require.NoError(t, err)
+	require.Len(t, sig, 65)
+}
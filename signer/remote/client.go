@@ -0,0 +1,237 @@
+package remote
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/openweb3-io/crosschain/signer"
+	xc "github.com/openweb3-io/crosschain/types"
+)
+
+// How long a request to the signing service may take, unless another http client is set
+const DefaultTimeout = 30 * time.Second
+
+// Signs with a key held by a signing service
+type Signer struct {
+	url        string
+	key        string
+	metadata   Metadata
+	httpClient *http.Client
+	tlsConfig  *tls.Config
+}
+
+var _ signer.Signer = &Signer{}
+
+type Option func(s *Signer) error
+
+// The http client to send requests with, e.g. to set a timeout or a transport.  A TLS config set with
+// WithTLSConfig is applied to a copy of its transport.
+func WithHTTPClient(client *http.Client) Option {
+	return func(s *Signer) error {
+		if client == nil {
+			return errors.New("http client must not be nil")
+		}
+		s.httpClient = client
+		return nil
+	}
+}
+
+// Authenticate with a client certificate, see LoadClientTLSConfig
+func WithTLSConfig(cfg *tls.Config) Option {
+	return func(s *Signer) error {
+		if cfg == nil {
+			return errors.New("tls config must not be nil")
+		}
+		s.tlsConfig = cfg
+		return nil
+	}
+}
+
+func WithAppID(appID string) Option {
+	return func(s *Signer) error {
+		s.metadata.AppID = appID
+		return nil
+	}
+}
+
+func WithChain(chain xc.NativeAsset) Option {
+	return func(s *Signer) error {
+		s.metadata.Chain = chain
+		return nil
+	}
+}
+
+// NewSigner signs with the key of the given id in the signing service at url
+func NewSigner(url string, key string, options ...Option) (*Signer, error) {
+	if url == "" {
+		return nil, errors.New("url of the signing service is required")
+	}
+	if key == "" {
+		return nil, errors.New("key is required")
+	}
+	s := &Signer{
+		url:        strings.TrimSuffix(url, "/"),
+		key:        key,
+		httpClient: &http.Client{Timeout: DefaultTimeout},
+	}
+	for _, opt := range options {
+		if err := opt(s); err != nil {
+			return nil, err
+		}
+	}
+	if s.tlsConfig != nil {
+		if err := s.applyTLSConfig(); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// Set the TLS config on a copy of the http client and its transport, leaving the one given untouched
+func (s *Signer) applyTLSConfig() error {
+	var transport *http.Transport
+	switch current := s.httpClient.Transport.(type) {
+	case nil:
+		transport = http.DefaultTransport.(*http.Transport).Clone()
+	case *http.Transport:
+		transport = current.Clone()
+	default:
+		return fmt.Errorf("cannot set the tls config on a %T transport", current)
+	}
+	transport.TLSClientConfig = s.tlsConfig
+	client := *s.httpClient
+	client.Transport = transport
+	s.httpClient = &client
+	return nil
+}
+
+// NewSignerCreator creates remote signers for a network of a signer.SignerProvider, with the app id
+// and key given to the provider.
+func NewSignerCreator(url string, chain xc.NativeAsset, options ...Option) signer.SignerCreator {
+	return func(ctx context.Context, appId, key string) (signer.Signer, error) {
+		options := append([]Option{WithChain(chain), WithAppID(appId)}, options...)
+		return NewSigner(url, key, options...)
+	}
+}
+
+// WithTxHash returns a copy of the signer that tags its requests with the hash of the transaction
+// being signed.
+func (s *Signer) WithTxHash(hash xc.TxHash) *Signer {
+	copy := *s
+	copy.metadata.TxHash = hash
+	return &copy
+}
+
+func (s *Signer) PublicKey(ctx context.Context) ([]byte, error) {
+	resp := &PublicKeyResponse{}
+	err := s.post(ctx, PathPublicKey, &PublicKeyRequest{Key: s.key, Metadata: s.metadata}, resp)
+	if err != nil {
+		return nil, err
+	}
+	return decodeHex("public key", resp.PublicKey)
+}
+
+func (s *Signer) SharedKey(theirKey []byte) ([]byte, error) {
+	req := &SharedKeyRequest{Key: s.key, TheirKey: hex.EncodeToString(theirKey), Metadata: s.metadata}
+	resp := &SharedKeyResponse{}
+	if err := s.post(context.Background(), PathSharedKey, req, resp); err != nil {
+		return nil, err
+	}
+	return decodeHex("shared key", resp.SharedKey)
+}
+
+// Sign with the algorithm of the chain
+func (s *Signer) Sign(payload xc.TxDataToSign) (xc.TxSignature, error) {
+	return s.SignWithType("", payload)
+}
+
+// Sign with a specific algorithm, for transactions that mix signature types
+func (s *Signer) SignWithType(alg xc.SignatureType, payload xc.TxDataToSign) (xc.TxSignature, error) {
+	req := &SignRequest{Key: s.key, Payload: hex.EncodeToString(payload), Algorithm: alg, Metadata: s.metadata}
+	resp := &SignResponse{}
+	if err := s.post(context.Background(), PathSign, req, resp); err != nil {
+		return nil, err
+	}
+	sig, err := decodeHex("signature", resp.Signature)
+	if err != nil {
+		return nil, err
+	}
+	return xc.TxSignature(sig), nil
+}
+
+// Sign all of the sighashes of a transaction, tagging each request with the hash of the transaction.
+func (s *Signer) SignTx(tx xc.Tx) ([]xc.TxSignature, error) {
+	data, err := tx.Sighashes()
+	if err != nil {
+		return nil, err
+	}
+	types := make([]xc.SignatureType, len(data))
+	if withTypes, ok := tx.(xc.TxWithSignatureTypes); ok {
+		types, err = withTypes.SignatureTypes()
+		if err != nil {
+			return nil, err
+		}
+		if len(types) != len(data) {
+			return nil, fmt.Errorf("expected %d signature types, got %d", len(data), len(types))
+		}
+	}
+	txSigner := s.WithTxHash(tx.Hash())
+	signatures := make([]xc.TxSignature, len(data))
+	for i, d := range data {
+		sig, err := txSigner.SignWithType(types[i], d)
+		if err != nil {
+			return nil, err
+		}
+		signatures[i] = sig
+	}
+	return signatures, nil
+}
+
+func (s *Signer) post(ctx context.Context, path string, req any, resp any) error {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url+path, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpResp, err := s.httpClient.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("could not reach signing service: %v", err)
+	}
+	defer httpResp.Body.Close()
+	respBody, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return err
+	}
+	if httpResp.StatusCode != http.StatusOK {
+		errResp := &ErrorResponse{}
+		if err := json.Unmarshal(respBody, errResp); err != nil || errResp.Error == "" {
+			return fmt.Errorf("signing service returned %d: %s", httpResp.StatusCode, string(respBody))
+		}
+		return fmt.Errorf("signing service returned %d: %s", httpResp.StatusCode, errResp.Error)
+	}
+	return json.Unmarshal(respBody, resp)
+}
+
+func decodeHex(name string, value string) ([]byte, error) {
+	bz, err := hex.DecodeString(value)
+	if err != nil {
+		return nil, fmt.Errorf("signing service returned an invalid %s: %v", name, err)
+	}
+	if len(bz) == 0 {
+		return nil, fmt.Errorf("signing service returned an empty %s", name)
+	}
+	return bz, nil
+}
@@ -0,0 +1,70 @@
+// Package remote signs with keys held by a separate signing service, so services that build and
+// broadcast transactions never hold private keys.
+//
+// The protocol is JSON over HTTPS, where both sides should authenticate with certificates (mutual TLS).
+// Every request is a POST with a JSON body, and byte strings are hex encoded:
+//
+//	POST /v1/public-key  {"key": "...", "metadata": {...}}
+//	                  -> {"public_key": "02ab..."}
+//	POST /v1/sign        {"key": "...", "payload": "1f2e...", "algorithm": "schnorr", "metadata": {...}}
+//	                  -> {"signature": "9c8d..."}
+//	POST /v1/shared-key  {"key": "...", "their_key": "5a6b...", "metadata": {...}}
+//	                  -> {"shared_key": "7e8f..."}
+//
+// The key is an identifier of a key in the signing service, never the key itself.  The algorithm
+// is optional and defaults to the one of the chain.  The metadata is for the audit log of the signing
+// service: {"app_id": "...", "chain": "ETH", "tx_hash": "0x..."}.
+//
+// A request that fails has a status other than 200 and the body {"error": "..."}.
+package remote
+
+import (
+	xc "github.com/openweb3-io/crosschain/types"
+)
+
+const (
+	PathPublicKey = "/v1/public-key"
+	PathSign      = "/v1/sign"
+	PathSharedKey = "/v1/shared-key"
+)
+
+// Describes what a request is for, so the signing service can audit it
+type Metadata struct {
+	AppID  string         `json:"app_id,omitempty"`
+	Chain  xc.NativeAsset `json:"chain,omitempty"`
+	TxHash xc.TxHash      `json:"tx_hash,omitempty"`
+}
+
+type PublicKeyRequest struct {
+	Key      string   `json:"key"`
+	Metadata Metadata `json:"metadata"`
+}
+
+type PublicKeyResponse struct {
+	PublicKey string `json:"public_key"`
+}
+
+type SignRequest struct {
+	Key       string           `json:"key"`
+	Payload   string           `json:"payload"`
+	Algorithm xc.SignatureType `json:"algorithm,omitempty"`
+	Metadata  Metadata         `json:"metadata"`
+}
+
+type SignResponse struct {
+	Signature string `json:"signature"`
+}
+
+type SharedKeyRequest struct {
+	Key      string   `json:"key"`
+	TheirKey string   `json:"their_key"`
+	Metadata Metadata `json:"metadata"`
+}
+
+type SharedKeyResponse struct {
+	SharedKey string `json:"shared_key"`
+}
+
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
@@ -0,0 +1,264 @@
+package remote_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	evminput "github.com/openweb3-io/crosschain/blockchain/evm/tx_input"
+	"github.com/openweb3-io/crosschain/blockchain/ton"
+	xcbuilder "github.com/openweb3-io/crosschain/builder"
+	"github.com/openweb3-io/crosschain/factory/blockchains"
+	factorysigner "github.com/openweb3-io/crosschain/factory/signer"
+	"github.com/openweb3-io/crosschain/signer"
+	"github.com/openweb3-io/crosschain/signer/remote"
+	xc "github.com/openweb3-io/crosschain/types"
+	"github.com/stretchr/testify/suite"
+)
+
+const secret = "a3e4de6e2a1d5fa3e4c6f0ad5e2bd4e0e6b7ac6d1ac4f1d3c0d5b7a9e8f6c4d2"
+
+type RemoteTestSuite struct {
+	suite.Suite
+	dir       string
+	server    *httptest.Server
+	local     *factorysigner.Signer
+	auditLock sync.Mutex
+	audits    []*remote.AuditRecord
+}
+
+func TestRemote(t *testing.T) {
+	suite.Run(t, new(RemoteTestSuite))
+}
+
+func (s *RemoteTestSuite) SetupTest() {
+	require := s.Require()
+	s.dir = s.T().TempDir()
+	s.audits = nil
+
+	caKey, caCert := s.newCert("ca", nil, nil)
+	s.writePem("ca.pem", "CERTIFICATE", caCert.Raw)
+	serverKey, serverCert := s.newCert("signer", caKey, caCert)
+	s.writeKeyPair("server", serverKey, serverCert)
+	clientKey, clientCert := s.newCert("wallet-service", caKey, caCert)
+	s.writeKeyPair("client", clientKey, clientCert)
+
+	var err error
+	s.local, err = factorysigner.New(xc.BlockchainEVM, secret, nil)
+	require.NoError(err)
+	tonLocal, err := factorysigner.New(xc.BlockchainTon, secret, nil)
+	require.NoError(err)
+	server, err := remote.NewServer(
+		remote.NewStaticKeyResolver(map[string]*factorysigner.Signer{"hot-wallet": s.local, "ton-wallet": tonLocal}),
+		remote.WithAuditLogger(func(ctx context.Context, record *remote.AuditRecord) {
+			s.auditLock.Lock()
+			defer s.auditLock.Unlock()
+			s.audits = append(s.audits, record)
+		}),
+	)
+	require.NoError(err)
+	tlsConfig, err := remote.LoadServerTLSConfig(s.path("server.pem"), s.path("server.key"), s.path("ca.pem"))
+	require.NoError(err)
+	s.server = httptest.NewUnstartedServer(server)
+	s.server.TLS = tlsConfig
+	s.server.StartTLS()
+}
+
+func (s *RemoteTestSuite) TearDownTest() {
+	s.server.Close()
+}
+
+func (s *RemoteTestSuite) path(name string) string {
+	return filepath.Join(s.dir, name)
+}
+
+func (s *RemoteTestSuite) newCert(name string, parentKey *ecdsa.PrivateKey, parent *x509.Certificate) (*ecdsa.PrivateKey, *x509.Certificate) {
+	require := s.Require()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	if parent == nil {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+		parent = template
+		parentKey = key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	require.NoError(err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(err)
+	return key, cert
+}
+
+func (s *RemoteTestSuite) writePem(name string, blockType string, der []byte) {
+	err := os.WriteFile(s.path(name), pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0600)
+	s.Require().NoError(err)
+}
+
+func (s *RemoteTestSuite) writeKeyPair(name string, key *ecdsa.PrivateKey, cert *x509.Certificate) {
+	der, err := x509.MarshalECPrivateKey(key)
+	s.Require().NoError(err)
+	s.writePem(name+".key", "EC PRIVATE KEY", der)
+	s.writePem(name+".pem", "CERTIFICATE", cert.Raw)
+}
+
+func (s *RemoteTestSuite) newSigner(key string, options ...remote.Option) *remote.Signer {
+	require := s.Require()
+	tlsConfig, err := remote.LoadClientTLSConfig(s.path("client.pem"), s.path("client.key"), s.path("ca.pem"))
+	require.NoError(err)
+	options = append([]remote.Option{remote.WithTLSConfig(tlsConfig)}, options...)
+	remoteSigner, err := remote.NewSigner(s.server.URL, key, options...)
+	require.NoError(err)
+	return remoteSigner
+}
+
+func (s *RemoteTestSuite) TestPublicKey() {
+	require := s.Require()
+	remoteSigner := s.newSigner("hot-wallet")
+	publicKey, err := remoteSigner.PublicKey(context.Background())
+	require.NoError(err)
+	require.Equal([]byte(s.local.MustPublicKey()), publicKey)
+
+	require.Len(s.audits, 1)
+	require.Equal("wallet-service", s.audits[0].Client)
+	require.Equal(remote.PathPublicKey, s.audits[0].Operation)
+	require.Equal("hot-wallet", s.audits[0].Key)
+}
+
+func (s *RemoteTestSuite) TestSignTx() {
+	require := s.Require()
+	chain := &xc.ChainConfig{Chain: xc.ETH, Blockchain: xc.BlockchainEVM, ChainID: 1}
+	builder, err := blockchains.NewTxBuilder(chain)
+	require.NoError(err)
+	args, err := xcbuilder.NewTransferArgs("0x724435CC1B2821362c2CD425F2744Bd7347bf299", "0x3ad57b83B2E3dC5648F32e98e386935A9B10bb9F", xc.NewBigIntFromUint64(1000))
+	require.NoError(err)
+	tx, err := builder.NewTransfer(args, &evminput.TxInput{GasLimit: 21_000, GasFeeCap: xc.NewBigIntFromUint64(10)})
+	require.NoError(err)
+
+	remoteSigner := s.newSigner("hot-wallet", remote.WithAppID("payouts"), remote.WithChain(xc.ETH))
+	sigs, err := remoteSigner.SignTx(tx)
+	require.NoError(err)
+	expected, err := s.local.SignTx(tx)
+	require.NoError(err)
+	require.Equal(expected, sigs)
+
+	require.Len(s.audits, 1)
+	require.Equal(remote.PathSign, s.audits[0].Operation)
+	require.Equal(remote.Metadata{AppID: "payouts", Chain: xc.ETH, TxHash: tx.Hash()}, s.audits[0].Metadata)
+	require.Empty(s.audits[0].Error)
+}
+
+func (s *RemoteTestSuite) TestSignerProvider() {
+	require := s.Require()
+	tlsConfig, err := remote.LoadClientTLSConfig(s.path("client.pem"), s.path("client.key"), s.path("ca.pem"))
+	require.NoError(err)
+	provider := signer.NewSignerProvider()
+	provider.Register(string(xc.ETH), remote.NewSignerCreator(s.server.URL, xc.ETH, remote.WithTLSConfig(tlsConfig)))
+
+	remoteSigner, err := provider.Provide(context.Background(), "payouts", string(xc.ETH), "hot-wallet")
+	require.NoError(err)
+	payload := make([]byte, 32)
+	sig, err := remoteSigner.Sign(payload)
+	require.NoError(err)
+	expected, err := s.local.Sign(payload)
+	require.NoError(err)
+	require.Equal(expected, sig)
+	require.Equal(remote.Metadata{AppID: "payouts", Chain: xc.ETH}, s.audits[0].Metadata)
+}
+
+func (s *RemoteTestSuite) TestSharedKey() {
+	require := s.Require()
+	theirKey, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(err)
+	seed, err := hex.DecodeString(secret)
+	require.NoError(err)
+	expected, err := ton.NewLocalSigner(ed25519.NewKeyFromSeed(seed)).SharedKey(theirKey)
+	require.NoError(err)
+
+	sharedKey, err := s.newSigner("ton-wallet").SharedKey(theirKey)
+	require.NoError(err)
+	require.Equal(expected, sharedKey)
+	require.Equal(remote.PathSharedKey, s.audits[0].Operation)
+
+	_, err = s.newSigner("ton-wallet").SharedKey([]byte{1, 2, 3})
+	require.ErrorContains(err, "ed25519 public key")
+}
+
+func (s *RemoteTestSuite) TestErrors() {
+	require := s.Require()
+	_, err := s.newSigner("cold-wallet").Sign(make([]byte, 32))
+	require.ErrorContains(err, "unknown key cold-wallet")
+	require.Equal("unknown key cold-wallet", s.audits[0].Error)
+
+	_, err = s.newSigner("hot-wallet").SignWithType(xc.Ed255, make([]byte, 32))
+	require.Error(err)
+
+	_, err = s.newSigner("hot-wallet").SharedKey(make([]byte, 32))
+	require.ErrorContains(err, "not supported")
+
+	_, err = remote.NewSigner("", "hot-wallet")
+	require.Error(err)
+}
+
+func (s *RemoteTestSuite) TestClientCertificateRequired() {
+	require := s.Require()
+	tlsConfig, err := remote.LoadClientTLSConfig(s.path("client.pem"), s.path("client.key"), s.path("ca.pem"))
+	require.NoError(err)
+	tlsConfig.Certificates = nil
+	client := &http.Client{Transport: &http.Transport{TLSClientConfig: tlsConfig}}
+	remoteSigner, err := remote.NewSigner(s.server.URL, "hot-wallet", remote.WithHTTPClient(client))
+	require.NoError(err)
+	_, err = remoteSigner.PublicKey(context.Background())
+	require.ErrorContains(err, "could not reach signing service")
+	require.Empty(s.audits)
+}
+
+func (s *RemoteTestSuite) TestHTTPClientWithTLSConfig() {
+	require := s.Require()
+	tlsConfig, err := remote.LoadClientTLSConfig(s.path("client.pem"), s.path("client.key"), s.path("ca.pem"))
+	require.NoError(err)
+	transport := &http.Transport{}
+	client := &http.Client{Timeout: time.Minute, Transport: transport}
+
+	// the tls config applies whichever order the options are given in
+	for _, options := range [][]remote.Option{
+		{remote.WithHTTPClient(client), remote.WithTLSConfig(tlsConfig)},
+		{remote.WithTLSConfig(tlsConfig), remote.WithHTTPClient(client)},
+	} {
+		remoteSigner, err := remote.NewSigner(s.server.URL, "hot-wallet", options...)
+		require.NoError(err)
+		_, err = remoteSigner.PublicKey(context.Background())
+		require.NoError(err)
+	}
+	// the caller's client keeps its own transport
+	require.Same(transport, client.Transport)
+	if transport.TLSClientConfig != nil {
+		require.Empty(transport.TLSClientConfig.Certificates)
+	}
+
+	_, err = remote.NewSigner(s.server.URL, "hot-wallet", remote.WithHTTPClient(&http.Client{Transport: http.NewFileTransport(http.Dir("."))}), remote.WithTLSConfig(tlsConfig))
+	require.ErrorContains(err, "cannot set the tls config")
+}
@@ -0,0 +1,207 @@
+package remote
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	factorysigner "github.com/openweb3-io/crosschain/factory/signer"
+	xc "github.com/openweb3-io/crosschain/types"
+	"github.com/sirupsen/logrus"
+)
+
+// The largest request body the server accepts
+const MaxRequestSize = 1 << 20
+
+// Looks up the key of the given id, e.g. from an HSM or an encrypted keystore
+type KeyResolver func(ctx context.Context, key string, metadata *Metadata) (*factorysigner.Signer, error)
+
+// A request to the signing service, logged whether or not it succeeded
+type AuditRecord struct {
+	Time time.Time
+	// The common name of the client certificate, if any
+	Client    string
+	Operation string
+	Key       string
+	Metadata  Metadata
+	// Why the request failed, if it did
+	Error string
+}
+
+type AuditLogger func(ctx context.Context, record *AuditRecord)
+
+// Reference signing service, serving the protocol with keys of factory/signer.  It should be served
+// with LoadServerTLSConfig so only clients with a certificate may sign.
+type Server struct {
+	resolve KeyResolver
+	audit   AuditLogger
+	mux     *http.ServeMux
+}
+
+var _ http.Handler = &Server{}
+
+type ServerOption func(s *Server) error
+
+// Where each request is audited, defaults to logging it
+func WithAuditLogger(audit AuditLogger) ServerOption {
+	return func(s *Server) error {
+		if audit == nil {
+			return errors.New("audit logger must not be nil")
+		}
+		s.audit = audit
+		return nil
+	}
+}
+
+func NewServer(resolve KeyResolver, options ...ServerOption) (*Server, error) {
+	if resolve == nil {
+		return nil, errors.New("key resolver must not be nil")
+	}
+	s := &Server{
+		resolve: resolve,
+		audit:   logAudit,
+		mux:     http.NewServeMux(),
+	}
+	for _, opt := range options {
+		if err := opt(s); err != nil {
+			return nil, err
+		}
+	}
+	s.mux.HandleFunc("POST "+PathPublicKey, s.handlePublicKey)
+	s.mux.HandleFunc("POST "+PathSign, s.handleSign)
+	s.mux.HandleFunc("POST "+PathSharedKey, s.handleSharedKey)
+	return s, nil
+}
+
+// NewStaticKeyResolver resolves keys from a fixed set, e.g. for tests
+func NewStaticKeyResolver(keys map[string]*factorysigner.Signer) KeyResolver {
+	return func(ctx context.Context, key string, metadata *Metadata) (*factorysigner.Signer, error) {
+		s, ok := keys[key]
+		if !ok {
+			return nil, fmt.Errorf("unknown key %s", key)
+		}
+		return s, nil
+	}
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+func (s *Server) handlePublicKey(w http.ResponseWriter, r *http.Request) {
+	req := &PublicKeyRequest{}
+	s.handle(w, r, PathPublicKey, req, func() (any, int, *AuditRecord, error) {
+		record := &AuditRecord{Key: req.Key, Metadata: req.Metadata}
+		keySigner, err := s.resolve(r.Context(), req.Key, &req.Metadata)
+		if err != nil {
+			return nil, http.StatusNotFound, record, err
+		}
+		publicKey, err := keySigner.PublicKey()
+		if err != nil {
+			return nil, http.StatusInternalServerError, record, err
+		}
+		return &PublicKeyResponse{PublicKey: hex.EncodeToString(publicKey)}, http.StatusOK, record, nil
+	})
+}
+
+func (s *Server) handleSign(w http.ResponseWriter, r *http.Request) {
+	req := &SignRequest{}
+	s.handle(w, r, PathSign, req, func() (any, int, *AuditRecord, error) {
+		record := &AuditRecord{Key: req.Key, Metadata: req.Metadata}
+		payload, err := hex.DecodeString(req.Payload)
+		if err != nil || len(payload) == 0 {
+			return nil, http.StatusBadRequest, record, errors.New("payload must be non-empty hex")
+		}
+		keySigner, err := s.resolve(r.Context(), req.Key, &req.Metadata)
+		if err != nil {
+			return nil, http.StatusNotFound, record, err
+		}
+		var sig xc.TxSignature
+		if req.Algorithm == "" {
+			sig, err = keySigner.Sign(payload)
+		} else {
+			sig, err = keySigner.SignWithType(req.Algorithm, payload)
+		}
+		if err != nil {
+			return nil, http.StatusBadRequest, record, err
+		}
+		return &SignResponse{Signature: hex.EncodeToString(sig)}, http.StatusOK, record, nil
+	})
+}
+
+func (s *Server) handleSharedKey(w http.ResponseWriter, r *http.Request) {
+	req := &SharedKeyRequest{}
+	s.handle(w, r, PathSharedKey, req, func() (any, int, *AuditRecord, error) {
+		record := &AuditRecord{Key: req.Key, Metadata: req.Metadata}
+		theirKey, err := hex.DecodeString(req.TheirKey)
+		if err != nil || len(theirKey) != ed25519.PublicKeySize {
+			return nil, http.StatusBadRequest, record, errors.New("their key must be a hex ed25519 public key")
+		}
+		keySigner, err := s.resolve(r.Context(), req.Key, &req.Metadata)
+		if err != nil {
+			return nil, http.StatusNotFound, record, err
+		}
+		sharedKey, err := keySigner.SharedKey(theirKey)
+		if err != nil {
+			return nil, http.StatusBadRequest, record, err
+		}
+		return &SharedKeyResponse{SharedKey: hex.EncodeToString(sharedKey)}, http.StatusOK, record, nil
+	})
+}
+
+// Decodes the request, runs it, then audits and writes the result
+func (s *Server) handle(w http.ResponseWriter, r *http.Request, operation string, req any, run func() (any, int, *AuditRecord, error)) {
+	var resp any
+	var status int
+	var record *AuditRecord
+	var err error
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, MaxRequestSize))
+	if err == nil {
+		err = json.Unmarshal(body, req)
+	}
+	if err != nil {
+		status = http.StatusBadRequest
+		record = &AuditRecord{}
+		err = fmt.Errorf("invalid request: %v", err)
+	} else {
+		resp, status, record, err = run()
+	}
+
+	record.Time = time.Now()
+	record.Operation = operation
+	if r.TLS != nil && len(r.TLS.PeerCertificates) > 0 {
+		record.Client = r.TLS.PeerCertificates[0].Subject.CommonName
+	}
+	if err != nil {
+		record.Error = err.Error()
+		resp = &ErrorResponse{Error: err.Error()}
+	}
+	s.audit(r.Context(), record)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+func logAudit(ctx context.Context, record *AuditRecord) {
+	entry := logrus.WithFields(logrus.Fields{
+		"client":    record.Client,
+		"operation": record.Operation,
+		"key":       record.Key,
+		"app_id":    record.Metadata.AppID,
+		"chain":     record.Metadata.Chain,
+		"tx_hash":   record.Metadata.TxHash,
+	})
+	if record.Error != "" {
+		entry.WithField("error", record.Error).Warn("signing request refused")
+	} else {
+		entry.Info("signing request")
+	}
+}
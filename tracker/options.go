@@ -0,0 +1,36 @@
+package tracker
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	DefaultPollInterval = 5 * time.Second
+	// How long a transaction may not be found before it is reported as dropped
+	DefaultDropTimeout = 10 * time.Minute
+)
+
+type Option func(t *Tracker) error
+
+func WithPollInterval(interval time.Duration) Option {
+	return func(t *Tracker) error {
+		if interval <= 0 {
+			return errors.New("poll interval must be positive")
+		}
+		t.pollInterval = interval
+		return nil
+	}
+}
+
+// How long a transaction may not be found before it is reported as dropped.  Clients don't distinguish
+// a missing transaction from other errors, so this should cover any expected downtime of the node.
+func WithDropTimeout(timeout time.Duration) Option {
+	return func(t *Tracker) error {
+		if timeout <= 0 {
+			return errors.New("drop timeout must be positive")
+		}
+		t.dropTimeout = timeout
+		return nil
+	}
+}
@@ -0,0 +1,219 @@
+package tracker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	xclient "github.com/openweb3-io/crosschain/client"
+	xc_types "github.com/openweb3-io/crosschain/types"
+	"github.com/sirupsen/logrus"
+)
+
+type Status string
+
+const (
+	// The transaction is not yet included in a block
+	Pending Status = "pending"
+	// The transaction is included in a block that may still be reorged
+	Included Status = "included"
+	// The transaction met the finality policy of the chain
+	Final Status = "final"
+	// The block the transaction was included in is no longer part of the chain
+	Reorged Status = "reorged"
+	// The transaction could not be found for longer than the drop timeout
+	Dropped Status = "dropped"
+)
+
+// Whether there is nothing left to track
+func (status Status) Done() bool {
+	return status == Final || status == Dropped
+}
+
+// The state of a tracked transaction, which may be persisted to resume tracking later.
+type Tx struct {
+	Hash   xc_types.TxHash `json:"hash"`
+	Status Status          `json:"status"`
+	// the block the transaction was last seen in, if any
+	Block *xclient.Block `json:"block,omitempty"`
+	// when the transaction was last found, or started being tracked
+	LastSeen time.Time `json:"last_seen"`
+}
+
+type Update struct {
+	Status Status `json:"status"`
+	// the transaction as last fetched, nil if it could not be found
+	TxInfo *xclient.TxInfo `json:"tx_info,omitempty"`
+	// set if the transaction is no longer in the block it was included in
+	PreviousBlock *xclient.Block `json:"previous_block,omitempty"`
+}
+
+// Called whenever the status of a transaction changes
+type Handler func(ctx context.Context, update *Update) error
+
+// Re-polls transactions until they meet the finality policy configured for the chain,
+// reporting if they get reorged into another block or dropped.
+type Tracker struct {
+	client   xclient.IClient
+	chain    xc_types.NativeAsset
+	finality xc_types.FinalityConfig
+
+	pollInterval time.Duration
+	dropTimeout  time.Duration
+}
+
+func New(client xclient.IClient, chain *xc_types.ChainConfig, options ...Option) (*Tracker, error) {
+	t := &Tracker{
+		client:       client,
+		chain:        chain.Chain,
+		finality:     chain.GetFinality(),
+		pollInterval: DefaultPollInterval,
+		dropTimeout:  DefaultDropTimeout,
+	}
+	switch t.finality.Policy {
+	case xc_types.FinalityConfirmations, xc_types.FinalityMasterchain:
+	case xc_types.FinalityFinalized, xc_types.FinalitySolidified:
+		if _, ok := client.(xclient.FinalityClient); !ok {
+			return nil, fmt.Errorf("client for %s does not support the %s finality policy", t.chain, t.finality.Policy)
+		}
+	default:
+		return nil, fmt.Errorf("unknown finality policy: %s", t.finality.Policy)
+	}
+	for _, opt := range options {
+		if err := opt(t); err != nil {
+			return nil, err
+		}
+	}
+	return t, nil
+}
+
+func (t *Tracker) NewTx(hash xc_types.TxHash) *Tx {
+	return &Tx{
+		Hash:     hash,
+		Status:   Pending,
+		LastSeen: time.Now(),
+	}
+}
+
+// Poll a transaction until it is final or dropped, calling the handler on every change of status.
+// Returns the last update, or an error if the context is cancelled or the handler fails.
+func (t *Tracker) Track(ctx context.Context, hash xc_types.TxHash, handler Handler) (*Update, error) {
+	tx := t.NewTx(hash)
+	for {
+		update, err := t.Poll(ctx, tx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			logrus.WithError(err).WithFields(logrus.Fields{
+				"chain": t.chain,
+				"hash":  hash,
+			}).Warn("failed to poll transaction")
+		}
+		if update != nil {
+			if err := handler(ctx, update); err != nil {
+				return update, err
+			}
+			if update.Status.Done() {
+				return update, nil
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(t.pollInterval):
+		}
+	}
+}
+
+// Fetch the transaction once and update its state.  Returns an update if the status or the block of the
+// transaction changed, or nil if it did not.  Errors are only returned while the transaction is not dropped.
+func (t *Tracker) Poll(ctx context.Context, tx *Tx) (*Update, error) {
+	info, err := t.client.FetchTxInfo(ctx, tx.Hash)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if tx.Status == Dropped || time.Since(tx.LastSeen) < t.dropTimeout {
+			return nil, fmt.Errorf("could not fetch transaction %s: %v", tx.Hash, err)
+		}
+		update := &Update{Status: Dropped, PreviousBlock: tx.Block}
+		tx.Status = Dropped
+		tx.Block = nil
+		return update, nil
+	}
+	tx.LastSeen = time.Now()
+
+	if !isIncluded(info) {
+		if tx.Block != nil {
+			// back in the mempool
+			return t.reorged(tx, info, Pending), nil
+		}
+		if tx.Status == Pending {
+			return nil, nil
+		}
+		tx.Status = Pending
+		return &Update{Status: Pending, TxInfo: info}, nil
+	}
+
+	if tx.Block != nil && tx.Block.Hash != info.Block.Hash {
+		return t.reorged(tx, info, Included), nil
+	}
+	tx.Block = info.Block
+
+	final, err := t.isFinal(ctx, info)
+	if err != nil {
+		if tx.Status == Included {
+			return nil, err
+		}
+		// report the inclusion anyway, finality is checked again on the next poll
+		final = false
+	}
+	status := Included
+	if final {
+		status = Final
+	}
+	if status == tx.Status {
+		return nil, nil
+	}
+	tx.Status = status
+	return &Update{Status: status, TxInfo: info}, nil
+}
+
+func (t *Tracker) reorged(tx *Tx, info *xclient.TxInfo, status Status) *Update {
+	logrus.WithFields(logrus.Fields{
+		"chain":  t.chain,
+		"hash":   tx.Hash,
+		"height": tx.Block.Height,
+		"block":  tx.Block.Hash,
+	}).Warn("transaction was reorged")
+	update := &Update{Status: Reorged, TxInfo: info, PreviousBlock: tx.Block}
+	tx.Status = status
+	tx.Block = nil
+	if status == Included {
+		tx.Block = info.Block
+	}
+	return update
+}
+
+func (t *Tracker) isFinal(ctx context.Context, info *xclient.TxInfo) (bool, error) {
+	switch t.finality.Policy {
+	case xc_types.FinalityConfirmations:
+		return info.Confirmations >= t.finality.Confirmations, nil
+	case xc_types.FinalityMasterchain:
+		// transactions are only reported once their shard block is committed to the masterchain, which is final
+		return true, nil
+	case xc_types.FinalityFinalized, xc_types.FinalitySolidified:
+		finalized, err := t.client.(xclient.FinalityClient).FetchFinalizedHeight(ctx)
+		if err != nil {
+			return false, fmt.Errorf("could not fetch finalized height: %v", err)
+		}
+		return info.Block.Height <= finalized, nil
+	}
+	return false, errors.New("unknown finality policy")
+}
+
+func isIncluded(info *xclient.TxInfo) bool {
+	return info.Block != nil && info.Block.Height > 0
+}
@@ -0,0 +1,184 @@
+package tracker_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	xclient "github.com/openweb3-io/crosschain/client"
+	"github.com/openweb3-io/crosschain/tracker"
+	xc_types "github.com/openweb3-io/crosschain/types"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeClient struct {
+	// only FetchTxInfo is used by the tracker
+	xclient.IClient
+	info      *xclient.TxInfo
+	finalized uint64
+}
+
+func (c *fakeClient) FetchTxInfo(ctx context.Context, txHash xc_types.TxHash) (*xclient.TxInfo, error) {
+	if c.info == nil {
+		return nil, errors.New("transaction not found")
+	}
+	return c.info, nil
+}
+
+type finalityClient struct {
+	fakeClient
+}
+
+var _ xclient.FinalityClient = &finalityClient{}
+
+func (c *finalityClient) FetchFinalizedHeight(ctx context.Context) (uint64, error) {
+	return c.finalized, nil
+}
+
+func txIn(height uint64, hash string, confirmations uint64) *xclient.TxInfo {
+	return xclient.NewTxInfo(xclient.NewBlock(height, hash, time.Unix(int64(height), 0)), xc_types.BTC, "tx", confirmations, nil)
+}
+
+func TestFinalityConfig(t *testing.T) {
+	require := require.New(t)
+
+	finality := (&xc_types.ChainConfig{Chain: xc_types.BTC}).GetFinality()
+	require.Equal(xc_types.FinalityConfirmations, finality.Policy)
+	require.EqualValues(6, finality.Confirmations)
+
+	finality = (&xc_types.ChainConfig{Chain: xc_types.DOGE, Finality: xc_types.FinalityConfig{Confirmations: 40}}).GetFinality()
+	require.Equal(xc_types.FinalityConfirmations, finality.Policy)
+	require.EqualValues(40, finality.Confirmations)
+
+	require.Equal(xc_types.FinalityFinalized, (&xc_types.ChainConfig{Chain: xc_types.ETH}).GetFinality().Policy)
+	finality = (&xc_types.ChainConfig{Chain: xc_types.BNB}).GetFinality()
+	require.Equal(xc_types.FinalityConfirmations, finality.Policy)
+	require.EqualValues(12, finality.Confirmations)
+	require.Equal(xc_types.FinalitySolidified, (&xc_types.ChainConfig{Chain: xc_types.TRX}).GetFinality().Policy)
+	require.Equal(xc_types.FinalityMasterchain, (&xc_types.ChainConfig{Chain: xc_types.TON}).GetFinality().Policy)
+
+	// the finalized policy needs support from the client
+	_, err := tracker.New(&fakeClient{}, &xc_types.ChainConfig{Chain: xc_types.ETH})
+	require.ErrorContains(err, "does not support")
+	_, err = tracker.New(&finalityClient{}, &xc_types.ChainConfig{Chain: xc_types.ETH})
+	require.NoError(err)
+}
+
+func TestTrackConfirmations(t *testing.T) {
+	require := require.New(t)
+	client := &fakeClient{}
+	chain := &xc_types.ChainConfig{Chain: xc_types.BTC, Finality: xc_types.FinalityConfig{Confirmations: 3}}
+	tr, err := tracker.New(client, chain)
+	require.NoError(err)
+	ctx := context.Background()
+	tx := tr.NewTx("tx")
+
+	// not broadcasted yet
+	_, err = tr.Poll(ctx, tx)
+	require.Error(err)
+	require.Equal(tracker.Pending, tx.Status)
+
+	client.info = txIn(0, "", 0)
+	update, err := tr.Poll(ctx, tx)
+	require.NoError(err)
+	require.Nil(update)
+
+	client.info = txIn(100, "a-100", 1)
+	update, err = tr.Poll(ctx, tx)
+	require.NoError(err)
+	require.Equal(tracker.Included, update.Status)
+	require.Equal("a-100", tx.Block.Hash)
+
+	client.info = txIn(100, "a-100", 2)
+	update, err = tr.Poll(ctx, tx)
+	require.NoError(err)
+	require.Nil(update)
+
+	// the block is replaced
+	client.info = txIn(101, "b-101", 1)
+	update, err = tr.Poll(ctx, tx)
+	require.NoError(err)
+	require.Equal(tracker.Reorged, update.Status)
+	require.Equal("a-100", update.PreviousBlock.Hash)
+	require.Equal("b-101", update.TxInfo.Block.Hash)
+	require.Equal(tracker.Included, tx.Status)
+
+	client.info = txIn(101, "b-101", 3)
+	update, err = tr.Poll(ctx, tx)
+	require.NoError(err)
+	require.Equal(tracker.Final, update.Status)
+	require.True(update.Status.Done())
+}
+
+func TestTrackFinalized(t *testing.T) {
+	require := require.New(t)
+	client := &finalityClient{}
+	tr, err := tracker.New(client, &xc_types.ChainConfig{Chain: xc_types.ETH})
+	require.NoError(err)
+	ctx := context.Background()
+	tx := tr.NewTx("tx")
+
+	client.info = txIn(100, "a-100", 30)
+	client.finalized = 99
+	update, err := tr.Poll(ctx, tx)
+	require.NoError(err)
+	require.Equal(tracker.Included, update.Status)
+
+	// back in the mempool
+	client.info = txIn(0, "", 0)
+	update, err = tr.Poll(ctx, tx)
+	require.NoError(err)
+	require.Equal(tracker.Reorged, update.Status)
+	require.Equal("a-100", update.PreviousBlock.Hash)
+	require.Equal(tracker.Pending, tx.Status)
+	require.Nil(tx.Block)
+
+	client.info = txIn(102, "b-102", 0)
+	client.finalized = 102
+	update, err = tr.Poll(ctx, tx)
+	require.NoError(err)
+	require.Equal(tracker.Final, update.Status)
+}
+
+func TestTrackDropped(t *testing.T) {
+	require := require.New(t)
+	client := &fakeClient{}
+	tr, err := tracker.New(client, &xc_types.ChainConfig{Chain: xc_types.BTC}, tracker.WithDropTimeout(time.Minute))
+	require.NoError(err)
+	ctx := context.Background()
+	tx := tr.NewTx("tx")
+
+	client.info = txIn(100, "a-100", 1)
+	update, err := tr.Poll(ctx, tx)
+	require.NoError(err)
+	require.Equal(tracker.Included, update.Status)
+
+	// errors are expected to be transient until the drop timeout
+	client.info = nil
+	_, err = tr.Poll(ctx, tx)
+	require.ErrorContains(err, "not found")
+
+	tx.LastSeen = time.Now().Add(-2 * time.Minute)
+	update, err = tr.Poll(ctx, tx)
+	require.NoError(err)
+	require.Equal(tracker.Dropped, update.Status)
+	require.Equal("a-100", update.PreviousBlock.Hash)
+	require.True(tx.Status.Done())
+}
+
+func TestTrackHandler(t *testing.T) {
+	require := require.New(t)
+	client := &fakeClient{info: txIn(100, "a-100", 1)}
+	tr, err := tracker.New(client, &xc_types.ChainConfig{Chain: xc_types.TON}, tracker.WithPollInterval(time.Millisecond))
+	require.NoError(err)
+
+	statuses := []tracker.Status{}
+	update, err := tr.Track(context.Background(), "tx", func(ctx context.Context, update *tracker.Update) error {
+		statuses = append(statuses, update.Status)
+		return nil
+	})
+	require.NoError(err)
+	require.Equal(tracker.Final, update.Status)
+	require.Equal([]tracker.Status{tracker.Final}, statuses)
+}
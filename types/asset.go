@@ -119,6 +119,27 @@ func (staking *StakingConfig) Enabled() bool {
 	return len(staking.Providers) > 0
 }
 
+type FinalityPolicy string
+
+const (
+	// Final after a number of confirmations, for chains with probabilistic finality like bitcoin
+	FinalityConfirmations FinalityPolicy = "confirmations"
+	// Final once the block is no later than the block the chain reports as finalized,
+	// e.g. the "finalized" block tag on EVM or the "finalized" commitment on Solana
+	FinalityFinalized FinalityPolicy = "finalized"
+	// Final once the block is solidified (tron)
+	FinalitySolidified FinalityPolicy = "solidified"
+	// Final once the transaction is included in a masterchain block (ton)
+	FinalityMasterchain FinalityPolicy = "masterchain"
+)
+
+type FinalityConfig struct {
+	// Defaults to the native notion of finality of the blockchain
+	Policy FinalityPolicy `yaml:"policy,omitempty"`
+	// Number of confirmations needed with the "confirmations" policy
+	Confirmations uint64 `yaml:"confirmations,omitempty"`
+}
+
 type ChainConfig struct {
 	Blockchain       Blockchain    `yaml:"blockchain,omitempty"` // chain
 	Chain            NativeAsset   `yaml:"chain,omitempty"`      // chainId
@@ -140,11 +161,19 @@ type ChainConfig struct {
 	ChainIDStr           string  `yaml:"chain_id_str,omitempty"`
 	ChainGasPriceDefault float64 `yaml:"chain_gas_price_default,omitempty"`
 
+	// Template of the path keys are derived from mnemonics with, e.g. m/44'/{coin}'/{account}'/0/{index}
+	HDPath string `yaml:"hd_path,omitempty"`
+
 	ExplorerURL string `yaml:"explorer_url,omitempty"`
 	NoGasFees   bool   `yaml:"no_gas_fees,omitempty"`
 
 	Staking StakingConfig `yaml:"staking,omitempty"`
 
+	// ERC-2771 forwarder through which fee payers relay transactions on EVM chains
+	ForwarderContract string `yaml:"forwarder_contract,omitempty"`
+
+	Finality FinalityConfig `yaml:"finality,omitempty"`
+
 	// Internal
 	// AuthSecret string `yaml:"-"`
 }
@@ -153,6 +182,26 @@ func (asset *ChainConfig) ID() AssetID {
 	return GetAssetIDFromAsset("", asset.Chain)
 }
 
+// The finality config of the chain, with any unset fields defaulted based on the blockchain
+func (asset *ChainConfig) GetFinality() FinalityConfig {
+	blockchain := asset.Blockchain
+	if blockchain == "" {
+		blockchain = asset.Chain.Blockchain()
+	}
+	defaults := blockchain.DefaultFinality()
+	finality := asset.Finality
+	if finality.Policy == "" {
+		finality.Policy = defaults.Policy
+	}
+	if finality.Policy == FinalityConfirmations && finality.Confirmations == 0 {
+		finality.Confirmations = defaults.Confirmations
+		if finality.Confirmations == 0 {
+			finality.Confirmations = 1
+		}
+	}
+	return finality
+}
+
 func (asset *ChainConfig) GetDecimals() int32 {
 	return asset.Decimals
 }
@@ -145,6 +145,25 @@ func (blockchain Blockchain) SignatureAlgorithm() SignatureType {
 	return ""
 }
 
+// The native notion of finality of the blockchain, used when a chain does not configure its own
+func (blockchain Blockchain) DefaultFinality() FinalityConfig {
+	switch blockchain {
+	case BlockchainBtc, BlockchainBtcCash, BlockchainBtcLegacy:
+		return FinalityConfig{Policy: FinalityConfirmations, Confirmations: 6}
+	case BlockchainEVM, BlockchainSolana:
+		return FinalityConfig{Policy: FinalityFinalized}
+	case BlockchainEVMLegacy:
+		// legacy evm chains mostly predate the "finalized" block tag
+		return FinalityConfig{Policy: FinalityConfirmations, Confirmations: 12}
+	case BlockchainTron:
+		return FinalityConfig{Policy: FinalitySolidified}
+	case BlockchainTon:
+		return FinalityConfig{Policy: FinalityMasterchain}
+	}
+	// cosmos is final as soon as a block is committed
+	return FinalityConfig{Policy: FinalityConfirmations, Confirmations: 1}
+}
+
 type PublicKeyFormat string
 
 var Raw PublicKeyFormat = "raw"
@@ -1,5 +1,7 @@
 package types
 
+import "encoding/json"
+
 // TxStatus is the status of a tx on chain, currently success or failure.
 type TxStatus uint8
 
@@ -32,6 +34,52 @@ type Tx interface {
 	GetSignatures() []TxSignature
 }
 
+// Optional interface for transactions where some sighashes must be signed with a different
+// algorithm than the default of the chain, e.g. taproot inputs on bitcoin are signed with schnorr.
+type TxWithSignatureTypes interface {
+	// The signature algorithm of each sighash, in the same order as Sighashes()
+	SignatureTypes() ([]SignatureType, error)
+}
+
+// A payload that must be signed by a specific account of the transaction
+type SignatureRequest struct {
+	// The address that must sign the payload
+	Signer  Address
+	Payload TxDataToSign
+	// The algorithm to sign the payload with
+	Algorithm SignatureType
+}
+
+func NewSignatureRequest(signer Address, payload TxDataToSign, algorithm SignatureType) *SignatureRequest {
+	return &SignatureRequest{
+		Signer:    signer,
+		Payload:   payload,
+		Algorithm: algorithm,
+	}
+}
+
+// Optional interface for transactions that may need to be signed by more than one account, e.g. when
+// a separate fee payer sponsors the transaction, or inputs are owned by different keys.  This lets
+// each payload be routed to the key of its signer.
+type TxWithSigners interface {
+	// Each payload with the address that must sign it, in the same order as Sighashes().  Signatures
+	// are added with AddSignatures in this order too.
+	SignatureRequests() ([]*SignatureRequest, error)
+}
+
+// An unsigned transaction encoded with the blockchain it is for, so it can be built on one host and
+// signed on another, e.g. an offline signer.  The transaction is the JSON encoding of the Tx of the blockchain.
+type TxEnvelope struct {
+	Type Blockchain      `json:"type"`
+	Tx   json.RawMessage `json:"tx"`
+}
+
+func NewTxEnvelope(envType Blockchain) *TxEnvelope {
+	return &TxEnvelope{
+		Type: envType,
+	}
+}
+
 type TxVariantInput interface {
 	TxInput
 	GetVariant() TxVariantInputType
@@ -30,6 +30,11 @@ type TxInputWithUnix interface {
 	SetUnix(int64)
 }
 
+// For chains that order the transactions of an address by a nonce or sequence, to use one that is managed locally
+type TxInputWithNonce interface {
+	SetNonce(uint64)
+}
+
 type TxInputGasFeeMultiplier interface {
 	SetGasFeePriority(priority GasFeePriority) error
 }
@@ -0,0 +1,37 @@
+package watcher
+
+import (
+	xclient "github.com/openweb3-io/crosschain/client"
+	xc_types "github.com/openweb3-io/crosschain/types"
+)
+
+type EventType string
+
+const (
+	// A deposit was included in a block
+	DepositDetected EventType = "detected"
+	// A deposit reached the required number of confirmations
+	DepositConfirmed EventType = "confirmed"
+	// The block of a deposit was rolled back by a reorg.  The deposit may be detected again on the new chain.
+	DepositReorged EventType = "reorged"
+)
+
+type Deposit struct {
+	Chain  xc_types.NativeAsset `json:"chain"`
+	TxHash string               `json:"tx_hash"`
+	Block  xclient.Block        `json:"block"`
+	// the first source of the transfer, may be empty (e.g. for coinbase transactions)
+	From xc_types.Address `json:"from"`
+	// the watched address that received the deposit
+	To xc_types.Address `json:"to"`
+	// the chain for the native asset, as in TxInfo transfers
+	Contract      xc_types.ContractAddress `json:"contract"`
+	Amount        xc_types.BigInt          `json:"amount"`
+	Memo          string                   `json:"memo,omitempty"`
+	Confirmations uint64                   `json:"confirmations"`
+}
+
+type Event struct {
+	Type    EventType `json:"type"`
+	Deposit *Deposit  `json:"deposit"`
+}
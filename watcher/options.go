@@ -0,0 +1,72 @@
+package watcher
+
+import (
+	"errors"
+	"time"
+
+	xc_types "github.com/openweb3-io/crosschain/types"
+)
+
+const (
+	DefaultPollInterval  = 5 * time.Second
+	DefaultConfirmations = 1
+	// Number of recent blocks kept to be able to roll back a reorg
+	DefaultMaxReorgDepth = 64
+)
+
+type Option func(w *Watcher) error
+
+// Number of confirmations after which a deposit is reported as confirmed.  A deposit in the head block has 1 confirmation.
+func WithConfirmations(confirmations uint64) Option {
+	return func(w *Watcher) error {
+		if confirmations == 0 {
+			return errors.New("confirmations must be at least 1")
+		}
+		w.confirmations = confirmations
+		return nil
+	}
+}
+
+// Height of the first block to scan, e.g. to resume from the last height persisted.  Defaults to the head of the chain.
+func WithStartHeight(height uint64) Option {
+	return func(w *Watcher) error {
+		w.next = height
+		w.started = true
+		return nil
+	}
+}
+
+func WithPollInterval(interval time.Duration) Option {
+	return func(w *Watcher) error {
+		if interval <= 0 {
+			return errors.New("poll interval must be positive")
+		}
+		w.pollInterval = interval
+		return nil
+	}
+}
+
+func WithMaxReorgDepth(depth int) Option {
+	return func(w *Watcher) error {
+		if depth <= 0 {
+			return errors.New("max reorg depth must be positive")
+		}
+		w.maxReorgDepth = depth
+		return nil
+	}
+}
+
+func WithAddresses(addresses ...xc_types.Address) Option {
+	return func(w *Watcher) error {
+		w.AddAddresses(addresses...)
+		return nil
+	}
+}
+
+// Only report deposits of the given contracts.  Use "" or the chain for the native asset.  Defaults to all assets.
+func WithContracts(contracts ...xc_types.ContractAddress) Option {
+	return func(w *Watcher) error {
+		w.AddContracts(contracts...)
+		return nil
+	}
+}
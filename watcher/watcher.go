@@ -0,0 +1,334 @@
+package watcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	xclient "github.com/openweb3-io/crosschain/client"
+	"github.com/openweb3-io/crosschain/normalize"
+	xc_types "github.com/openweb3-io/crosschain/types"
+	"github.com/sirupsen/logrus"
+)
+
+// Returned once a reorg replaces blocks that were already pruned, whose deposits can no longer be
+// retracted.  The watcher stops, and needs to be restarted from a height before the reorg.
+var ErrReorgTooDeep = errors.New("reorg is deeper than the blocks kept")
+
+// Called for every event in the order they happen.  Returning an error stops the watcher
+// without advancing past the block of the event, so events may be delivered more than once.
+type Handler func(ctx context.Context, event *Event) error
+
+type trackedBlock struct {
+	height   uint64
+	hash     string
+	deposits []*Deposit
+	// set once all of the deposits in the block are confirmed
+	confirmed bool
+}
+
+// Follows the head of a chain and reports deposits to a set of watched addresses, which scales
+// to any number of addresses as each block is only fetched once.
+type Watcher struct {
+	client xclient.BlockClient
+	chain  xc_types.NativeAsset
+
+	confirmations uint64
+	pollInterval  time.Duration
+	maxReorgDepth int
+
+	lock      sync.RWMutex
+	addresses map[xclient.AddressName]xc_types.Address
+	contracts map[string]bool
+
+	// the next height to fetch
+	next    uint64
+	started bool
+	// recently processed blocks, oldest first
+	recent []*trackedBlock
+	// set once blocks have been dropped from recent
+	pruned bool
+	// set once the watcher can't continue
+	err error
+}
+
+func New(client xclient.BlockClient, chain *xc_types.ChainConfig, options ...Option) (*Watcher, error) {
+	w := &Watcher{
+		client:        client,
+		chain:         chain.Chain,
+		confirmations: DefaultConfirmations,
+		pollInterval:  DefaultPollInterval,
+		maxReorgDepth: DefaultMaxReorgDepth,
+		addresses:     map[xclient.AddressName]xc_types.Address{},
+		contracts:     map[string]bool{},
+	}
+	for _, opt := range options {
+		if err := opt(w); err != nil {
+			return nil, err
+		}
+	}
+	return w, nil
+}
+
+func (w *Watcher) AddAddresses(addresses ...xc_types.Address) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	for _, addr := range addresses {
+		w.addresses[xclient.NewAddressName(w.chain, string(addr))] = addr
+	}
+}
+
+func (w *Watcher) RemoveAddresses(addresses ...xc_types.Address) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	for _, addr := range addresses {
+		delete(w.addresses, xclient.NewAddressName(w.chain, string(addr)))
+	}
+}
+
+func (w *Watcher) AddContracts(contracts ...xc_types.ContractAddress) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	for _, contract := range contracts {
+		w.contracts[w.contractKey(contract)] = true
+	}
+}
+
+func (w *Watcher) contractKey(contract xc_types.ContractAddress) string {
+	if contract == "" {
+		// transfers report the native asset as the chain
+		contract = xc_types.ContractAddress(w.chain)
+	}
+	return normalize.Normalize(string(contract), w.chain)
+}
+
+// The next height that will be scanned; persist this to resume with WithStartHeight.
+func (w *Watcher) Height() uint64 {
+	return w.next
+}
+
+// Poll the chain until the context is cancelled or the handler fails.
+func (w *Watcher) Run(ctx context.Context, handler Handler) error {
+	for {
+		if err := w.Poll(ctx, handler); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			var handlerErr *HandlerError
+			if errors.As(err, &handlerErr) || errors.Is(err, ErrReorgTooDeep) {
+				return err
+			}
+			// client errors are expected to be transient
+			logrus.WithError(err).WithField("chain", w.chain).Warn("failed to poll chain")
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(w.pollInterval):
+		}
+	}
+}
+
+type HandlerError struct {
+	Err error
+}
+
+func (e *HandlerError) Error() string {
+	return fmt.Sprintf("handler failed: %v", e.Err)
+}
+
+func (e *HandlerError) Unwrap() error {
+	return e.Err
+}
+
+// Scan all new blocks up to the current head once.
+func (w *Watcher) Poll(ctx context.Context, handler Handler) error {
+	if w.err != nil {
+		return w.err
+	}
+	latest, err := w.client.FetchLatestHeight(ctx)
+	if err != nil {
+		return fmt.Errorf("could not fetch latest height: %v", err)
+	}
+	if !w.started {
+		w.next = latest
+		w.started = true
+	}
+
+	for w.next <= latest {
+		block, err := w.client.FetchBlock(ctx, w.next)
+		if errors.Is(err, xclient.ErrBlockSkipped) {
+			w.next++
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("could not fetch block %d: %v", w.next, err)
+		}
+
+		if w.isReorged(block) {
+			if err := w.rollback(ctx, handler); err != nil {
+				return err
+			}
+			continue
+		}
+
+		deposits := w.findDeposits(block, latest)
+		for _, deposit := range deposits {
+			if err := w.emit(ctx, handler, DepositDetected, deposit); err != nil {
+				return err
+			}
+		}
+		w.recent = append(w.recent, &trackedBlock{
+			height:   block.Height,
+			hash:     block.Hash,
+			deposits: deposits,
+		})
+		w.next = block.Height + 1
+	}
+
+	if err := w.confirm(ctx, handler, latest); err != nil {
+		return err
+	}
+	w.prune()
+	return nil
+}
+
+// A block is reorged if its parent is not the last block we processed.
+func (w *Watcher) isReorged(block *xclient.BlockWithTransactions) bool {
+	if block.ParentHash == "" || len(w.recent) == 0 {
+		return false
+	}
+	last := w.recent[len(w.recent)-1]
+	return last.height < block.Height && last.hash != block.ParentHash
+}
+
+// Drop the last processed block so that it gets fetched again from the new chain.
+func (w *Watcher) rollback(ctx context.Context, handler Handler) error {
+	last := w.recent[len(w.recent)-1]
+	logrus.WithFields(logrus.Fields{
+		"chain":  w.chain,
+		"height": last.height,
+		"hash":   last.hash,
+	}).Warn("block was reorged")
+
+	for _, deposit := range last.deposits {
+		if err := w.emit(ctx, handler, DepositReorged, deposit); err != nil {
+			return err
+		}
+	}
+	w.recent = w.recent[:len(w.recent)-1]
+	w.next = last.height
+	if len(w.recent) == 0 && w.pruned {
+		// the new chain can't be checked against anything we still know, and the deposits of
+		// pruned blocks may have been reorged too
+		w.err = fmt.Errorf("%w: more than %d blocks at height %d", ErrReorgTooDeep, w.maxReorgDepth, last.height)
+		return w.err
+	}
+	return nil
+}
+
+func (w *Watcher) confirm(ctx context.Context, handler Handler, latest uint64) error {
+	for _, block := range w.recent {
+		if block.confirmed {
+			continue
+		}
+		confirmations := confirmationsAt(latest, block.height)
+		for _, deposit := range block.deposits {
+			deposit.Confirmations = confirmations
+		}
+		if confirmations < w.confirmations {
+			continue
+		}
+		for _, deposit := range block.deposits {
+			if err := w.emit(ctx, handler, DepositConfirmed, deposit); err != nil {
+				return err
+			}
+		}
+		block.confirmed = true
+	}
+	return nil
+}
+
+// Forget about old blocks, as long as all of their deposits are confirmed.
+func (w *Watcher) prune() {
+	for len(w.recent) > w.maxReorgDepth && w.recent[0].confirmed {
+		w.recent = w.recent[1:]
+		w.pruned = true
+	}
+}
+
+// The block at the head has 1 confirmation.  A lagging node may report a head below blocks that
+// were already processed, which have no confirmations then.
+func confirmationsAt(latest uint64, height uint64) uint64 {
+	if latest < height {
+		return 0
+	}
+	return latest - height + 1
+}
+
+func (w *Watcher) emit(ctx context.Context, handler Handler, eventType EventType, deposit *Deposit) error {
+	// pass a copy, as the confirmations of tracked deposits keep being updated
+	copied := *deposit
+	if err := handler(ctx, &Event{Type: eventType, Deposit: &copied}); err != nil {
+		return &HandlerError{err}
+	}
+	return nil
+}
+
+func (w *Watcher) findDeposits(block *xclient.BlockWithTransactions, latest uint64) []*Deposit {
+	w.lock.RLock()
+	defer w.lock.RUnlock()
+
+	deposits := []*Deposit{}
+	for _, tx := range block.Transactions {
+		if tx.Error != nil {
+			continue
+		}
+		for _, tf := range tx.Transfers {
+			sources := map[xclient.AddressName]bool{}
+			for _, from := range tf.From {
+				sources[from.Address] = true
+			}
+			for _, to := range tf.To {
+				addr, ok := w.addresses[to.Address]
+				if !ok {
+					continue
+				}
+				// change returned to a source of the transfer is not a deposit
+				if sources[to.Address] {
+					continue
+				}
+				if len(w.contracts) > 0 && !w.contracts[w.contractKey(to.Contract)] {
+					continue
+				}
+				from := xc_types.Address("")
+				if len(tf.From) > 0 {
+					from = addressOf(tf.From[0].Address)
+				}
+				deposits = append(deposits, &Deposit{
+					Chain:         w.chain,
+					TxHash:        tx.Hash,
+					Block:         block.Block,
+					From:          from,
+					To:            addr,
+					Contract:      to.Contract,
+					Amount:        to.Balance,
+					Memo:          tf.Memo,
+					Confirmations: confirmationsAt(latest, block.Height),
+				})
+			}
+		}
+	}
+	return deposits
+}
+
+// Recover the address from an address name, "chains/<chain>/addresses/<address>"
+func addressOf(name xclient.AddressName) xc_types.Address {
+	if _, addr, ok := strings.Cut(string(name), "/addresses/"); ok {
+		return xc_types.Address(addr)
+	}
+	return xc_types.Address(name)
+}
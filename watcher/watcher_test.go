@@ -0,0 +1,231 @@
+package watcher_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	xclient "github.com/openweb3-io/crosschain/client"
+	xc_types "github.com/openweb3-io/crosschain/types"
+	"github.com/openweb3-io/crosschain/watcher"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeChain struct {
+	blocks  []*xclient.BlockWithTransactions
+	skipped map[uint64]bool
+}
+
+var _ xclient.BlockClient = &fakeChain{}
+
+func (c *fakeChain) FetchLatestHeight(ctx context.Context) (uint64, error) {
+	return c.blocks[len(c.blocks)-1].Height, nil
+}
+
+func (c *fakeChain) FetchBlock(ctx context.Context, height uint64) (*xclient.BlockWithTransactions, error) {
+	if c.skipped[height] {
+		return nil, xclient.ErrBlockSkipped
+	}
+	for _, block := range c.blocks {
+		if block.Height == height {
+			return block, nil
+		}
+	}
+	return nil, errors.New("not found")
+}
+
+// Add a block on top of the chain, dropping any blocks at the same height or higher
+func (c *fakeChain) addBlock(height uint64, fork string, transfers ...[2]string) {
+	for len(c.blocks) > 0 && c.blocks[len(c.blocks)-1].Height >= height {
+		c.blocks = c.blocks[:len(c.blocks)-1]
+	}
+	parent := ""
+	if len(c.blocks) > 0 {
+		parent = c.blocks[len(c.blocks)-1].Hash
+	}
+	hash := fmt.Sprintf("%s-%d", fork, height)
+	block := xclient.NewBlock(height, hash, time.Unix(int64(height), 0))
+	txs := []*xclient.TxInfo{}
+	for i, tf := range transfers {
+		tx := xclient.NewTxInfo(block, xc_types.ETH, fmt.Sprintf("%s-tx%d", hash, i), 0, nil)
+		tx.AddSimpleTransfer(xc_types.Address(tf[0]), xc_types.Address(tf[1]), "", xc_types.NewBigIntFromUint64(100), nil, "")
+		txs = append(txs, tx)
+	}
+	c.blocks = append(c.blocks, xclient.NewBlockWithTransactions(block, parent, txs))
+}
+
+type recorder struct {
+	events []*watcher.Event
+}
+
+func (r *recorder) handle(ctx context.Context, event *watcher.Event) error {
+	r.events = append(r.events, event)
+	return nil
+}
+
+func (r *recorder) types() []watcher.EventType {
+	types := []watcher.EventType{}
+	for _, ev := range r.events {
+		types = append(types, ev.Type)
+	}
+	return types
+}
+
+var chainConfig = &xc_types.ChainConfig{Chain: xc_types.ETH}
+
+const watched = "0x00000000000000000000000000000000000000aa"
+const other = "0x00000000000000000000000000000000000000bb"
+
+func TestWatcherConfirmations(t *testing.T) {
+	require := require.New(t)
+	chain := &fakeChain{}
+	chain.addBlock(10, "a")
+	chain.addBlock(11, "a", [2]string{other, watched}, [2]string{watched, other})
+
+	w, err := watcher.New(chain, chainConfig, watcher.WithStartHeight(10), watcher.WithConfirmations(3), watcher.WithAddresses(watched))
+	require.NoError(err)
+	rec := &recorder{}
+	ctx := context.Background()
+
+	require.NoError(w.Poll(ctx, rec.handle))
+	// only the incoming transfer is a deposit
+	require.Equal([]watcher.EventType{watcher.DepositDetected}, rec.types())
+	deposit := rec.events[0].Deposit
+	require.EqualValues(watched, deposit.To)
+	require.EqualValues(other, deposit.From)
+	require.EqualValues(100, deposit.Amount.Uint64())
+	require.EqualValues(11, deposit.Block.Height)
+	require.EqualValues(1, deposit.Confirmations)
+	require.EqualValues(12, w.Height())
+
+	chain.addBlock(12, "a")
+	require.NoError(w.Poll(ctx, rec.handle))
+	require.Len(rec.events, 1)
+
+	chain.addBlock(13, "a")
+	require.NoError(w.Poll(ctx, rec.handle))
+	require.Equal([]watcher.EventType{watcher.DepositDetected, watcher.DepositConfirmed}, rec.types())
+	require.EqualValues(3, rec.events[1].Deposit.Confirmations)
+}
+
+func TestWatcherReorg(t *testing.T) {
+	require := require.New(t)
+	chain := &fakeChain{}
+	chain.addBlock(10, "a")
+	chain.addBlock(11, "a", [2]string{other, watched})
+	chain.addBlock(12, "a")
+
+	w, err := watcher.New(chain, chainConfig, watcher.WithStartHeight(10), watcher.WithConfirmations(6), watcher.WithAddresses(watched))
+	require.NoError(err)
+	rec := &recorder{}
+	ctx := context.Background()
+	require.NoError(w.Poll(ctx, rec.handle))
+	require.Equal([]watcher.EventType{watcher.DepositDetected}, rec.types())
+
+	// replace blocks 11 and 12, moving the deposit to block 12
+	chain.addBlock(11, "b")
+	chain.addBlock(12, "b", [2]string{other, watched})
+	chain.addBlock(13, "b")
+	require.NoError(w.Poll(ctx, rec.handle))
+	require.Equal([]watcher.EventType{watcher.DepositDetected, watcher.DepositReorged, watcher.DepositDetected}, rec.types())
+	require.Equal("a-11", rec.events[1].Deposit.Block.Hash)
+	require.Equal("b-12", rec.events[2].Deposit.Block.Hash)
+	require.EqualValues(14, w.Height())
+}
+
+func TestWatcherReorgTooDeep(t *testing.T) {
+	require := require.New(t)
+	chain := &fakeChain{}
+	chain.addBlock(10, "a", [2]string{other, watched})
+	chain.addBlock(11, "a")
+	chain.addBlock(12, "a")
+
+	w, err := watcher.New(chain, chainConfig, watcher.WithStartHeight(10), watcher.WithMaxReorgDepth(1), watcher.WithAddresses(watched))
+	require.NoError(err)
+	rec := &recorder{}
+	ctx := context.Background()
+	require.NoError(w.Poll(ctx, rec.handle))
+	require.Equal([]watcher.EventType{watcher.DepositDetected, watcher.DepositConfirmed}, rec.types())
+
+	// replace the pruned blocks
+	chain.addBlock(10, "b")
+	chain.addBlock(11, "b")
+	chain.addBlock(12, "b")
+	chain.addBlock(13, "b")
+	err = w.Poll(ctx, rec.handle)
+	require.ErrorIs(err, watcher.ErrReorgTooDeep)
+	// the watcher stays stopped
+	require.ErrorIs(w.Run(ctx, rec.handle), watcher.ErrReorgTooDeep)
+}
+
+func TestWatcherLaggingNode(t *testing.T) {
+	require := require.New(t)
+	chain := &fakeChain{}
+	chain.addBlock(10, "a", [2]string{other, watched})
+
+	w, err := watcher.New(chain, chainConfig, watcher.WithStartHeight(10), watcher.WithConfirmations(2), watcher.WithAddresses(watched))
+	require.NoError(err)
+	rec := &recorder{}
+	ctx := context.Background()
+	require.NoError(w.Poll(ctx, rec.handle))
+	require.Equal([]watcher.EventType{watcher.DepositDetected}, rec.types())
+
+	// a node behind the one that served block 10
+	chain.blocks = nil
+	chain.addBlock(8, "a")
+	require.NoError(w.Poll(ctx, rec.handle))
+	require.Equal([]watcher.EventType{watcher.DepositDetected}, rec.types())
+}
+
+func TestWatcherSkippedBlocksAndFilters(t *testing.T) {
+	require := require.New(t)
+	chain := &fakeChain{skipped: map[uint64]bool{11: true}}
+	chain.addBlock(10, "a")
+	chain.addBlock(12, "a", [2]string{other, watched})
+
+	w, err := watcher.New(chain, chainConfig, watcher.WithStartHeight(10), watcher.WithContracts("0x00000000000000000000000000000000000000cc"))
+	require.NoError(err)
+	w.AddAddresses(watched)
+	rec := &recorder{}
+	ctx := context.Background()
+
+	// deposits of other assets are ignored
+	require.NoError(w.Poll(ctx, rec.handle))
+	require.Len(rec.events, 0)
+	require.EqualValues(13, w.Height())
+
+	w.AddContracts("")
+	chain.addBlock(13, "a", [2]string{other, watched})
+	require.NoError(w.Poll(ctx, rec.handle))
+	require.Equal([]watcher.EventType{watcher.DepositDetected, watcher.DepositConfirmed}, rec.types())
+
+	w.RemoveAddresses(watched)
+	chain.addBlock(14, "a", [2]string{other, watched})
+	require.NoError(w.Poll(ctx, rec.handle))
+	require.Len(rec.events, 2)
+}
+
+func TestWatcherHandlerError(t *testing.T) {
+	require := require.New(t)
+	chain := &fakeChain{}
+	chain.addBlock(10, "a", [2]string{other, watched})
+
+	w, err := watcher.New(chain, chainConfig, watcher.WithStartHeight(10), watcher.WithAddresses(watched))
+	require.NoError(err)
+	ctx := context.Background()
+
+	err = w.Poll(ctx, func(ctx context.Context, event *watcher.Event) error {
+		return errors.New("database down")
+	})
+	require.ErrorContains(err, "database down")
+	var handlerErr *watcher.HandlerError
+	require.ErrorAs(err, &handlerErr)
+	// the block is scanned again
+	require.EqualValues(10, w.Height())
+
+	rec := &recorder{}
+	require.NoError(w.Poll(ctx, rec.handle))
+	require.Equal([]watcher.EventType{watcher.DepositDetected, watcher.DepositConfirmed}, rec.types())
+}